@@ -0,0 +1,256 @@
+-- 游戏运维模块初始化 SQL
+-- 功能组: game_ops
+-- 功能名称: 游戏运维
+-- 说明: 仅初始化权限与接口，前端页面接入后再按 init_demo.sql 的方式补充菜单与按钮
+
+-- ============================================
+-- 1. 插入权限数据
+-- ============================================
+-- 游戏运维在线列表权限
+INSERT INTO `admin_permission` (`name`, `code`, `description`, `created_at`, `updated_at`, `deleted_at`)
+VALUES (
+    '游戏运维在线列表',
+    'game_ops:list',
+    '查看区服与在线角色',
+    UNIX_TIMESTAMP(),
+    UNIX_TIMESTAMP(),
+    0
+);
+
+SET @list_permission_id = LAST_INSERT_ID();
+
+-- 游戏运维踢下线权限
+INSERT INTO `admin_permission` (`name`, `code`, `description`, `created_at`, `updated_at`, `deleted_at`)
+VALUES (
+    '游戏运维踢下线',
+    'game_ops:kick',
+    '将在线角色踢下线',
+    UNIX_TIMESTAMP(),
+    UNIX_TIMESTAMP(),
+    0
+);
+
+SET @kick_permission_id = LAST_INSERT_ID();
+
+-- 游戏运维禁言权限
+INSERT INTO `admin_permission` (`name`, `code`, `description`, `created_at`, `updated_at`, `deleted_at`)
+VALUES (
+    '游戏运维禁言',
+    'game_ops:mute',
+    '禁言或解除禁言角色',
+    UNIX_TIMESTAMP(),
+    UNIX_TIMESTAMP(),
+    0
+);
+
+SET @mute_permission_id = LAST_INSERT_ID();
+
+-- 游戏运维封号权限
+INSERT INTO `admin_permission` (`name`, `code`, `description`, `created_at`, `updated_at`, `deleted_at`)
+VALUES (
+    '游戏运维封号',
+    'game_ops:ban',
+    '封禁或解封游戏账号',
+    UNIX_TIMESTAMP(),
+    UNIX_TIMESTAMP(),
+    0
+);
+
+SET @ban_permission_id = LAST_INSERT_ID();
+
+-- 游戏运维系统邮件权限
+INSERT INTO `admin_permission` (`name`, `code`, `description`, `created_at`, `updated_at`, `deleted_at`)
+VALUES (
+    '游戏运维系统邮件',
+    'game_ops:mail',
+    '发送系统邮件',
+    UNIX_TIMESTAMP(),
+    UNIX_TIMESTAMP(),
+    0
+);
+
+SET @mail_permission_id = LAST_INSERT_ID();
+
+-- 游戏运维公告权限
+INSERT INTO `admin_permission` (`name`, `code`, `description`, `created_at`, `updated_at`, `deleted_at`)
+VALUES (
+    '游戏运维公告',
+    'game_ops:announce',
+    '广播全服公告',
+    UNIX_TIMESTAMP(),
+    UNIX_TIMESTAMP(),
+    0
+);
+
+SET @announce_permission_id = LAST_INSERT_ID();
+
+-- 游戏运维角色数据权限
+INSERT INTO `admin_permission` (`name`, `code`, `description`, `created_at`, `updated_at`, `deleted_at`)
+VALUES (
+    '游戏运维角色数据',
+    'game_ops:role',
+    '查看角色数据',
+    UNIX_TIMESTAMP(),
+    UNIX_TIMESTAMP(),
+    0
+);
+
+SET @role_permission_id = LAST_INSERT_ID();
+
+-- ============================================
+-- 2. 插入接口数据
+-- ============================================
+-- 游戏运维区服列表接口
+INSERT INTO `admin_api` (`name`, `method`, `path`, `description`, `status`, `created_at`, `updated_at`, `deleted_at`)
+VALUES (
+    '游戏运维区服列表',
+    'GET',
+    '/api/v1/game-ops/servers',
+    '获取已配置的游戏区服',
+    1, -- 状态：1 启用
+    UNIX_TIMESTAMP(),
+    UNIX_TIMESTAMP(),
+    0
+);
+
+SET @list_api_id_1 = LAST_INSERT_ID();
+
+-- 游戏运维在线角色接口
+INSERT INTO `admin_api` (`name`, `method`, `path`, `description`, `status`, `created_at`, `updated_at`, `deleted_at`)
+VALUES (
+    '游戏运维在线角色',
+    'GET',
+    '/api/v1/game-ops/online',
+    '获取区服在线角色列表',
+    1, -- 状态：1 启用
+    UNIX_TIMESTAMP(),
+    UNIX_TIMESTAMP(),
+    0
+);
+
+SET @list_api_id_2 = LAST_INSERT_ID();
+
+-- 游戏运维踢下线接口
+INSERT INTO `admin_api` (`name`, `method`, `path`, `description`, `status`, `created_at`, `updated_at`, `deleted_at`)
+VALUES (
+    '游戏运维踢下线',
+    'POST',
+    '/api/v1/game-ops/kick',
+    '将在线角色踢下线',
+    1, -- 状态：1 启用
+    UNIX_TIMESTAMP(),
+    UNIX_TIMESTAMP(),
+    0
+);
+
+SET @kick_api_id = LAST_INSERT_ID();
+
+-- 游戏运维禁言接口
+INSERT INTO `admin_api` (`name`, `method`, `path`, `description`, `status`, `created_at`, `updated_at`, `deleted_at`)
+VALUES (
+    '游戏运维禁言',
+    'POST',
+    '/api/v1/game-ops/mute',
+    '禁言或解除禁言角色',
+    1, -- 状态：1 启用
+    UNIX_TIMESTAMP(),
+    UNIX_TIMESTAMP(),
+    0
+);
+
+SET @mute_api_id = LAST_INSERT_ID();
+
+-- 游戏运维封号接口
+INSERT INTO `admin_api` (`name`, `method`, `path`, `description`, `status`, `created_at`, `updated_at`, `deleted_at`)
+VALUES (
+    '游戏运维封号',
+    'POST',
+    '/api/v1/game-ops/ban',
+    '封禁或解封游戏账号',
+    1, -- 状态：1 启用
+    UNIX_TIMESTAMP(),
+    UNIX_TIMESTAMP(),
+    0
+);
+
+SET @ban_api_id = LAST_INSERT_ID();
+
+-- 游戏运维系统邮件接口
+INSERT INTO `admin_api` (`name`, `method`, `path`, `description`, `status`, `created_at`, `updated_at`, `deleted_at`)
+VALUES (
+    '游戏运维系统邮件',
+    'POST',
+    '/api/v1/game-ops/mail',
+    '向指定角色或全服发送系统邮件',
+    1, -- 状态：1 启用
+    UNIX_TIMESTAMP(),
+    UNIX_TIMESTAMP(),
+    0
+);
+
+SET @mail_api_id = LAST_INSERT_ID();
+
+-- 游戏运维公告接口
+INSERT INTO `admin_api` (`name`, `method`, `path`, `description`, `status`, `created_at`, `updated_at`, `deleted_at`)
+VALUES (
+    '游戏运维公告',
+    'POST',
+    '/api/v1/game-ops/announce',
+    '向区服在线角色广播公告',
+    1, -- 状态：1 启用
+    UNIX_TIMESTAMP(),
+    UNIX_TIMESTAMP(),
+    0
+);
+
+SET @announce_api_id = LAST_INSERT_ID();
+
+-- 游戏运维角色数据接口
+INSERT INTO `admin_api` (`name`, `method`, `path`, `description`, `status`, `created_at`, `updated_at`, `deleted_at`)
+VALUES (
+    '游戏运维角色数据',
+    'GET',
+    '/api/v1/game-ops/role',
+    '查看角色的 PlayerRoleData',
+    1, -- 状态：1 启用
+    UNIX_TIMESTAMP(),
+    UNIX_TIMESTAMP(),
+    0
+);
+
+SET @role_api_id = LAST_INSERT_ID();
+
+-- ============================================
+-- 3. 插入权限-接口关联数据
+-- ============================================
+-- 游戏运维在线列表权限 -> GET /api/v1/game-ops/servers接口
+INSERT INTO `admin_permission_api` (`permission_id`, `api_id`, `created_at`, `updated_at`)
+VALUES (@list_permission_id, @list_api_id_1, UNIX_TIMESTAMP(), UNIX_TIMESTAMP());
+
+-- 游戏运维在线列表权限 -> GET /api/v1/game-ops/online接口
+INSERT INTO `admin_permission_api` (`permission_id`, `api_id`, `created_at`, `updated_at`)
+VALUES (@list_permission_id, @list_api_id_2, UNIX_TIMESTAMP(), UNIX_TIMESTAMP());
+
+-- 游戏运维踢下线权限 -> POST /api/v1/game-ops/kick接口
+INSERT INTO `admin_permission_api` (`permission_id`, `api_id`, `created_at`, `updated_at`)
+VALUES (@kick_permission_id, @kick_api_id, UNIX_TIMESTAMP(), UNIX_TIMESTAMP());
+
+-- 游戏运维禁言权限 -> POST /api/v1/game-ops/mute接口
+INSERT INTO `admin_permission_api` (`permission_id`, `api_id`, `created_at`, `updated_at`)
+VALUES (@mute_permission_id, @mute_api_id, UNIX_TIMESTAMP(), UNIX_TIMESTAMP());
+
+-- 游戏运维封号权限 -> POST /api/v1/game-ops/ban接口
+INSERT INTO `admin_permission_api` (`permission_id`, `api_id`, `created_at`, `updated_at`)
+VALUES (@ban_permission_id, @ban_api_id, UNIX_TIMESTAMP(), UNIX_TIMESTAMP());
+
+-- 游戏运维系统邮件权限 -> POST /api/v1/game-ops/mail接口
+INSERT INTO `admin_permission_api` (`permission_id`, `api_id`, `created_at`, `updated_at`)
+VALUES (@mail_permission_id, @mail_api_id, UNIX_TIMESTAMP(), UNIX_TIMESTAMP());
+
+-- 游戏运维公告权限 -> POST /api/v1/game-ops/announce接口
+INSERT INTO `admin_permission_api` (`permission_id`, `api_id`, `created_at`, `updated_at`)
+VALUES (@announce_permission_id, @announce_api_id, UNIX_TIMESTAMP(), UNIX_TIMESTAMP());
+
+-- 游戏运维角色数据权限 -> GET /api/v1/game-ops/role接口
+INSERT INTO `admin_permission_api` (`permission_id`, `api_id`, `created_at`, `updated_at`)
+VALUES (@role_permission_id, @role_api_id, UNIX_TIMESTAMP(), UNIX_TIMESTAMP());
@@ -14,6 +14,7 @@ type Config struct {
 	Bcrypt        BcryptConf    `json:"bcrypt" yaml:"bcrypt" mapstructure:"bcrypt"`
 	RateLimit     RateLimitConf `json:"rateLimit" yaml:"rateLimit" mapstructure:"rateLimit"`
 	BaseURL       string        `json:"baseUrl" yaml:"baseUrl" mapstructure:"baseUrl"` // API 基础 URL，用于生成文件完整访问路径
+	GameOps       GameOpsConf   `json:"gameOps" yaml:"gameOps" mapstructure:"gameOps"`
 }
 
 type DatabaseConf struct {
@@ -68,3 +69,16 @@ type RateLimitConf struct {
 		Period  int  `json:"period" yaml:"period" mapstructure:"period"` // 时间窗口（秒）
 	} `json:"globalLimit" yaml:"globalLimit" mapstructure:"globalLimit"`
 }
+
+// GameOpsConf 游戏运维配置，对应各区服 GameServer 的运维接口（gamesrv.json 的 ops_addr）
+type GameOpsConf struct {
+	Secret  string           `json:"secret" yaml:"secret" mapstructure:"secret"`    // 签名密钥，与 gamesrv.json 的 ops_secret 一致
+	Timeout int              `json:"timeout" yaml:"timeout" mapstructure:"timeout"` // 请求超时（秒），默认 5
+	Servers []GameServerConf `json:"servers" yaml:"servers" mapstructure:"servers"`
+}
+
+type GameServerConf struct {
+	SrvId uint32 `json:"srvId" yaml:"srvId" mapstructure:"srvId"`
+	Name  string `json:"name" yaml:"name" mapstructure:"name"`
+	Addr  string `json:"addr" yaml:"addr" mapstructure:"addr"` // 运维接口地址，如 http://127.0.0.1:8091
+}
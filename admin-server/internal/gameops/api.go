@@ -0,0 +1,128 @@
+package gameops
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+)
+
+// OnlineRole 在线角色（GET /ops/online）
+type OnlineRole struct {
+	RoleId    uint64 `json:"role_id"`
+	RoleName  string `json:"role_name"`
+	Job       uint32 `json:"job"`
+	Level     uint32 `json:"level"`
+	GmLevel   uint32 `json:"gm_level"`
+	SessionId string `json:"session_id"`
+}
+
+// OnlineResult 在线角色列表
+type OnlineResult struct {
+	Total int64         `json:"total"`
+	List  []*OnlineRole `json:"list"`
+}
+
+// MuteResult 禁言结果
+type MuteResult struct {
+	RoleId    uint64 `json:"role_id"`
+	MuteUntil int64  `json:"mute_until"`
+}
+
+// BanRequest 封禁账号请求，AccountId 为 0 时按 RoleId 查找所属账号
+type BanRequest struct {
+	AccountId uint64 `json:"account_id"`
+	RoleId    uint64 `json:"role_id"`
+	Seconds   int64  `json:"seconds"`
+	Reason    string `json:"reason"`
+}
+
+// BanResult 封禁结果
+type BanResult struct {
+	AccountId uint64   `json:"account_id"`
+	BanUntil  int64    `json:"ban_until"`
+	Kicked    []uint64 `json:"kicked"`
+}
+
+// MailItem 邮件附件
+type MailItem struct {
+	ItemId uint32 `json:"item_id"`
+	Count  int64  `json:"count"`
+}
+
+// MailRequest 系统邮件请求，RoleIds 为空表示全服
+type MailRequest struct {
+	RoleIds []uint64    `json:"role_ids"`
+	Title   string      `json:"title"`
+	Content string      `json:"content"`
+	Items   []*MailItem `json:"items"`
+}
+
+// RoleResult 角色数据，RoleData 为 PlayerRoleData 的 protojson 编码
+type RoleResult struct {
+	RoleId    uint64          `json:"role_id"`
+	AccountId uint64          `json:"account_id"`
+	RoleName  string          `json:"role_name"`
+	GmLevel   uint32          `json:"gm_level"`
+	MuteUntil int64           `json:"mute_until"`
+	Online    bool            `json:"online"`
+	RoleData  json.RawMessage `json:"role_data"`
+}
+
+// Online 在线角色列表
+func (c *Client) Online(ctx context.Context, srvId uint32) (*OnlineResult, error) {
+	var result OnlineResult
+	if err := c.Get(ctx, srvId, "/ops/online", nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Kick 踢角色下线
+func (c *Client) Kick(ctx context.Context, srvId uint32, roleId uint64, msg string) error {
+	return c.Post(ctx, srvId, "/ops/kick", map[string]interface{}{"role_id": roleId, "msg": msg}, nil)
+}
+
+// Mute 禁言，seconds 为 0 解除、负数永久
+func (c *Client) Mute(ctx context.Context, srvId uint32, roleId uint64, seconds int64) (*MuteResult, error) {
+	var result MuteResult
+	if err := c.Post(ctx, srvId, "/ops/mute", map[string]interface{}{"role_id": roleId, "seconds": seconds}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Ban 封禁 / 解封账号
+func (c *Client) Ban(ctx context.Context, srvId uint32, req *BanRequest) (*BanResult, error) {
+	var result BanResult
+	if err := c.Post(ctx, srvId, "/ops/ban", req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Mail 发送系统邮件
+func (c *Client) Mail(ctx context.Context, srvId uint32, req *MailRequest) error {
+	return c.Post(ctx, srvId, "/ops/mail", req, nil)
+}
+
+// Announce 广播公告，返回收到公告的在线角色数
+func (c *Client) Announce(ctx context.Context, srvId uint32, content string) (int64, error) {
+	var result struct {
+		Sent int64 `json:"sent"`
+	}
+	if err := c.Post(ctx, srvId, "/ops/announce", map[string]interface{}{"content": content}, &result); err != nil {
+		return 0, err
+	}
+	return result.Sent, nil
+}
+
+// Role 查看角色数据
+func (c *Client) Role(ctx context.Context, srvId uint32, roleId uint64) (*RoleResult, error) {
+	var result RoleResult
+	query := url.Values{"role_id": []string{strconv.FormatUint(roleId, 10)}}
+	if err := c.Get(ctx, srvId, "/ops/role", query, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,145 @@
+package gameops
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"postapocgame/admin-server/internal/config"
+	"postapocgame/admin-server/pkg/errs"
+
+	"github.com/google/uuid"
+)
+
+const (
+	headerTimestamp = "X-Ops-Timestamp"
+	headerNonce     = "X-Ops-Nonce"
+	headerSignature = "X-Ops-Signature"
+
+	defaultTimeout = 5 * time.Second
+
+	// gameCodePlayerNotFound 游戏服错误码 Player_NotFound
+	gameCodePlayerNotFound = 3001
+)
+
+// response 游戏服运维接口统一响应，code 为游戏服 ErrorCode
+type response struct {
+	Code int32           `json:"code"`
+	Msg  string          `json:"msg"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Client 调用各区服 GameServer 运维接口的客户端，请求按 GameServer opsapi 的约定签名
+type Client struct {
+	secret     string
+	servers    []config.GameServerConf
+	httpClient *http.Client
+}
+
+// NewClient 创建游戏运维客户端
+func NewClient(c config.GameOpsConf) *Client {
+	timeout := defaultTimeout
+	if c.Timeout > 0 {
+		timeout = time.Duration(c.Timeout) * time.Second
+	}
+	return &Client{
+		secret:     c.Secret,
+		servers:    c.Servers,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Servers 已配置的区服
+func (c *Client) Servers() []config.GameServerConf {
+	return c.servers
+}
+
+// Get 调用 GET 接口，结果解析到 out
+func (c *Client) Get(ctx context.Context, srvId uint32, path string, query url.Values, out interface{}) error {
+	requestURI := path
+	if len(query) > 0 {
+		requestURI += "?" + query.Encode()
+	}
+	return c.do(ctx, srvId, http.MethodGet, requestURI, nil, out)
+}
+
+// Post 调用 POST 接口，body 以 JSON 发送，结果解析到 out（可为 nil）
+func (c *Client) Post(ctx context.Context, srvId uint32, path string, body interface{}, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return errs.Wrap(errs.CodeInternalError, "序列化请求失败", err)
+	}
+	return c.do(ctx, srvId, http.MethodPost, path, data, out)
+}
+
+func (c *Client) do(ctx context.Context, srvId uint32, method, requestURI string, body []byte, out interface{}) error {
+	server, ok := c.findServer(srvId)
+	if !ok {
+		return errs.New(errs.CodeBadRequest, fmt.Sprintf("区服 %d 未配置", srvId))
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(server.Addr, "/")+requestURI, bytes.NewReader(body))
+	if err != nil {
+		return errs.Wrap(errs.CodeInternalError, "构建游戏服请求失败", err)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	// 每个请求使用新的 nonce，游戏服拒绝重复的 nonce，同一请求不可被重放
+	nonce := uuid.NewString()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(headerTimestamp, timestamp)
+	req.Header.Set(headerNonce, nonce)
+	req.Header.Set(headerSignature, sign(c.secret, timestamp, nonce, method, requestURI, body))
+
+	httpResp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errs.Wrap(errs.CodeInternalError, fmt.Sprintf("区服 %d 运维接口不可用", srvId), err)
+	}
+	defer httpResp.Body.Close()
+	raw, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return errs.Wrap(errs.CodeInternalError, "读取游戏服响应失败", err)
+	}
+	var resp response
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return errs.Wrap(errs.CodeInternalError, fmt.Sprintf("游戏服响应格式错误(HTTP %d)", httpResp.StatusCode), err)
+	}
+	if resp.Code != 0 {
+		code := errs.CodeBadRequest
+		if resp.Code == gameCodePlayerNotFound {
+			code = errs.CodeNotFound
+		}
+		return errs.New(code, fmt.Sprintf("游戏服返回错误[%d]: %s", resp.Code, resp.Msg))
+	}
+	if out != nil && len(resp.Data) > 0 {
+		if err := json.Unmarshal(resp.Data, out); err != nil {
+			return errs.Wrap(errs.CodeInternalError, "解析游戏服响应失败", err)
+		}
+	}
+	return nil
+}
+
+func (c *Client) findServer(srvId uint32) (config.GameServerConf, bool) {
+	for _, server := range c.servers {
+		if server.SrvId == srvId {
+			return server, true
+		}
+	}
+	return config.GameServerConf{}, false
+}
+
+// sign 与 GameServer opsapi.Sign 一致：hex(HMAC-SHA256(secret, timestamp\nnonce\nmethod\nrequestURI\nbody))
+func sign(secret, timestamp, nonce, method, requestURI string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + nonce + "\n" + method + "\n" + requestURI + "\n"))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
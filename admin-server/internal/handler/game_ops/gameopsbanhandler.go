@@ -0,0 +1,43 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package game_ops
+
+import (
+	"net/http"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+	gameops "postapocgame/admin-server/internal/logic/game_ops"
+	"postapocgame/admin-server/internal/svc"
+	"postapocgame/admin-server/internal/types"
+	"postapocgame/admin-server/pkg/audit"
+)
+
+func GameOpsBanHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.GameOpsBanReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := gameops.NewGameOpsBanLogic(r.Context(), svcCtx)
+		resp, err := l.GameOpsBan(&req)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			// 记录审计日志：封禁账号
+			audit.RecordAuditLog(svcCtx, r.Context(), r, audit.AuditTypeGameOps, audit.AuditObjectGameAccount, map[string]interface{}{
+				"action":    "ban",
+				"srvId":     req.SrvId,
+				"accountId": resp.AccountId,
+				"roleId":    req.RoleId,
+				"seconds":   req.Seconds,
+				"reason":    req.Reason,
+				"banUntil":  resp.BanUntil,
+				"kicked":    resp.Kicked,
+			})
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package game_ops
+
+import (
+	"net/http"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+	gameops "postapocgame/admin-server/internal/logic/game_ops"
+	"postapocgame/admin-server/internal/svc"
+	"postapocgame/admin-server/internal/types"
+	"postapocgame/admin-server/pkg/audit"
+)
+
+func GameOpsKickHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.GameOpsKickReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := gameops.NewGameOpsKickLogic(r.Context(), svcCtx)
+		err := l.GameOpsKick(&req)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			// 记录审计日志：踢下线
+			audit.RecordAuditLog(svcCtx, r.Context(), r, audit.AuditTypeGameOps, audit.AuditObjectGameRole, map[string]interface{}{
+				"action": "kick",
+				"srvId":  req.SrvId,
+				"roleId": req.RoleId,
+				"msg":    req.Msg,
+			})
+			httpx.Ok(w)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package game_ops
+
+import (
+	"net/http"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+	gameops "postapocgame/admin-server/internal/logic/game_ops"
+	"postapocgame/admin-server/internal/svc"
+	"postapocgame/admin-server/internal/types"
+	"postapocgame/admin-server/pkg/audit"
+)
+
+func GameOpsMailHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.GameOpsMailReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := gameops.NewGameOpsMailLogic(r.Context(), svcCtx)
+		err := l.GameOpsMail(&req)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			// 记录审计日志：系统邮件（未指定角色为全服邮件）
+			auditObject := audit.AuditObjectGameRole
+			if len(req.RoleIds) == 0 {
+				auditObject = audit.AuditObjectGameServer
+			}
+			audit.RecordAuditLog(svcCtx, r.Context(), r, audit.AuditTypeGameOps, auditObject, map[string]interface{}{
+				"action":  "mail",
+				"srvId":   req.SrvId,
+				"roleIds": req.RoleIds,
+				"title":   req.Title,
+				"content": req.Content,
+				"items":   req.Items,
+			})
+			httpx.Ok(w)
+		}
+	}
+}
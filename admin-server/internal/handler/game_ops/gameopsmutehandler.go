@@ -0,0 +1,40 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package game_ops
+
+import (
+	"net/http"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+	gameops "postapocgame/admin-server/internal/logic/game_ops"
+	"postapocgame/admin-server/internal/svc"
+	"postapocgame/admin-server/internal/types"
+	"postapocgame/admin-server/pkg/audit"
+)
+
+func GameOpsMuteHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.GameOpsMuteReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := gameops.NewGameOpsMuteLogic(r.Context(), svcCtx)
+		resp, err := l.GameOpsMute(&req)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			// 记录审计日志：禁言
+			audit.RecordAuditLog(svcCtx, r.Context(), r, audit.AuditTypeGameOps, audit.AuditObjectGameRole, map[string]interface{}{
+				"action":    "mute",
+				"srvId":     req.SrvId,
+				"roleId":    req.RoleId,
+				"seconds":   req.Seconds,
+				"muteUntil": resp.MuteUntil,
+			})
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package game_ops
+
+import (
+	"net/http"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+	gameops "postapocgame/admin-server/internal/logic/game_ops"
+	"postapocgame/admin-server/internal/svc"
+	"postapocgame/admin-server/internal/types"
+	"postapocgame/admin-server/pkg/audit"
+)
+
+func GameOpsOnlineHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.GameOpsOnlineReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := gameops.NewGameOpsOnlineLogic(r.Context(), svcCtx)
+		resp, err := l.GameOpsOnline(&req)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			// 记录审计日志：查看在线角色
+			audit.RecordAuditLog(svcCtx, r.Context(), r, audit.AuditTypeGameOps, audit.AuditObjectGameServer, map[string]interface{}{
+				"action": "online",
+				"srvId":  req.SrvId,
+			})
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
@@ -19,6 +19,7 @@ import (
 	dict_item "postapocgame/admin-server/internal/handler/dict_item"
 	dict_type "postapocgame/admin-server/internal/handler/dict_type"
 	file "postapocgame/admin-server/internal/handler/file"
+	game_ops "postapocgame/admin-server/internal/handler/game_ops"
 	login_log "postapocgame/admin-server/internal/handler/login_log"
 	menu "postapocgame/admin-server/internal/handler/menu"
 	monitor "postapocgame/admin-server/internal/handler/monitor"
@@ -439,6 +440,55 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 		rest.WithPrefix("/api/v1"),
 	)
 
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.PerformanceMiddleware, serverCtx.RateLimitMiddleware, serverCtx.AuthMiddleware, serverCtx.PermissionMiddleware, serverCtx.OperationLogMiddleware},
+			[]rest.Route{
+				{
+					Method:  http.MethodGet,
+					Path:    "/game-ops/servers",
+					Handler: game_ops.GameOpsServerListHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/game-ops/online",
+					Handler: game_ops.GameOpsOnlineHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/game-ops/kick",
+					Handler: game_ops.GameOpsKickHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/game-ops/mute",
+					Handler: game_ops.GameOpsMuteHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/game-ops/ban",
+					Handler: game_ops.GameOpsBanHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/game-ops/mail",
+					Handler: game_ops.GameOpsMailHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/game-ops/announce",
+					Handler: game_ops.GameOpsAnnounceHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/game-ops/role",
+					Handler: game_ops.GameOpsRoleHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithPrefix("/api/v1"),
+	)
+
 	server.AddRoutes(
 		rest.WithMiddlewares(
 			[]rest.Middleware{serverCtx.RateLimitMiddleware, serverCtx.AuthMiddleware, serverCtx.PermissionMiddleware, serverCtx.OperationLogMiddleware},
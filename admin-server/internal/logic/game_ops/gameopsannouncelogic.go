@@ -0,0 +1,43 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package game_ops
+
+import (
+	"context"
+	"strings"
+
+	"postapocgame/admin-server/internal/svc"
+	"postapocgame/admin-server/internal/types"
+	"postapocgame/admin-server/pkg/errs"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GameOpsAnnounceLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGameOpsAnnounceLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GameOpsAnnounceLogic {
+	return &GameOpsAnnounceLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GameOpsAnnounceLogic) GameOpsAnnounce(req *types.GameOpsAnnounceReq) (resp *types.GameOpsAnnounceResp, err error) {
+	if req == nil || req.SrvId == 0 {
+		return nil, errs.New(errs.CodeBadRequest, "区服不能为空")
+	}
+	if strings.TrimSpace(req.Content) == "" {
+		return nil, errs.New(errs.CodeBadRequest, "公告内容不能为空")
+	}
+	sent, err := l.svcCtx.GameOps.Announce(l.ctx, req.SrvId, req.Content)
+	if err != nil {
+		return nil, err
+	}
+	return &types.GameOpsAnnounceResp{Sent: sent}, nil
+}
@@ -0,0 +1,48 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package game_ops
+
+import (
+	"context"
+
+	"postapocgame/admin-server/internal/gameops"
+	"postapocgame/admin-server/internal/svc"
+	"postapocgame/admin-server/internal/types"
+	"postapocgame/admin-server/pkg/errs"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GameOpsBanLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGameOpsBanLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GameOpsBanLogic {
+	return &GameOpsBanLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GameOpsBanLogic) GameOpsBan(req *types.GameOpsBanReq) (resp *types.GameOpsBanResp, err error) {
+	if req == nil || req.SrvId == 0 {
+		return nil, errs.New(errs.CodeBadRequest, "区服不能为空")
+	}
+	if req.AccountId == 0 && req.RoleId == 0 {
+		return nil, errs.New(errs.CodeBadRequest, "账号ID和角色ID不能同时为空")
+	}
+	result, err := l.svcCtx.GameOps.Ban(l.ctx, req.SrvId, &gameops.BanRequest{
+		AccountId: req.AccountId,
+		RoleId:    req.RoleId,
+		Seconds:   req.Seconds,
+		Reason:    req.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &types.GameOpsBanResp{AccountId: result.AccountId, BanUntil: result.BanUntil, Kicked: result.Kicked}, nil
+}
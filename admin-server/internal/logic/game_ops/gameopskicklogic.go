@@ -0,0 +1,35 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package game_ops
+
+import (
+	"context"
+
+	"postapocgame/admin-server/internal/svc"
+	"postapocgame/admin-server/internal/types"
+	"postapocgame/admin-server/pkg/errs"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GameOpsKickLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGameOpsKickLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GameOpsKickLogic {
+	return &GameOpsKickLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GameOpsKickLogic) GameOpsKick(req *types.GameOpsKickReq) error {
+	if req == nil || req.SrvId == 0 || req.RoleId == 0 {
+		return errs.New(errs.CodeBadRequest, "区服和角色ID不能为空")
+	}
+	return l.svcCtx.GameOps.Kick(l.ctx, req.SrvId, req.RoleId, req.Msg)
+}
@@ -0,0 +1,52 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package game_ops
+
+import (
+	"context"
+	"strings"
+
+	"postapocgame/admin-server/internal/gameops"
+	"postapocgame/admin-server/internal/svc"
+	"postapocgame/admin-server/internal/types"
+	"postapocgame/admin-server/pkg/errs"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GameOpsMailLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGameOpsMailLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GameOpsMailLogic {
+	return &GameOpsMailLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GameOpsMailLogic) GameOpsMail(req *types.GameOpsMailReq) error {
+	if req == nil || req.SrvId == 0 {
+		return errs.New(errs.CodeBadRequest, "区服不能为空")
+	}
+	if strings.TrimSpace(req.Title) == "" {
+		return errs.New(errs.CodeBadRequest, "邮件标题不能为空")
+	}
+	items := make([]*gameops.MailItem, 0, len(req.Items))
+	for _, item := range req.Items {
+		if item.ItemId == 0 || item.Count <= 0 {
+			return errs.New(errs.CodeBadRequest, "邮件附件道具ID和数量必须大于0")
+		}
+		items = append(items, &gameops.MailItem{ItemId: item.ItemId, Count: item.Count})
+	}
+	return l.svcCtx.GameOps.Mail(l.ctx, req.SrvId, &gameops.MailRequest{
+		RoleIds: req.RoleIds,
+		Title:   req.Title,
+		Content: req.Content,
+		Items:   items,
+	})
+}
@@ -0,0 +1,39 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package game_ops
+
+import (
+	"context"
+
+	"postapocgame/admin-server/internal/svc"
+	"postapocgame/admin-server/internal/types"
+	"postapocgame/admin-server/pkg/errs"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GameOpsMuteLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGameOpsMuteLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GameOpsMuteLogic {
+	return &GameOpsMuteLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GameOpsMuteLogic) GameOpsMute(req *types.GameOpsMuteReq) (resp *types.GameOpsMuteResp, err error) {
+	if req == nil || req.SrvId == 0 || req.RoleId == 0 {
+		return nil, errs.New(errs.CodeBadRequest, "区服和角色ID不能为空")
+	}
+	result, err := l.svcCtx.GameOps.Mute(l.ctx, req.SrvId, req.RoleId, req.Seconds)
+	if err != nil {
+		return nil, err
+	}
+	return &types.GameOpsMuteResp{RoleId: result.RoleId, MuteUntil: result.MuteUntil}, nil
+}
@@ -0,0 +1,50 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package game_ops
+
+import (
+	"context"
+
+	"postapocgame/admin-server/internal/svc"
+	"postapocgame/admin-server/internal/types"
+	"postapocgame/admin-server/pkg/errs"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GameOpsOnlineLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGameOpsOnlineLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GameOpsOnlineLogic {
+	return &GameOpsOnlineLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GameOpsOnlineLogic) GameOpsOnline(req *types.GameOpsOnlineReq) (resp *types.GameOpsOnlineResp, err error) {
+	if req == nil || req.SrvId == 0 {
+		return nil, errs.New(errs.CodeBadRequest, "区服不能为空")
+	}
+	result, err := l.svcCtx.GameOps.Online(l.ctx, req.SrvId)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]types.GameOpsOnlineRole, 0, len(result.List))
+	for _, role := range result.List {
+		list = append(list, types.GameOpsOnlineRole{
+			RoleId:    role.RoleId,
+			RoleName:  role.RoleName,
+			Job:       role.Job,
+			Level:     role.Level,
+			GmLevel:   role.GmLevel,
+			SessionId: role.SessionId,
+		})
+	}
+	return &types.GameOpsOnlineResp{Total: result.Total, List: list}, nil
+}
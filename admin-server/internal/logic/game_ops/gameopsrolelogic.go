@@ -0,0 +1,47 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package game_ops
+
+import (
+	"context"
+
+	"postapocgame/admin-server/internal/svc"
+	"postapocgame/admin-server/internal/types"
+	"postapocgame/admin-server/pkg/errs"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GameOpsRoleLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGameOpsRoleLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GameOpsRoleLogic {
+	return &GameOpsRoleLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GameOpsRoleLogic) GameOpsRole(req *types.GameOpsRoleReq) (resp *types.GameOpsRoleResp, err error) {
+	if req == nil || req.SrvId == 0 || req.RoleId == 0 {
+		return nil, errs.New(errs.CodeBadRequest, "区服和角色ID不能为空")
+	}
+	result, err := l.svcCtx.GameOps.Role(l.ctx, req.SrvId, req.RoleId)
+	if err != nil {
+		return nil, err
+	}
+	return &types.GameOpsRoleResp{
+		RoleId:    result.RoleId,
+		AccountId: result.AccountId,
+		RoleName:  result.RoleName,
+		GmLevel:   result.GmLevel,
+		MuteUntil: result.MuteUntil,
+		Online:    result.Online,
+		RoleData:  string(result.RoleData),
+	}, nil
+}
@@ -0,0 +1,36 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package game_ops
+
+import (
+	"context"
+
+	"postapocgame/admin-server/internal/svc"
+	"postapocgame/admin-server/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GameOpsServerListLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGameOpsServerListLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GameOpsServerListLogic {
+	return &GameOpsServerListLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GameOpsServerListLogic) GameOpsServerList() (resp *types.GameOpsServerListResp, err error) {
+	servers := l.svcCtx.GameOps.Servers()
+	list := make([]types.GameOpsServerItem, 0, len(servers))
+	for _, server := range servers {
+		list = append(list, types.GameOpsServerItem{SrvId: server.SrvId, Name: server.Name})
+	}
+	return &types.GameOpsServerListResp{List: list}, nil
+}
@@ -2,6 +2,7 @@ package svc
 
 import (
 	"postapocgame/admin-server/internal/config"
+	"postapocgame/admin-server/internal/gameops"
 	"postapocgame/admin-server/internal/hub"
 	"postapocgame/admin-server/internal/repository"
 
@@ -12,6 +13,7 @@ type ServiceContext struct {
 	Config                 config.Config
 	Repository             *repository.Repository
 	ChatHub                *hub.ChatHub
+	GameOps                *gameops.Client
 	AuthMiddleware         rest.Middleware
 	PermissionMiddleware   rest.Middleware
 	OperationLogMiddleware rest.Middleware
@@ -33,6 +35,7 @@ func NewServiceContext(c config.Config) (*ServiceContext, error) {
 		Config:     c,
 		Repository: repo,
 		ChatHub:    chatHub,
+		GameOps:    gameops.NewClient(c.GameOps),
 		// AuthMiddleware 和 PermissionMiddleware 需要在外部初始化，避免循环依赖
 	}, nil
 }
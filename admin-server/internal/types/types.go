@@ -512,6 +512,101 @@ type FileUploadResp struct {
 	Ext          string `json:"ext"`
 }
 
+type GameOpsAnnounceReq struct {
+	SrvId   uint32 `json:"srvId"`
+	Content string `json:"content"`
+}
+
+type GameOpsAnnounceResp struct {
+	Sent int64 `json:"sent"` // 收到公告的在线角色数
+}
+
+type GameOpsBanReq struct {
+	SrvId     uint32 `json:"srvId"`
+	AccountId uint64 `json:"accountId,optional"` // 为 0 时封禁 roleId 所属账号
+	RoleId    uint64 `json:"roleId,optional"`
+	Seconds   int64  `json:"seconds"` // 封禁时长（秒），0 解封，负数永久
+	Reason    string `json:"reason,optional"`
+}
+
+type GameOpsBanResp struct {
+	AccountId uint64   `json:"accountId"`
+	BanUntil  int64    `json:"banUntil"` // 封禁截止时间(秒级时间戳)，0 未封禁，-1 永久
+	Kicked    []uint64 `json:"kicked"`   // 被踢下线的在线角色
+}
+
+type GameOpsKickReq struct {
+	SrvId  uint32 `json:"srvId"`
+	RoleId uint64 `json:"roleId"`
+	Msg    string `json:"msg,optional"` // 展示给玩家的踢下线原因
+}
+
+type GameOpsMailItem struct {
+	ItemId uint32 `json:"itemId"`
+	Count  int64  `json:"count"`
+}
+
+type GameOpsMailReq struct {
+	SrvId   uint32            `json:"srvId"`
+	RoleIds []uint64          `json:"roleIds,optional"` // 为空表示全服
+	Title   string            `json:"title"`
+	Content string            `json:"content,optional"`
+	Items   []GameOpsMailItem `json:"items,optional"`
+}
+
+type GameOpsMuteReq struct {
+	SrvId   uint32 `json:"srvId"`
+	RoleId  uint64 `json:"roleId"`
+	Seconds int64  `json:"seconds"` // 禁言时长（秒），0 解除，负数永久
+}
+
+type GameOpsMuteResp struct {
+	RoleId    uint64 `json:"roleId"`
+	MuteUntil int64  `json:"muteUntil"` // 禁言截止时间(秒级时间戳)，0 未禁言，-1 永久
+}
+
+type GameOpsOnlineReq struct {
+	SrvId uint32 `json:"srvId" form:"srvId"`
+}
+
+type GameOpsOnlineResp struct {
+	Total int64               `json:"total"`
+	List  []GameOpsOnlineRole `json:"list"`
+}
+
+type GameOpsOnlineRole struct {
+	RoleId    uint64 `json:"roleId"`
+	RoleName  string `json:"roleName"`
+	Job       uint32 `json:"job"`
+	Level     uint32 `json:"level"`
+	GmLevel   uint32 `json:"gmLevel"`
+	SessionId string `json:"sessionId"`
+}
+
+type GameOpsRoleReq struct {
+	SrvId  uint32 `json:"srvId" form:"srvId"`
+	RoleId uint64 `json:"roleId" form:"roleId"`
+}
+
+type GameOpsRoleResp struct {
+	RoleId    uint64 `json:"roleId"`
+	AccountId uint64 `json:"accountId"`
+	RoleName  string `json:"roleName"`
+	GmLevel   uint32 `json:"gmLevel"`
+	MuteUntil int64  `json:"muteUntil"`
+	Online    bool   `json:"online"`
+	RoleData  string `json:"roleData"` // PlayerRoleData 的 JSON
+}
+
+type GameOpsServerItem struct {
+	SrvId uint32 `json:"srvId"`
+	Name  string `json:"name"`
+}
+
+type GameOpsServerListResp struct {
+	List []GameOpsServerItem `json:"list"`
+}
+
 type LoginLogDetailReq struct {
 	Id uint64 `json:"id" form:"id"`
 }
@@ -22,6 +22,7 @@ const (
 	AuditTypeRoleChange       = "role_change"       // 角色变更
 	AuditTypeConfigModify     = "config_modify"     // 配置修改
 	AuditTypeDataDelete       = "data_delete"       // 数据删除
+	AuditTypeGameOps          = "game_ops"          // 游戏运维
 )
 
 // AuditObject 审计对象常量
@@ -32,6 +33,9 @@ const (
 	AuditObjectUser           = "user"            // 用户
 	AuditObjectPermission     = "permission"      // 权限
 	AuditObjectConfig         = "config"          // 配置
+	AuditObjectGameRole       = "game_role"       // 游戏角色
+	AuditObjectGameAccount    = "game_account"    // 游戏账号
+	AuditObjectGameServer     = "game_server"     // 游戏区服（全服公告、邮件等）
 )
 
 // RecordAuditLog 记录审计日志（异步）
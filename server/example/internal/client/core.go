@@ -61,6 +61,7 @@ type RoleStatus struct {
 type Core struct {
 	id          string
 	gatewayAddr string
+	encrypt     bool
 	tcpClient   network.ITCPClient
 	codec       *network.Codec
 	actorMgr    actor.IActorManager
@@ -95,10 +96,11 @@ type Core struct {
 	flow flowRegistry
 }
 
-func NewCore(playerID string, gatewayAddr string, actorMgr actor.IActorManager) *Core {
+func NewCore(playerID string, gatewayAddr string, encrypt bool, actorMgr actor.IActorManager) *Core {
 	core := &Core{
 		id:          playerID,
 		gatewayAddr: gatewayAddr,
+		encrypt:     encrypt,
 		codec:       network.DefaultCodec(),
 		actorMgr:    actorMgr,
 		observed:    make(map[uint64]*EntityView),
@@ -119,6 +121,7 @@ func (c *Core) Start(ctx context.Context) error {
 		network.WithTCPClientOptionOnConn(func(conn network.IConnection) {
 			log.Infof("[%s] connected to gateway", c.id)
 		}),
+		network.WithTCPClientOptionEncrypt(c.encrypt),
 	)
 
 	if err := c.tcpClient.Connect(ctx, c.gatewayAddr); err != nil {
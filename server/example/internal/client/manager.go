@@ -10,9 +10,13 @@ import (
 
 const DefaultGatewayAddr = "0.0.0.0:1011"
 
+// DefaultGatewayEncrypt 需与 Gateway 配置的 encrypt 保持一致，调试默认明文
+const DefaultGatewayEncrypt = false
+
 // Manager 管理调试客户端的生命周期
 type Manager struct {
 	actorMgr actor.IActorManager
+	encrypt  bool
 	clients  map[string]*Core
 	mu       sync.RWMutex
 	ctx      context.Context
@@ -24,6 +28,7 @@ func NewManager(ctx context.Context) *Manager {
 
 	mgr := &Manager{
 		clients: make(map[string]*Core),
+		encrypt: DefaultGatewayEncrypt,
 		ctx:     clientCtx,
 		cancel:  cancel,
 	}
@@ -47,12 +52,26 @@ func NewManager(ctx context.Context) *Manager {
 	return mgr
 }
 
+// SetEncrypt 设置之后创建的客户端是否与 Gateway 握手加密
+func (cm *Manager) SetEncrypt(encrypt bool) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.encrypt = encrypt
+}
+
+// Encrypt 之后创建的客户端是否加密
+func (cm *Manager) Encrypt() bool {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.encrypt
+}
+
 // CreateClient 创建新的调试客户端核心
 func (cm *Manager) CreateClient(playerID string, gatewayAddr string) *Core {
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
 
-	core := NewCore(playerID, gatewayAddr, cm.actorMgr)
+	core := NewCore(playerID, gatewayAddr, cm.encrypt, cm.actorMgr)
 	cm.clients[playerID] = core
 
 	log.Infof("创建客户端: %s", playerID)
@@ -32,6 +32,11 @@ func (p *AdventurePanel) exec(line string) error {
 		return p.connect(addr)
 	case "disconnect":
 		return p.disconnect()
+	case "encrypt":
+		if len(fields) < 2 {
+			return fmt.Errorf("用法: encrypt <on|off>")
+		}
+		return p.setEncrypt(fields[1])
 	case "register":
 		if len(fields) < 3 {
 			return fmt.Errorf("用法: register <account> <password>")
@@ -187,6 +192,7 @@ func (p *AdventurePanel) printHelp() {
 	fmt.Println("  help                     显示本帮助")
 	fmt.Println("  connect [addr]           连接 Gateway (默认 0.0.0.0:1011)")
 	fmt.Println("  disconnect               断开当前连接")
+	fmt.Println("  encrypt <on|off>         之后的连接是否与 Gateway 握手加密（需与 Gateway 配置一致）")
 	fmt.Println("  register <acc> <pwd>     注册账号")
 	fmt.Println("  login <acc> <pwd>        登录账号")
 	fmt.Println("  roles                    查看账号下的角色列表")
@@ -100,7 +100,29 @@ func (p *AdventurePanel) connect(addr string) error {
 	p.loggedIn = false
 	p.inScene = false
 	p.activeRole = 0
-	p.appendLog("🔗 已连接 Gateway %s", addr)
+	if p.manager.Encrypt() {
+		p.appendLog("🔗 已连接 Gateway %s（加密）", addr)
+	} else {
+		p.appendLog("🔗 已连接 Gateway %s", addr)
+	}
+	return nil
+}
+
+// setEncrypt 切换之后创建的连接是否加密，已建立的连接需重新 connect 才生效
+func (p *AdventurePanel) setEncrypt(mode string) error {
+	switch strings.ToLower(mode) {
+	case "on":
+		p.manager.SetEncrypt(true)
+	case "off":
+		p.manager.SetEncrypt(false)
+	default:
+		return fmt.Errorf("用法: encrypt <on|off>")
+	}
+	if p.core != nil {
+		p.appendLog("🔐 链路加密已%s，重新 connect 后生效", mode)
+	} else {
+		p.appendLog("🔐 链路加密已%s", mode)
+	}
 	return nil
 }
 
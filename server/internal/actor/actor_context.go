@@ -7,10 +7,14 @@
 package actor
 
 import (
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/internal/servertime"
+	"postapocgame/server/pkg/customerr"
 	"postapocgame/server/pkg/log"
 	"postapocgame/server/pkg/routine"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 type DropMessageCallback func(actorId string, message IActorMessage)
@@ -25,17 +29,32 @@ type actorContext struct {
 
 	dataMap map[string]interface{}
 
-	// ✅ 新增：消息丢弃计数
-	droppedCount  atomic.Int64
 	onDropMessage DropMessageCallback // 🔧 新增
+
+	// 溢出策略
+	overflowPolicy OverflowPolicy
+	blockTimeout   time.Duration
+	msgPolicies    map[uint16]OverflowPolicy // 按消息ID覆盖溢出策略
+
+	// 高优先级通道，不限长度、永不丢弃
+	priorityMsgIds map[uint16]struct{}
+	priorityMu     sync.Mutex
+	priorityQueue  []IActorMessage
+	priorityNotify chan struct{}
+
+	metrics *actorMetrics
+	timers  *actorTimers
 }
 
 func newActorContext(id string, mailboxSize int, opts ...ContextOption) *actorContext {
 	a := &actorContext{
-		id:       id,
-		mailbox:  make(chan IActorMessage, mailboxSize),
-		stopChan: make(chan struct{}),
-		dataMap:  make(map[string]interface{}),
+		id:             id,
+		mailbox:        make(chan IActorMessage, mailboxSize),
+		stopChan:       make(chan struct{}),
+		dataMap:        make(map[string]interface{}),
+		priorityNotify: make(chan struct{}, 1),
+		metrics:        newActorMetrics(),
+		timers:         newActorTimers(),
 	}
 	for _, opt := range opts {
 		opt(a)
@@ -47,26 +66,52 @@ func (a *actorContext) GetID() string {
 	return a.id
 }
 
-func (a *actorContext) ExecuteAsync(message IActorMessage) {
+// ExecuteAsync 投递消息，高优先级消息总是入队，普通消息在邮箱已满时按溢出策略处理
+func (a *actorContext) ExecuteAsync(message IActorMessage) error {
 	if !a.IsRunning() {
-		return
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Actor_Stopped), "actor %s stopped", a.id)
+	}
+	if a.isPriority(message.GetMsgId()) {
+		a.pushPriority(message)
+		return nil
 	}
 
 	select {
 	case a.mailbox <- message:
+		return nil
 	case <-a.stopChan:
-		return
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Actor_Stopped), "actor %s stopped", a.id)
 	default:
-		dropped := a.droppedCount.Add(1)
-		if dropped%100 == 1 {
-			log.Warnf("Actor %s mailbox full, dropped %d messages", a.id, dropped)
-		}
+		return a.onOverflow(message)
+	}
+}
 
-		// 🔧 通知上层
-		if a.onDropMessage != nil {
-			a.onDropMessage(a.id, message)
-		}
+// AfterFunc d 之后在 Actor goroutine 上执行一次 fn，按 servertime 计时
+func (a *actorContext) AfterFunc(d time.Duration, fn func()) TimerId {
+	return a.timers.add(servertime.Now().Add(d), 0, fn)
+}
+
+// Every 每隔 interval 在 Actor goroutine 上执行一次 fn，按 servertime 计时
+func (a *actorContext) Every(interval time.Duration, fn func()) TimerId {
+	if interval <= 0 {
+		return 0
 	}
+	return a.timers.add(servertime.Now().Add(interval), interval, fn)
+}
+
+// Cancel 取消定时器，定时器不存在或已执行完时返回 false
+func (a *actorContext) Cancel(id TimerId) bool {
+	return a.timers.cancel(id)
+}
+
+// GetMetrics 获取运行指标快照
+func (a *actorContext) GetMetrics() ActorMetrics {
+	s := a.metrics.snapshot()
+	s.ActorId = a.id
+	s.QueueDepth = len(a.mailbox)
+	s.PriorityDepth = a.priorityDepth()
+	s.Timers = a.timers.count()
+	return s
 }
 
 func (a *actorContext) GetData(key string) interface{} {
@@ -104,9 +149,10 @@ func (a *actorContext) stop() {
 	}
 	close(a.stopChan)
 	a.wg.Wait()
+	a.timers.close()
 
 	// ✅ 记录最终丢弃数
-	if dropped := a.droppedCount.Load(); dropped > 0 {
+	if dropped := a.metrics.dropped.Load(); dropped > 0 {
 		log.Warnf("Actor %s stopped with %d dropped messages", a.id, dropped)
 	}
 }
@@ -119,11 +165,16 @@ func (a *actorContext) loop() {
 		if a.handler == nil {
 			return
 		}
+		start := time.Now()
 		// 使用 routine.Run 添加 panic 恢复机制
 		routine.Run(func() {
 			a.handler.HandleMessage(msg)
 		})
+		a.metrics.onHandled(time.Since(start))
 	}
+	wake := time.NewTimer(timerMaxWait)
+	wake.Stop()
+	defer wake.Stop()
 	for {
 		if a.handler != nil {
 			// Loop 方法也添加 panic 恢复
@@ -131,12 +182,31 @@ func (a *actorContext) loop() {
 				a.handler.Loop()
 			})
 		}
+		a.timers.fire(servertime.Now())
+		// 高优先级消息先于普通邮箱处理
+		if msg, ok := a.popPriority(); ok {
+			doMsgLogic(msg)
+			continue
+		}
+		// 没有定时器时 wakeC 为 nil，只等待消息
+		var wakeC <-chan time.Time
+		if wait, ok := a.timers.nextWait(servertime.Now()); ok {
+			wake.Reset(wait)
+			wakeC = wake.C
+		}
 		select {
+		case <-a.priorityNotify:
+		case <-a.timers.notify:
+		case <-wakeC:
 		case msg := <-a.mailbox:
 			doMsgLogic(msg)
 		case <-a.stopChan:
-			// 处理剩余消息
+			// 处理剩余消息，高优先级优先
 			for {
+				if msg, ok := a.popPriority(); ok {
+					doMsgLogic(msg)
+					continue
+				}
 				select {
 				case msg := <-a.mailbox:
 					doMsgLogic(msg)
@@ -2,6 +2,7 @@ package actor
 
 import (
 	"context"
+	"time"
 )
 
 type ActorMode int
@@ -18,8 +19,8 @@ type IActorContext interface {
 	// GetID 获取Actor的唯一标识
 	GetID() string
 
-	// ExecuteAsync 在Actor上下文中异步执行
-	ExecuteAsync(message IActorMessage)
+	// ExecuteAsync 在Actor上下文中异步执行，邮箱已满时按溢出策略处理，策略为拒绝或阻塞超时时返回错误
+	ExecuteAsync(message IActorMessage) error
 
 	// GetData 获取绑定的数据
 	GetData(key string) interface{}
@@ -29,6 +30,18 @@ type IActorContext interface {
 
 	// IsRunning 是否正在运行
 	IsRunning() bool
+
+	// GetMetrics 获取运行指标快照
+	GetMetrics() ActorMetrics
+
+	// AfterFunc d 之后在 Actor goroutine 上执行一次 fn（panic 由 routine.Run 恢复）
+	AfterFunc(d time.Duration, fn func()) TimerId
+
+	// Every 每隔 interval 在 Actor goroutine 上执行一次 fn，直到 Cancel 或 Actor 停止
+	Every(interval time.Duration, fn func()) TimerId
+
+	// Cancel 取消定时器
+	Cancel(id TimerId) bool
 }
 
 type IActorManager interface {
@@ -48,6 +61,9 @@ type IActorManager interface {
 
 	// GetMode 获取运行模式
 	GetMode() ActorMode
+
+	// GetAllMetrics 获取所有Actor的运行指标快照
+	GetAllMetrics() []ActorMetrics
 }
 
 type IActorHandler interface {
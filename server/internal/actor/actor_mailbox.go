@@ -0,0 +1,173 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/6
+ * @Desc: Actor 邮箱溢出策略与高优先级通道
+**/
+
+package actor
+
+import (
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+	"time"
+)
+
+// OverflowPolicy 普通邮箱已满时的处理策略
+type OverflowPolicy int
+
+const (
+	// OverflowDropNewest 丢弃新消息，不通知发送方（默认）
+	OverflowDropNewest OverflowPolicy = iota
+	// OverflowDropOldest 丢弃邮箱中最旧的消息，为新消息腾出位置，适合移动同步等只关心最新状态的消息
+	OverflowDropOldest
+	// OverflowBlock 阻塞发送方直到邮箱有空位，超时后丢弃新消息并返回错误
+	// 不要在 Actor 自身的 goroutine 内向自己投递
+	OverflowBlock
+	// OverflowReject 丢弃新消息并向发送方返回错误
+	OverflowReject
+)
+
+const (
+	// dropOldestRetry 丢弃最旧消息后与其他发送方竞争空位的重试次数
+	dropOldestRetry = 3
+	// priorityWarnDepth 高优先级通道积压告警阈值，每积压该数量告警一次
+	priorityWarnDepth = 1000
+)
+
+// WithOverflowPolicy 设置普通邮箱溢出策略，blockTimeout 仅对 OverflowBlock 生效
+func WithOverflowPolicy(policy OverflowPolicy, blockTimeout time.Duration) ContextOption {
+	return func(actorCtx *actorContext) {
+		actorCtx.overflowPolicy = policy
+		actorCtx.blockTimeout = blockTimeout
+	}
+}
+
+// WithMsgOverflowPolicy 为指定消息ID单独设置溢出策略，覆盖 WithOverflowPolicy
+// 典型用法：整体 OverflowReject，仅移动同步消息 OverflowDropOldest
+func WithMsgOverflowPolicy(policy OverflowPolicy, msgIds ...uint16) ContextOption {
+	return func(actorCtx *actorContext) {
+		if actorCtx.msgPolicies == nil {
+			actorCtx.msgPolicies = make(map[uint16]OverflowPolicy, len(msgIds))
+		}
+		for _, msgId := range msgIds {
+			actorCtx.msgPolicies[msgId] = policy
+		}
+	}
+}
+
+// policyOf 消息适用的溢出策略
+func (a *actorContext) policyOf(msgId uint16) OverflowPolicy {
+	if policy, ok := a.msgPolicies[msgId]; ok {
+		return policy
+	}
+	return a.overflowPolicy
+}
+
+// WithPriorityMsgIds 设置走高优先级通道的消息ID（登出、存盘等系统消息）
+// 高优先级通道不限长度、永不丢弃，Actor 总是先处理完高优先级消息再处理普通邮箱
+// 通道有意不设上限（丢弃登出等消息的代价高于内存），只应登记低频的系统消息；积压超过 priorityWarnDepth 时告警
+func WithPriorityMsgIds(msgIds ...uint16) ContextOption {
+	return func(actorCtx *actorContext) {
+		if actorCtx.priorityMsgIds == nil {
+			actorCtx.priorityMsgIds = make(map[uint16]struct{}, len(msgIds))
+		}
+		for _, msgId := range msgIds {
+			actorCtx.priorityMsgIds[msgId] = struct{}{}
+		}
+	}
+}
+
+func (a *actorContext) isPriority(msgId uint16) bool {
+	_, ok := a.priorityMsgIds[msgId]
+	return ok
+}
+
+// pushPriority 写入高优先级通道并唤醒 Actor
+func (a *actorContext) pushPriority(message IActorMessage) {
+	a.priorityMu.Lock()
+	a.priorityQueue = append(a.priorityQueue, message)
+	depth := len(a.priorityQueue)
+	a.priorityMu.Unlock()
+	if depth%priorityWarnDepth == 0 {
+		log.Warnf("Actor %s priority queue depth reached %d, msgId=%d", a.id, depth, message.GetMsgId())
+	}
+	select {
+	case a.priorityNotify <- struct{}{}:
+	default:
+	}
+}
+
+// popPriority 取出一条高优先级消息
+func (a *actorContext) popPriority() (IActorMessage, bool) {
+	a.priorityMu.Lock()
+	defer a.priorityMu.Unlock()
+	if len(a.priorityQueue) == 0 {
+		return nil, false
+	}
+	msg := a.priorityQueue[0]
+	a.priorityQueue[0] = nil
+	a.priorityQueue = a.priorityQueue[1:]
+	return msg, true
+}
+
+func (a *actorContext) priorityDepth() int {
+	a.priorityMu.Lock()
+	defer a.priorityMu.Unlock()
+	return len(a.priorityQueue)
+}
+
+// onOverflow 普通邮箱已满，按新消息适用的溢出策略处理
+func (a *actorContext) onOverflow(message IActorMessage) error {
+	switch a.policyOf(message.GetMsgId()) {
+	case OverflowDropOldest:
+		for i := 0; i < dropOldestRetry; i++ {
+			select {
+			case old := <-a.mailbox:
+				if a.policyOf(old.GetMsgId()) == OverflowDropOldest {
+					a.drop(old)
+				} else {
+					// 最旧的消息不允许丢弃：移入高优先级通道，仍先于邮箱内其余消息处理
+					a.pushPriority(old)
+				}
+			default:
+			}
+			select {
+			case a.mailbox <- message:
+				return nil
+			default:
+			}
+		}
+		a.drop(message)
+		return nil
+	case OverflowBlock:
+		timer := time.NewTimer(a.blockTimeout)
+		defer timer.Stop()
+		select {
+		case a.mailbox <- message:
+			return nil
+		case <-a.stopChan:
+			return customerr.NewErrorByCode(int32(protocol.ErrorCode_Actor_Stopped), "actor %s stopped", a.id)
+		case <-timer.C:
+			a.drop(message)
+			return customerr.NewErrorByCode(int32(protocol.ErrorCode_Actor_MailboxFull), "actor %s mailbox full after %v, msgId=%d", a.id, a.blockTimeout, message.GetMsgId())
+		}
+	case OverflowReject:
+		a.drop(message)
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Actor_MailboxFull), "actor %s mailbox full, msgId=%d", a.id, message.GetMsgId())
+	default:
+		a.drop(message)
+		return nil
+	}
+}
+
+// drop 记录丢弃并通知上层
+func (a *actorContext) drop(message IActorMessage) {
+	dropped := a.metrics.onDrop(message.GetMsgId())
+	if dropped%100 == 1 {
+		log.Warnf("Actor %s mailbox full, dropped %d messages", a.id, dropped)
+	}
+	if a.onDropMessage != nil {
+		a.onDropMessage(a.id, message)
+	}
+}
@@ -0,0 +1,234 @@
+package actor
+
+import (
+	"context"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testMsgMove  uint16 = 1
+	testMsgSkill uint16 = 2
+)
+
+// newTestContext 创建未启动 goroutine 的 Actor，消息留在邮箱中便于检查
+func newTestContext(t *testing.T, size int, opts ...ContextOption) *actorContext {
+	t.Helper()
+	a := newActorContext("test", size, opts...)
+	a.running.Store(true)
+	return a
+}
+
+func msg(msgId uint16) IActorMessage {
+	return NewBaseMessage(context.Background(), msgId, nil)
+}
+
+// drain 按 Actor 的处理顺序（先高优先级，再普通邮箱）取出全部消息ID
+func drain(a *actorContext) []uint16 {
+	var ids []uint16
+	for {
+		if m, ok := a.popPriority(); ok {
+			ids = append(ids, m.GetMsgId())
+			continue
+		}
+		select {
+		case m := <-a.mailbox:
+			ids = append(ids, m.GetMsgId())
+		default:
+			return ids
+		}
+	}
+}
+
+func TestMsgOverflowPolicyOnlyDropsMove(t *testing.T) {
+	a := newTestContext(t, 2,
+		WithOverflowPolicy(OverflowReject, 0),
+		WithMsgOverflowPolicy(OverflowDropOldest, testMsgMove),
+	)
+	if err := a.ExecuteAsync(msg(testMsgSkill)); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.ExecuteAsync(msg(testMsgMove)); err != nil {
+		t.Fatal(err)
+	}
+	// 邮箱已满：技能消息被拒绝并返回错误
+	if err := a.ExecuteAsync(msg(testMsgSkill)); err == nil {
+		t.Fatal("skill message should be rejected when mailbox is full")
+	}
+	// 移动消息挤掉旧消息：最旧的技能消息不可丢弃，移入高优先级通道；再挤掉旧的移动消息
+	if err := a.ExecuteAsync(msg(testMsgMove)); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.ExecuteAsync(msg(testMsgMove)); err != nil {
+		t.Fatal(err)
+	}
+	got := drain(a)
+	if want := []uint16{testMsgSkill, testMsgMove, testMsgMove}; !equalIds(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	m := a.GetMetrics()
+	if m.DroppedByMsg[testMsgSkill] != 1 || m.DroppedByMsg[testMsgMove] != 1 {
+		t.Fatalf("unexpected drop counters %v", m.DroppedByMsg)
+	}
+}
+
+func TestOverflowPolicies(t *testing.T) {
+	cases := []struct {
+		name    string
+		policy  OverflowPolicy
+		wantErr bool
+		want    []uint16 // 邮箱中剩余的消息
+	}{
+		{"drop newest", OverflowDropNewest, false, []uint16{1, 2}},
+		{"drop oldest", OverflowDropOldest, false, []uint16{2, 3}},
+		{"reject", OverflowReject, true, []uint16{1, 2}},
+		{"block timeout", OverflowBlock, true, []uint16{1, 2}},
+	}
+	for _, c := range cases {
+		a := newTestContext(t, 2, WithOverflowPolicy(c.policy, 10*time.Millisecond))
+		for _, id := range []uint16{1, 2} {
+			if err := a.ExecuteAsync(msg(id)); err != nil {
+				t.Fatalf("%s: %v", c.name, err)
+			}
+		}
+		err := a.ExecuteAsync(msg(3))
+		if (err != nil) != c.wantErr {
+			t.Fatalf("%s: err=%v, wantErr=%v", c.name, err, c.wantErr)
+		}
+		if c.wantErr && customerr.GetErrCode(err) != int32(protocol.ErrorCode_Actor_MailboxFull) {
+			t.Fatalf("%s: unexpected error code %d", c.name, customerr.GetErrCode(err))
+		}
+		if got := drain(a); !equalIds(got, c.want) {
+			t.Fatalf("%s: mailbox %v, want %v", c.name, got, c.want)
+		}
+		if m := a.GetMetrics(); m.Dropped != 1 {
+			t.Fatalf("%s: dropped %d, want 1", c.name, m.Dropped)
+		}
+	}
+}
+
+func TestOverflowBlockWaitsForSpace(t *testing.T) {
+	a := newTestContext(t, 1, WithOverflowPolicy(OverflowBlock, time.Second))
+	if err := a.ExecuteAsync(msg(1)); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		<-a.mailbox
+	}()
+	if err := a.ExecuteAsync(msg(2)); err != nil {
+		t.Fatalf("blocked sender should succeed once space frees: %v", err)
+	}
+	if got := drain(a); !equalIds(got, []uint16{2}) {
+		t.Fatalf("mailbox %v", got)
+	}
+}
+
+func TestPriorityNotDroppedWhenMailboxFull(t *testing.T) {
+	const logout uint16 = 100
+	a := newTestContext(t, 3,
+		WithOverflowPolicy(OverflowDropOldest, 0),
+		WithPriorityMsgIds(logout),
+	)
+	for i := 0; i < 10; i++ {
+		if err := a.ExecuteAsync(msg(testMsgMove)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := a.ExecuteAsync(msg(logout)); err != nil {
+		t.Fatal(err)
+	}
+	got := drain(a)
+	if !equalIds(got, []uint16{logout, testMsgMove, testMsgMove, testMsgMove}) {
+		t.Fatalf("got %v, logout must be kept and handled first", got)
+	}
+	m := a.GetMetrics()
+	if m.DroppedByMsg[testMsgMove] != 7 || m.DroppedByMsg[logout] != 0 || m.Dropped != 7 {
+		t.Fatalf("unexpected drop counters dropped=%d byMsg=%v", m.Dropped, m.DroppedByMsg)
+	}
+}
+
+// TestLoopHandlesPriorityFirst 真实 Actor goroutine：邮箱堆满移动消息时登出仍被处理且先于积压的移动消息
+func TestLoopHandlesPriorityFirst(t *testing.T) {
+	const logout uint16 = 100
+	handler := NewBaseActorHandler("test")
+	var (
+		mu    sync.Mutex
+		order []uint16
+	)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	record := func(m IActorMessage) {
+		mu.Lock()
+		order = append(order, m.GetMsgId())
+		mu.Unlock()
+	}
+	handler.RegisterMessageHandler(testMsgSkill, func(m IActorMessage) {
+		close(started)
+		<-release
+		record(m)
+	})
+	handler.RegisterMessageHandler(testMsgMove, record)
+	handler.RegisterMessageHandler(logout, record)
+
+	a := newActorContext("loop", 4,
+		WithIActorHandler(handler),
+		WithOverflowPolicy(OverflowDropOldest, 0),
+		WithPriorityMsgIds(logout),
+	)
+	a.start()
+	t.Cleanup(a.stop)
+
+	// 第一条消息阻塞 Actor，随后邮箱被移动消息塞满并持续丢弃最旧的
+	if err := a.ExecuteAsync(msg(testMsgSkill)); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	for i := 0; i < 20; i++ {
+		if err := a.ExecuteAsync(msg(testMsgMove)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := a.ExecuteAsync(msg(logout)); err != nil {
+		t.Fatal(err)
+	}
+	close(release)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		n := len(order)
+		mu.Unlock()
+		if n == 6 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("handled %d messages, want 6", n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !equalIds(order, []uint16{testMsgSkill, logout, testMsgMove, testMsgMove, testMsgMove, testMsgMove}) {
+		t.Fatalf("handled order %v", order)
+	}
+	m := a.GetMetrics()
+	if m.Handled != 6 || m.DroppedByMsg[testMsgMove] != 16 {
+		t.Fatalf("handled=%d dropped=%v", m.Handled, m.DroppedByMsg)
+	}
+}
+
+func equalIds(got, want []uint16) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -7,14 +7,33 @@ import (
 	"postapocgame/server/pkg/customerr"
 	"postapocgame/server/pkg/log"
 	"sync"
+	"sync/atomic"
 )
 
-// NewActorManager 创建Actor管理器
-func NewActorManager(mode ActorMode, mailboxSize int, actorHandlerFactoryFunc func() IActorHandler) IActorManager {
+// ManagerProvider 创建Actor管理器的函数
+type ManagerProvider func(mode ActorMode, mailboxSize int, actorHandlerFactoryFunc func() IActorHandler, opts ...ContextOption) IActorManager
+
+var managerProvider atomic.Pointer[ManagerProvider]
+
+// SetManagerProvider 替换 NewActorManager 的实现（测试用途，如同步驱动的 actortest），传 nil 恢复默认
+func SetManagerProvider(provider ManagerProvider) {
+	if provider == nil {
+		managerProvider.Store(nil)
+		return
+	}
+	managerProvider.Store(&provider)
+}
+
+// NewActorManager 创建Actor管理器，opts 应用到管理器创建的每个Actor（溢出策略、高优先级消息等）
+func NewActorManager(mode ActorMode, mailboxSize int, actorHandlerFactoryFunc func() IActorHandler, opts ...ContextOption) IActorManager {
+	if provider := managerProvider.Load(); provider != nil {
+		return (*provider)(mode, mailboxSize, actorHandlerFactoryFunc, opts...)
+	}
 	return &actorManager{
 		mode:                    mode,
 		mailboxSize:             mailboxSize,
 		actorHandlerFactoryFunc: actorHandlerFactoryFunc,
+		opts:                    opts,
 	}
 }
 
@@ -23,6 +42,7 @@ type actorManager struct {
 	actors                  sync.Map
 	mailboxSize             int
 	actorHandlerFactoryFunc func() IActorHandler
+	opts                    []ContextOption
 	ctx                     context.Context
 	cancel                  context.CancelFunc
 }
@@ -44,11 +64,11 @@ func (m *actorManager) Start(ctx context.Context) error {
 	if m.mode == ModeSingle {
 		actorHandler := m.actorHandlerFactoryFunc()
 		key := "global"
-		actor := newActorContext(key, m.mailboxSize, WithIActorHandler(actorHandler))
-		actor.start()
-		actorHandler.SetActorContext(actor)
+		actor := m.newActor(key, actorHandler)
 		actor.SetData("key", key)
+		actorHandler.SetActorContext(actor)
 		m.actors.Store(key, actor)
+		actor.start()
 	}
 
 	return nil
@@ -80,14 +100,23 @@ func (m *actorManager) GetOrCreateActor(key string) (IActorContext, error) {
 		return actor, nil
 	}
 	actorHandler := m.actorHandlerFactoryFunc()
-	actor = newActorContext(key, m.mailboxSize, WithIActorHandler(actorHandler))
-	actor.start()
-	m.actors.Store(key, actor)
+	actor = m.newActor(key, actorHandler)
+	// 启动前完成上下文绑定，Handler 在首条消息前即可拿到自身 Actor
 	actor.SetData("key", key)
 	actorHandler.SetActorContext(actor)
+	// 并发创建同一 key 时只保留先写入的 Actor，未启动的直接丢弃
+	if value, loaded := m.actors.LoadOrStore(key, actor); loaded {
+		return value.(*actorContext), nil
+	}
+	actor.start()
 	return actor, nil
 }
 
+func (m *actorManager) newActor(key string, actorHandler IActorHandler) *actorContext {
+	opts := append([]ContextOption{WithIActorHandler(actorHandler)}, m.opts...)
+	return newActorContext(key, m.mailboxSize, opts...)
+}
+
 func (m *actorManager) getActor(key string) (*actorContext, bool) {
 	if m.mode == ModeSingle {
 		key = "global"
@@ -127,6 +156,17 @@ func (m *actorManager) SendMessageAsync(key string, message IActorMessage) error
 	if err != nil {
 		return customerr.Wrap(err)
 	}
-	actor.ExecuteAsync(message)
+	if err := actor.ExecuteAsync(message); err != nil {
+		return customerr.Wrap(err)
+	}
 	return nil
 }
+
+func (m *actorManager) GetAllMetrics() []ActorMetrics {
+	var all []ActorMetrics
+	m.actors.Range(func(_, value any) bool {
+		all = append(all, value.(*actorContext).GetMetrics())
+		return true
+	})
+	return all
+}
@@ -0,0 +1,99 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/6
+ * @Desc: Actor 运行指标
+**/
+
+package actor
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// latencyBounds 处理耗时直方图的桶上界，最后一个桶收纳超过 1s 的消息
+var latencyBounds = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+}
+
+// LatencyBucket 处理耗时直方图的一个桶，UpperBound 为 0 表示无上界
+type LatencyBucket struct {
+	UpperBound time.Duration
+	Count      int64
+}
+
+// ActorMetrics Actor 运行指标快照
+type ActorMetrics struct {
+	ActorId       string
+	QueueDepth    int              // 普通邮箱当前积压
+	PriorityDepth int              // 高优先级通道当前积压
+	Timers        int              // 待触发的定时器数
+	Handled       int64            // 已处理消息数
+	Dropped       int64            // 已丢弃消息数
+	Latency       []LatencyBucket  // 消息处理耗时直方图（各桶独立计数）
+	DroppedByMsg  map[uint16]int64 // msgId -> 丢弃数
+}
+
+// actorMetrics Actor 内部计数，发送方与 Actor goroutine 并发更新
+type actorMetrics struct {
+	handled atomic.Int64
+	dropped atomic.Int64
+	latency [8]atomic.Int64 // len(latencyBounds) + 1
+
+	mu           sync.Mutex
+	droppedByMsg map[uint16]int64
+}
+
+func newActorMetrics() *actorMetrics {
+	return &actorMetrics{droppedByMsg: make(map[uint16]int64)}
+}
+
+// onHandled 记录一条消息的处理耗时
+func (m *actorMetrics) onHandled(cost time.Duration) {
+	m.handled.Add(1)
+	idx := len(latencyBounds)
+	for i, bound := range latencyBounds {
+		if cost <= bound {
+			idx = i
+			break
+		}
+	}
+	m.latency[idx].Add(1)
+}
+
+// onDrop 记录一条丢弃的消息，返回累计丢弃数
+func (m *actorMetrics) onDrop(msgId uint16) int64 {
+	m.mu.Lock()
+	m.droppedByMsg[msgId]++
+	m.mu.Unlock()
+	return m.dropped.Add(1)
+}
+
+func (m *actorMetrics) snapshot() ActorMetrics {
+	s := ActorMetrics{
+		Handled: m.handled.Load(),
+		Dropped: m.dropped.Load(),
+		Latency: make([]LatencyBucket, 0, len(m.latency)),
+	}
+	for i := range m.latency {
+		var bound time.Duration
+		if i < len(latencyBounds) {
+			bound = latencyBounds[i]
+		}
+		s.Latency = append(s.Latency, LatencyBucket{UpperBound: bound, Count: m.latency[i].Load()})
+	}
+	m.mu.Lock()
+	s.DroppedByMsg = make(map[uint16]int64, len(m.droppedByMsg))
+	for msgId, count := range m.droppedByMsg {
+		s.DroppedByMsg[msgId] = count
+	}
+	m.mu.Unlock()
+	return s
+}
@@ -0,0 +1,183 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/7
+ * @Desc: Actor 内定时器
+**/
+
+package actor
+
+import (
+	"container/heap"
+	"postapocgame/server/pkg/routine"
+	"sync"
+	"time"
+)
+
+// TimerId 定时器ID，0 表示注册失败
+type TimerId uint64
+
+// timerMaxWait 有定时器时 Actor 的最长休眠时间，servertime 被调快时最迟在该间隔后补触发
+const timerMaxWait = 100 * time.Millisecond
+
+type actorTimer struct {
+	id        TimerId
+	deadline  time.Time
+	interval  time.Duration // 大于 0 为周期定时器
+	fn        func()
+	index     int
+	cancelled bool
+}
+
+// timerHeap 按到期时间排序的最小堆
+type timerHeap []*actorTimer
+
+func (h timerHeap) Len() int           { return len(h) }
+func (h timerHeap) Less(i, j int) bool { return h[i].deadline.Before(h[j].deadline) }
+func (h timerHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *timerHeap) Push(x any) {
+	t := x.(*actorTimer)
+	t.index = len(*h)
+	*h = append(*h, t)
+}
+
+func (h *timerHeap) Pop() any {
+	old := *h
+	n := len(old)
+	t := old[n-1]
+	old[n-1] = nil
+	t.index = -1
+	*h = old[:n-1]
+	return t
+}
+
+// actorTimers Actor 的定时器集合
+// 可在任意 goroutine 注册与取消，回调只在 Actor goroutine 上执行
+type actorTimers struct {
+	mu     sync.Mutex
+	nextId TimerId
+	heap   timerHeap
+	byId   map[TimerId]*actorTimer
+	closed bool
+	notify chan struct{}
+}
+
+func newActorTimers() *actorTimers {
+	return &actorTimers{
+		byId:   make(map[TimerId]*actorTimer),
+		notify: make(chan struct{}, 1),
+	}
+}
+
+func (ts *actorTimers) add(deadline time.Time, interval time.Duration, fn func()) TimerId {
+	if fn == nil {
+		return 0
+	}
+	ts.mu.Lock()
+	if ts.closed {
+		ts.mu.Unlock()
+		return 0
+	}
+	ts.nextId++
+	t := &actorTimer{id: ts.nextId, deadline: deadline, interval: interval, fn: fn}
+	heap.Push(&ts.heap, t)
+	ts.byId[t.id] = t
+	first := t.index == 0
+	ts.mu.Unlock()
+
+	// 新定时器最先到期时唤醒 Actor 重新计算休眠时间
+	if first {
+		select {
+		case ts.notify <- struct{}{}:
+		default:
+		}
+	}
+	return t.id
+}
+
+func (ts *actorTimers) cancel(id TimerId) bool {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	t, ok := ts.byId[id]
+	if !ok {
+		return false
+	}
+	t.cancelled = true
+	delete(ts.byId, id)
+	if t.index >= 0 {
+		heap.Remove(&ts.heap, t.index)
+	}
+	return true
+}
+
+// fire 执行所有已到期的定时器
+// 先取出到期定时器再逐个执行，回调内新注册的定时器留到下一轮，回调内取消的定时器不再执行
+func (ts *actorTimers) fire(now time.Time) {
+	ts.mu.Lock()
+	var due []*actorTimer
+	for len(ts.heap) > 0 && !ts.heap[0].deadline.After(now) {
+		t := ts.heap[0]
+		due = append(due, t)
+		if t.interval > 0 {
+			// 落后过多时不补触发，从当前时间重新计算
+			t.deadline = t.deadline.Add(t.interval)
+			if !t.deadline.After(now) {
+				t.deadline = now.Add(t.interval)
+			}
+			heap.Fix(&ts.heap, 0)
+		} else {
+			heap.Pop(&ts.heap)
+			delete(ts.byId, t.id)
+		}
+	}
+	ts.mu.Unlock()
+
+	for _, t := range due {
+		ts.mu.Lock()
+		cancelled := t.cancelled
+		ts.mu.Unlock()
+		if cancelled {
+			continue
+		}
+		routine.Run(t.fn)
+	}
+}
+
+// nextWait 距最近一个定时器到期的休眠时间，没有定时器时返回 false
+func (ts *actorTimers) nextWait(now time.Time) (time.Duration, bool) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if len(ts.heap) == 0 {
+		return 0, false
+	}
+	wait := ts.heap[0].deadline.Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	if wait > timerMaxWait {
+		wait = timerMaxWait
+	}
+	return wait, true
+}
+
+func (ts *actorTimers) count() int {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return len(ts.heap)
+}
+
+// close 清空定时器，之后的注册直接失败
+func (ts *actorTimers) close() {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.closed = true
+	for _, t := range ts.heap {
+		t.cancelled = true
+	}
+	ts.heap = nil
+	ts.byId = make(map[TimerId]*actorTimer)
+}
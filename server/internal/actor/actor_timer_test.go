@@ -0,0 +1,114 @@
+package actor
+
+import (
+	"postapocgame/server/internal/servertime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// startTimerActor 启动真实 Actor goroutine，处理 testMsgMove 时向 handled 发送信号
+func startTimerActor(t *testing.T) (*actorContext, chan struct{}) {
+	t.Helper()
+	handled := make(chan struct{}, 1)
+	handler := NewBaseActorHandler("timer")
+	handler.RegisterMessageHandler(testMsgMove, func(IActorMessage) {
+		handled <- struct{}{}
+	})
+	a := newActorContext("timer", 16, WithIActorHandler(handler))
+	a.start()
+	t.Cleanup(a.stop)
+	return a, handled
+}
+
+func waitSignal(t *testing.T, ch <-chan struct{}, within time.Duration, what string) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(within):
+		t.Fatalf("%s not fired within %v", what, within)
+	}
+}
+
+func TestAfterFuncFires(t *testing.T) {
+	a, _ := startTimerActor(t)
+	fired := make(chan struct{})
+	if id := a.AfterFunc(20*time.Millisecond, func() { close(fired) }); id == 0 {
+		t.Fatal("AfterFunc returned 0")
+	}
+	waitSignal(t, fired, time.Second, "AfterFunc")
+	if n := a.GetMetrics().Timers; n != 0 {
+		t.Fatalf("one-shot timer should be removed, %d left", n)
+	}
+}
+
+func TestEveryRepeatsUntilCancel(t *testing.T) {
+	a, _ := startTimerActor(t)
+	var count atomic.Int32
+	ticked := make(chan struct{}, 16)
+	id := a.Every(10*time.Millisecond, func() {
+		count.Add(1)
+		ticked <- struct{}{}
+	})
+	for i := 0; i < 3; i++ {
+		waitSignal(t, ticked, time.Second, "Every")
+	}
+	if !a.Cancel(id) {
+		t.Fatal("Cancel should succeed on a running timer")
+	}
+	if a.Cancel(id) {
+		t.Fatal("Cancel twice should return false")
+	}
+	after := count.Load()
+	time.Sleep(50 * time.Millisecond)
+	// 取消前已取出的一次回调可能仍在执行
+	if n := count.Load(); n > after+1 {
+		t.Fatalf("timer kept firing after cancel: %d -> %d", after, n)
+	}
+}
+
+func TestCancelBeforeFire(t *testing.T) {
+	a, _ := startTimerActor(t)
+	var fired atomic.Bool
+	id := a.AfterFunc(20*time.Millisecond, func() { fired.Store(true) })
+	if !a.Cancel(id) {
+		t.Fatal("Cancel should succeed")
+	}
+	time.Sleep(60 * time.Millisecond)
+	if fired.Load() {
+		t.Fatal("cancelled timer fired")
+	}
+}
+
+func TestTimerPanicRecovered(t *testing.T) {
+	a, handled := startTimerActor(t)
+	a.AfterFunc(time.Millisecond, func() { panic("timer panic") })
+	fired := make(chan struct{})
+	a.AfterFunc(10*time.Millisecond, func() { close(fired) })
+	waitSignal(t, fired, time.Second, "timer after panic")
+
+	// Actor 仍在处理消息
+	if err := a.ExecuteAsync(msg(testMsgMove)); err != nil {
+		t.Fatal(err)
+	}
+	waitSignal(t, handled, time.Second, "message after panic")
+}
+
+func TestTimerFiresOnServerTimeFastForward(t *testing.T) {
+	t.Cleanup(servertime.ResetOffset)
+	a, _ := startTimerActor(t)
+	fired := make(chan struct{})
+	a.AfterFunc(time.Hour, func() { close(fired) })
+	servertime.AddOffset(2 * time.Hour)
+	// Actor 休眠最多 timerMaxWait，之后按新的 servertime 补触发
+	waitSignal(t, fired, 5*timerMaxWait, "fast-forwarded timer")
+}
+
+func TestTimerRegisterAfterStop(t *testing.T) {
+	a := newActorContext("timer", 1, WithIActorHandler(NewBaseActorHandler("timer")))
+	a.start()
+	a.stop()
+	if id := a.AfterFunc(time.Millisecond, func() {}); id != 0 {
+		t.Fatalf("AfterFunc after stop should fail, got %d", id)
+	}
+}
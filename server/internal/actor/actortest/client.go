@@ -0,0 +1,178 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/8
+ * @Desc: 捕获发往客户端的 S2C 消息
+**/
+
+package actortest
+
+import (
+	"context"
+	"net"
+	"postapocgame/server/internal/network"
+	"postapocgame/server/internal/protocol"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+var _ network.IConnection = (*ClientCapture)(nil)
+
+// S2CMessage 捕获到的一条客户端消息
+type S2CMessage struct {
+	SessionId string
+	MsgId     uint16
+	Data      []byte
+	Msg       proto.Message // 按 msgId 解码后的消息体，类型未知时为 nil
+}
+
+// s2cTypeOverrides 消息体命名不符合 "<协议名>Req" 约定的 S2C 协议
+var s2cTypeOverrides = map[protocol.S2CProtocol]proto.Message{
+	protocol.S2CProtocol_S2CError:      &protocol.ErrorData{},
+	protocol.S2CProtocol_S2CUpdateMove: &protocol.S2CEntityUpdateMoveReq{},
+	protocol.S2CProtocol_S2CStopMove:   &protocol.S2CEndMoveReq{},
+}
+
+// ClientCapture 实现 network.IConnection，替代 GameServer 到 Gateway 的连接
+// 设置为 gatewaylink 的发送连接后，所有发往客户端的消息都会被记录并解码
+type ClientCapture struct {
+	codec *network.Codec
+
+	mu    sync.Mutex
+	msgs  []*S2CMessage
+	types map[uint16]protoreflect.MessageType
+	meta  interface{}
+}
+
+// NewClientCapture 创建客户端消息捕获器
+func NewClientCapture() *ClientCapture {
+	return &ClientCapture{
+		codec: network.DefaultCodec(),
+		types: make(map[uint16]protoreflect.MessageType),
+	}
+}
+
+// RegisterType 指定 msgId 对应的消息体类型，覆盖默认的按名称解析
+func (c *ClientCapture) RegisterType(msgId uint16, msg proto.Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.types[msgId] = msg.ProtoReflect().Type()
+}
+
+// SendMessage 解析转发给客户端的消息，其余类型（RPC 等）忽略
+func (c *ClientCapture) SendMessage(msg *network.Message) error {
+	if msg == nil || msg.Type != network.MsgTypeClient {
+		return nil
+	}
+	fwd, err := c.codec.DecodeForwardMessage(msg.Payload)
+	if err != nil {
+		return err
+	}
+	defer network.PutForwardMessage(fwd)
+	clientMsg, err := c.codec.DecodeClientMessage(fwd.Payload)
+	if err != nil {
+		return err
+	}
+	// 发送方会回收缓冲区，必须拷贝
+	s2c := &S2CMessage{
+		SessionId: fwd.SessionId,
+		MsgId:     clientMsg.MsgId,
+		Data:      append([]byte(nil), clientMsg.Data...),
+	}
+	if mt := c.resolveType(s2c.MsgId); mt != nil {
+		body := mt.New().Interface()
+		if err := proto.Unmarshal(s2c.Data, body); err == nil {
+			s2c.Msg = body
+		}
+	}
+	c.mu.Lock()
+	c.msgs = append(c.msgs, s2c)
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *ClientCapture) resolveType(msgId uint16) protoreflect.MessageType {
+	c.mu.Lock()
+	mt, ok := c.types[msgId]
+	c.mu.Unlock()
+	if ok {
+		return mt
+	}
+	if msg, ok := s2cTypeOverrides[protocol.S2CProtocol(msgId)]; ok {
+		return msg.ProtoReflect().Type()
+	}
+	name, ok := protocol.S2CProtocol_name[int32(msgId)]
+	if !ok {
+		return nil
+	}
+	pkg := (&protocol.ErrorData{}).ProtoReflect().Descriptor().ParentFile().Package()
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(pkg.Append(protoreflect.Name(name + "Req")))
+	if err != nil {
+		return nil
+	}
+	return mt
+}
+
+// Messages 按发送顺序返回捕获到的所有消息
+func (c *ClientCapture) Messages() []*S2CMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*S2CMessage(nil), c.msgs...)
+}
+
+// Find 返回发给会话的指定协议消息，sessionId 为空时不限会话
+func (c *ClientCapture) Find(sessionId string, msgId protocol.S2CProtocol) []*S2CMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var found []*S2CMessage
+	for _, m := range c.msgs {
+		if m.MsgId == uint16(msgId) && (sessionId == "" || m.SessionId == sessionId) {
+			found = append(found, m)
+		}
+	}
+	return found
+}
+
+// Last 返回发给会话的最后一条指定协议消息
+func (c *ClientCapture) Last(sessionId string, msgId protocol.S2CProtocol) (*S2CMessage, bool) {
+	found := c.Find(sessionId, msgId)
+	if len(found) == 0 {
+		return nil, false
+	}
+	return found[len(found)-1], true
+}
+
+// Reset 清空已捕获的消息
+func (c *ClientCapture) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.msgs = nil
+}
+
+// ReceiveMessage 捕获器只记录发送，接收一直阻塞到 ctx 结束
+func (c *ClientCapture) ReceiveMessage(ctx context.Context) (*network.Message, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (c *ClientCapture) Close() error {
+	return nil
+}
+
+func (c *ClientCapture) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)}
+}
+
+func (c *ClientCapture) GetMeta() interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.meta
+}
+
+func (c *ClientCapture) SetMeta(meta interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.meta = meta
+}
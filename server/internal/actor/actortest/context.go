@@ -0,0 +1,125 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/8
+ * @Desc: 同步驱动的 Actor 上下文
+**/
+
+package actortest
+
+import (
+	"postapocgame/server/internal/actor"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"sync/atomic"
+	"time"
+)
+
+var _ actor.IActorContext = (*Context)(nil)
+
+// Context 同步驱动的 Actor 上下文，消息与定时器回调都在调用 Harness.Run/Advance 的 goroutine 上执行
+type Context struct {
+	harness *Harness
+	id      string
+	handler actor.IActorHandler
+	running atomic.Bool
+	handled atomic.Int64
+	dataMap map[string]interface{}
+
+	nextTimerId actor.TimerId // 由 harness.mu 保护
+}
+
+func (c *Context) GetID() string {
+	return c.id
+}
+
+// ExecuteAsync 投递到 Harness 的消息队列，Run 时处理
+func (c *Context) ExecuteAsync(message actor.IActorMessage) error {
+	if !c.IsRunning() {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Actor_Stopped), "actor %s stopped", c.id)
+	}
+	c.harness.enqueue(c, message)
+	return nil
+}
+
+func (c *Context) GetData(key string) interface{} {
+	return c.dataMap[key]
+}
+
+func (c *Context) SetData(key string, data interface{}) {
+	c.dataMap[key] = data
+}
+
+func (c *Context) IsRunning() bool {
+	return c.running.Load()
+}
+
+func (c *Context) GetMetrics() actor.ActorMetrics {
+	return actor.ActorMetrics{
+		ActorId:    c.id,
+		QueueDepth: c.harness.pendingOf(c),
+		Handled:    c.handled.Load(),
+		Timers:     c.harness.timerCount(c),
+	}
+}
+
+// AfterFunc 虚拟时间推进 d 之后执行一次 fn
+func (c *Context) AfterFunc(d time.Duration, fn func()) actor.TimerId {
+	return c.harness.addTimer(c, d, 0, fn)
+}
+
+// Every 虚拟时间每推进 interval 执行一次 fn
+func (c *Context) Every(interval time.Duration, fn func()) actor.TimerId {
+	if interval <= 0 {
+		return 0
+	}
+	return c.harness.addTimer(c, interval, interval, fn)
+}
+
+func (c *Context) Cancel(id actor.TimerId) bool {
+	return c.harness.cancelTimer(c, id)
+}
+
+// Handler 获取 Actor 的消息处理器，便于测试断言处理器内部状态
+func (c *Context) Handler() actor.IActorHandler {
+	return c.handler
+}
+
+// handle 与真实 Actor 一致，处理每条消息前先执行一次 Loop
+// 已停止的 Actor 仍处理队列中剩余的消息，对应真实 Actor 停止时清空邮箱
+func (c *Context) handle(msg actor.IActorMessage) {
+	if c.handler == nil || msg == nil {
+		return
+	}
+	c.call(c.handler.Loop)
+	c.call(func() {
+		c.handler.HandleMessage(msg)
+	})
+	c.handled.Add(1)
+}
+
+// call 执行处理器代码，panic 记为测试失败而不是像真实 Actor 那样只打日志
+func (c *Context) call(fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.harness.tb.Errorf("actortest: actor %s panic: %v", c.id, r)
+		}
+	}()
+	fn()
+}
+
+func (c *Context) start() {
+	c.running.Store(true)
+	if c.handler != nil {
+		c.handler.OnStart()
+	}
+}
+
+func (c *Context) stop() {
+	if !c.running.CompareAndSwap(true, false) {
+		return
+	}
+	if c.handler != nil {
+		c.handler.OnStop()
+	}
+	c.harness.clearTimers(c)
+}
@@ -0,0 +1,190 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/8
+ * @Desc: 确定性 Actor 测试驱动：同步执行消息、虚拟时间推进定时器
+**/
+
+package actortest
+
+import (
+	"container/heap"
+	"postapocgame/server/internal/actor"
+	"postapocgame/server/internal/servertime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// DefaultStartTime 虚拟时间的默认起点
+var DefaultStartTime = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// maxStepsPerRun 单次 Run 最多处理的消息数，超出视为消息风暴
+const maxStepsPerRun = 100000
+
+// Harness 确定性 Actor 测试驱动
+// 安装后 actor.NewActorManager 创建的管理器都由 Harness 在测试 goroutine 上同步驱动：
+// 所有 Actor 的消息进入同一个 FIFO 队列，Run 依次处理；定时器只在 Advance 推进虚拟时间时按到期顺序触发；
+// servertime.Now 返回虚拟时间
+// 邮箱容量、溢出策略与高优先级通道不生效，消息不会被丢弃
+type Harness struct {
+	tb testing.TB
+
+	mu       sync.Mutex
+	now      time.Time
+	queue    []envelope
+	timers   timerQueue
+	timerSeq uint64
+	byId     map[timerKey]*virtualTimer
+	managers []*Manager
+
+	client *ClientCapture
+}
+
+type envelope struct {
+	ctx *Context
+	msg actor.IActorMessage
+}
+
+// New 创建并安装测试驱动，测试结束时自动恢复时间源与 ActorManager 实现
+func New(tb testing.TB) *Harness {
+	tb.Helper()
+	h := &Harness{
+		tb:     tb,
+		now:    DefaultStartTime,
+		byId:   make(map[timerKey]*virtualTimer),
+		client: NewClientCapture(),
+	}
+	servertime.SetTimeProvider(h.Now)
+	servertime.ResetOffset()
+	actor.SetManagerProvider(h.newManager)
+	tb.Cleanup(h.Close)
+	return h
+}
+
+// Close 恢复默认时间源与 ActorManager 实现
+func (h *Harness) Close() {
+	actor.SetManagerProvider(nil)
+	servertime.SetTimeProvider(nil)
+}
+
+// Now 当前虚拟时间
+func (h *Harness) Now() time.Time {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.now
+}
+
+// Client 捕获的客户端消息
+func (h *Harness) Client() *ClientCapture {
+	return h.client
+}
+
+// Managers 已创建的 ActorManager，按创建顺序排列
+func (h *Harness) Managers() []*Manager {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]*Manager(nil), h.managers...)
+}
+
+// Pending 队列中待处理的消息数
+func (h *Harness) Pending() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.queue)
+}
+
+// Run 依次处理队列中的消息，处理过程中新投递的消息排到队尾，直到队列为空，返回处理的消息数
+func (h *Harness) Run() int {
+	h.tb.Helper()
+	steps := 0
+	for {
+		h.mu.Lock()
+		if len(h.queue) == 0 {
+			h.mu.Unlock()
+			return steps
+		}
+		env := h.queue[0]
+		h.queue[0] = envelope{}
+		h.queue = h.queue[1:]
+		h.mu.Unlock()
+
+		env.ctx.handle(env.msg)
+		steps++
+		if steps >= maxStepsPerRun {
+			h.tb.Fatalf("actortest: more than %d messages in one Run, message storm?", maxStepsPerRun)
+			return steps
+		}
+	}
+}
+
+// Advance 推进虚拟时间 d，按到期顺序触发定时器，每个定时器回调后处理完队列中的消息
+func (h *Harness) Advance(d time.Duration) {
+	h.tb.Helper()
+	h.Run()
+	target := h.Now().Add(d)
+	for {
+		h.mu.Lock()
+		if len(h.timers) == 0 || h.timers[0].deadline.After(target) {
+			h.now = target
+			h.mu.Unlock()
+			break
+		}
+		t := h.timers[0]
+		if t.deadline.After(h.now) {
+			h.now = t.deadline
+		}
+		if t.interval > 0 {
+			t.deadline = t.deadline.Add(t.interval)
+			t.seq = h.nextTimerSeq()
+			heap.Fix(&h.timers, 0)
+		} else {
+			heap.Pop(&h.timers)
+			delete(h.byId, t.key)
+		}
+		h.mu.Unlock()
+
+		t.ctx.call(t.fn)
+		h.Run()
+	}
+	h.Run()
+}
+
+// AdvanceTo 推进虚拟时间到 t，t 早于当前时间时不做任何事
+func (h *Harness) AdvanceTo(t time.Time) {
+	h.tb.Helper()
+	if d := t.Sub(h.Now()); d > 0 {
+		h.Advance(d)
+	}
+}
+
+func (h *Harness) newManager(mode actor.ActorMode, _ int, factory func() actor.IActorHandler, _ ...actor.ContextOption) actor.IActorManager {
+	m := &Manager{
+		harness: h,
+		mode:    mode,
+		factory: factory,
+		actors:  make(map[string]*Context),
+	}
+	h.mu.Lock()
+	h.managers = append(h.managers, m)
+	h.mu.Unlock()
+	return m
+}
+
+func (h *Harness) enqueue(ctx *Context, msg actor.IActorMessage) {
+	h.mu.Lock()
+	h.queue = append(h.queue, envelope{ctx: ctx, msg: msg})
+	h.mu.Unlock()
+}
+
+// pendingOf 某个 Actor 在队列中待处理的消息数
+func (h *Harness) pendingOf(ctx *Context) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	n := 0
+	for _, env := range h.queue {
+		if env.ctx == ctx {
+			n++
+		}
+	}
+	return n
+}
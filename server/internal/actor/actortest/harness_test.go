@@ -0,0 +1,147 @@
+package actortest
+
+import (
+	"context"
+	"postapocgame/server/internal/actor"
+	"postapocgame/server/internal/network"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/internal/servertime"
+	"testing"
+	"time"
+)
+
+type recordHandler struct {
+	*actor.BaseActorHandler
+	actorCtx actor.IActorContext
+}
+
+func (h *recordHandler) SetActorContext(ctx actor.IActorContext) {
+	h.actorCtx = ctx
+}
+
+func newRecordManager(log *[]string) actor.IActorManager {
+	base := actor.NewBaseActorHandler("record")
+	base.RegisterMessageHandler(1, func(msg actor.IActorMessage) {
+		*log = append(*log, string(msg.GetData()))
+	})
+	return actor.NewActorManager(actor.ModePerKey, 8, func() actor.IActorHandler {
+		return &recordHandler{BaseActorHandler: base}
+	})
+}
+
+func TestRunProcessesMessagesInSendOrder(t *testing.T) {
+	h := New(t)
+	var got []string
+	mgr := newRecordManager(&got)
+	if _, ok := mgr.(*Manager); !ok {
+		t.Fatalf("NewActorManager returned %T, want *actortest.Manager", mgr)
+	}
+	for _, s := range []string{"a1", "b1", "a2", "c1", "b2"} {
+		key := s[:1]
+		if err := mgr.SendMessageAsync(key, actor.NewBaseMessage(context.Background(), 1, []byte(s))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(got) != 0 {
+		t.Fatalf("messages handled before Run: %v", got)
+	}
+	if n := h.Run(); n != 5 {
+		t.Fatalf("Run handled %d messages, want 5", n)
+	}
+	want := []string{"a1", "b1", "a2", "c1", "b2"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("order = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestAdvanceFiresTimersInDeadlineOrder(t *testing.T) {
+	h := New(t)
+	var got []string
+	mgr := newRecordManager(&got)
+	a, _ := mgr.GetOrCreateActor("a")
+	b, _ := mgr.GetOrCreateActor("b")
+
+	start := servertime.Now()
+	if !start.Equal(DefaultStartTime) {
+		t.Fatalf("servertime.Now() = %v, want virtual start %v", start, DefaultStartTime)
+	}
+	every := a.Every(time.Second, func() {
+		got = append(got, "a-every@"+servertime.Since(start).String())
+	})
+	b.AfterFunc(1500*time.Millisecond, func() {
+		got = append(got, "b-after@"+servertime.Since(start).String())
+		// 定时器回调中投递的消息在同一次 Advance 内处理
+		_ = a.ExecuteAsync(actor.NewBaseMessage(context.Background(), 1, []byte("from-timer")))
+	})
+	cancelled := b.AfterFunc(time.Second, func() { got = append(got, "cancelled") })
+	if !b.Cancel(cancelled) {
+		t.Fatal("Cancel returned false for pending timer")
+	}
+
+	h.Advance(2 * time.Second)
+	a.Cancel(every)
+	h.Advance(5 * time.Second)
+
+	want := []string{"a-every@1s", "b-after@1.5s", "from-timer", "a-every@2s"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+	if d := servertime.Since(start); d != 7*time.Second {
+		t.Fatalf("virtual time advanced %v, want 7s", d)
+	}
+}
+
+func TestRemoveActorDropsTimers(t *testing.T) {
+	h := New(t)
+	var got []string
+	mgr := newRecordManager(&got)
+	a, _ := mgr.GetOrCreateActor("a")
+	a.Every(time.Second, func() { got = append(got, "tick") })
+	h.Advance(time.Second)
+	if err := mgr.RemoveActor("a"); err != nil {
+		t.Fatal(err)
+	}
+	h.Advance(10 * time.Second)
+	if len(got) != 1 {
+		t.Fatalf("timer fired %d times, want 1", len(got))
+	}
+	if err := a.ExecuteAsync(actor.NewBaseMessage(context.Background(), 1, nil)); err == nil {
+		t.Fatal("ExecuteAsync on removed actor returned nil error")
+	}
+}
+
+func TestClientCaptureDecodesS2C(t *testing.T) {
+	h := New(t)
+	sender := network.NewBaseMessageSender(h.Client())
+	if err := sender.SendToClientProto("s1", uint16(protocol.S2CProtocol_S2CTimeSync), &protocol.S2CTimeSyncReq{ServerTimeMs: 42}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sender.SendToClientProto("s2", uint16(protocol.S2CProtocol_S2CError), &protocol.ErrorData{Code: 7}); err != nil {
+		t.Fatal(err)
+	}
+
+	m, ok := h.Client().Last("s1", protocol.S2CProtocol_S2CTimeSync)
+	if !ok {
+		t.Fatal("S2CTimeSync not captured")
+	}
+	if sync, ok := m.Msg.(*protocol.S2CTimeSyncReq); !ok || sync.ServerTimeMs != 42 {
+		t.Fatalf("decoded %T %v, want S2CTimeSyncReq{42}", m.Msg, m.Msg)
+	}
+	m, ok = h.Client().Last("s2", protocol.S2CProtocol_S2CError)
+	if !ok {
+		t.Fatal("S2CError not captured")
+	}
+	if e, ok := m.Msg.(*protocol.ErrorData); !ok || e.Code != 7 {
+		t.Fatalf("decoded %T %v, want ErrorData{7}", m.Msg, m.Msg)
+	}
+	if len(h.Client().Find("s1", protocol.S2CProtocol_S2CError)) != 0 {
+		t.Fatal("Find returned messages of another session")
+	}
+}
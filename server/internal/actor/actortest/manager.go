@@ -0,0 +1,168 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/8
+ * @Desc: 同步驱动的 ActorManager
+**/
+
+package actortest
+
+import (
+	"context"
+	"fmt"
+	"postapocgame/server/internal/actor"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"sort"
+	"sync"
+)
+
+var _ actor.IActorManager = (*Manager)(nil)
+
+const globalKey = "global"
+
+// Manager 同步驱动的 ActorManager，由 Harness 安装后通过 actor.NewActorManager 创建
+type Manager struct {
+	harness *Harness
+	mode    actor.ActorMode
+	factory func() actor.IActorHandler
+
+	mu     sync.Mutex
+	actors map[string]*Context
+}
+
+func (m *Manager) GetMode() actor.ActorMode {
+	return m.mode
+}
+
+func (m *Manager) Init() error {
+	return nil
+}
+
+func (m *Manager) Start(context.Context) error {
+	if m.factory == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "not found msg handler factory func")
+	}
+	if m.mode == actor.ModeSingle {
+		if _, err := m.GetOrCreateActor(globalKey); err != nil {
+			return customerr.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// Stop 停止所有 Actor，队列中剩余的消息在下一次 Run 时处理
+func (m *Manager) Stop(context.Context) error {
+	m.mu.Lock()
+	actors := m.actors
+	m.actors = make(map[string]*Context)
+	m.mu.Unlock()
+	for _, key := range sortedKeys(actors) {
+		actors[key].stop()
+	}
+	return nil
+}
+
+func (m *Manager) GetOrCreateActor(key string) (actor.IActorContext, error) {
+	return m.getOrCreate(key)
+}
+
+func (m *Manager) getOrCreate(key string) (*Context, error) {
+	if m.mode == actor.ModeSingle {
+		key = globalKey
+	}
+	m.mu.Lock()
+	if ctx, ok := m.actors[key]; ok {
+		m.mu.Unlock()
+		return ctx, nil
+	}
+	if m.factory == nil {
+		m.mu.Unlock()
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "not found msg handler factory func")
+	}
+	handler := m.factory()
+	ctx := &Context{
+		harness: m.harness,
+		id:      key,
+		handler: handler,
+		dataMap: make(map[string]interface{}),
+	}
+	ctx.SetData("key", key)
+	handler.SetActorContext(ctx)
+	m.actors[key] = ctx
+	m.mu.Unlock()
+
+	ctx.start()
+	return ctx, nil
+}
+
+// GetActor 获取已存在的 Actor
+func (m *Manager) GetActor(key string) (*Context, bool) {
+	if m.mode == actor.ModeSingle {
+		key = globalKey
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ctx, ok := m.actors[key]
+	return ctx, ok
+}
+
+func (m *Manager) RemoveActor(key string) error {
+	if m.mode == actor.ModeSingle {
+		return fmt.Errorf("cannot remove global actor in single mode")
+	}
+	m.mu.Lock()
+	ctx, ok := m.actors[key]
+	delete(m.actors, key)
+	m.mu.Unlock()
+	if ok {
+		ctx.stop()
+	}
+	return nil
+}
+
+func (m *Manager) SendMessageAsync(key string, message actor.IActorMessage) error {
+	ctx, err := m.getOrCreate(key)
+	if err != nil {
+		return customerr.Wrap(err)
+	}
+	if err := ctx.ExecuteAsync(message); err != nil {
+		return customerr.Wrap(err)
+	}
+	return nil
+}
+
+// BroadcastAsync 按 key 排序投递，保证处理顺序确定
+func (m *Manager) BroadcastAsync(message actor.IActorMessage) {
+	m.mu.Lock()
+	actors := make(map[string]*Context, len(m.actors))
+	for key, ctx := range m.actors {
+		actors[key] = ctx
+	}
+	m.mu.Unlock()
+	for _, key := range sortedKeys(actors) {
+		_ = actors[key].ExecuteAsync(message)
+	}
+}
+
+func (m *Manager) GetAllMetrics() []actor.ActorMetrics {
+	m.mu.Lock()
+	actors := make(map[string]*Context, len(m.actors))
+	for key, ctx := range m.actors {
+		actors[key] = ctx
+	}
+	m.mu.Unlock()
+	all := make([]actor.ActorMetrics, 0, len(actors))
+	for _, key := range sortedKeys(actors) {
+		all = append(all, actors[key].GetMetrics())
+	}
+	return all
+}
+
+func sortedKeys(actors map[string]*Context) []string {
+	keys := make([]string, 0, len(actors))
+	for key := range actors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,131 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/8
+ * @Desc: 虚拟时间定时器
+**/
+
+package actortest
+
+import (
+	"container/heap"
+	"postapocgame/server/internal/actor"
+	"time"
+)
+
+type timerKey struct {
+	ctx *Context
+	id  actor.TimerId
+}
+
+type virtualTimer struct {
+	key      timerKey
+	ctx      *Context
+	deadline time.Time
+	seq      uint64 // 到期时间相同时按注册顺序触发
+	interval time.Duration
+	fn       func()
+	index    int
+}
+
+// timerQueue 按 (到期时间, 注册顺序) 排序的最小堆
+type timerQueue []*virtualTimer
+
+func (q timerQueue) Len() int { return len(q) }
+func (q timerQueue) Less(i, j int) bool {
+	if q[i].deadline.Equal(q[j].deadline) {
+		return q[i].seq < q[j].seq
+	}
+	return q[i].deadline.Before(q[j].deadline)
+}
+func (q timerQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *timerQueue) Push(x any) {
+	t := x.(*virtualTimer)
+	t.index = len(*q)
+	*q = append(*q, t)
+}
+
+func (q *timerQueue) Pop() any {
+	old := *q
+	n := len(old)
+	t := old[n-1]
+	old[n-1] = nil
+	t.index = -1
+	*q = old[:n-1]
+	return t
+}
+
+// nextTimerSeq 调用方需持有 h.mu
+func (h *Harness) nextTimerSeq() uint64 {
+	h.timerSeq++
+	return h.timerSeq
+}
+
+func (h *Harness) addTimer(ctx *Context, d, interval time.Duration, fn func()) actor.TimerId {
+	if fn == nil {
+		return 0
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !ctx.IsRunning() {
+		return 0
+	}
+	ctx.nextTimerId++
+	t := &virtualTimer{
+		key:      timerKey{ctx: ctx, id: ctx.nextTimerId},
+		ctx:      ctx,
+		deadline: h.now.Add(d),
+		seq:      h.nextTimerSeq(),
+		interval: interval,
+		fn:       fn,
+	}
+	heap.Push(&h.timers, t)
+	h.byId[t.key] = t
+	return t.key.id
+}
+
+func (h *Harness) cancelTimer(ctx *Context, id actor.TimerId) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	key := timerKey{ctx: ctx, id: id}
+	t, ok := h.byId[key]
+	if !ok {
+		return false
+	}
+	delete(h.byId, key)
+	if t.index >= 0 {
+		heap.Remove(&h.timers, t.index)
+	}
+	return true
+}
+
+// clearTimers 移除 Actor 的所有定时器
+func (h *Harness) clearTimers(ctx *Context) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for key, t := range h.byId {
+		if key.ctx != ctx {
+			continue
+		}
+		delete(h.byId, key)
+		if t.index >= 0 {
+			heap.Remove(&h.timers, t.index)
+		}
+	}
+}
+
+func (h *Harness) timerCount(ctx *Context) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	n := 0
+	for key := range h.byId {
+		if key.ctx == ctx {
+			n++
+		}
+	}
+	return n
+}
@@ -6,7 +6,9 @@ import "golang.org/x/crypto/bcrypt"
 type Account struct {
 	ID        uint   `gorm:"primaryKey"`
 	Username  string `gorm:"unique;not null;size:32"`
-	Password  string `gorm:"not null;size:128"` // 存储bcrypt hash
+	Password  string `gorm:"not null;size:128"`  // 存储bcrypt hash
+	BanUntil  int64  `gorm:"not null;default:0"` // 封禁截止时间（秒），0 未封禁，-1 永久封禁
+	BanReason string `gorm:"size:128"`
 	CreatedAt int64  `gorm:"autoCreateTime"`
 	UpdatedAt int64  `gorm:"autoUpdateTime"`
 }
@@ -45,3 +47,19 @@ func GetAccountByUsername(username string) (*Account, error) {
 	}
 	return &acct, nil
 }
+
+// GetAccountByID 通过账号ID查找
+func GetAccountByID(accountId uint) (*Account, error) {
+	var acct Account
+	result := DB.First(&acct, accountId)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &acct, nil
+}
+
+// UpdateAccountBan 更新账号封禁状态，until 为 0 表示解封
+func UpdateAccountBan(accountId uint, until int64, reason string) error {
+	return DB.Model(&Account{}).Where("id = ?", accountId).
+		Updates(map[string]interface{}{"ban_until": until, "ban_reason": reason}).Error
+}
@@ -0,0 +1,187 @@
+package database
+
+import (
+	"postapocgame/server/internal/protocol"
+
+	"gorm.io/gorm"
+)
+
+// 拍卖行上架状态，与 protocol.AuctionStatus 一致
+const (
+	AuctionStatusActive    uint32 = 1 // 在售
+	AuctionStatusSold      uint32 = 2 // 已售出
+	AuctionStatusExpired   uint32 = 3 // 已过期
+	AuctionStatusCancelled uint32 = 4 // 已下架
+)
+
+// AuctionListing 拍卖行上架表，同时作为托管记录：
+// 上架时道具从卖家背包扣除并与本记录同事务落库；售出后卖家所得、过期/下架后退回的道具
+// 由拍卖行通过邮件发放，Delivered 标记发放完成，未发放的记录在重启后继续补发
+type AuctionListing struct {
+	ID         uint   `gorm:"primaryKey"`
+	SellerId   uint64 `gorm:"not null;index"`
+	SellerName string `gorm:"size:32;not null"`
+	ItemId     uint32 `gorm:"not null;index"`
+	ItemType   uint32 `gorm:"not null;index"`
+	Count      uint32 `gorm:"not null"`
+	Price      int64  `gorm:"not null"`                     // 一口价（总价）
+	UnitPrice  int64  `gorm:"not null;index"`               // 单价，用于排序
+	Fee        int64  `gorm:"not null;default:0"`           // 已扣除的上架手续费
+	Status     uint32 `gorm:"not null;default:1;index"`     // 见 AuctionStatus*
+	ExpireAt   int64  `gorm:"not null;index"`               // 过期时间（秒）
+	BuyerId    uint64 `gorm:"not null;default:0;index"`     // 买家ID
+	SoldAt     int64  `gorm:"not null;default:0"`           // 成交时间（秒）
+	Delivered  bool   `gorm:"not null;default:false;index"` // 卖家所得或退回道具是否已发放
+	CreatedAt  int64  `gorm:"autoCreateTime;index"`
+	UpdatedAt  int64  `gorm:"autoUpdateTime"`
+}
+
+// AuctionFilter 拍卖行搜索条件，零值表示不限
+type AuctionFilter struct {
+	ItemType uint32
+	ItemId   uint32
+	MinPrice int64
+	MaxPrice int64
+	OrderBy  string // 排序子句，如 "unit_price ASC"
+}
+
+// GetAuctionListing 获取上架记录
+func GetAuctionListing(id uint) (*AuctionListing, error) {
+	var listing AuctionListing
+	if err := DB.First(&listing, id).Error; err != nil {
+		return nil, err
+	}
+	return &listing, nil
+}
+
+// SearchAuctionListings 分页搜索在售记录，返回当前页与总数
+func SearchAuctionListings(filter *AuctionFilter, now int64, offset, limit int) ([]*AuctionListing, int64, error) {
+	query := DB.Model(&AuctionListing{}).Where("status = ? AND expire_at > ?", AuctionStatusActive, now)
+	if filter.ItemType != 0 {
+		query = query.Where("item_type = ?", filter.ItemType)
+	}
+	if filter.ItemId != 0 {
+		query = query.Where("item_id = ?", filter.ItemId)
+	}
+	if filter.MinPrice > 0 {
+		query = query.Where("price >= ?", filter.MinPrice)
+	}
+	if filter.MaxPrice > 0 {
+		query = query.Where("price <= ?", filter.MaxPrice)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	orderBy := filter.OrderBy
+	if orderBy == "" {
+		orderBy = "id DESC"
+	}
+	var listings []*AuctionListing
+	if err := query.Order(orderBy).Order("id DESC").Offset(offset).Limit(limit).Find(&listings).Error; err != nil {
+		return nil, 0, err
+	}
+	return listings, total, nil
+}
+
+// GetActiveAuctionListingsBySeller 获取卖家在售的记录
+func GetActiveAuctionListingsBySeller(sellerId uint64) ([]*AuctionListing, error) {
+	var listings []*AuctionListing
+	result := DB.Where("seller_id = ? AND status = ?", sellerId, AuctionStatusActive).
+		Order("id DESC").
+		Find(&listings)
+	return listings, result.Error
+}
+
+// CountActiveAuctionListingsBySeller 统计卖家在售的记录数
+func CountActiveAuctionListingsBySeller(sellerId uint64) (int64, error) {
+	var count int64
+	result := DB.Model(&AuctionListing{}).Where("seller_id = ? AND status = ?", sellerId, AuctionStatusActive).Count(&count)
+	return count, result.Error
+}
+
+// CancelAuctionListing 卖家下架在售记录，记录不属于卖家或已结束时返回 false
+func CancelAuctionListing(id uint, sellerId uint64) (bool, error) {
+	result := DB.Model(&AuctionListing{}).
+		Where("id = ? AND seller_id = ? AND status = ?", id, sellerId, AuctionStatusActive).
+		Update("status", AuctionStatusCancelled)
+	return result.RowsAffected == 1, result.Error
+}
+
+// ExpireAuctionListings 将已到期的在售记录标记为过期，返回处理条数
+func ExpireAuctionListings(now int64) (int64, error) {
+	result := DB.Model(&AuctionListing{}).
+		Where("status = ? AND expire_at <= ?", AuctionStatusActive, now).
+		Update("status", AuctionStatusExpired)
+	return result.RowsAffected, result.Error
+}
+
+// GetUndeliveredAuctionListings 获取已结束但卖家所得或退回道具尚未发放的记录
+func GetUndeliveredAuctionListings(limit int) ([]*AuctionListing, error) {
+	var listings []*AuctionListing
+	result := DB.Where("status IN ? AND delivered = ?", []uint32{AuctionStatusSold, AuctionStatusExpired, AuctionStatusCancelled}, false).
+		Order("id ASC").
+		Limit(limit).
+		Find(&listings)
+	return listings, result.Error
+}
+
+// CreateAuctionListingWithBinaryData 上架：创建上架记录并保存卖家扣除道具与手续费后的数据（同一事务）
+func CreateAuctionListingWithBinaryData(listing *AuctionListing, sellerBinaryData *protocol.PlayerRoleBinaryData) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(listing).Error; err != nil {
+			return err
+		}
+		return SavePlayerBinaryDataTx(tx, uint(listing.SellerId), sellerBinaryData)
+	})
+}
+
+// BuyAuctionListingWithBinaryData 购买：将在售且未过期的记录标记为售出并保存买家扣款、得到道具后的数据（同一事务）
+// 记录已被抢先购买、下架或过期时返回 false 且不做任何修改
+func BuyAuctionListingWithBinaryData(id uint, buyerId uint64, now int64, buyerBinaryData *protocol.PlayerRoleBinaryData) (bool, error) {
+	sold := false
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&AuctionListing{}).
+			Where("id = ? AND status = ? AND expire_at > ?", id, AuctionStatusActive, now).
+			Updates(map[string]interface{}{"status": AuctionStatusSold, "buyer_id": buyerId, "sold_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return nil
+		}
+		sold = true
+		return SavePlayerBinaryDataTx(tx, uint(buyerId), buyerBinaryData)
+	})
+	if err != nil {
+		return false, err
+	}
+	return sold, nil
+}
+
+// DeliverAuctionListing 发放卖家所得或退回道具：标记已发放并在同一事务内执行 deliver（如写入邮件），
+// 已发放过时返回 false 且不执行 deliver
+func DeliverAuctionListing(id uint, deliver func(tx *gorm.DB) error) (bool, error) {
+	delivered := false
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&AuctionListing{}).
+			Where("id = ? AND delivered = ?", id, false).
+			Update("delivered", true)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return nil
+		}
+		if err := deliver(tx); err != nil {
+			return err
+		}
+		delivered = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return delivered, nil
+}
@@ -0,0 +1,18 @@
+package database
+
+// GmAudit GM 指令审计表，GM 角色的每次指令调用（含权限不足、参数错误）都会记录，非 GM 角色直接拒绝不记录
+type GmAudit struct {
+	ID        uint   `gorm:"primaryKey"`
+	RoleId    uint64 `gorm:"not null;index"`   // 执行者角色ID
+	GmLevel   uint32 `gorm:"not null"`         // 执行时的 gm_level
+	Command   string `gorm:"size:64;not null"` // 指令名
+	Args      string `gorm:"type:text"`        // 原始参数，空格分隔
+	Success   bool   `gorm:"not null"`         // 是否执行成功
+	Result    string `gorm:"type:text"`        // 执行结果或失败原因
+	CreatedAt int64  `gorm:"autoCreateTime;index"`
+}
+
+// SaveGmAudit 保存 GM 指令审计记录
+func SaveGmAudit(audit *GmAudit) error {
+	return DB.Create(audit).Error
+}
@@ -0,0 +1,261 @@
+package database
+
+import (
+	"postapocgame/server/internal/protocol"
+
+	"google.golang.org/protobuf/proto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Mail 邮件表，每个收件角色一行；全服邮件在角色登录或发送时按 GlobalMailId 展开为角色邮件
+type Mail struct {
+	ID            uint   `gorm:"primaryKey"`
+	RoleId        uint64 `gorm:"not null;index;uniqueIndex:idx_mail_role_global"` // 收件角色ID
+	GlobalMailId  *uint  `gorm:"uniqueIndex:idx_mail_role_global"`                // 来源全服邮件ID，非全服邮件为空
+	SenderId      uint64 `gorm:"not null;default:0"`                              // 发件角色ID，0 表示系统
+	SenderName    string `gorm:"size:32;not null"`
+	Title         string `gorm:"size:64;not null"`
+	Content       string `gorm:"type:text"`
+	Attachments   []byte `gorm:"type:blob"`              // protocol.MailAttachments 的二进制数据
+	HasAttachment bool   `gorm:"not null;default:false"` // 是否带附件
+	ExpireAt      int64  `gorm:"not null;index"`         // 过期时间（秒）
+	IsRead        bool   `gorm:"not null;default:false"` // 是否已读
+	Claimed       bool   `gorm:"not null;default:false"` // 附件是否已领取
+	Deleted       bool   `gorm:"not null;default:false"` // 是否已删除（保留记录，避免全服邮件重复展开）
+	CreatedAt     int64  `gorm:"autoCreateTime"`
+}
+
+// GlobalMail 全服邮件表，发送时在线角色立即展开，离线及之后创建的角色在登录时展开
+type GlobalMail struct {
+	ID          uint   `gorm:"primaryKey"`
+	SenderName  string `gorm:"size:32;not null"`
+	Title       string `gorm:"size:64;not null"`
+	Content     string `gorm:"type:text"`
+	Attachments []byte `gorm:"type:blob"` // protocol.MailAttachments 的二进制数据
+	ExpireAt    int64  `gorm:"not null;index"`
+	CreatedAt   int64  `gorm:"autoCreateTime"`
+}
+
+// SetAttachments 设置附件
+func (m *Mail) SetAttachments(items []*protocol.ItemAmount) error {
+	data, err := marshalAttachments(items)
+	if err != nil {
+		return err
+	}
+	m.Attachments = data
+	m.HasAttachment = len(items) > 0
+	return nil
+}
+
+// GetAttachments 解析附件
+func (m *Mail) GetAttachments() ([]*protocol.ItemAmount, error) {
+	return unmarshalAttachments(m.Attachments)
+}
+
+// SetAttachments 设置附件
+func (g *GlobalMail) SetAttachments(items []*protocol.ItemAmount) error {
+	data, err := marshalAttachments(items)
+	if err != nil {
+		return err
+	}
+	g.Attachments = data
+	return nil
+}
+
+// NewRoleMail 将全服邮件展开为角色邮件
+func (g *GlobalMail) NewRoleMail(roleId uint64) *Mail {
+	globalMailId := g.ID
+	return &Mail{
+		RoleId:        roleId,
+		GlobalMailId:  &globalMailId,
+		SenderName:    g.SenderName,
+		Title:         g.Title,
+		Content:       g.Content,
+		Attachments:   g.Attachments,
+		HasAttachment: len(g.Attachments) > 0,
+		ExpireAt:      g.ExpireAt,
+		CreatedAt:     g.CreatedAt,
+	}
+}
+
+func marshalAttachments(items []*protocol.ItemAmount) ([]byte, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+	return proto.Marshal(&protocol.MailAttachments{Items: items})
+}
+
+func unmarshalAttachments(data []byte) ([]*protocol.ItemAmount, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var attachments protocol.MailAttachments
+	if err := proto.Unmarshal(data, &attachments); err != nil {
+		return nil, err
+	}
+	return attachments.Items, nil
+}
+
+// CreateMails 批量创建角色邮件
+func CreateMails(mails []*Mail) error {
+	return CreateMailsTx(DB, mails)
+}
+
+// CreateMailsTx 批量创建角色邮件（事务内）
+func CreateMailsTx(tx *gorm.DB, mails []*Mail) error {
+	if len(mails) == 0 {
+		return nil
+	}
+	return tx.Create(mails).Error
+}
+
+// CreateMailWithBinaryData 玩家邮件：创建邮件并保存发件人扣除附件后的数据（同一事务）
+func CreateMailWithBinaryData(mail *Mail, senderBinaryData *protocol.PlayerRoleBinaryData) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(mail).Error; err != nil {
+			return err
+		}
+		return SavePlayerBinaryDataTx(tx, uint(mail.SenderId), senderBinaryData)
+	})
+}
+
+// CreateGlobalMail 创建全服邮件
+func CreateGlobalMail(mail *GlobalMail) error {
+	return DB.Create(mail).Error
+}
+
+// ExpandGlobalMails 将未过期的全服邮件展开到角色邮箱，已展开过的跳过，返回本次新展开的邮件
+// globalMailId 不为 0 时只展开该全服邮件
+func ExpandGlobalMails(roleId uint64, globalMailId uint, now int64) ([]*Mail, error) {
+	query := DB.Where("expire_at > ?", now)
+	if globalMailId != 0 {
+		query = query.Where("id = ?", globalMailId)
+	}
+	var globals []*GlobalMail
+	if err := query.Order("id ASC").Find(&globals).Error; err != nil {
+		return nil, err
+	}
+	if len(globals) == 0 {
+		return nil, nil
+	}
+
+	var expanded []uint
+	if err := DB.Model(&Mail{}).Where("role_id = ? AND global_mail_id IS NOT NULL", roleId).
+		Pluck("global_mail_id", &expanded).Error; err != nil {
+		return nil, err
+	}
+	skip := make(map[uint]struct{}, len(expanded))
+	for _, id := range expanded {
+		skip[id] = struct{}{}
+	}
+	created := make([]*Mail, 0, len(globals))
+	for _, g := range globals {
+		if _, ok := skip[g.ID]; ok {
+			continue
+		}
+		mail := g.NewRoleMail(roleId)
+		// 并发展开时由 (role_id, global_mail_id) 唯一索引去重
+		result := DB.Clauses(clause.OnConflict{DoNothing: true}).Create(mail)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 1 {
+			created = append(created, mail)
+		}
+	}
+	return created, nil
+}
+
+// GetMailsByRole 获取角色未删除、未过期的邮件，按发送时间倒序
+func GetMailsByRole(roleId uint64, now int64, limit int) ([]*Mail, error) {
+	var mails []*Mail
+	result := DB.Where("role_id = ? AND deleted = ? AND expire_at > ?", roleId, false, now).
+		Order("id DESC").
+		Limit(limit).
+		Find(&mails)
+	return mails, result.Error
+}
+
+// GetClaimableMails 获取角色附件未领取且未过期的邮件
+func GetClaimableMails(roleId uint64, now int64, limit int) ([]*Mail, error) {
+	var mails []*Mail
+	result := DB.Where("role_id = ? AND deleted = ? AND has_attachment = ? AND claimed = ? AND expire_at > ?", roleId, false, true, false, now).
+		Order("id ASC").
+		Limit(limit).
+		Find(&mails)
+	return mails, result.Error
+}
+
+// CountUnreadMails 统计角色未读邮件数
+func CountUnreadMails(roleId uint64, now int64) (int64, error) {
+	var count int64
+	result := DB.Model(&Mail{}).Where("role_id = ? AND deleted = ? AND is_read = ? AND expire_at > ?", roleId, false, false, now).Count(&count)
+	return count, result.Error
+}
+
+// GetMail 获取角色的一封未删除邮件
+func GetMail(id uint, roleId uint64) (*Mail, error) {
+	var mail Mail
+	if err := DB.Where("id = ? AND role_id = ? AND deleted = ?", id, roleId, false).First(&mail).Error; err != nil {
+		return nil, err
+	}
+	return &mail, nil
+}
+
+// MarkMailRead 标记邮件已读
+func MarkMailRead(id uint, roleId uint64) error {
+	return DB.Model(&Mail{}).Where("id = ? AND role_id = ?", id, roleId).Update("is_read", true).Error
+}
+
+// ClaimMailWithBinaryData 领取附件：以“未领取、未过期”为条件标记已领取，并保存收件人得到附件后的数据（同一事务）
+// 附件已领取、邮件已删除或过期时返回 false 且不做任何修改，重复领取不会重复发放
+func ClaimMailWithBinaryData(id uint, roleId uint64, now int64, binaryData *protocol.PlayerRoleBinaryData) (bool, error) {
+	claimed := false
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&Mail{}).
+			Where("id = ? AND role_id = ? AND claimed = ? AND deleted = ? AND expire_at > ?", id, roleId, false, false, now).
+			Updates(map[string]interface{}{"claimed": true, "is_read": true})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return nil
+		}
+		claimed = true
+		return SavePlayerBinaryDataTx(tx, uint(roleId), binaryData)
+	})
+	if err != nil {
+		return false, err
+	}
+	return claimed, nil
+}
+
+// DeleteMails 删除角色邮件，带未领取附件的邮件跳过，返回删除成功的邮件ID
+func DeleteMails(roleId uint64, ids []uint) ([]uint, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var deletable []uint
+	if err := DB.Model(&Mail{}).
+		Where("id IN ? AND role_id = ? AND deleted = ? AND (has_attachment = ? OR claimed = ?)", ids, roleId, false, false, true).
+		Pluck("id", &deletable).Error; err != nil {
+		return nil, err
+	}
+	if len(deletable) == 0 {
+		return nil, nil
+	}
+	if err := DB.Model(&Mail{}).Where("id IN ?", deletable).Update("deleted", true).Error; err != nil {
+		return nil, err
+	}
+	return deletable, nil
+}
+
+// GetPlayerByRoleName 按角色名查找角色
+func GetPlayerByRoleName(roleName string) (*Player, error) {
+	var player Player
+	if err := DB.Where("role_name = ?", roleName).First(&player).Error; err != nil {
+		return nil, err
+	}
+	return &player, nil
+}
@@ -6,5 +6,10 @@ func AutoMigrate() error {
 		&Account{},
 		&Player{},
 		&ServerInfo{},
+		&GmAudit{},
+		&TransactionAudit{},
+		&AuctionListing{},
+		&Mail{},
+		&GlobalMail{},
 	)
 }
@@ -17,6 +17,8 @@ type Player struct {
 	Job          int
 	Sex          int
 	Level        int
+	GmLevel      uint32 `gorm:"not null;default:0"` // GM等级: 0=普通玩家 1=GM 2=高级GM 3=超级GM
+	MuteUntil    int64  `gorm:"not null;default:0"` // 禁言截止时间（秒），0 未禁言，-1 永久禁言
 	LastLoginAt  int64  `gorm:"not null;default:0"`
 	LastLogoutAt int64  `gorm:"not null;default:0"`
 	BinaryData   []byte `gorm:"type:blob"` // PlayerRoleBinaryData的二进制数据
@@ -130,3 +132,13 @@ func UpdatePlayerLoginTime(playerId uint, loginAt time.Time) error {
 func UpdatePlayerLogoutTime(playerId uint, logoutAt time.Time) error {
 	return DB.Model(&Player{}).Where("id = ?", playerId).Update("last_logout_at", logoutAt.Unix()).Error
 }
+
+// UpdatePlayerLevel 更新角色等级（角色列表展示用）
+func UpdatePlayerLevel(playerId uint, level uint32) error {
+	return DB.Model(&Player{}).Where("id = ?", playerId).Update("level", level).Error
+}
+
+// UpdatePlayerMuteUntil 更新角色禁言截止时间
+func UpdatePlayerMuteUntil(playerId uint, until int64) error {
+	return DB.Model(&Player{}).Where("id = ?", playerId).Update("mute_until", until).Error
+}
@@ -1,5 +1,19 @@
 package database
 
+// 交易类型
+const (
+	TransactionTypeAuctionBuy  uint32 = 1 // 拍卖行购买
+	TransactionTypeAuctionSell uint32 = 2 // 拍卖行出售
+	TransactionTypeOther       uint32 = 3 // 其他
+)
+
+// 交易状态
+const (
+	TransactionStatusSuccess    uint32 = 1 // 成功
+	TransactionStatusFailed     uint32 = 2 // 失败
+	TransactionStatusSuspicious uint32 = 3 // 可疑
+)
+
 // TransactionAudit 交易审计表
 type TransactionAudit struct {
 	ID              uint   `gorm:"primaryKey"`
@@ -51,7 +65,7 @@ func GetTransactionAuditByRoleId(roleId uint64, limit int) ([]*TransactionAudit,
 // GetSuspiciousTransactions 获取可疑交易记录
 func GetSuspiciousTransactions(limit int) ([]*TransactionAudit, error) {
 	var audits []TransactionAudit
-	query := DB.Where("status = ?", 3).
+	query := DB.Where("status = ?", TransactionStatusSuspicious).
 		Order("created_at DESC").
 		Limit(limit)
 	result := query.Find(&audits)
@@ -65,3 +79,14 @@ func GetSuspiciousTransactions(limit int) ([]*TransactionAudit, error) {
 	}
 	return resultList, nil
 }
+
+// GetRecentTransactionAuditsByItem 获取道具最近的成交记录（成功与可疑），用于成交价参考
+func GetRecentTransactionAuditsByItem(transactionType uint32, itemId uint32, limit int) ([]*TransactionAudit, error) {
+	var audits []*TransactionAudit
+	result := DB.Where("transaction_type = ? AND item_id = ? AND status IN ?", transactionType, itemId,
+		[]uint32{TransactionStatusSuccess, TransactionStatusSuspicious}).
+		Order("id DESC").
+		Limit(limit).
+		Find(&audits)
+	return audits, result.Error
+}
@@ -0,0 +1,55 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/12
+ * @Desc: 拍卖行配置
+**/
+
+package jsonconf
+
+// AuctionConfig 拍卖行配置，比例字段均为万分比
+type AuctionConfig struct {
+	CurrencyItemId uint32                   `json:"currencyItemId"` // 交易货币道具ID（道具类型须为货币）
+	FeeRate        uint32                   `json:"feeRate"`        // 上架手续费率，按一口价计算，上架时扣除且不退还
+	MinFee         int64                    `json:"minFee"`         // 最低上架手续费
+	TaxRate        uint32                   `json:"taxRate"`        // 成交税率，从卖家所得中扣除
+	MinPrice       int64                    `json:"minPrice"`       // 一口价下限
+	MaxPrice       int64                    `json:"maxPrice"`       // 一口价上限
+	DurationHours  []uint32                 `json:"durationHours"`  // 可选上架时长（小时），第一个为默认时长
+	MaxListings    uint32                   `json:"maxListings"`    // 每个角色同时在售的上限
+	MaxPageSize    uint32                   `json:"maxPageSize"`    // 搜索每页最大条数
+	Suspicious     *AuctionSuspiciousConfig `json:"suspicious"`     // 可疑交易判定
+}
+
+// AuctionSuspiciousConfig 可疑交易判定：成交单价与该道具近期成交单价中位数偏离过大时标记为可疑
+type AuctionSuspiciousConfig struct {
+	SampleSize uint32 `json:"sampleSize"` // 参考的近期成交记录条数
+	MinSamples uint32 `json:"minSamples"` // 参考记录少于该值时不做价格判定
+	HighRate   uint32 `json:"highRate"`   // 单价高于中位数的该比例时可疑，如 50000 表示 5 倍
+	LowRate    uint32 `json:"lowRate"`    // 单价低于中位数的该比例时可疑，如 2000 表示 20%
+}
+
+// CalcFee 计算上架手续费
+func (c *AuctionConfig) CalcFee(price int64) int64 {
+	return max(price*int64(c.FeeRate)/10000, c.MinFee)
+}
+
+// CalcTax 计算成交税
+func (c *AuctionConfig) CalcTax(price int64) int64 {
+	return price * int64(c.TaxRate) / 10000
+}
+
+// ResolveDuration 校验上架时长，0 取默认时长；不在可选列表中返回 false
+func (c *AuctionConfig) ResolveDuration(hours uint32) (uint32, bool) {
+	if len(c.DurationHours) == 0 {
+		return 0, false
+	}
+	if hours == 0 {
+		return c.DurationHours[0], true
+	}
+	for _, h := range c.DurationHours {
+		if h == hours {
+			return h, true
+		}
+	}
+	return 0, false
+}
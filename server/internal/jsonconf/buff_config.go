@@ -0,0 +1,31 @@
+/**
+ * @Author: zjj
+ * @Date: 2025/12/27
+ * @Desc: Buff配置
+**/
+
+package jsonconf
+
+// BuffConfig Buff配置
+type BuffConfig struct {
+	BuffId     uint32      `json:"buffId"`     // BuffId
+	Name       string      `json:"name"`       // Buff名称
+	Type       uint32      `json:"type"`       // 类型，见 protocol.BuffType
+	EffectType uint32      `json:"effectType"` // 效果类型，见 protocol.BuffEffectType
+	Duration   uint32      `json:"duration"`   // 默认持续时间（毫秒），0表示永久；技能效果配置的持续时间优先
+	StackRule  uint32      `json:"stackRule"`  // 叠加规则，见 protocol.BuffStackRule
+	MaxStack   uint32      `json:"maxStack"`   // 最大层数（叠加规则为叠加时生效）
+	Attrs      []*BuffAttr `json:"attrs"`      // 属性修正（每层）
+	States     []uint32    `json:"states"`     // 状态效果，见 protocol.EntityStateFlag
+	TickMs     uint32      `json:"tickMs"`     // 周期间隔（毫秒），0表示无周期效果
+	TickDamage int64       `json:"tickDamage"` // 每跳伤害（每层）
+	TickHeal   int64       `json:"tickHeal"`   // 每跳治疗（每层）
+	Desc       string      `json:"desc"`       // 描述
+}
+
+// BuffAttr Buff属性修正
+type BuffAttr struct {
+	Type  uint32 `json:"type"`  // 属性类型，见 attrdef
+	Value int64  `json:"value"` // 固定值
+	Rate  int64  `json:"rate"`  // 万分比，按添加时的属性值计算
+}
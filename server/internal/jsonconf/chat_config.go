@@ -0,0 +1,32 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/12
+ * @Desc: 聊天配置
+**/
+
+package jsonconf
+
+// ChatConfig 聊天配置
+type ChatConfig struct {
+	MaxLength      uint32               `json:"maxLength"`      // 单条消息最大字符数
+	HistorySize    uint32               `json:"historySize"`    // 世界频道保留的最近消息条数，登录时下发
+	Channels       []*ChatChannelConfig `json:"channels"`       // 开放的频道，未列出的频道不可用
+	SensitiveWords []string             `json:"sensitiveWords"` // 敏感词，不区分大小写，命中部分按字替换为 replacement
+	Replacement    string               `json:"replacement"`    // 敏感词替换字符，默认 *
+}
+
+// ChatChannelConfig 频道配置
+type ChatChannelConfig struct {
+	Channel    uint32 `json:"channel"`    // 频道，见 protocol.ChatChannel
+	CooldownMs int64  `json:"cooldownMs"` // 同一角色在该频道的发言间隔（毫秒）
+}
+
+// GetChannel 获取频道配置，频道未开放时返回 nil
+func (c *ChatConfig) GetChannel(channel uint32) *ChatChannelConfig {
+	for _, ch := range c.Channels {
+		if ch.Channel == channel {
+			return ch
+		}
+	}
+	return nil
+}
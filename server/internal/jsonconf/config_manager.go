@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"postapocgame/server/internal"
+	"postapocgame/server/internal/protocol"
 	"postapocgame/server/pkg/customerr"
 	"postapocgame/server/pkg/log"
 	"sync"
@@ -20,6 +21,17 @@ type ConfigManager struct {
 	jobConfigs   map[uint32]*JobConfig
 	sceneConfigs map[uint32]*SceneConfig
 	mapConfigs   map[uint32]*MapConfig
+
+	monsterConfigs      map[uint32]*MonsterConfig
+	monsterSceneConfigs map[uint32]*MonsterSceneConfig
+	buffConfigs         map[uint32]*BuffConfig
+	levelConfigs        map[uint32]*LevelConfig
+	maxLevel            uint32
+	itemConfigs         map[uint32]*ItemConfig
+	dropConfigs         map[uint32]*DropConfig
+	fubenConfigs        map[uint32]*FuBenConfig
+	auctionConfig       *AuctionConfig
+	chatConfig          *ChatConfig
 }
 
 var (
@@ -33,6 +45,14 @@ func newConfigManager() *ConfigManager {
 		jobConfigs:   make(map[uint32]*JobConfig),
 		sceneConfigs: make(map[uint32]*SceneConfig),
 		mapConfigs:   make(map[uint32]*MapConfig),
+
+		monsterConfigs:      make(map[uint32]*MonsterConfig),
+		monsterSceneConfigs: make(map[uint32]*MonsterSceneConfig),
+		buffConfigs:         make(map[uint32]*BuffConfig),
+		levelConfigs:        make(map[uint32]*LevelConfig),
+		itemConfigs:         make(map[uint32]*ItemConfig),
+		dropConfigs:         make(map[uint32]*DropConfig),
+		fubenConfigs:        make(map[uint32]*FuBenConfig),
 	}
 }
 
@@ -67,6 +87,31 @@ func (cm *ConfigManager) LoadAllConfigs() error {
 		return customerr.Wrap(err)
 	}
 
+	// 加载Buff配置（依赖技能配置）
+	if err := cm.loadBuffConfigs(); err != nil {
+		return customerr.Wrap(err)
+	}
+
+	// 加载等级配置（依赖技能配置）
+	if err := cm.loadLevelConfigs(); err != nil {
+		return customerr.Wrap(err)
+	}
+
+	// 加载道具配置（依赖Buff配置）
+	if err := cm.loadItemConfigs(); err != nil {
+		return customerr.Wrap(err)
+	}
+
+	// 加载拍卖行配置（依赖道具配置）
+	if err := cm.loadAuctionConfig(); err != nil {
+		return customerr.Wrap(err)
+	}
+
+	// 加载聊天配置
+	if err := cm.loadChatConfig(); err != nil {
+		return customerr.Wrap(err)
+	}
+
 	// 加载职业配置
 	if err := cm.loadJobConfigs(); err != nil {
 		return customerr.Wrap(err)
@@ -82,6 +127,26 @@ func (cm *ConfigManager) LoadAllConfigs() error {
 		return customerr.Wrap(err)
 	}
 
+	// 加载怪物配置
+	if err := cm.loadMonsterConfigs(); err != nil {
+		return customerr.Wrap(err)
+	}
+
+	// 加载场景刷怪配置（依赖怪物配置）
+	if err := cm.loadMonsterSceneConfigs(); err != nil {
+		return customerr.Wrap(err)
+	}
+
+	// 加载掉落配置（依赖道具与怪物配置）
+	if err := cm.loadDropConfigs(); err != nil {
+		return customerr.Wrap(err)
+	}
+
+	// 加载副本配置（依赖场景配置）
+	if err := cm.loadFuBenConfigs(); err != nil {
+		return customerr.Wrap(err)
+	}
+
 	log.Infof("All configs loaded successfully")
 	return nil
 }
@@ -270,3 +335,523 @@ func (cm *ConfigManager) GetMapConfig(mapId uint32) *MapConfig {
 	defer cm.mu.RUnlock()
 	return cm.mapConfigs[mapId]
 }
+
+// loadMonsterConfigs 加载怪物配置
+func (cm *ConfigManager) loadMonsterConfigs() error {
+	filePath := filepath.Join(cm.configPath, "monsterconfig.json")
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.Warnf("monsterconfig.json not found, using empty config")
+			cm.monsterConfigs = make(map[uint32]*MonsterConfig)
+			return nil
+		}
+		return fmt.Errorf("read monster config failed: %w", err)
+	}
+
+	var configs []*MonsterConfig
+	if err := internal.Unmarshal(data, &configs); err != nil {
+		return fmt.Errorf("unmarshal monster config failed: %w", err)
+	}
+
+	// 注意：LoadAllConfigs 已经持有锁，这里不需要再次获取锁
+	cm.monsterConfigs = make(map[uint32]*MonsterConfig)
+	for _, cfg := range configs {
+		if cfg != nil {
+			cm.monsterConfigs[cfg.MonsterId] = cfg
+		}
+	}
+
+	log.Infof("Loaded %d monster configs", len(cm.monsterConfigs))
+	return nil
+}
+
+// loadMonsterSceneConfigs 加载场景刷怪配置
+func (cm *ConfigManager) loadMonsterSceneConfigs() error {
+	filePath := filepath.Join(cm.configPath, "monstersceneconfig.json")
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.Warnf("monstersceneconfig.json not found, using empty config")
+			cm.monsterSceneConfigs = make(map[uint32]*MonsterSceneConfig)
+			return nil
+		}
+		return fmt.Errorf("read monster scene config failed: %w", err)
+	}
+
+	var configs []*MonsterSceneConfig
+	if err := internal.Unmarshal(data, &configs); err != nil {
+		return fmt.Errorf("unmarshal monster scene config failed: %w", err)
+	}
+
+	// 注意：LoadAllConfigs 已经持有锁，这里不需要再次获取锁
+	cm.monsterSceneConfigs = make(map[uint32]*MonsterSceneConfig)
+	for _, cfg := range configs {
+		if cfg == nil {
+			continue
+		}
+		for _, spawn := range cfg.Spawns {
+			if spawn == nil {
+				continue
+			}
+			if _, ok := cm.monsterConfigs[spawn.MonsterId]; !ok {
+				return fmt.Errorf("scene %d spawn %d references missing monsterId=%d", cfg.SceneId, spawn.SpawnId, spawn.MonsterId)
+			}
+		}
+		cm.monsterSceneConfigs[cfg.SceneId] = cfg
+	}
+
+	log.Infof("Loaded %d monster scene configs", len(cm.monsterSceneConfigs))
+	return nil
+}
+
+// GetMonsterConfig 获取怪物配置，未找到返回 nil
+func (cm *ConfigManager) GetMonsterConfig(monsterId uint32) *MonsterConfig {
+	if cm == nil || cm.monsterConfigs == nil {
+		return nil
+	}
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.monsterConfigs[monsterId]
+}
+
+// GetMonsterSceneConfig 获取场景刷怪配置，未找到返回 nil
+func (cm *ConfigManager) GetMonsterSceneConfig(sceneId uint32) *MonsterSceneConfig {
+	if cm == nil || cm.monsterSceneConfigs == nil {
+		return nil
+	}
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.monsterSceneConfigs[sceneId]
+}
+
+// loadBuffConfigs 加载Buff配置，并校验技能效果引用的BuffId
+func (cm *ConfigManager) loadBuffConfigs() error {
+	filePath := filepath.Join(cm.configPath, "buffconfig.json")
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.Warnf("buffconfig.json not found, using empty config")
+			cm.buffConfigs = make(map[uint32]*BuffConfig)
+			return nil
+		}
+		return fmt.Errorf("read buff config failed: %w", err)
+	}
+
+	var configs []*BuffConfig
+	if err := internal.Unmarshal(data, &configs); err != nil {
+		return fmt.Errorf("unmarshal buff config failed: %w", err)
+	}
+
+	// 注意：LoadAllConfigs 已经持有锁，这里不需要再次获取锁
+	cm.buffConfigs = make(map[uint32]*BuffConfig)
+	for _, cfg := range configs {
+		if cfg == nil {
+			continue
+		}
+		for _, state := range cfg.States {
+			// 0 为无效状态，死亡状态(1)只能由战斗结算设置
+			if state == 0 || state == 1 || state >= 64 {
+				return fmt.Errorf("buff %d has invalid state %d", cfg.BuffId, state)
+			}
+		}
+		cm.buffConfigs[cfg.BuffId] = cfg
+	}
+
+	for _, skillCfg := range cm.skillConfigs {
+		for _, effect := range skillCfg.Effects {
+			if effect == nil || (effect.Type != uint32(protocol.SkillResultType_SkillResultTypeAddBuff) &&
+				effect.Type != uint32(protocol.SkillResultType_SkillResultTypeRemoveBuff)) {
+				continue
+			}
+			if _, ok := cm.buffConfigs[effect.Value]; !ok {
+				return fmt.Errorf("skill %d references missing buffId=%d", skillCfg.SkillId, effect.Value)
+			}
+		}
+	}
+
+	log.Infof("Loaded %d buff configs", len(cm.buffConfigs))
+	return nil
+}
+
+// GetBuffConfig 获取Buff配置，未找到返回 nil
+func (cm *ConfigManager) GetBuffConfig(buffId uint32) *BuffConfig {
+	if cm == nil || cm.buffConfigs == nil {
+		return nil
+	}
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.buffConfigs[buffId]
+}
+
+// loadLevelConfigs 加载等级配置，等级需从1开始连续配置
+func (cm *ConfigManager) loadLevelConfigs() error {
+	filePath := filepath.Join(cm.configPath, "levelconfig.json")
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("read level config failed: %w", err)
+	}
+
+	var configs []*LevelConfig
+	if err := internal.Unmarshal(data, &configs); err != nil {
+		return fmt.Errorf("unmarshal level config failed: %w", err)
+	}
+
+	// 注意：LoadAllConfigs 已经持有锁，这里不需要再次获取锁
+	cm.levelConfigs = make(map[uint32]*LevelConfig)
+	cm.maxLevel = 0
+	for _, cfg := range configs {
+		if cfg == nil {
+			continue
+		}
+		if cfg.Level == 0 {
+			return fmt.Errorf("level config has invalid level 0")
+		}
+		for _, skillId := range cfg.UnlockSkillIds {
+			if _, ok := cm.skillConfigs[skillId]; !ok {
+				return fmt.Errorf("level %d unlocks missing skillId=%d", cfg.Level, skillId)
+			}
+		}
+		cm.levelConfigs[cfg.Level] = cfg
+		if cfg.Level > cm.maxLevel {
+			cm.maxLevel = cfg.Level
+		}
+	}
+	for level := uint32(1); level <= cm.maxLevel; level++ {
+		if _, ok := cm.levelConfigs[level]; !ok {
+			return fmt.Errorf("level config missing level %d", level)
+		}
+	}
+
+	log.Infof("Loaded %d level configs, maxLevel=%d", len(cm.levelConfigs), cm.maxLevel)
+	return nil
+}
+
+// GetLevelConfig 获取等级配置，未找到返回 nil
+func (cm *ConfigManager) GetLevelConfig(level uint32) *LevelConfig {
+	if cm == nil || cm.levelConfigs == nil {
+		return nil
+	}
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.levelConfigs[level]
+}
+
+// GetMaxLevel 获取满级等级
+func (cm *ConfigManager) GetMaxLevel() uint32 {
+	if cm == nil {
+		return 0
+	}
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.maxLevel
+}
+
+// loadItemConfigs 加载道具配置，并校验礼包内容与Buff引用
+func (cm *ConfigManager) loadItemConfigs() error {
+	filePath := filepath.Join(cm.configPath, "itemconfig.json")
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("read item config failed: %w", err)
+	}
+
+	var configs []*ItemConfig
+	if err := internal.Unmarshal(data, &configs); err != nil {
+		return fmt.Errorf("unmarshal item config failed: %w", err)
+	}
+
+	// 注意：LoadAllConfigs 已经持有锁，这里不需要再次获取锁
+	cm.itemConfigs = make(map[uint32]*ItemConfig)
+	for _, cfg := range configs {
+		if cfg == nil {
+			continue
+		}
+		if cfg.Type == uint32(protocol.ItemType_ItemTypeEquip) {
+			if cfg.Equip == nil || cfg.Equip.Slot == 0 || cfg.Equip.Slot > MaxEquipSlot {
+				return fmt.Errorf("equip item %d has invalid equip slot", cfg.ItemId)
+			}
+			if cfg.GetStackLimit() != 1 {
+				return fmt.Errorf("equip item %d must not be stackable", cfg.ItemId)
+			}
+		}
+		cm.itemConfigs[cfg.ItemId] = cfg
+	}
+
+	for _, cfg := range cm.itemConfigs {
+		if cfg.Use == nil {
+			continue
+		}
+		switch protocol.ItemUseType(cfg.Use.Type) {
+		case protocol.ItemUseType_ItemUseTypeGift:
+			for _, gift := range cfg.Use.Items {
+				if gift == nil {
+					continue
+				}
+				if _, ok := cm.itemConfigs[gift.ItemId]; !ok {
+					return fmt.Errorf("item %d gift references missing itemId=%d", cfg.ItemId, gift.ItemId)
+				}
+			}
+		case protocol.ItemUseType_ItemUseTypeAddBuff:
+			if _, ok := cm.buffConfigs[uint32(cfg.Use.Value)]; !ok {
+				return fmt.Errorf("item %d references missing buffId=%d", cfg.ItemId, cfg.Use.Value)
+			}
+		}
+	}
+
+	// 等级奖励在道具配置之后校验
+	for _, levelCfg := range cm.levelConfigs {
+		for _, reward := range levelCfg.Rewards {
+			if reward == nil || reward.Count == 0 {
+				return fmt.Errorf("level %d has invalid reward", levelCfg.Level)
+			}
+			if _, ok := cm.itemConfigs[reward.ItemId]; !ok {
+				return fmt.Errorf("level %d rewards missing itemId=%d", levelCfg.Level, reward.ItemId)
+			}
+		}
+	}
+
+	log.Infof("Loaded %d item configs", len(cm.itemConfigs))
+	return nil
+}
+
+// GetItemConfig 获取道具配置，未找到返回 nil
+func (cm *ConfigManager) GetItemConfig(itemId uint32) *ItemConfig {
+	if cm == nil || cm.itemConfigs == nil {
+		return nil
+	}
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.itemConfigs[itemId]
+}
+
+// loadDropConfigs 加载掉落配置，并校验掉落道具与怪物掉落引用
+func (cm *ConfigManager) loadDropConfigs() error {
+	filePath := filepath.Join(cm.configPath, "dropconfig.json")
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.Warnf("dropconfig.json not found, using empty config")
+			cm.dropConfigs = make(map[uint32]*DropConfig)
+			return nil
+		}
+		return fmt.Errorf("read drop config failed: %w", err)
+	}
+
+	var configs []*DropConfig
+	if err := internal.Unmarshal(data, &configs); err != nil {
+		return fmt.Errorf("unmarshal drop config failed: %w", err)
+	}
+
+	// 注意：LoadAllConfigs 已经持有锁，这里不需要再次获取锁
+	cm.dropConfigs = make(map[uint32]*DropConfig)
+	for _, cfg := range configs {
+		if cfg == nil {
+			continue
+		}
+		for _, item := range cfg.Guaranteed {
+			if item == nil || item.ItemId == 0 {
+				return fmt.Errorf("drop %d has empty guaranteed item", cfg.DropId)
+			}
+			if _, ok := cm.itemConfigs[item.ItemId]; !ok {
+				return fmt.Errorf("drop %d references missing itemId=%d", cfg.DropId, item.ItemId)
+			}
+		}
+		for _, item := range cfg.Pool {
+			if item == nil || item.ItemId == 0 {
+				continue
+			}
+			if _, ok := cm.itemConfigs[item.ItemId]; !ok {
+				return fmt.Errorf("drop %d references missing itemId=%d", cfg.DropId, item.ItemId)
+			}
+		}
+		cm.dropConfigs[cfg.DropId] = cfg
+	}
+
+	for _, monsterCfg := range cm.monsterConfigs {
+		for _, drop := range monsterCfg.Drops {
+			if drop == nil {
+				continue
+			}
+			if _, ok := cm.dropConfigs[drop.DropId]; !ok {
+				return fmt.Errorf("monster %d references missing dropId=%d", monsterCfg.MonsterId, drop.DropId)
+			}
+		}
+	}
+
+	log.Infof("Loaded %d drop configs", len(cm.dropConfigs))
+	return nil
+}
+
+// GetDropConfig 获取掉落配置，未找到返回 nil
+func (cm *ConfigManager) GetDropConfig(dropId uint32) *DropConfig {
+	if cm == nil || cm.dropConfigs == nil {
+		return nil
+	}
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.dropConfigs[dropId]
+}
+
+// loadFuBenConfigs 加载副本配置
+func (cm *ConfigManager) loadFuBenConfigs() error {
+	filePath := filepath.Join(cm.configPath, "fubenconfig.json")
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.Warnf("fubenconfig.json not found, using empty config")
+			cm.fubenConfigs = make(map[uint32]*FuBenConfig)
+			return nil
+		}
+		return fmt.Errorf("read fuben config failed: %w", err)
+	}
+
+	var configs []*FuBenConfig
+	if err := internal.Unmarshal(data, &configs); err != nil {
+		return fmt.Errorf("unmarshal fuben config failed: %w", err)
+	}
+
+	// 注意：LoadAllConfigs 已经持有锁，这里不需要再次获取锁
+	cm.fubenConfigs = make(map[uint32]*FuBenConfig)
+	for _, cfg := range configs {
+		if cfg == nil {
+			continue
+		}
+		if cfg.FuBenId == 0 {
+			return fmt.Errorf("fuben id 0 is reserved for the default fuben")
+		}
+		if len(cfg.SceneIds) == 0 {
+			return fmt.Errorf("fuben %d has no scene", cfg.FuBenId)
+		}
+		for _, sceneId := range cfg.SceneIds {
+			if _, ok := cm.sceneConfigs[sceneId]; !ok {
+				return fmt.Errorf("fuben %d references missing sceneId=%d", cfg.FuBenId, sceneId)
+			}
+		}
+		cm.fubenConfigs[cfg.FuBenId] = cfg
+	}
+
+	log.Infof("Loaded %d fuben configs", len(cm.fubenConfigs))
+	return nil
+}
+
+// GetFuBenConfig 获取副本配置，未找到返回 nil
+func (cm *ConfigManager) GetFuBenConfig(fubenId uint32) *FuBenConfig {
+	if cm == nil || cm.fubenConfigs == nil {
+		return nil
+	}
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.fubenConfigs[fubenId]
+}
+
+// loadAuctionConfig 加载拍卖行配置，文件不存在时拍卖行不开放
+func (cm *ConfigManager) loadAuctionConfig() error {
+	filePath := filepath.Join(cm.configPath, "auctionconfig.json")
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.Warnf("auctionconfig.json not found, auction house closed")
+			cm.auctionConfig = nil
+			return nil
+		}
+		return fmt.Errorf("read auction config failed: %w", err)
+	}
+
+	var cfg AuctionConfig
+	if err := internal.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("unmarshal auction config failed: %w", err)
+	}
+	currency, ok := cm.itemConfigs[cfg.CurrencyItemId]
+	if !ok {
+		return fmt.Errorf("auction currency references missing itemId=%d", cfg.CurrencyItemId)
+	}
+	if currency.Type != uint32(protocol.ItemType_ItemTypeCurrency) {
+		return fmt.Errorf("auction currency item %d is not a currency", cfg.CurrencyItemId)
+	}
+	if cfg.MinPrice <= 0 || cfg.MaxPrice < cfg.MinPrice {
+		return fmt.Errorf("auction price range [%d, %d] invalid", cfg.MinPrice, cfg.MaxPrice)
+	}
+	if len(cfg.DurationHours) == 0 {
+		return fmt.Errorf("auction durationHours is empty")
+	}
+	for _, h := range cfg.DurationHours {
+		if h == 0 {
+			return fmt.Errorf("auction durationHours contains 0")
+		}
+	}
+	if cfg.FeeRate > 10000 || cfg.TaxRate > 10000 {
+		return fmt.Errorf("auction feeRate/taxRate must be <= 10000")
+	}
+	if cfg.MaxPageSize == 0 {
+		return fmt.Errorf("auction maxPageSize must be > 0")
+	}
+	if cfg.Suspicious == nil {
+		cfg.Suspicious = &AuctionSuspiciousConfig{}
+	}
+
+	// 注意：LoadAllConfigs 已经持有锁，这里不需要再次获取锁
+	cm.auctionConfig = &cfg
+	log.Infof("Loaded auction config, currencyItemId=%d", cfg.CurrencyItemId)
+	return nil
+}
+
+// GetAuctionConfig 获取拍卖行配置，未配置时返回 nil（拍卖行不开放）
+func (cm *ConfigManager) GetAuctionConfig() *AuctionConfig {
+	if cm == nil {
+		return nil
+	}
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.auctionConfig
+}
+
+// loadChatConfig 加载聊天配置，文件不存在时聊天不开放
+func (cm *ConfigManager) loadChatConfig() error {
+	filePath := filepath.Join(cm.configPath, "chatconfig.json")
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.Warnf("chatconfig.json not found, chat closed")
+			cm.chatConfig = nil
+			return nil
+		}
+		return fmt.Errorf("read chat config failed: %w", err)
+	}
+
+	var cfg ChatConfig
+	if err := internal.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("unmarshal chat config failed: %w", err)
+	}
+	if cfg.MaxLength == 0 {
+		return fmt.Errorf("chat maxLength must be > 0")
+	}
+	for _, ch := range cfg.Channels {
+		if ch == nil {
+			return fmt.Errorf("chat channel config is nil")
+		}
+		if _, ok := protocol.ChatChannel_name[int32(ch.Channel)]; !ok || ch.Channel == uint32(protocol.ChatChannel_ChatChannelNil) {
+			return fmt.Errorf("chat channel %d invalid", ch.Channel)
+		}
+		if ch.CooldownMs < 0 {
+			return fmt.Errorf("chat channel %d cooldownMs must be >= 0", ch.Channel)
+		}
+	}
+	if cfg.Replacement == "" {
+		cfg.Replacement = "*"
+	}
+
+	// 注意：LoadAllConfigs 已经持有锁，这里不需要再次获取锁
+	cm.chatConfig = &cfg
+	log.Infof("Loaded chat config, channels=%d sensitiveWords=%d", len(cfg.Channels), len(cfg.SensitiveWords))
+	return nil
+}
+
+// GetChatConfig 获取聊天配置，未配置时返回 nil（聊天不开放）
+func (cm *ConfigManager) GetChatConfig() *ChatConfig {
+	if cm == nil {
+		return nil
+	}
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.chatConfig
+}
@@ -0,0 +1,25 @@
+/**
+ * @Author: zjj
+ * @Date: 2025/12/29
+ * @Desc: 掉落配置
+**/
+
+package jsonconf
+
+// DropConfig 掉落配置
+type DropConfig struct {
+	DropId     uint32      `json:"dropId"`     // 掉落ID
+	Guaranteed []*DropItem `json:"guaranteed"` // 必掉道具
+	Rolls      uint32      `json:"rolls"`      // 权重池抽取次数
+	Pool       []*DropItem `json:"pool"`       // 权重池，ItemId 为 0 表示本次不掉落
+	ProtectMs  uint32      `json:"protectMs"`  // 归属保护时间（毫秒），0使用默认值
+	ExpireMs   uint32      `json:"expireMs"`   // 地面存在时间（毫秒），0使用默认值
+}
+
+// DropItem 掉落道具
+type DropItem struct {
+	ItemId   uint32 `json:"itemId"`   // 道具ID
+	MinCount uint32 `json:"minCount"` // 最小数量
+	MaxCount uint32 `json:"maxCount"` // 最大数量，小于最小数量时按最小数量
+	Weight   uint32 `json:"weight"`   // 权重，仅权重池有效
+}
@@ -0,0 +1,17 @@
+/**
+ * @Author: zjj
+ * @Date: 2025/12/31
+ * @Desc: 副本配置
+**/
+
+package jsonconf
+
+// FuBenConfig 副本配置，每次进入按配置创建独立的限时副本实例
+type FuBenConfig struct {
+	FuBenId        uint32   `json:"fubenId"`        // 副本ID
+	Name           string   `json:"name"`           // 副本名称
+	SceneIds       []uint32 `json:"sceneIds"`       // 副本包含的场景，第一个为入口场景
+	MaxPlayers     int      `json:"maxPlayers"`     // 最大玩家数，0表示无限制
+	MaxDurationSec uint32   `json:"maxDurationSec"` // 实例最长存在时间（秒），0表示不限时
+	Difficulty     uint32   `json:"difficulty"`     // 难度，影响怪物掉落，0按普通难度
+}
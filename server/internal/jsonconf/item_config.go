@@ -0,0 +1,76 @@
+/**
+ * @Author: zjj
+ * @Date: 2025/12/28
+ * @Desc: 道具配置
+**/
+
+package jsonconf
+
+import "postapocgame/server/internal/protocol"
+
+// ItemConfig 道具配置
+type ItemConfig struct {
+	ItemId     uint32           `json:"itemId"`     // 道具ID
+	Name       string           `json:"name"`       // 道具名称
+	Type       uint32           `json:"type"`       // 类型，见 protocol.ItemType
+	Quality    uint32           `json:"quality"`    // 品质
+	StackLimit uint32           `json:"stackLimit"` // 堆叠上限，0/1表示不可堆叠
+	Bind       uint32           `json:"bind"`       // 绑定规则：0=不绑定 1=获得即绑定
+	Discard    bool             `json:"discard"`    // 是否可丢弃
+	Use        *ItemUseConfig   `json:"use"`        // 使用效果，为空表示不可使用
+	Equip      *ItemEquipConfig `json:"equip"`      // 装备属性，仅装备类道具配置
+	Desc       string           `json:"desc"`       // 描述
+}
+
+// ItemUseConfig 道具使用效果
+type ItemUseConfig struct {
+	Type     uint32          `json:"type"`     // 效果类型，见 protocol.ItemUseType
+	Value    int64           `json:"value"`    // 效果值（经验值/BuffId）
+	Duration uint32          `json:"duration"` // Buff持续时间（毫秒），0使用Buff配置
+	Items    []*ItemGiftItem `json:"items"`    // 礼包内容
+}
+
+// MaxEquipSlot 最大装备槽位，槽位取值 1~MaxEquipSlot，见 protocol.EquipSlot
+const MaxEquipSlot = uint32(protocol.EquipSlot_EquipSlotAccessory)
+
+// ItemEquipConfig 装备配置
+type ItemEquipConfig struct {
+	Slot       uint32            `json:"slot"`       // 装备槽位，见 protocol.EquipSlot
+	Jobs       []uint32          `json:"jobs"`       // 可穿戴职业，为空表示不限
+	Level      uint32            `json:"level"`      // 穿戴等级
+	Appearance uint32            `json:"appearance"` // 外观ID，0表示不影响外观
+	Attrs      []*ItemAttrConfig `json:"attrs"`      // 装备属性
+}
+
+// ItemAttrConfig 装备属性
+type ItemAttrConfig struct {
+	Type  uint32 `json:"type"`  // 属性类型，见 attrdef
+	Value int64  `json:"value"` // 属性值
+}
+
+// CanWearByJob 职业是否可以穿戴
+func (c *ItemEquipConfig) CanWearByJob(job uint32) bool {
+	if len(c.Jobs) == 0 {
+		return true
+	}
+	for _, j := range c.Jobs {
+		if j == job {
+			return true
+		}
+	}
+	return false
+}
+
+// ItemGiftItem 礼包道具
+type ItemGiftItem struct {
+	ItemId uint32 `json:"itemId"`
+	Count  uint32 `json:"count"`
+}
+
+// GetStackLimit 获取堆叠上限（至少为1）
+func (c *ItemConfig) GetStackLimit() uint32 {
+	if c.StackLimit == 0 {
+		return 1
+	}
+	return c.StackLimit
+}
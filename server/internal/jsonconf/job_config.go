@@ -8,6 +8,17 @@ package jsonconf
 
 // JobConfig 职业配置
 type JobConfig struct {
-	JobId    uint32   `json:"jobId"`    // 职业ID
-	SkillIds []uint32 `json:"skillIds"` // 初始技能ID列表
+	JobId      uint32   `json:"jobId"`      // 职业ID
+	SkillIds   []uint32 `json:"skillIds"`   // 初始技能ID列表
+	EquipSlots []uint32 `json:"equipSlots"` // 可用装备槽位，见 protocol.EquipSlot
+}
+
+// HasEquipSlot 职业是否可以使用该装备槽位
+func (c *JobConfig) HasEquipSlot(slot uint32) bool {
+	for _, s := range c.EquipSlots {
+		if s == slot {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,22 @@
+/**
+ * @Author: zjj
+ * @Date: 2025/12/27
+ * @Desc: 等级配置
+**/
+
+package jsonconf
+
+// LevelConfig 等级配置
+type LevelConfig struct {
+	Level          uint32             `json:"level"`          // 等级
+	Exp            int64              `json:"exp"`            // 升到下一级所需经验，0表示满级
+	Attrs          []*LevelAttrConfig `json:"attrs"`          // 该等级的基础属性（总值）
+	UnlockSkillIds []uint32           `json:"unlockSkillIds"` // 达到该等级时解锁的技能
+	Rewards        []*ItemGiftItem    `json:"rewards"`        // 升到该等级时通过系统邮件发放的道具
+}
+
+// LevelAttrConfig 等级属性
+type LevelAttrConfig struct {
+	Type  uint32 `json:"type"`  // 属性类型，见 attrdef
+	Value int64  `json:"value"` // 属性值
+}
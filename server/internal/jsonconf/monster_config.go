@@ -0,0 +1,83 @@
+/**
+ * @Author: zjj
+ * @Date: 2025/12/26
+ * @Desc: 怪物配置
+**/
+
+package jsonconf
+
+// MonsterConfig 怪物配置
+type MonsterConfig struct {
+	MonsterId  uint32           `json:"monsterId"`  // 怪物ID
+	Name       string           `json:"name"`       // 怪物名称
+	Level      uint32           `json:"level"`      // 等级
+	MaxHP      int64            `json:"maxHp"`      // 最大生命值
+	MaxMP      int64            `json:"maxMp"`      // 最大魔法值
+	Attack     int64            `json:"attack"`     // 攻击力
+	Defense    int64            `json:"defense"`    // 防御力
+	Speed      int64            `json:"speed"`      // 移动速度（像素/秒）
+	CritRate   int64            `json:"critRate"`   // 暴击率（万分比）
+	CritDamage int64            `json:"critDamage"` // 暴击伤害（万分比）
+	DodgeRate  int64            `json:"dodgeRate"`  // 闪避率（万分比）
+	HitRate    int64            `json:"hitRate"`    // 命中率（万分比）
+	SkillIds   []uint32         `json:"skillIds"`   // 技能ID列表
+	Exp        int64            `json:"exp"`        // 击杀经验
+	Drops      []*MonsterDrop   `json:"drops"`      // 掉落配置，按副本难度选择
+	AI         *MonsterAIConfig `json:"ai"`         // AI配置，为空表示无AI
+}
+
+// MonsterDrop 怪物掉落，Difficulty 为 0 表示未单独配置的难度通用
+type MonsterDrop struct {
+	Difficulty uint32 `json:"difficulty"` // 副本难度: 1=普通 2=精英 3=地狱
+	DropId     uint32 `json:"dropId"`     // 掉落ID，见 dropconfig
+}
+
+// GetDropId 获取指定难度的掉落ID，未配置返回 0
+func (c *MonsterConfig) GetDropId(difficulty uint32) uint32 {
+	var fallback uint32
+	for _, drop := range c.Drops {
+		if drop == nil {
+			continue
+		}
+		if drop.Difficulty == difficulty {
+			return drop.DropId
+		}
+		if drop.Difficulty == 0 {
+			fallback = drop.DropId
+		}
+	}
+	return fallback
+}
+
+// MonsterAIConfig 怪物AI配置
+type MonsterAIConfig struct {
+	Type            uint32         `json:"type"`            // AI类型，见 protocol.AIType
+	AggroRange      uint32         `json:"aggroRange"`      // 索敌范围（格子）
+	LeashRange      uint32         `json:"leashRange"`      // 脱战距离（格子，相对出生点）
+	PatrolPath      []*PatrolPoint `json:"patrolPath"`      // 巡逻路径，为空则原地待机
+	PatrolWaitMs    uint32         `json:"patrolWaitMs"`    // 巡逻点停留时间（毫秒）
+	ThinkIntervalMs uint32         `json:"thinkIntervalMs"` // 决策间隔（毫秒）
+}
+
+// PatrolPoint 巡逻点，相对出生点的格子偏移
+type PatrolPoint struct {
+	Dx int32 `json:"dx"`
+	Dy int32 `json:"dy"`
+}
+
+// MonsterSceneConfig 场景刷怪配置
+type MonsterSceneConfig struct {
+	SceneId uint32               `json:"sceneId"` // 场景ID
+	Spawns  []*MonsterSpawnPoint `json:"spawns"`  // 刷怪点列表
+}
+
+// MonsterSpawnPoint 刷怪点
+type MonsterSpawnPoint struct {
+	SpawnId   uint32 `json:"spawnId"`   // 刷怪点ID（场景内唯一）
+	MonsterId uint32 `json:"monsterId"` // 怪物ID
+	X         uint32 `json:"x"`         // 中心点X（格子坐标）
+	Y         uint32 `json:"y"`         // 中心点Y（格子坐标）
+	Radius    uint32 `json:"radius"`    // 随机半径（格子），0表示固定在中心点
+	MaxAlive  uint32 `json:"maxAlive"`  // 最大存活数量
+	RespawnMs uint32 `json:"respawnMs"` // 死亡后重生间隔（毫秒）
+}
@@ -34,6 +34,13 @@ func WithTCPClientOptionNetworkMessageHandler(handler INetworkMessageHandler) TC
 	}
 }
 
+// WithTCPClientOptionEncrypt 连接建立后先与服务端握手，之后的消息全部加密
+func WithTCPClientOptionEncrypt(enable bool) TCPClientOption {
+	return func(client *TCPClient) {
+		client.encrypt = enable
+	}
+}
+
 // TCPClient TCP客户端
 type TCPClient struct {
 	addr string
@@ -45,6 +52,8 @@ type TCPClient struct {
 	Multiplier      float64
 	MaxRetries      int
 
+	encrypt bool // 是否握手加密
+
 	conn      IConnection
 	connected atomic.Bool
 	stopping  atomic.Bool
@@ -126,8 +135,16 @@ func (c *TCPClient) doConnect(_ context.Context) error {
 		return customerr.Wrap(err, int32(protocol.ErrorCode_Internal_Error))
 	}
 
+	tcpConn := NewTCPConnection(conn)
+	if c.encrypt {
+		if err := ClientHandshake(tcpConn); err != nil {
+			_ = conn.Close()
+			return customerr.Wrap(err, int32(protocol.ErrorCode_Internal_Error))
+		}
+	}
+
 	c.mu.Lock()
-	c.conn = NewTCPConnection(conn)
+	c.conn = tcpConn
 	c.mu.Unlock()
 
 	c.connected.Store(true)
@@ -4,13 +4,16 @@ import (
 	"context"
 	"net"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // TCPConnection TCP连接实现
 type TCPConnection struct {
-	conn net.Conn
-	meta interface{}
-	mu   sync.RWMutex
+	conn   net.Conn
+	meta   interface{}
+	mu     sync.RWMutex
+	cipher atomic.Pointer[SessionCipher] // 握手完成后设置，为空表示明文
 }
 
 // NewTCPConnection 创建TCP连接
@@ -22,7 +25,12 @@ func NewTCPConnection(conn net.Conn) *TCPConnection {
 
 // SendMessage 发送消息
 func (tc *TCPConnection) SendMessage(msg *Message) error {
-	data, err := defaultCompressionCodec.EncodeMessageWithCompression(msg)
+	sc := tc.cipher.Load()
+	if sc != nil {
+		sc.sendMu.Lock()
+		defer sc.sendMu.Unlock()
+	}
+	data, err := defaultCompressionCodec.encodeMessage(msg, sc)
 	if err != nil {
 		return err
 	}
@@ -33,7 +41,7 @@ func (tc *TCPConnection) SendMessage(msg *Message) error {
 
 // ReceiveMessage 接收消息
 func (tc *TCPConnection) ReceiveMessage(_ context.Context) (*Message, error) {
-	msg, err := defaultCompressionCodec.DecodeMessageWithCompression(tc.conn)
+	msg, err := defaultCompressionCodec.decodeMessage(tc.conn, tc.cipher.Load())
 	if err != nil {
 		return nil, err
 	}
@@ -41,6 +49,19 @@ func (tc *TCPConnection) ReceiveMessage(_ context.Context) (*Message, error) {
 	return msg, nil
 }
 
+// IsEncrypted 是否已完成握手进入加密模式
+func (tc *TCPConnection) IsEncrypted() bool {
+	return tc.cipher.Load() != nil
+}
+
+func (tc *TCPConnection) setCipher(sc *SessionCipher) {
+	tc.cipher.Store(sc)
+}
+
+func (tc *TCPConnection) setReadDeadline(t time.Time) error {
+	return tc.conn.SetReadDeadline(t)
+}
+
 // Close 关闭连接
 func (tc *TCPConnection) Close() error {
 	return tc.conn.Close()
@@ -82,6 +82,11 @@ func NewCompressionCodec(config *CompressionConfig) *CompressionCodec {
 }
 
 func (cc *CompressionCodec) EncodeMessageWithCompression(msg *Message) ([]byte, error) {
+	return cc.encodeMessage(msg, nil)
+}
+
+// encodeMessage 先压缩后加密，sc 不为空时调用方需持有 sc.sendMu 直到数据写出
+func (cc *CompressionCodec) encodeMessage(msg *Message, sc *SessionCipher) ([]byte, error) {
 	if msg == nil {
 		return nil, ErrInvalidMessage
 	}
@@ -100,6 +105,12 @@ func (cc *CompressionCodec) EncodeMessageWithCompression(msg *Message) ([]byte,
 		}
 	}
 
+	// 握手消息本身用于协商密钥，始终明文
+	if sc != nil && msg.Type != MsgTypeHandshake {
+		flags |= FlagEncrypted
+		payload = sc.seal(msg.Type, flags, payload)
+	}
+
 	totalLen := 2 + len(payload)
 	if totalLen > cc.codec.maxFrameSize {
 		return nil, ErrFrameTooLarge
@@ -116,6 +127,11 @@ func (cc *CompressionCodec) EncodeMessageWithCompression(msg *Message) ([]byte,
 }
 
 func (cc *CompressionCodec) DecodeMessageWithCompression(reader io.Reader) (*Message, error) {
+	return cc.decodeMessage(reader, nil)
+}
+
+// decodeMessage 先解密后解压，sc 不为空时拒绝明文消息
+func (cc *CompressionCodec) decodeMessage(reader io.Reader, sc *SessionCipher) (*Message, error) {
 	header := make([]byte, 4)
 	if _, err := io.ReadFull(reader, header); err != nil {
 		return nil, err
@@ -138,6 +154,19 @@ func (cc *CompressionCodec) DecodeMessageWithCompression(reader io.Reader) (*Mes
 	flags := body[1]
 	payload := body[2:]
 
+	if flags&FlagEncrypted != 0 {
+		if sc == nil {
+			return nil, ErrInvalidMessage
+		}
+		plain, err := sc.open(msgType, flags, payload)
+		if err != nil {
+			return nil, err
+		}
+		payload = plain
+	} else if sc != nil {
+		return nil, ErrNotEncrypted
+	}
+
 	if flags&FlagCompressed != 0 {
 		decompressed, err := cc.decompress(payload)
 		if err != nil {
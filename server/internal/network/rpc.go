@@ -0,0 +1,249 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/2
+ * @Desc: 基于 RPCRequest/RPCResponse 编解码的请求-响应式RPC
+**/
+
+package network
+
+import (
+	"context"
+	"errors"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/pkg/routine"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultRPCTimeout 调用方 ctx 未设置超时时使用的默认超时
+const DefaultRPCTimeout = 5 * time.Second
+
+// IRPCSender RPC消息发送方，IConnection 与 ITCPClient 都满足该接口
+type IRPCSender interface {
+	SendMessage(msg *Message) error
+}
+
+// RPCHandlerFunc RPC请求处理函数，返回的错误码与错误信息会回传给调用方
+type RPCHandlerFunc func(ctx context.Context, req *RPCRequest) ([]byte, error)
+
+// RPCClient 发起RPC调用，按 requestId 关联响应
+type RPCClient struct {
+	codec    *Codec
+	senderMu sync.RWMutex
+	sender   IRPCSender
+
+	nextId  atomic.Uint32
+	mu      sync.Mutex
+	pending map[uint32]chan *RPCResponse
+}
+
+// NewRPCClient 创建RPC客户端，sender 可为空，之后通过 SetSender 设置
+func NewRPCClient(sender IRPCSender) *RPCClient {
+	return &RPCClient{
+		codec:   DefaultCodec(),
+		sender:  sender,
+		pending: make(map[uint32]chan *RPCResponse),
+	}
+}
+
+// SetSender 设置发送连接（断线重连后更新）
+func (c *RPCClient) SetSender(sender IRPCSender) {
+	c.senderMu.Lock()
+	defer c.senderMu.Unlock()
+	c.sender = sender
+}
+
+// Call 发送RPC请求并等待响应，超时由 ctx 控制
+func (c *RPCClient) Call(ctx context.Context, sessionId string, msgId uint16, data []byte) ([]byte, error) {
+	c.senderMu.RLock()
+	sender := c.sender
+	c.senderMu.RUnlock()
+	if sender == nil {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Rpc_ConnClosed), "rpc sender not set, msgId=%d", msgId)
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultRPCTimeout)
+		defer cancel()
+	}
+
+	requestId := c.nextId.Add(1)
+	if requestId == 0 {
+		requestId = c.nextId.Add(1)
+	}
+	respCh := make(chan *RPCResponse, 1)
+	c.mu.Lock()
+	c.pending[requestId] = respCh
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, requestId)
+		c.mu.Unlock()
+	}()
+
+	buf := c.codec.EncodeRPCRequest(&RPCRequest{
+		RequestId: requestId,
+		SessionId: sessionId,
+		MsgId:     msgId,
+		Data:      data,
+	})
+	defer PutBuffer(buf)
+	message := GetMessage()
+	message.Type = MsgTypeRPCRequest
+	message.Payload = buf
+	defer PutMessage(message)
+	if err := sender.SendMessage(message); err != nil {
+		return nil, customerr.Wrap(err, int32(protocol.ErrorCode_Rpc_ConnClosed))
+	}
+
+	select {
+	case resp, ok := <-respCh:
+		if !ok {
+			return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Rpc_ConnClosed), "rpc connection closed, msgId=%d", msgId)
+		}
+		if resp.Code != 0 {
+			return nil, customerr.NewErrorByCode(resp.Code, "%s", resp.Data)
+		}
+		return resp.Data, nil
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Network_Timeout), "rpc timeout, msgId=%d requestId=%d", msgId, requestId)
+		}
+		return nil, customerr.Wrap(ctx.Err())
+	}
+}
+
+// CallProto 以 proto 消息发起RPC调用，响应解码到 resp（可为空）
+func (c *RPCClient) CallProto(ctx context.Context, sessionId string, msgId uint16, req proto.Message, resp proto.Message) error {
+	data, err := proto.Marshal(req)
+	if err != nil {
+		return customerr.Wrap(err, int32(protocol.ErrorCode_Internal_Error))
+	}
+	respData, err := c.Call(ctx, sessionId, msgId, data)
+	if err != nil {
+		return err
+	}
+	if resp == nil {
+		return nil
+	}
+	if err := proto.Unmarshal(respData, resp); err != nil {
+		return customerr.Wrap(err, int32(protocol.ErrorCode_Internal_Error))
+	}
+	return nil
+}
+
+// HandleResponse 处理 MsgTypeRPCResponse 消息，唤醒等待中的调用
+func (c *RPCClient) HandleResponse(msg *Message) error {
+	resp, err := c.codec.DecodeRPCResponse(msg.Payload)
+	if err != nil {
+		return customerr.Wrap(err)
+	}
+	c.mu.Lock()
+	respCh, ok := c.pending[resp.RequestId]
+	delete(c.pending, resp.RequestId)
+	c.mu.Unlock()
+	if !ok {
+		// 调用方已超时返回
+		log.Debugf("rpc response dropped, requestId=%d", resp.RequestId)
+		return nil
+	}
+	respCh <- resp
+	return nil
+}
+
+// CancelAll 连接断开时让全部等待中的调用立即失败
+func (c *RPCClient) CancelAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for requestId, respCh := range c.pending {
+		close(respCh)
+		delete(c.pending, requestId)
+	}
+}
+
+// RPCServer 按 msgId 分发RPC请求并回写响应
+type RPCServer struct {
+	codec    *Codec
+	timeout  time.Duration
+	mu       sync.RWMutex
+	handlers map[uint16]RPCHandlerFunc
+}
+
+// NewRPCServer 创建RPC服务端
+func NewRPCServer() *RPCServer {
+	return &RPCServer{
+		codec:    DefaultCodec(),
+		timeout:  DefaultRPCTimeout,
+		handlers: make(map[uint16]RPCHandlerFunc),
+	}
+}
+
+// Register 注册RPC处理函数
+func (s *RPCServer) Register(msgId uint16, handler RPCHandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.handlers[msgId]; ok {
+		log.Warnf("rpc handler overwritten, msgId=%d", msgId)
+	}
+	s.handlers[msgId] = handler
+}
+
+// RegisterRPCProtoHandler 注册 proto 类型的RPC处理函数，请求自动解码、响应自动编码
+func RegisterRPCProtoHandler[Req proto.Message, Resp proto.Message](s *RPCServer, msgId uint16, handler func(ctx context.Context, sessionId string, req Req) (Resp, error)) {
+	s.Register(msgId, func(ctx context.Context, rpcReq *RPCRequest) ([]byte, error) {
+		var zero Req
+		req := zero.ProtoReflect().New().Interface().(Req)
+		if err := proto.Unmarshal(rpcReq.Data, req); err != nil {
+			return nil, customerr.Wrap(err, int32(protocol.ErrorCode_Param_Invalid))
+		}
+		resp, err := handler(ctx, rpcReq.SessionId, req)
+		if err != nil {
+			return nil, err
+		}
+		return proto.Marshal(resp)
+	})
+}
+
+// HandleRequest 处理 MsgTypeRPCRequest 消息，处理函数在独立协程中执行，不阻塞连接的接收循环
+func (s *RPCServer) HandleRequest(ctx context.Context, conn IConnection, msg *Message) error {
+	// 接收循环传入的 ctx 在 HandleRequest 返回后可能被取消，处理协程只继承其中的值
+	ctx = context.WithoutCancel(ctx)
+	req, err := s.codec.DecodeRPCRequest(msg.Payload)
+	if err != nil {
+		return customerr.Wrap(err)
+	}
+	s.mu.RLock()
+	handler, ok := s.handlers[req.MsgId]
+	s.mu.RUnlock()
+
+	routine.GoV2(func() error {
+		resp := &RPCResponse{RequestId: req.RequestId}
+		if !ok {
+			resp.Code = int32(protocol.ErrorCode_Rpc_MethodNotFound)
+			resp.Data = []byte("rpc method not found")
+		} else {
+			handleCtx, cancel := context.WithTimeout(ctx, s.timeout)
+			data, err := handler(handleCtx, req)
+			cancel()
+			if err != nil {
+				resp.Code = customerr.GetErrCode(err)
+				if resp.Code <= 0 {
+					resp.Code = int32(protocol.ErrorCode_Internal_Error)
+				}
+				resp.Data = []byte(customerr.GetErrMsgByErr(err))
+			} else {
+				resp.Data = data
+			}
+		}
+		if err := NewBaseMessageSender(conn).SendRPCResponse(resp); err != nil {
+			log.Warnf("send rpc response failed, msgId=%d requestId=%d err=%v", req.MsgId, req.RequestId, err)
+		}
+		return nil
+	})
+	return nil
+}
@@ -0,0 +1,234 @@
+/**
+ * @Author: zjj
+ * @Date: 2025/12/31
+ * @Desc: 客户端链路加密：X25519 密钥交换 + AES-GCM 会话加密
+**/
+
+package network
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// 握手消息格式: [版本(1字节)][X25519公钥(32字节)]
+// 客户端连接后先发送握手消息，服务端回复自己的公钥，双方各自派生两个方向的密钥
+// 握手完成后除握手消息外的所有消息都必须加密，明文消息直接断开
+//
+// 加密后的 payload 格式: [seq(8字节)][密文+tag]
+// seq 为发送方向的递增序号（从1开始），同时作为 GCM nonce 的后 8 字节，
+// 接收方要求 seq 严格等于上一条+1，用于拒绝重放、丢弃与乱序的消息
+// 消息类型与 flags 作为附加认证数据，防止被篡改
+const (
+	HandshakeVersion = 0x01
+	HandshakeTimeout = 10 * time.Second
+
+	handshakeKeySize = 32
+	handshakeMsgSize = 1 + handshakeKeySize
+	sessionKeySize   = 32 // AES-256
+	seqSize          = 8
+
+	keyInfoClientToServer = "postapoc c2s"
+	keyInfoServerToClient = "postapoc s2c"
+)
+
+var (
+	ErrHandshakeFailed = errors.New("handshake failed")
+	ErrNotEncrypted    = errors.New("plain message on encrypted connection")
+	ErrReplayedMessage = errors.New("replayed or out of order message")
+)
+
+// SessionCipher 单个连接的会话加密状态，发送与接收方向使用独立密钥与序号
+type SessionCipher struct {
+	sendAEAD cipher.AEAD
+	recvAEAD cipher.AEAD
+
+	sendMu  sync.Mutex // 保证序号分配与写出顺序一致
+	sendSeq uint64
+	recvSeq uint64 // 只在连接的接收协程中访问
+}
+
+func newSessionCipher(sendKey, recvKey []byte) (*SessionCipher, error) {
+	sendAEAD, err := newAEAD(sendKey)
+	if err != nil {
+		return nil, err
+	}
+	recvAEAD, err := newAEAD(recvKey)
+	if err != nil {
+		return nil, err
+	}
+	return &SessionCipher{sendAEAD: sendAEAD, recvAEAD: recvAEAD}, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal 加密 payload，调用方需持有 sendMu 直到数据写出
+func (sc *SessionCipher) seal(msgType, flags byte, payload []byte) []byte {
+	sc.sendSeq++
+	out := make([]byte, seqSize, seqSize+len(payload)+sc.sendAEAD.Overhead())
+	binary.BigEndian.PutUint64(out, sc.sendSeq)
+	return sc.sendAEAD.Seal(out, sc.nonce(sc.sendAEAD, sc.sendSeq), payload, []byte{msgType, flags})
+}
+
+// open 校验序号并解密 payload
+func (sc *SessionCipher) open(msgType, flags byte, data []byte) ([]byte, error) {
+	if len(data) < seqSize+sc.recvAEAD.Overhead() {
+		return nil, ErrInvalidMessage
+	}
+	seq := binary.BigEndian.Uint64(data[:seqSize])
+	if seq != sc.recvSeq+1 {
+		return nil, ErrReplayedMessage
+	}
+	plain, err := sc.recvAEAD.Open(nil, sc.nonce(sc.recvAEAD, seq), data[seqSize:], []byte{msgType, flags})
+	if err != nil {
+		return nil, ErrInvalidMessage
+	}
+	sc.recvSeq = seq
+	return plain, nil
+}
+
+func (sc *SessionCipher) nonce(aead cipher.AEAD, seq uint64) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint64(nonce[len(nonce)-seqSize:], seq)
+	return nonce
+}
+
+// secureConnection 支持握手后切换为加密模式的连接
+type secureConnection interface {
+	IConnection
+	setCipher(sc *SessionCipher)
+	setReadDeadline(t time.Time) error
+}
+
+// ServerHandshake 服务端等待客户端握手并回复公钥，成功后连接切换为加密模式
+func ServerHandshake(conn IConnection) error {
+	sConn, ok := conn.(secureConnection)
+	if !ok {
+		return fmt.Errorf("%w: connection %T not support encryption", ErrHandshakeFailed, conn)
+	}
+	_ = sConn.setReadDeadline(time.Now().Add(HandshakeTimeout))
+	msg, err := sConn.ReceiveMessage(context.Background())
+	_ = sConn.setReadDeadline(time.Time{})
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrHandshakeFailed, err)
+	}
+	clientPub, err := parseHandshake(msg)
+	if err != nil {
+		return err
+	}
+
+	priv, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrHandshakeFailed, err)
+	}
+	serverPub := priv.PublicKey().Bytes()
+	c2s, s2c, err := deriveSessionKeys(priv, clientPub, clientPub, serverPub)
+	if err != nil {
+		return err
+	}
+	sc, err := newSessionCipher(s2c, c2s)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrHandshakeFailed, err)
+	}
+
+	// 回复必须以明文发出，之后才切换为加密模式
+	if err := sConn.SendMessage(buildHandshake(serverPub)); err != nil {
+		return fmt.Errorf("%w: %v", ErrHandshakeFailed, err)
+	}
+	sConn.setCipher(sc)
+	return nil
+}
+
+// ClientHandshake 客户端发起握手，成功后连接切换为加密模式
+// 需在启动接收协程之前调用
+func ClientHandshake(conn IConnection) error {
+	sConn, ok := conn.(secureConnection)
+	if !ok {
+		return fmt.Errorf("%w: connection %T not support encryption", ErrHandshakeFailed, conn)
+	}
+	priv, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrHandshakeFailed, err)
+	}
+	clientPub := priv.PublicKey().Bytes()
+	if err := sConn.SendMessage(buildHandshake(clientPub)); err != nil {
+		return fmt.Errorf("%w: %v", ErrHandshakeFailed, err)
+	}
+
+	_ = sConn.setReadDeadline(time.Now().Add(HandshakeTimeout))
+	msg, err := sConn.ReceiveMessage(context.Background())
+	_ = sConn.setReadDeadline(time.Time{})
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrHandshakeFailed, err)
+	}
+	serverPub, err := parseHandshake(msg)
+	if err != nil {
+		return err
+	}
+	c2s, s2c, err := deriveSessionKeys(priv, serverPub, clientPub, serverPub)
+	if err != nil {
+		return err
+	}
+	sc, err := newSessionCipher(c2s, s2c)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrHandshakeFailed, err)
+	}
+	sConn.setCipher(sc)
+	return nil
+}
+
+func buildHandshake(pub []byte) *Message {
+	payload := make([]byte, handshakeMsgSize)
+	payload[0] = HandshakeVersion
+	copy(payload[1:], pub)
+	return &Message{Type: MsgTypeHandshake, Payload: payload}
+}
+
+func parseHandshake(msg *Message) ([]byte, error) {
+	if msg.Type != MsgTypeHandshake || len(msg.Payload) != handshakeMsgSize {
+		return nil, fmt.Errorf("%w: unexpected message type=%d len=%d", ErrHandshakeFailed, msg.Type, len(msg.Payload))
+	}
+	if msg.Payload[0] != HandshakeVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrHandshakeFailed, msg.Payload[0])
+	}
+	return msg.Payload[1:], nil
+}
+
+// deriveSessionKeys 由 X25519 共享密钥经 HKDF-SHA256 派生两个方向的会话密钥
+// 双方公钥作为 salt，把密钥绑定到本次握手
+func deriveSessionKeys(priv *ecdh.PrivateKey, peerPub, clientPub, serverPub []byte) (c2s, s2c []byte, err error) {
+	peer, err := ecdh.X25519().NewPublicKey(peerPub)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrHandshakeFailed, err)
+	}
+	shared, err := priv.ECDH(peer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrHandshakeFailed, err)
+	}
+	salt := make([]byte, 0, len(clientPub)+len(serverPub))
+	salt = append(salt, clientPub...)
+	salt = append(salt, serverPub...)
+	if c2s, err = hkdf.Key(sha256.New, shared, salt, keyInfoClientToServer, sessionKeySize); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrHandshakeFailed, err)
+	}
+	if s2c, err = hkdf.Key(sha256.New, shared, salt, keyInfoServerToClient, sessionKeySize); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrHandshakeFailed, err)
+	}
+	return c2s, s2c, nil
+}
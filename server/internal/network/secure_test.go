@@ -0,0 +1,110 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+// newCipherPair 创建互为收发方向的一对会话加密状态
+func newCipherPair(t *testing.T) (client, server *SessionCipher) {
+	t.Helper()
+	c2s := bytes.Repeat([]byte{1}, sessionKeySize)
+	s2c := bytes.Repeat([]byte{2}, sessionKeySize)
+	client, err := newSessionCipher(c2s, s2c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err = newSessionCipher(s2c, c2s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, server
+}
+
+func TestSessionCipherRoundTrip(t *testing.T) {
+	client, server := newCipherPair(t)
+	for _, payload := range [][]byte{[]byte("hello"), {}, bytes.Repeat([]byte{0xAB}, 4096)} {
+		sealed := client.seal(byte(MsgTypeClient), FlagEncrypted, payload)
+		if len(payload) > 0 && bytes.Contains(sealed, payload) {
+			t.Fatal("sealed payload contains plaintext")
+		}
+		plain, err := server.open(byte(MsgTypeClient), FlagEncrypted, sealed)
+		if err != nil {
+			t.Fatalf("open: %v", err)
+		}
+		if !bytes.Equal(plain, payload) {
+			t.Fatalf("round trip got %x, want %x", plain, payload)
+		}
+	}
+}
+
+func TestSessionCipherRejectsReplayAndTamper(t *testing.T) {
+	client, server := newCipherPair(t)
+	first := client.seal(byte(MsgTypeClient), FlagEncrypted, []byte("first"))
+	if _, err := server.open(byte(MsgTypeClient), FlagEncrypted, first); err != nil {
+		t.Fatalf("open first: %v", err)
+	}
+	if _, err := server.open(byte(MsgTypeClient), FlagEncrypted, first); !errors.Is(err, ErrReplayedMessage) {
+		t.Fatalf("replayed nonce got %v", err)
+	}
+
+	// 跳过序号（丢弃或乱序）同样拒绝
+	skipped := client.seal(byte(MsgTypeClient), FlagEncrypted, []byte("second"))
+	third := client.seal(byte(MsgTypeClient), FlagEncrypted, []byte("third"))
+	if _, err := server.open(byte(MsgTypeClient), FlagEncrypted, third); !errors.Is(err, ErrReplayedMessage) {
+		t.Fatalf("out of order got %v", err)
+	}
+
+	// 消息类型作为附加认证数据，被篡改时解密失败且不推进序号
+	if _, err := server.open(byte(MsgTypeRPCRequest), FlagEncrypted, skipped); !errors.Is(err, ErrInvalidMessage) {
+		t.Fatalf("tampered type got %v", err)
+	}
+	if _, err := server.open(byte(MsgTypeClient), FlagEncrypted, skipped); err != nil {
+		t.Fatalf("open after tamper: %v", err)
+	}
+}
+
+func TestHandshakeEncryptsConnection(t *testing.T) {
+	clientRaw, serverRaw := net.Pipe()
+	defer clientRaw.Close()
+	defer serverRaw.Close()
+	client, server := NewTCPConnection(clientRaw), NewTCPConnection(serverRaw)
+
+	done := make(chan error, 1)
+	go func() { done <- ServerHandshake(server) }()
+	if err := ClientHandshake(client); err != nil {
+		t.Fatalf("client handshake: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("server handshake: %v", err)
+	}
+	if !client.IsEncrypted() || !server.IsEncrypted() {
+		t.Fatal("connection not encrypted after handshake")
+	}
+
+	ctx := context.Background()
+	for _, payload := range []string{"ping", "pong"} {
+		go func() { done <- client.SendMessage(&Message{Type: MsgTypeClient, Payload: []byte(payload)}) }()
+		msg, err := server.ReceiveMessage(ctx)
+		if err != nil {
+			t.Fatalf("receive: %v", err)
+		}
+		if err := <-done; err != nil {
+			t.Fatalf("send: %v", err)
+		}
+		if msg.Type != MsgTypeClient || string(msg.Payload) != payload {
+			t.Fatalf("got type=%d payload=%q, want %q", msg.Type, msg.Payload, payload)
+		}
+	}
+
+	// 加密连接收到明文消息直接拒绝
+	plain := NewTCPConnection(clientRaw)
+	go func() { done <- plain.SendMessage(&Message{Type: MsgTypeClient, Payload: []byte("plain")}) }()
+	if _, err := server.ReceiveMessage(ctx); !errors.Is(err, ErrNotEncrypted) {
+		t.Fatalf("plain message got %v", err)
+	}
+	<-done
+}
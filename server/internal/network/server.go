@@ -42,6 +42,14 @@ func WithTCPServerOptionAddr(addr string) TCPServerOption {
 		tcpServer.addr = addr
 	}
 }
+
+// WithTCPServerOptionHandshake 开启后连接建立时必须先完成密钥交换，之后的消息全部加密
+func WithTCPServerOptionHandshake(enable bool) TCPServerOption {
+	return func(tcpServer *TCPServer) {
+		tcpServer.handshakeEnable = enable
+	}
+}
+
 func WithTCPServerOptionMaxConnections(maxConnections uint32) TCPServerOption {
 	return func(tcpServer *TCPServer) {
 		tcpServer.maxConnections = maxConnections
@@ -50,9 +58,10 @@ func WithTCPServerOptionMaxConnections(maxConnections uint32) TCPServerOption {
 
 // TCPServer TCP服务器
 type TCPServer struct {
-	addr           string   // 监听地址
-	allowedIPs     []string // 允许的IP列表(为空则允许所有)
-	maxConnections uint32   // 最大连接数
+	addr            string   // 监听地址
+	allowedIPs      []string // 允许的IP列表(为空则允许所有)
+	maxConnections  uint32   // 最大连接数
+	handshakeEnable bool     // 是否要求握手加密
 
 	onConnected    func(conn IConnection)
 	onDisconnected func(conn IConnection)
@@ -231,6 +240,12 @@ func (s *TCPServer) handleConnection(ctx context.Context, tcpConn IConnection, r
 		defer cancel()
 
 		// 握手阶段
+		if s.handshakeEnable {
+			if err := ServerHandshake(tcpConn); err != nil {
+				log.Warnf("[SECURITY] handshake with %s failed: %v", rawConn.RemoteAddr().String(), err)
+				return
+			}
+		}
 		if s.onConnected != nil {
 			s.onConnected(tcpConn)
 		}
@@ -270,6 +285,8 @@ func (s *TCPServer) handleConnection(ctx context.Context, tcpConn IConnection, r
 				switch {
 				case errors.Is(err, ErrFrameTooLarge):
 					log.Warnf("[SECURITY] recv frame too large from %s: %v", rawConn.RemoteAddr().String(), err)
+				case errors.Is(err, ErrNotEncrypted), errors.Is(err, ErrReplayedMessage):
+					log.Warnf("[SECURITY] rejected message from %s: %v", rawConn.RemoteAddr().String(), err)
 				case errors.Is(err, ErrInvalidMessage):
 					log.Warnf("[PROTO] invalid message from %s: %v", rawConn.RemoteAddr().String(), err)
 				default:
@@ -6,15 +6,19 @@ import (
 	"github.com/gorilla/websocket"
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // WebSocketConnection WebSocket连接实现
+// WS 明文帧没有 flags 字节，加密模式下帧格式为 [类型][flags][seq][密文]，与 TCP 帧保持一致
 type WebSocketConnection struct {
-	conn  *websocket.Conn
-	codec *Codec
-	meta  interface{}
-	mu    sync.RWMutex
+	conn         *websocket.Conn
+	codec        *Codec
+	meta         interface{}
+	mu           sync.RWMutex
+	cipher       atomic.Pointer[SessionCipher] // 握手完成后设置，为空表示明文
+	readDeadline time.Time                     // 非零时覆盖默认读超时，仅握手阶段使用
 }
 
 // NewWebSocketConnection 创建WebSocket连接
@@ -27,6 +31,14 @@ func NewWebSocketConnection(conn *websocket.Conn) *WebSocketConnection {
 
 // SendMessage 发送消息
 func (wc *WebSocketConnection) SendMessage(msg *Message) error {
+	if sc := wc.cipher.Load(); sc != nil && msg.Type != MsgTypeHandshake {
+		sc.sendMu.Lock()
+		defer sc.sendMu.Unlock()
+		sealed := sc.seal(msg.Type, FlagEncrypted, msg.Payload)
+		payload := make([]byte, 0, 1+len(sealed))
+		payload = append(payload, FlagEncrypted)
+		msg = &Message{Type: msg.Type, Payload: append(payload, sealed...)}
+	}
 	data, err := wc.codec.EncodeMessage(msg)
 	if err != nil {
 		return err
@@ -37,7 +49,10 @@ func (wc *WebSocketConnection) SendMessage(msg *Message) error {
 // ReceiveMessage 接收消息
 func (wc *WebSocketConnection) ReceiveMessage(ctx context.Context) (*Message, error) {
 	// 为ws连接设置读超时
-	deadline := time.Now().Add(60 * time.Second)
+	deadline := wc.readDeadline
+	if deadline.IsZero() {
+		deadline = time.Now().Add(60 * time.Second)
+	}
 	_ = wc.conn.SetReadDeadline(deadline)
 	messageType, data, err := wc.conn.ReadMessage()
 	if err != nil {
@@ -51,7 +66,38 @@ func (wc *WebSocketConnection) ReceiveMessage(ctx context.Context) (*Message, er
 
 	// 使用 bytes.Buffer 包装数据以便 DecodeMessage 读取
 	buf := bytes.NewBuffer(data)
-	return wc.codec.DecodeMessage(buf)
+	msg, err := wc.codec.DecodeMessage(buf)
+	if err != nil {
+		return nil, err
+	}
+	sc := wc.cipher.Load()
+	if sc == nil {
+		return msg, nil
+	}
+	if len(msg.Payload) < 1 || msg.Payload[0]&FlagEncrypted == 0 {
+		return nil, ErrNotEncrypted
+	}
+	plain, err := sc.open(msg.Type, msg.Payload[0], msg.Payload[1:])
+	if err != nil {
+		return nil, err
+	}
+	msg.Payload = plain
+	return msg, nil
+}
+
+// IsEncrypted 是否已完成握手进入加密模式
+func (wc *WebSocketConnection) IsEncrypted() bool {
+	return wc.cipher.Load() != nil
+}
+
+func (wc *WebSocketConnection) setCipher(sc *SessionCipher) {
+	wc.cipher.Store(sc)
+}
+
+// setReadDeadline 只在握手阶段（接收协程启动前）调用
+func (wc *WebSocketConnection) setReadDeadline(t time.Time) error {
+	wc.readDeadline = t
+	return wc.conn.SetReadDeadline(t)
 }
 
 // Close 关闭连接
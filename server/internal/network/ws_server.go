@@ -17,8 +17,9 @@ type WSServerConfig struct {
 	Path            string                     // WebSocket路径
 	AllowedIPs      []string                   // 允许的IP列表(为空则允许所有)
 	MaxConnections  uint32                     // 最大连接数
-	HandshakeEnable bool                       // 是否启用握手
+	HandshakeEnable bool                       // 是否启用握手（密钥交换后全部消息加密）
 	CheckOrigin     func(r *http.Request) bool // Origin检查函数
+	OnDisconnected  func(conn IConnection)     // 连接断开回调
 }
 
 // WSServer WebSocket服务器
@@ -160,6 +161,9 @@ func (s *WSServer) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 func (s *WSServer) handleConnection(ctx context.Context, wsConn IConnection, rawConn *websocket.Conn) {
 	defer s.wg.Done()
 	defer func() {
+		if s.config.OnDisconnected != nil {
+			s.config.OnDisconnected(wsConn)
+		}
 		wsConn.Close()
 		s.mu.Lock()
 		delete(s.connections, rawConn)
@@ -167,6 +171,13 @@ func (s *WSServer) handleConnection(ctx context.Context, wsConn IConnection, raw
 		log.Infof("WebSocket connection closed: %s", rawConn.RemoteAddr().String())
 	}()
 
+	if s.config.HandshakeEnable {
+		if err := ServerHandshake(wsConn); err != nil {
+			log.Warnf("[SECURITY] ws handshake with %s failed: %v", rawConn.RemoteAddr().String(), err)
+			return
+		}
+	}
+
 	const defaultHeartbeatTimeout = 60 * time.Second
 	lastActive := time.Now()
 	for {
@@ -0,0 +1,250 @@
+/**
+ * @Author: zjj
+ * @Date: 2025/12/30
+ * @Desc: 基于格子的A*寻路
+**/
+
+package pathfind
+
+import (
+	"container/heap"
+	"errors"
+)
+
+const (
+	// DefaultMaxNodes 单次寻路默认最多展开的节点数
+	DefaultMaxNodes = 4096
+
+	straightCost = 10 // 直线移动代价
+	diagonalCost = 14 // 斜向移动代价（约 10*√2）
+)
+
+var (
+	ErrUnwalkable  = errors.New("pathfind: start or end not walkable")
+	ErrNoPath      = errors.New("pathfind: no path")
+	ErrNodesBudget = errors.New("pathfind: search node budget exhausted")
+)
+
+// Grid 可寻路的格子地图，jsonconf.GameMap 直接满足该接口
+type Grid interface {
+	Width() int32
+	Height() int32
+	IsWalkable(x, y int32) bool
+}
+
+// Point 格子坐标
+type Point struct {
+	X int32
+	Y int32
+}
+
+var neighborDirs = [8]Point{
+	{1, 0}, {-1, 0}, {0, 1}, {0, -1},
+	{1, 1}, {1, -1}, {-1, 1}, {-1, -1},
+}
+
+type node struct {
+	pos    Point
+	g      int32
+	f      int32
+	parent *node
+	closed bool
+	index  int // 在开放列表中的下标，-1 表示不在开放列表
+}
+
+type openList []*node
+
+func (ol openList) Len() int { return len(ol) }
+func (ol openList) Less(i, j int) bool {
+	if ol[i].f != ol[j].f {
+		return ol[i].f < ol[j].f
+	}
+	// f 相同时优先离终点更近的节点，减少展开数量
+	return ol[i].g > ol[j].g
+}
+func (ol openList) Swap(i, j int) {
+	ol[i], ol[j] = ol[j], ol[i]
+	ol[i].index = i
+	ol[j].index = j
+}
+func (ol *openList) Push(x any) {
+	n := x.(*node)
+	n.index = len(*ol)
+	*ol = append(*ol, n)
+}
+func (ol *openList) Pop() any {
+	old := *ol
+	n := old[len(old)-1]
+	old[len(old)-1] = nil
+	n.index = -1
+	*ol = old[:len(old)-1]
+	return n
+}
+
+// FindPath 在 grid 上搜索 start 到 end 的路径，8方向移动且不允许斜穿障碍拐角
+// 返回经过平滑的路点（不含起点，含终点），起点与终点相同时返回空路径
+// maxNodes 为本次搜索最多展开的节点数，<=0 时使用 DefaultMaxNodes，超出返回 ErrNodesBudget
+func FindPath(grid Grid, start, end Point, maxNodes int) ([]Point, error) {
+	if !grid.IsWalkable(start.X, start.Y) || !grid.IsWalkable(end.X, end.Y) {
+		return nil, ErrUnwalkable
+	}
+	if start == end {
+		return nil, nil
+	}
+	if maxNodes <= 0 {
+		maxNodes = DefaultMaxNodes
+	}
+
+	width := grid.Width()
+	nodes := make(map[int32]*node)
+	open := &openList{}
+	startNode := &node{pos: start, f: heuristic(start, end), index: -1}
+	nodes[start.Y*width+start.X] = startNode
+	heap.Push(open, startNode)
+
+	expanded := 0
+	for open.Len() > 0 {
+		cur := heap.Pop(open).(*node)
+		if cur.pos == end {
+			return SmoothPath(grid, buildPath(cur)), nil
+		}
+		cur.closed = true
+		expanded++
+		if expanded > maxNodes {
+			return nil, ErrNodesBudget
+		}
+
+		for _, dir := range neighborDirs {
+			nx, ny := cur.pos.X+dir.X, cur.pos.Y+dir.Y
+			if !grid.IsWalkable(nx, ny) {
+				continue
+			}
+			cost := int32(straightCost)
+			if dir.X != 0 && dir.Y != 0 {
+				// 斜向移动要求两侧格子都可走，避免贴着障碍拐角穿过
+				if !grid.IsWalkable(cur.pos.X+dir.X, cur.pos.Y) || !grid.IsWalkable(cur.pos.X, cur.pos.Y+dir.Y) {
+					continue
+				}
+				cost = diagonalCost
+			}
+			g := cur.g + cost
+			key := ny*width + nx
+			next, ok := nodes[key]
+			if !ok {
+				next = &node{pos: Point{nx, ny}, index: -1}
+				nodes[key] = next
+			} else if next.closed || g >= next.g {
+				continue
+			}
+			next.g = g
+			next.f = g + heuristic(next.pos, end)
+			next.parent = cur
+			if next.index >= 0 {
+				heap.Fix(open, next.index)
+			} else {
+				heap.Push(open, next)
+			}
+		}
+	}
+	return nil, ErrNoPath
+}
+
+// SmoothPath 通过视线检测去掉可直线到达的中间路点
+// 传入的 path 需包含起点，返回结果不含起点
+func SmoothPath(grid Grid, path []Point) []Point {
+	if len(path) <= 2 {
+		if len(path) == 0 {
+			return nil
+		}
+		return path[1:]
+	}
+	result := make([]Point, 0, 4)
+	anchor := 0
+	for anchor < len(path)-1 {
+		// 从最远的点往回找第一个视线可达的点
+		next := anchor + 1
+		for i := len(path) - 1; i > anchor+1; i-- {
+			if LineWalkable(grid, path[anchor], path[i]) {
+				next = i
+				break
+			}
+		}
+		result = append(result, path[next])
+		anchor = next
+	}
+	return result
+}
+
+// LineWalkable 判断两格中心连线经过的所有格子是否都可走
+// 连线恰好穿过格子拐角时要求拐角两侧格子都可走，与寻路的斜向规则一致
+func LineWalkable(grid Grid, from, to Point) bool {
+	dx, dy := abs32(to.X-from.X), abs32(to.Y-from.Y)
+	stepX, stepY := sign32(to.X-from.X), sign32(to.Y-from.Y)
+	x, y := from.X, from.Y
+	n := dx + dy
+	diff := dx - dy
+	dx *= 2
+	dy *= 2
+	for n > 0 {
+		switch {
+		case diff > 0:
+			x += stepX
+			diff -= dy
+			n--
+		case diff < 0:
+			y += stepY
+			diff += dx
+			n--
+		default:
+			if !grid.IsWalkable(x+stepX, y) || !grid.IsWalkable(x, y+stepY) {
+				return false
+			}
+			x += stepX
+			y += stepY
+			diff += dx - dy
+			n -= 2
+		}
+		if !grid.IsWalkable(x, y) {
+			return false
+		}
+	}
+	return true
+}
+
+// heuristic 8方向的对角距离估价
+func heuristic(a, b Point) int32 {
+	dx, dy := abs32(a.X-b.X), abs32(a.Y-b.Y)
+	if dx > dy {
+		return straightCost*(dx-dy) + diagonalCost*dy
+	}
+	return straightCost*(dy-dx) + diagonalCost*dx
+}
+
+// buildPath 回溯父节点得到包含起点的完整路径
+func buildPath(end *node) []Point {
+	var path []Point
+	for n := end; n != nil; n = n.parent {
+		path = append(path, n.pos)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+func abs32(v int32) int32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func sign32(v int32) int32 {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
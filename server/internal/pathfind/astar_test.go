@@ -0,0 +1,132 @@
+package pathfind
+
+import (
+	"errors"
+	"testing"
+)
+
+// asciiGrid 以字符画描述的地图，'#' 为障碍，其余可走
+type asciiGrid []string
+
+func (g asciiGrid) Width() int32  { return int32(len(g[0])) }
+func (g asciiGrid) Height() int32 { return int32(len(g)) }
+func (g asciiGrid) IsWalkable(x, y int32) bool {
+	if x < 0 || y < 0 || x >= g.Width() || y >= g.Height() {
+		return false
+	}
+	return g[y][x] != '#'
+}
+
+// checkPath 校验路点从 start 出发逐段视线可达并以 end 结束
+func checkPath(t *testing.T, grid Grid, start, end Point, path []Point) {
+	t.Helper()
+	if len(path) == 0 {
+		t.Fatalf("empty path from %v to %v", start, end)
+	}
+	if last := path[len(path)-1]; last != end {
+		t.Fatalf("path ends at %v, want %v", last, end)
+	}
+	prev := start
+	for _, p := range path {
+		if !LineWalkable(grid, prev, p) {
+			t.Fatalf("segment %v -> %v crosses obstacle, path=%v", prev, p, path)
+		}
+		prev = p
+	}
+}
+
+func TestFindPathStraightLineSmoothed(t *testing.T) {
+	grid := asciiGrid{
+		".....",
+		".....",
+		".....",
+	}
+	path, err := FindPath(grid, Point{0, 1}, Point{4, 1}, 0)
+	if err != nil {
+		t.Fatalf("find path: %v", err)
+	}
+	if len(path) != 1 || path[0] != (Point{4, 1}) {
+		t.Fatalf("open line should smooth to single waypoint, got %v", path)
+	}
+}
+
+func TestFindPathAroundWall(t *testing.T) {
+	grid := asciiGrid{
+		"......",
+		"..#...",
+		"..#...",
+		"..#...",
+		"......",
+	}
+	start, end := Point{0, 2}, Point{5, 2}
+	path, err := FindPath(grid, start, end, 0)
+	if err != nil {
+		t.Fatalf("find path: %v", err)
+	}
+	checkPath(t, grid, start, end, path)
+	if len(path) < 2 {
+		t.Fatalf("path through wall should need a turn, got %v", path)
+	}
+}
+
+func TestFindPathNoDiagonalCornerCut(t *testing.T) {
+	grid := asciiGrid{
+		".#",
+		"#.",
+	}
+	if _, err := FindPath(grid, Point{0, 0}, Point{1, 1}, 0); !errors.Is(err, ErrNoPath) {
+		t.Fatalf("diagonal between two obstacles should be blocked, err=%v", err)
+	}
+}
+
+func TestFindPathErrors(t *testing.T) {
+	grid := asciiGrid{
+		"..#..",
+		"..#..",
+		"..#..",
+	}
+	if _, err := FindPath(grid, Point{0, 0}, Point{2, 0}, 0); !errors.Is(err, ErrUnwalkable) {
+		t.Fatalf("end on obstacle: err=%v", err)
+	}
+	if _, err := FindPath(grid, Point{0, 0}, Point{4, 0}, 0); !errors.Is(err, ErrNoPath) {
+		t.Fatalf("walled off end: err=%v", err)
+	}
+	path, err := FindPath(grid, Point{1, 1}, Point{1, 1}, 0)
+	if err != nil || len(path) != 0 {
+		t.Fatalf("same start and end should return empty path, path=%v err=%v", path, err)
+	}
+}
+
+func TestFindPathNodeBudget(t *testing.T) {
+	grid := asciiGrid{
+		"..........",
+		"########..",
+		"..........",
+	}
+	if _, err := FindPath(grid, Point{0, 0}, Point{0, 2}, 3); !errors.Is(err, ErrNodesBudget) {
+		t.Fatalf("small budget should be exhausted, err=%v", err)
+	}
+	path, err := FindPath(grid, Point{0, 0}, Point{0, 2}, 0)
+	if err != nil {
+		t.Fatalf("default budget: %v", err)
+	}
+	checkPath(t, grid, Point{0, 0}, Point{0, 2}, path)
+}
+
+func TestLineWalkable(t *testing.T) {
+	grid := asciiGrid{
+		"....",
+		".#..",
+		"....",
+	}
+	if LineWalkable(grid, Point{0, 1}, Point{3, 1}) {
+		t.Fatal("line through obstacle should be blocked")
+	}
+	if !LineWalkable(grid, Point{0, 0}, Point{3, 0}) {
+		t.Fatal("open row should be walkable")
+	}
+	// 斜线恰好穿过障碍拐角
+	if LineWalkable(grid, Point{0, 2}, Point{2, 0}) {
+		t.Fatal("diagonal touching obstacle corner should be blocked")
+	}
+}
@@ -12,14 +12,64 @@ func InitErrorCodes() {
 
 	// 批量注册所有错误码映射
 	errorTags := map[int32]string{
-		int32(ErrorCode_Success):           "Success",
-		int32(ErrorCode_Internal_Error):    "Internal_Error",
-		int32(ErrorCode_Param_Invalid):     "Param_Invalid",
-		int32(ErrorCode_Network_Timeout):   "Network_Timeout",
-		int32(ErrorCode_Player_NotFound):   "Player_NotFound",
-		int32(ErrorCode_Item_NotEnough):    "Item_NotEnough",
-		int32(ErrorCode_System_NotFound):   "System_NotFound",
-		int32(ErrorCode_System_NotEnabled): "System_NotEnabled",
+		int32(ErrorCode_Success):                "Success",
+		int32(ErrorCode_Internal_Error):         "Internal_Error",
+		int32(ErrorCode_Param_Invalid):          "Param_Invalid",
+		int32(ErrorCode_Network_Timeout):        "Network_Timeout",
+		int32(ErrorCode_Rpc_MethodNotFound):     "Rpc_MethodNotFound",
+		int32(ErrorCode_Rpc_ConnClosed):         "Rpc_ConnClosed",
+		int32(ErrorCode_Actor_MailboxFull):      "Actor_MailboxFull",
+		int32(ErrorCode_Actor_Stopped):          "Actor_Stopped",
+		int32(ErrorCode_Player_NotFound):        "Player_NotFound",
+		int32(ErrorCode_Player_ReconnectFailed): "Player_ReconnectFailed",
+		int32(ErrorCode_Realm_NotFound):         "Realm_NotFound",
+		int32(ErrorCode_Realm_Unavailable):      "Realm_Unavailable",
+		int32(ErrorCode_Realm_NotSelected):      "Realm_NotSelected",
+		int32(ErrorCode_Realm_SessionMismatch):  "Realm_SessionMismatch",
+		int32(ErrorCode_Gm_PermissionDenied):    "Gm_PermissionDenied",
+		int32(ErrorCode_Gm_CommandNotFound):     "Gm_CommandNotFound",
+		int32(ErrorCode_Gm_ArgInvalid):          "Gm_ArgInvalid",
+		int32(ErrorCode_Ops_Unauthorized):       "Ops_Unauthorized",
+		int32(ErrorCode_Ops_NotAvailable):       "Ops_NotAvailable",
+		int32(ErrorCode_Item_NotEnough):         "Item_NotEnough",
+		int32(ErrorCode_Bag_Full):               "Bag_Full",
+		int32(ErrorCode_Item_NotFound):          "Item_NotFound",
+		int32(ErrorCode_Item_CannotUse):         "Item_CannotUse",
+		int32(ErrorCode_Gold_NotEnough):         "Gold_NotEnough",
+		int32(ErrorCode_Item_Using):             "Item_Using",
+		int32(ErrorCode_Equip_JobLimit):         "Equip_JobLimit",
+		int32(ErrorCode_Equip_LevelLimit):       "Equip_LevelLimit",
+		int32(ErrorCode_Equip_SlotEmpty):        "Equip_SlotEmpty",
+		int32(ErrorCode_Drop_NotFound):          "Drop_NotFound",
+		int32(ErrorCode_Drop_Protected):         "Drop_Protected",
+		int32(ErrorCode_Drop_TooFar):            "Drop_TooFar",
+		int32(ErrorCode_Drop_Picking):           "Drop_Picking",
+		int32(ErrorCode_FuBen_NotFound):         "FuBen_NotFound",
+		int32(ErrorCode_FuBen_Full):             "FuBen_Full",
+		int32(ErrorCode_FuBen_Closed):           "FuBen_Closed",
+		int32(ErrorCode_FuBen_NotInInstance):    "FuBen_NotInInstance",
+		int32(ErrorCode_FuBen_InInstance):       "FuBen_InInstance",
+		int32(ErrorCode_Scene_NotFound):         "Scene_NotFound",
+		int32(ErrorCode_Auction_Closed):         "Auction_Closed",
+		int32(ErrorCode_Auction_NotFound):       "Auction_NotFound",
+		int32(ErrorCode_Auction_ItemBound):      "Auction_ItemBound",
+		int32(ErrorCode_Auction_PriceInvalid):   "Auction_PriceInvalid",
+		int32(ErrorCode_Auction_ListingLimit):   "Auction_ListingLimit",
+		int32(ErrorCode_Auction_OwnListing):     "Auction_OwnListing",
+		int32(ErrorCode_Mail_NotFound):          "Mail_NotFound",
+		int32(ErrorCode_Mail_Expired):           "Mail_Expired",
+		int32(ErrorCode_Mail_AlreadyClaimed):    "Mail_AlreadyClaimed",
+		int32(ErrorCode_Mail_ReceiverNotFound):  "Mail_ReceiverNotFound",
+		int32(ErrorCode_Mail_ItemBound):         "Mail_ItemBound",
+		int32(ErrorCode_Mail_AttachmentLimit):   "Mail_AttachmentLimit",
+		int32(ErrorCode_Chat_Closed):            "Chat_Closed",
+		int32(ErrorCode_Chat_Muted):             "Chat_Muted",
+		int32(ErrorCode_Chat_Cooldown):          "Chat_Cooldown",
+		int32(ErrorCode_Chat_ContentInvalid):    "Chat_ContentInvalid",
+		int32(ErrorCode_Chat_NoChannel):         "Chat_NoChannel",
+		int32(ErrorCode_Chat_TargetOffline):     "Chat_TargetOffline",
+		int32(ErrorCode_System_NotFound):        "System_NotFound",
+		int32(ErrorCode_System_NotEnabled):      "System_NotEnabled",
 		// 后续新增错误码在这里继续添加
 	}
 	customerr.RegisterErrorTags(errorTags)
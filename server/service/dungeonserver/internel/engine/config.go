@@ -0,0 +1,56 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/3
+ * @Desc:
+**/
+
+package engine
+
+import (
+	"net"
+	"os"
+	"path"
+	"postapocgame/server/internal"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/tool"
+)
+
+// ServerConfig DungeonServer配置
+type ServerConfig struct {
+	SrvType uint8 `json:"srv_type"` // 服务类型，与 GameServer dungeon_server_addr_map 的 key 对应
+
+	// TCP配置
+	TCPAddr            string   `json:"tcp_addr"`             // TCP监听地址
+	GameServerAllowIPs []string `json:"gameserver_allow_ips"` // 允许连接的GameServer IP列表
+}
+
+func (c *ServerConfig) Validate() error {
+	if c.SrvType == 0 {
+		return customerr.NewError("srv_type must be greater than 0")
+	}
+	if c.TCPAddr == "" {
+		return customerr.NewError("tcp_addr is required")
+	}
+	if _, _, err := net.SplitHostPort(c.TCPAddr); err != nil {
+		return customerr.NewError("invalid tcp_addr: %v", err)
+	}
+	return nil
+}
+
+func LoadServerConfig(confPath string) (*ServerConfig, error) {
+	if confPath == "" {
+		confPath = path.Join(tool.GetCurDir(), "dungeonsrv.json")
+	}
+	bytes, err := os.ReadFile(confPath)
+	if err != nil {
+		return nil, customerr.Wrap(err)
+	}
+	var conf ServerConfig
+	if err := internal.Unmarshal(bytes, &conf); err != nil {
+		return nil, customerr.Wrap(err)
+	}
+	if err := conf.Validate(); err != nil {
+		return nil, customerr.Wrap(err)
+	}
+	return &conf, nil
+}
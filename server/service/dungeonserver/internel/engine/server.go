@@ -0,0 +1,53 @@
+package engine
+
+import (
+	"context"
+	"postapocgame/server/internal/network"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/dungeonserver/internel/gameserverlink"
+)
+
+// DungeonServer 独立部署的副本服务器，接收 GameServer 连接
+type DungeonServer struct {
+	config    *ServerConfig
+	handler   *gameserverlink.NetworkHandler
+	tcpServer network.ITCPServer
+}
+
+// NewDungeonServer 创建DungeonServer
+func NewDungeonServer(config *ServerConfig, handler *gameserverlink.NetworkHandler) *DungeonServer {
+	return &DungeonServer{
+		config:  config,
+		handler: handler,
+	}
+}
+
+func (ds *DungeonServer) Start(ctx context.Context) error {
+	log.Infof("Starting DungeonServer: SrvType=%d, TCPAddr=%s", ds.config.SrvType, ds.config.TCPAddr)
+
+	ds.tcpServer = network.NewTCPServer(
+		network.WithTCPServerOptionNetworkMessageHandler(ds.handler),
+		network.WithTCPServerOptionOnConn(ds.handler.OnConnected),
+		network.WithTCPServerOptionOnDisConn(ds.handler.OnDisconnected),
+		network.WithTCPServerOptionAddr(ds.config.TCPAddr),
+		network.WithTCPServerOptionAllowedIPs(ds.config.GameServerAllowIPs),
+	)
+	if err := ds.tcpServer.Start(ctx); err != nil {
+		return customerr.Wrap(err, int32(protocol.ErrorCode_Internal_Error))
+	}
+	log.Infof("DungeonServer started successfully")
+	return nil
+}
+
+// Stop 停止服务器
+func (ds *DungeonServer) Stop(ctx context.Context) error {
+	if ds.tcpServer != nil {
+		if err := ds.tcpServer.Stop(ctx); err != nil {
+			return customerr.Wrap(err)
+		}
+	}
+	log.Infof("DungeonServer stopped")
+	return nil
+}
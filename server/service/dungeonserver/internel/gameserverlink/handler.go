@@ -0,0 +1,112 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/3
+ * @Desc: GameServer 链路（DungeonServer 侧）
+**/
+
+package gameserverlink
+
+import (
+	"context"
+	"postapocgame/server/internal/actor"
+	"postapocgame/server/internal/network"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/dungeonactor"
+	"postapocgame/server/service/gameserver/internel/gshare"
+	"sync"
+)
+
+// NetworkHandler 接收 GameServer 转发的 DungeonActor 消息，并作为 gshare.IActorFacade
+// 把 DungeonActor 发往 PlayerActor 的消息写回 GameServer
+// 同一时间只服务一个 GameServer 连接
+type NetworkHandler struct {
+	codec *network.Codec
+
+	mu   sync.RWMutex
+	conn network.IConnection
+}
+
+var _ gshare.IActorFacade = (*NetworkHandler)(nil)
+
+func NewNetworkHandler() *NetworkHandler {
+	return &NetworkHandler{codec: network.DefaultCodec()}
+}
+
+// OnConnected GameServer 连接建立
+func (h *NetworkHandler) OnConnected(conn network.IConnection) {
+	h.mu.Lock()
+	old := h.conn
+	h.conn = conn
+	h.mu.Unlock()
+
+	log.Infof("[gameserverlink] gameserver connected: %s", conn.RemoteAddr())
+	if old != nil {
+		// 旧连接上的玩家实体不再可达，由新连接上的 GameServer 重新进入
+		log.Warnf("[gameserverlink] gameserver connection replaced, old=%s", old.RemoteAddr())
+		h.notifyLinkLost()
+	}
+}
+
+// OnDisconnected GameServer 连接断开，清理该连接上的全部玩家实体
+func (h *NetworkHandler) OnDisconnected(conn network.IConnection) {
+	h.mu.Lock()
+	current := h.conn == conn
+	if current {
+		h.conn = nil
+	}
+	h.mu.Unlock()
+
+	if !current {
+		return
+	}
+	log.Warnf("[gameserverlink] gameserver disconnected: %s", conn.RemoteAddr())
+	h.notifyLinkLost()
+}
+
+func (h *NetworkHandler) notifyLinkLost() {
+	if err := dungeonactor.GetDungeonActor().AsyncCall(context.Background(), "", uint16(protocol.DungeonActorMsgId_DAMLinkLost), nil); err != nil {
+		log.Errorf("[gameserverlink] notify link lost failed: %v", err)
+	}
+}
+
+// HandleMessage 处理 GameServer 消息
+func (h *NetworkHandler) HandleMessage(ctx context.Context, _ network.IConnection, msg *network.Message) error {
+	switch msg.Type {
+	case network.MsgTypeClient:
+		fwdMsg, err := h.codec.DecodeForwardMessage(msg.Payload)
+		if err != nil {
+			return customerr.Wrap(err)
+		}
+		clientMsg, err := h.codec.DecodeClientMessage(fwdMsg.Payload)
+		if err != nil {
+			return customerr.Wrap(err)
+		}
+		return dungeonactor.GetDungeonActor().AsyncCall(context.WithoutCancel(ctx), fwdMsg.SessionId, clientMsg.MsgId, clientMsg.Data)
+	case network.MsgTypeHeartbeat:
+		return nil
+	default:
+		log.Warnf("[gameserverlink] unknown message type: %d", msg.Type)
+		return nil
+	}
+}
+
+// RegisterHandler PlayerActor 处理器注册在 GameServer 进程内，这里无需注册
+func (h *NetworkHandler) RegisterHandler(uint16, actor.HandlerMessageFunc) {}
+
+// SendMessageAsync 把发往 PlayerActor 的消息写回 GameServer，key 为玩家会话
+func (h *NetworkHandler) SendMessageAsync(key string, message actor.IActorMessage) error {
+	h.mu.RLock()
+	conn := h.conn
+	h.mu.RUnlock()
+	if conn == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Rpc_ConnClosed), "gameserver not connected, msgId=%d", message.GetMsgId())
+	}
+	return network.NewBaseMessageSenderWithCodec(conn, h.codec).SendToClient(key, message.GetMsgId(), message.GetData())
+}
+
+// RemoveActor PlayerActor 由 GameServer 管理，这里无需处理
+func (h *NetworkHandler) RemoveActor(string) error {
+	return nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"postapocgame/server/internal/actor"
+	"postapocgame/server/internal/event"
+	"postapocgame/server/internal/jsonconf"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/pkg/tool"
+	"postapocgame/server/service/dungeonserver/internel/engine"
+	"postapocgame/server/service/dungeonserver/internel/gameserverlink"
+	"postapocgame/server/service/gameserver/internel/dungeonactor"
+	"postapocgame/server/service/gameserver/internel/gevent"
+	"postapocgame/server/service/gameserver/internel/gshare"
+	"syscall"
+	"time"
+)
+
+func main() {
+	log.InitLogger(log.WithAppName("dungeonserver"), log.WithScreen(true), log.WithPath(tool.GetCurDir()+"log"), log.WithLevel(log.DebugLevel))
+
+	configPath := tool.GetCurDir() + "config"
+	if err := jsonconf.GetConfigManager().Init(configPath); err != nil {
+		log.Fatalf("init config manager failed: %v", err)
+	}
+
+	// 初始化错误码映射
+	protocol.InitErrorCodes()
+
+	serverConfig, err := engine.LoadServerConfig("")
+	if err != nil {
+		log.Fatalf("err:%v", err)
+		return
+	}
+
+	// DungeonActor 发往 PlayerActor 的消息经 GameServer 链路写回
+	handler := gameserverlink.NewNetworkHandler()
+	gshare.SetActorFacade(handler)
+
+	dActor := dungeonactor.NewDungeonActor(actor.ModePerKey)
+	ds := engine.NewDungeonServer(serverConfig, handler)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := dActor.Start(ctx); err != nil {
+		log.Fatalf("Start DungeonActor failed: %v", err)
+	}
+
+	// 先注册 DungeonActor 处理器，再接受 GameServer 连接
+	gevent.Publish(context.Background(), event.NewEvent(gevent.OnSrvStart))
+
+	if err := ds.Start(ctx); err != nil {
+		log.Fatalf("Start DungeonServer failed: %v", err)
+	}
+
+	// 等待退出信号
+	<-ctx.Done()
+
+	log.Infof("Shutting down DungeonServer...")
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer shutdownCancel()
+
+	gevent.Publish(context.Background(), event.NewEvent(gevent.OnSrvStop))
+
+	if err := ds.Stop(shutdownCtx); err != nil {
+		log.Errorf("Stop DungeonServer failed: %v", err)
+	}
+	if err := dActor.Stop(shutdownCtx); err != nil {
+		log.Errorf("Stop DungeonActor failed: %v", err)
+	}
+	log.Infof("DungeonServer shutdown complete")
+}
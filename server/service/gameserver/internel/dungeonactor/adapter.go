@@ -3,65 +3,99 @@ package dungeonactor
 import (
 	"context"
 	"postapocgame/server/internal/actor"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
 	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/fbmgr"
 	"postapocgame/server/service/gameserver/internel/gshare"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/proto"
 )
 
-// DungeonActor GameServer 进程内的战斗/副本 Actor（单例）
-// 负责处理所有副本和战斗相关的逻辑
+// shardTickInterval 分片定时驱动间隔
+const shardTickInterval = 50 * time.Millisecond
+
+// DungeonActor 战斗/副本 Actor，运行在 GameServer 进程内或独立的 DungeonServer 进程中
+// 按副本实例分片（ModePerKey，key 为副本实例ID），不同实例的场景与战斗在各自的 goroutine 上并行处理；
+// 玩家消息按 FuBenMgr 中的会话路由投递到其所在实例的分片，跨实例通过 DAMHandoff 转移玩家
 type DungeonActor struct {
 	actorMgr actor.IActorManager
 	mode     actor.ActorMode
-	handler  *Handler
+	handler  *actor.BaseActorHandler
 }
 
 // 全局唯一 DungeonActor 实例指针
 var defaultDungeonActor *DungeonActor
 
-// dungeonActorFacadeImpl 实现 gshare.IDungeonActorFacade，封装 DungeonActor 的 ActorManager。
+// dungeonActorFacadeImpl 实现 gshare.IDungeonActorFacade，封装 DungeonActor 的分片路由。
 type dungeonActorFacadeImpl struct {
-	actorMgr actor.IActorManager
-	handler  *Handler
+	dungeonActor *DungeonActor
 }
 
 func (f *dungeonActorFacadeImpl) RegisterHandler(msgId uint16, h actor.HandlerMessageFunc) {
-	if f.handler != nil {
-		f.handler.RegisterMessageHandler(msgId, h)
+	if f.dungeonActor != nil {
+		f.dungeonActor.handler.RegisterMessageHandler(msgId, h)
 	}
 }
 
-func (f *dungeonActorFacadeImpl) SendMessageAsync(key string, message actor.IActorMessage) error {
-	if f == nil || f.actorMgr == nil {
+// SendMessageAsync 按消息 ctx 中的会话路由到分片，key 不再使用
+func (f *dungeonActorFacadeImpl) SendMessageAsync(_ string, message actor.IActorMessage) error {
+	if f == nil || f.dungeonActor == nil {
 		return nil
 	}
-	return f.actorMgr.SendMessageAsync(key, message)
+	return f.dungeonActor.dispatch(message)
 }
 
-// NewDungeonActor 创建并注册全局 DungeonActor 单例
+// NewDungeonActor 创建并注册全局 DungeonActor，同时创建默认副本
 func NewDungeonActor(mode actor.ActorMode) *DungeonActor {
-	if mode != actor.ModeSingle {
-		log.Fatalf("[dungeon-actor] only ModeSingle is supported, got mode=%d", mode)
+	if mode != actor.ModePerKey {
+		log.Fatalf("[dungeon-actor] only ModePerKey is supported, got mode=%d", mode)
 	}
-	handler := NewDungeonActorHandler()
+	handler := actor.NewBaseActorHandler("dungeon_actor_handler")
+	handler.OnInit()
 	d := &DungeonActor{
 		mode:    mode,
 		handler: handler,
 	}
 
+	// 邮箱满时默认拒绝并把错误返回给发送方；移动同步只关心最新状态，丢弃最旧的移动消息；进出场景、转移与结算消息走高优先级通道
 	d.actorMgr = actor.NewActorManager(
 		mode,
 		1024,
 		func() actor.IActorHandler {
-			return handler
+			return newShardHandler(handler)
 		},
+		actor.WithOverflowPolicy(actor.OverflowReject, 0),
+		actor.WithMsgOverflowPolicy(actor.OverflowDropOldest,
+			uint16(protocol.DungeonActorMsgId_DAMStartMove),
+			uint16(protocol.DungeonActorMsgId_DAMUpdateMove),
+			uint16(protocol.DungeonActorMsgId_DAMEndMove),
+		),
+		actor.WithPriorityMsgIds(
+			uint16(protocol.DungeonActorMsgId_DAMLinkLost),
+			uint16(protocol.DungeonActorMsgId_DAMHandoff),
+			uint16(protocol.DungeonActorMsgId_DAMRebindSession),
+			uint16(protocol.DungeonActorMsgId_DAMEnterGame),
+			uint16(protocol.DungeonActorMsgId_DAMLeaveGame),
+			uint16(protocol.DungeonActorMsgId_DAMSyncLevel),
+			uint16(protocol.DungeonActorMsgId_DAMSyncEquip),
+			uint16(protocol.DungeonActorMsgId_DAMAddBuff),
+			uint16(protocol.DungeonActorMsgId_DAMPickUpResult),
+			uint16(protocol.DungeonActorMsgId_DAMEnterFuBen),
+			uint16(protocol.DungeonActorMsgId_DAMLeaveFuBen),
+		),
 	)
+	fbmgr.GetFuBenMgr().SetSender(d.sendToShard)
 
-	// 注册 DungeonActor 门面，供 PlayerActor 通过 gshare 发送内部 Actor 消息
-	facade := &dungeonActorFacadeImpl{
-		actorMgr: d.actorMgr,
-		handler:  handler,
+	// 初始化默认副本
+	if err := fbmgr.GetFuBenMgr().CreateDefaultFuBen(); err != nil {
+		log.Errorf("[dungeon-actor] failed to create default fuben: %v", err)
 	}
-	gshare.SetDungeonActorFacade(facade)
+
+	// 注册 DungeonActor 门面，供 PlayerActor 通过 gshare 发送内部 Actor 消息
+	gshare.SetDungeonActorFacade(&dungeonActorFacadeImpl{dungeonActor: d})
 
 	defaultDungeonActor = d
 	log.Infof("[dungeonactor] NewDungeonActor created with mode=%d", mode)
@@ -75,7 +109,14 @@ func GetDungeonActor() *DungeonActor {
 
 func (d *DungeonActor) Start(ctx context.Context) error {
 	log.Infof("[dungeon-actor] Start DungeonActor")
-	return d.actorMgr.Start(ctx)
+	if err := d.actorMgr.Start(ctx); err != nil {
+		return err
+	}
+	// 默认副本分片常驻
+	if _, err := d.actorMgr.GetOrCreateActor(shardKey(0)); err != nil {
+		return customerr.Wrap(err)
+	}
+	return nil
 }
 
 // Stop 停止 DungeonActor
@@ -84,13 +125,53 @@ func (d *DungeonActor) Stop(ctx context.Context) error {
 	return d.actorMgr.Stop(ctx)
 }
 
-// AsyncCall 将消息封装为 Actor 消息，投递到 DungeonActor 的单线程 Loop 中处理
+// GetAllMetrics 获取所有分片的运行指标
+func (d *DungeonActor) GetAllMetrics() []actor.ActorMetrics {
+	return d.actorMgr.GetAllMetrics()
+}
+
+// AsyncCall 将消息封装为 Actor 消息，投递到会话所在副本实例的分片中处理
 func (d *DungeonActor) AsyncCall(ctx context.Context, sessionId string, msgId uint16, data []byte) error {
 	ctxWithSession := context.WithValue(ctx, gshare.ContextKeySession, sessionId)
 	actorMsg := actor.NewBaseMessage(ctxWithSession, msgId, data)
-	if err := d.actorMgr.SendMessageAsync("global", actorMsg); err != nil {
+	if err := d.dispatch(actorMsg); err != nil {
 		log.Errorf("[dungeon-actor] AsyncCall send failed: sessionId=%s msgId=%d err=%v", sessionId, msgId, err)
 		return err
 	}
 	return nil
 }
+
+// dispatch 链路断开消息广播到所有分片，会话换绑消息同时切换路由，其余消息按会话路由
+func (d *DungeonActor) dispatch(message actor.IActorMessage) error {
+	if message.GetMsgId() == uint16(protocol.DungeonActorMsgId_DAMLinkLost) {
+		linkEpoch.Add(1)
+		d.actorMgr.BroadcastAsync(message)
+		return nil
+	}
+	var sessionId string
+	if ctx := message.GetContext(); ctx != nil {
+		sessionId, _ = ctx.Value(gshare.ContextKeySession).(string)
+	}
+	if message.GetMsgId() == uint16(protocol.DungeonActorMsgId_DAMRebindSession) {
+		var req protocol.DAMRebindSessionReq
+		if err := proto.Unmarshal(message.GetData(), &req); err != nil {
+			return customerr.Wrap(err)
+		}
+		return fbmgr.GetFuBenMgr().RebindSession(sessionId, req.GetNewSessionId(), message)
+	}
+	return fbmgr.GetFuBenMgr().SendToSession(sessionId, message)
+}
+
+// sendToShard 投递消息到副本实例所属分片，分片不存在时创建
+func (d *DungeonActor) sendToShard(fbId uint32, message actor.IActorMessage) error {
+	return d.actorMgr.SendMessageAsync(shardKey(fbId), message)
+}
+
+// removeShard 回收已关闭副本实例的分片
+func (d *DungeonActor) removeShard(key string) error {
+	return d.actorMgr.RemoveActor(key)
+}
+
+func shardKey(fbId uint32) string {
+	return strconv.FormatUint(uint64(fbId), 10)
+}
@@ -0,0 +1,73 @@
+package dungeonactor
+
+import (
+	"context"
+	"postapocgame/server/internal/actor"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/entitymgr"
+	"postapocgame/server/service/gameserver/internel/gshare"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// handleAddBuff 处理 PlayerActor → DungeonActor 的加Buff请求（道具使用等）
+// 入口：protocol.DungeonActorMsgId_DAMAddBuff
+// 道具使用发起的请求无论成功与否都回传 PAMAddBuffResult，成功后 PlayerActor 才扣除道具
+func handleAddBuff(msg actor.IActorMessage) error {
+	if msg == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "nil message")
+	}
+
+	ctx := msg.GetContext()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	sessionId, _ := ctx.Value(gshare.ContextKeySession).(string)
+	if sessionId == "" {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "not found session")
+	}
+
+	var req protocol.DAMAddBuffReq
+	if err := proto.Unmarshal(msg.GetData(), &req); err != nil {
+		return customerr.Wrap(err)
+	}
+
+	err := addBuff(ctx, sessionId, &req)
+	if req.ItemId != 0 {
+		replyAddBuffResult(sessionId, &req, err == nil)
+	}
+	return err
+}
+
+func addBuff(ctx context.Context, sessionId string, req *protocol.DAMAddBuffReq) error {
+	et, ok := entitymgr.GetBySession(ctx, sessionId)
+	if !ok || et == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "player entity not found")
+	}
+	if et.IsDead() {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Item_CannotUse), "player is dead")
+	}
+	buffSys := et.GetBuffSys()
+	if buffSys == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "buff system not found")
+	}
+	if !buffSys.AddBuff(req.BuffId, et, req.Duration) {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "add buff %d failed", req.BuffId)
+	}
+	return nil
+}
+
+// replyAddBuffResult 把道具加Buff结果回传给 PlayerActor
+func replyAddBuffResult(sessionId string, req *protocol.DAMAddBuffReq, success bool) {
+	payload, err := proto.Marshal(&protocol.PAMAddBuffResultReq{ItemId: req.ItemId, UseSeq: req.UseSeq, Success: success})
+	if err != nil {
+		log.Errorf("[dungeon-actor] marshal add buff result failed: %v", err)
+		return
+	}
+	msgCtx := context.WithValue(context.Background(), gshare.ContextKeySession, sessionId)
+	if err := gshare.SendMessageAsync(sessionId, actor.NewBaseMessage(msgCtx, uint16(protocol.PlayerActorMsgId_PAMAddBuffResult), payload)); err != nil {
+		log.Errorf("[dungeon-actor] send add buff result failed: item=%d success=%v err=%v", req.ItemId, success, err)
+	}
+}
@@ -0,0 +1,77 @@
+package dungeonactor
+
+import (
+	"postapocgame/server/internal/actor"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/entitymgr"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/iface"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// handleChatScene 场景频道：广播给发送者所在场景的全部玩家（含发送者）
+// 入口：protocol.DungeonActorMsgId_DAMChatScene
+func handleChatScene(msg actor.IActorMessage) error {
+	player, err := getPlayerFromMsg(msg)
+	if err != nil {
+		return err
+	}
+	var req protocol.DAMChatSceneReq
+	if err := proto.Unmarshal(msg.GetData(), &req); err != nil {
+		return customerr.Wrap(err)
+	}
+	scene, ok := entitymgr.GetSceneByEntity(player)
+	if !ok || scene == nil {
+		err := customerr.NewErrorByCode(int32(protocol.ErrorCode_Chat_NoChannel), "player scene not found")
+		sendErrorToPlayer(player, err)
+		return err
+	}
+	data, err := proto.Marshal(&protocol.S2CChatMessageReq{Msg: req.Msg})
+	if err != nil {
+		return customerr.Wrap(err)
+	}
+	for _, et := range scene.GetAllEntities() {
+		if _, ok := et.(iface.IPlayer); !ok {
+			continue
+		}
+		if err := et.SendMessage(uint16(protocol.S2CProtocol_S2CChatMessage), data); err != nil {
+			log.Warnf("[dungeon-actor] send scene chat to entity %d failed: %v", et.GetHdl(), err)
+		}
+	}
+	return nil
+}
+
+// handleChatTeam 队伍频道：同一限时副本实例内的玩家为一个队伍，广播给实例内全部玩家（含发送者）
+// 默认副本不组队，发送者不在限时副本实例时返回 Chat_NoChannel
+// 入口：protocol.DungeonActorMsgId_DAMChatTeam
+func handleChatTeam(msg actor.IActorMessage) error {
+	player, err := getPlayerFromMsg(msg)
+	if err != nil {
+		return err
+	}
+	var req protocol.DAMChatTeamReq
+	if err := proto.Unmarshal(msg.GetData(), &req); err != nil {
+		return customerr.Wrap(err)
+	}
+	fb := getPlayerFuBen(player)
+	if fb == nil || fb.GetFbType() != uint32(protocol.FuBenType_FuBenTypeTimed) {
+		err := customerr.NewErrorByCode(int32(protocol.ErrorCode_Chat_NoChannel), "role %d not in team", player.GetId())
+		sendErrorToPlayer(player, err)
+		return err
+	}
+	data, err := proto.Marshal(&protocol.S2CChatMessageReq{Msg: req.Msg})
+	if err != nil {
+		return customerr.Wrap(err)
+	}
+	for _, member := range getAllPlayers(fb.GetEntityMgr()) {
+		if getPlayerFuBen(member) != fb {
+			continue
+		}
+		if err := member.SendMessage(uint16(protocol.S2CProtocol_S2CChatMessage), data); err != nil {
+			log.Warnf("[dungeon-actor] send team chat to role %d failed: %v", member.GetId(), err)
+		}
+	}
+	return nil
+}
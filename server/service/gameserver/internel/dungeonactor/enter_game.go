@@ -3,10 +3,8 @@ package dungeonactor
 import (
 	"context"
 	"postapocgame/server/pkg/customerr"
-	"postapocgame/server/pkg/log"
 	"postapocgame/server/service/gameserver/internel/dungeonactor/entity"
 	"postapocgame/server/service/gameserver/internel/dungeonactor/entitymgr"
-	"postapocgame/server/service/gameserver/internel/dungeonactor/fbmgr"
 	"postapocgame/server/service/gameserver/internel/gshare"
 
 	"google.golang.org/protobuf/proto"
@@ -26,73 +24,41 @@ func handleEnterGame(msg actor.IActorMessage) error {
 		ctx = context.Background()
 	}
 
-	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
-	if err != nil || playerRole == nil {
-		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "player role missing")
-	}
-	roleData := playerRole.GetPlayerSimpleData()
-	if roleData == nil {
-		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "role data missing")
-	}
-	sessionID := playerRole.GetSessionId()
-	if sessionID == "" {
-		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "session id missing")
-	}
-
 	var req protocol.DAMEnterGameReq
 	if raw := msg.GetData(); len(raw) > 0 {
 		if err := proto.Unmarshal(raw, &req); err != nil {
 			return customerr.Wrap(err)
 		}
 	}
-
-	fb, ok := fbmgr.GetFuBenMgr().GetFuBen(0)
-	if !ok || fb == nil {
-		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "default fuben missing")
-	}
-	scenes := fb.GetAllScenes()
-	if len(scenes) == 0 {
-		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "default scenes missing")
+	roleData := req.Role
+	if roleData == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "role data missing")
 	}
-	scene := scenes[0]
-
-	spawnX, spawnY := scene.GetSpawnPos()
-
-	player := entity.NewPlayer(sessionID, roleData, req.SkillMap)
-	player.SetPosition(spawnX, spawnY)
-
-	if err := fb.OnPlayerEnter(sessionID); err != nil {
-		return customerr.Wrap(err)
+	sessionID := req.SessionId
+	if sessionID == "" {
+		sessionID, _ = ctx.Value(gshare.ContextKeySession).(string)
 	}
-	if err := scene.AddEntity(player); err != nil {
-		return customerr.Wrap(err)
+	if sessionID == "" {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "session id missing")
 	}
-	entitymgr.GetEntityMgr().BindSession(sessionID, player.GetHdl())
 
-	enterScene := &protocol.S2CEnterSceneReq{
-		EntityData: player.BuildProtoEntitySt(),
-	}
-	if err := player.SendProtoMessage(uint16(protocol.S2CProtocol_S2CEnterScene), enterScene); err != nil {
-		log.Warnf("[dungeon-actor] send enter scene failed: %v", err)
+	fb := entitymgr.FuBenFromContext(ctx)
+	if fb == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "fuben not found in context")
 	}
 
-	for _, et := range scene.GetAllEntities() {
-		if et == nil || et.GetHdl() == player.GetHdl() {
-			continue
-		}
+	// 重复进入（顶号、DungeonServer 链路重连后重新同步）时先移除本分片内的旧实体
+	// 消息按会话路由，同一会话的旧实体一定在本分片
+	removeStalePlayers(fb.GetEntityMgr(), sessionID, roleData.RoleId)
 
-		appear := &protocol.S2CEntityAppearReq{
-			Entity: et.BuildProtoEntitySt(),
-		}
-		if err := player.SendProtoMessage(uint16(protocol.S2CProtocol_S2CEntityAppear), appear); err != nil {
-			log.Warnf("[dungeon-actor] notify appear to player failed: %v", err)
-		}
+	player := entity.NewPlayer(sessionID, roleData, req.SkillMap)
+	player.ApplyLevel(req.Level, req.Attrs)
+	player.ApplyEquip(req.EquipAttrs, req.Appearance)
 
-		if et.GetEntityType() == uint32(protocol.EntityType_EtPlayer) {
-			back := &protocol.S2CEntityAppearReq{Entity: player.BuildProtoEntitySt()}
-			_ = et.SendProtoMessage(uint16(protocol.S2CProtocol_S2CEntityAppear), back)
-		}
+	// 进入游戏总是落在默认副本入口场景，旧实体在其他实例时转移到默认副本分片
+	target := handoffTarget{fbId: 0}
+	if fb.GetFbId() != target.fbId {
+		return handoffPlayer(player, target)
 	}
-
-	return nil
+	return enterTarget(fb, player, target)
 }
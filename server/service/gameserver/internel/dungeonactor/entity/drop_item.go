@@ -0,0 +1,89 @@
+/**
+ * @Author: zjj
+ * @Date: 2025/12/29
+ * @Desc:
+**/
+
+package entity
+
+import (
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/iface"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+var _ iface.IDropItem = (*DropItem)(nil)
+
+// DropItem 地面掉落物实体，由场景掉落管理器创建
+type DropItem struct {
+	*BaseEntity
+	item       *protocol.ItemAmount
+	ownerId    uint64    // 归属角色ID，0表示无归属
+	protectEnd time.Time // 归属保护结束时间
+	expireAt   time.Time // 消失时间
+	picking    bool      // 已发往 PlayerActor 入包，等待结果
+}
+
+// NewDropItem 创建掉落物，掉落物不可被攻击
+func NewDropItem(item *protocol.ItemAmount, ownerId uint64, protectEnd, expireAt time.Time, x, y uint32) *DropItem {
+	d := &DropItem{
+		BaseEntity: NewBaseEntity(uint64(item.ItemId), uint32(protocol.EntityType_EtDropItem)),
+		item:       item,
+		ownerId:    ownerId,
+		protectEnd: protectEnd,
+		expireAt:   expireAt,
+	}
+	d.setOwner(d)
+	d.SetPosition(x, y)
+	d.AddState(uint32(protocol.EntityStateFlag_EntityStateFlagInvincible))
+	return d
+}
+
+// GetItem 获取掉落道具（副本）
+func (d *DropItem) GetItem() *protocol.ItemAmount {
+	return proto.Clone(d.item).(*protocol.ItemAmount)
+}
+
+func (d *DropItem) GetOwnerId() uint64 {
+	return d.ownerId
+}
+
+func (d *DropItem) CanPickUp(roleId uint64, now time.Time) bool {
+	if d.ownerId == 0 || d.ownerId == roleId {
+		return true
+	}
+	return !now.Before(d.protectEnd)
+}
+
+func (d *DropItem) IsExpired(now time.Time) bool {
+	return !d.expireAt.IsZero() && !now.Before(d.expireAt)
+}
+
+func (d *DropItem) IsPicking() bool {
+	return d.picking
+}
+
+func (d *DropItem) LockPicking() {
+	d.picking = true
+}
+
+func (d *DropItem) UnlockPicking() {
+	d.picking = false
+}
+
+// BuildProtoEntitySt 在基础实体数据上附加掉落物信息
+func (d *DropItem) BuildProtoEntitySt() *protocol.EntitySt {
+	st := d.BaseEntity.BuildProtoEntitySt()
+	st.Drop = &protocol.DropItemSt{
+		ItemId:  d.item.ItemId,
+		Count:   uint32(d.item.Count),
+		Bind:    d.item.Bind,
+		OwnerId: d.ownerId,
+	}
+	if d.ownerId != 0 {
+		st.Drop.ProtectEndMs = d.protectEnd.UnixMilli()
+	}
+	return st
+}
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"postapocgame/server/internal/protocol"
+	"testing"
+	"time"
+)
+
+func TestDropItemPickingHeldUntilResult(t *testing.T) {
+	now := time.Unix(1000, 0)
+	d := NewDropItem(&protocol.ItemAmount{ItemId: 1001, Count: 1}, 0, now, now.Add(2*time.Minute), 1, 1)
+	if d.IsPicking() {
+		t.Fatal("new drop should not be picking")
+	}
+	d.LockPicking()
+	// 结果未返回前一直锁定，只能靠掉落物自身过期回收
+	if !d.IsPicking() {
+		t.Fatal("drop should stay locked until pick up result")
+	}
+	if !d.IsExpired(now.Add(2 * time.Minute)) {
+		t.Fatal("locked drop should still expire")
+	}
+	d.UnlockPicking()
+	if d.IsPicking() {
+		t.Fatal("unlock should release drop")
+	}
+}
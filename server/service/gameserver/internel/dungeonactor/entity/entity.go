@@ -21,17 +21,22 @@ type BaseEntity struct {
 	position   argsdef.Position
 	sceneId    uint32
 	fuBenId    uint32
+	entityMgr  iface.IEntityMgr // 所在副本实例的实体管理器
 
 	// 使用新的属性系统替代旧的attr
 	attrSys  *entitysystem.AttrSys
 	aoiSys   *entitysystem.AOISys
 	fightSys *entitysystem.FightSys
 	moveSys  *entitysystem.MoveSys
+	buffSys  *entitysystem.BuffSys
 
 	// 状态标记
 	stateFlags uint64
 
 	name string
+
+	// owner 指向外层具体实体（Player/Monster），用于回调被子类重写的方法
+	owner iface.IEntity
 }
 
 const (
@@ -55,10 +60,23 @@ func NewBaseEntity(Id uint64, entityType uint32) *BaseEntity {
 	entity.attrSys = entitysystem.NewAttrSys(entity)
 	entity.aoiSys = entitysystem.NewAOISys(entity)
 	entity.moveSys = entitysystem.NewMoveSys(entity)
+	entity.buffSys = entitysystem.NewBuffSys()
+	entity.buffSys.SetEntity(entity)
+	entity.owner = entity
 
 	return entity
 }
 
+// setOwner 绑定外层具体实体，子类构造时调用
+func (e *BaseEntity) setOwner(owner iface.IEntity) {
+	e.owner = owner
+	// 战斗结算与Buff周期伤害需要以外层实体作为施法者/受击者
+	e.fightSys.SetEntity(owner)
+	e.buffSys.SetEntity(owner)
+	// 移动校验的位置纠正需要发给外层玩家实体
+	e.moveSys.SetEntity(owner)
+}
+
 func (e *BaseEntity) GetHdl() uint64 {
 	return e.hdl
 }
@@ -101,6 +119,14 @@ func (e *BaseEntity) SetFuBenId(fuBenId uint32) {
 	e.fuBenId = fuBenId
 }
 
+func (e *BaseEntity) GetEntityMgr() iface.IEntityMgr {
+	return e.entityMgr
+}
+
+func (e *BaseEntity) SetEntityMgr(entityMgr iface.IEntityMgr) {
+	e.entityMgr = entityMgr
+}
+
 // GetAttrSys 获取属性系统
 func (e *BaseEntity) GetAttrSys() iface.IAttrSys {
 	return e.attrSys
@@ -109,6 +135,10 @@ func (e *BaseEntity) GetAttrSys() iface.IAttrSys {
 func (e *BaseEntity) GetMoveSys() iface.IMoveSys {
 	return e.moveSys
 }
+
+func (e *BaseEntity) GetBuffSys() iface.IBuffSys {
+	return e.buffSys
+}
 func (e *BaseEntity) GetHP() int64 {
 	return e.GetAttrSys().GetAttrValue(attrdef.HP)
 }
@@ -133,30 +163,33 @@ func (e *BaseEntity) GetMaxMP() int64 {
 }
 
 func (e *BaseEntity) IsDead() bool {
-	return tool.IsSetBit64(e.stateFlags, stateFlagDead)
+	return e.stateFlags&stateFlagDead != 0
 }
 
 func (e *BaseEntity) IsInvincible() bool {
-	return tool.IsSetBit64(e.stateFlags, stateFlagInvincible)
+	return e.stateFlags&stateFlagInvincible != 0
 }
 
 func (e *BaseEntity) CanBeAttacked() bool {
-	return tool.IsSetBit64(e.stateFlags, stateFlagDead) && tool.IsSetBit64(e.stateFlags, stateFlagInvincible)
+	return e.stateFlags&(stateFlagDead|stateFlagInvincible) == 0
 }
 
 func (e *BaseEntity) GetStateFlags() uint64 {
 	return e.stateFlags
 }
 
+// AddState 设置状态标志，state 取值为 protocol.EntityStateFlag
 func (e *BaseEntity) AddState(state uint32) {
-
+	e.stateFlags = tool.SetBit64(e.stateFlags, uint64(state))
 }
 
-func (e *BaseEntity) RemoveState(stateId uint32) {
-
+// RemoveState 清除状态标志
+func (e *BaseEntity) RemoveState(state uint32) {
+	e.stateFlags = tool.ClearBit64(e.stateFlags, state)
 }
-func (e *BaseEntity) HasState(stateId uint32) bool {
-	return false
+
+func (e *BaseEntity) HasState(state uint32) bool {
+	return tool.IsSetBit64(e.stateFlags, uint64(state))
 }
 
 func (e *BaseEntity) RunOne(now time.Time) {
@@ -166,6 +199,9 @@ func (e *BaseEntity) RunOne(now time.Time) {
 	if e.fightSys != nil {
 		e.fightSys.RunOne(now)
 	}
+	if e.buffSys != nil {
+		e.buffSys.RunOne(now)
+	}
 }
 
 func (e *BaseEntity) GetFightSys() iface.IFightSys {
@@ -173,17 +209,26 @@ func (e *BaseEntity) GetFightSys() iface.IFightSys {
 }
 
 func (e *BaseEntity) OnAttacked(attacker iface.IEntity, damage int64) {
+	if e.IsDead() {
+		return
+	}
 	currentHP := e.GetHP()
 	if damage >= currentHP {
 		e.SetHP(0)
-		e.OnDie(attacker)
+		e.owner.OnDie(attacker)
 	} else {
 		e.SetHP(currentHP - damage)
 	}
 }
 
 func (e *BaseEntity) OnDie(killer iface.IEntity) {
-	tool.SetBit64(e.stateFlags, stateFlagDead)
+	e.AddState(uint32(protocol.EntityStateFlag_EntityStateFlagDead))
+	if e.moveSys != nil {
+		e.moveSys.StopMove(true, false)
+	}
+	if e.buffSys != nil {
+		e.buffSys.ClearBuffs()
+	}
 }
 
 func (e *BaseEntity) GetAOISys() iface.IAOISys {
@@ -265,5 +310,6 @@ func (e *BaseEntity) BuildProtoEntitySt() *protocol.EntitySt {
 		ShowName:   e.GetName(),
 		Attrs:      e.buildAttrMap(),
 		StateFlags: e.GetStateFlags(),
+		Buffs:      e.buffSys.BuildProtoBuffs(),
 	}
 }
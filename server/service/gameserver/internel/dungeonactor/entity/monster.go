@@ -0,0 +1,161 @@
+/**
+ * @Author: zjj
+ * @Date: 2025/12/26
+ * @Desc:
+**/
+
+package entity
+
+import (
+	"postapocgame/server/internal/argsdef"
+	"postapocgame/server/internal/attrdef"
+	"postapocgame/server/internal/jsonconf"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/internal/servertime"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/entitymgr"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/entitysystem"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/iface"
+	"time"
+)
+
+var _ iface.IMonster = (*Monster)(nil)
+
+// Monster 怪物实体，由场景刷怪点创建
+type Monster struct {
+	*BaseEntity
+	monsterId uint32
+	spawnId   uint32           // 所属刷怪点
+	bornPos   argsdef.Position // 出生位置（格子坐标）
+	dieTime   time.Time        // 死亡时间
+	exp       int64            // 击杀经验
+	aiSys     *entitysystem.AISys
+}
+
+// NewMonster 根据怪物配置创建怪物，属性直接取自配置
+func NewMonster(cfg *jsonconf.MonsterConfig, spawnId uint32, bornX, bornY uint32) *Monster {
+	m := &Monster{
+		BaseEntity: NewBaseEntity(uint64(cfg.MonsterId), uint32(protocol.EntityType_EtMonster)),
+		monsterId:  cfg.MonsterId,
+		spawnId:    spawnId,
+		bornPos:    argsdef.Position{X: bornX, Y: bornY},
+		exp:        cfg.Exp,
+	}
+	m.setOwner(m)
+	m.SetName(cfg.Name)
+	m.SetPosition(bornX, bornY)
+
+	attrSys := m.GetAttrSys()
+	attrSys.SetAttrValue(attrdef.Level, int64(cfg.Level))
+	attrSys.SetAttrValue(attrdef.MaxHP, cfg.MaxHP)
+	attrSys.SetAttrValue(attrdef.MaxMP, cfg.MaxMP)
+	attrSys.SetAttrValue(attrdef.Attack, cfg.Attack)
+	attrSys.SetAttrValue(attrdef.Defense, cfg.Defense)
+	attrSys.SetAttrValue(attrdef.Speed, cfg.Speed)
+	attrSys.SetAttrValue(attrdef.CritRate, cfg.CritRate)
+	attrSys.SetAttrValue(attrdef.CritDamage, cfg.CritDamage)
+	attrSys.SetAttrValue(attrdef.DodgeRate, cfg.DodgeRate)
+	attrSys.SetAttrValue(attrdef.HitRate, cfg.HitRate)
+	attrSys.SetAttrValue(attrdef.HP, cfg.MaxHP)
+	attrSys.SetAttrValue(attrdef.MP, cfg.MaxMP)
+
+	for _, skillId := range cfg.SkillIds {
+		if err := m.GetFightSys().LearnSkill(skillId, 1); err != nil {
+			log.Errorf("monster %d learn skill %d failed: %v", cfg.MonsterId, skillId, err)
+		}
+	}
+
+	if cfg.AI != nil && cfg.AI.Type != uint32(protocol.AIType_AITypeNil) {
+		m.aiSys = entitysystem.NewAISys(m, cfg.AI, bornX, bornY)
+		if m.aiSys == nil {
+			log.Warnf("monster %d ai type %d not registered", cfg.MonsterId, cfg.AI.Type)
+		}
+	}
+
+	return m
+}
+
+func (m *Monster) GetMonsterId() uint32 {
+	return m.monsterId
+}
+
+func (m *Monster) GetSpawnId() uint32 {
+	return m.spawnId
+}
+
+// GetBornPos 获取出生位置（格子坐标）
+func (m *Monster) GetBornPos() *argsdef.Position {
+	return &argsdef.Position{X: m.bornPos.X, Y: m.bornPos.Y}
+}
+
+// GetDieTime 获取死亡时间，未死亡返回零值
+func (m *Monster) GetDieTime() time.Time {
+	return m.dieTime
+}
+
+// GetAISys 获取怪物AI，未配置AI时返回 nil
+func (m *Monster) GetAISys() *entitysystem.AISys {
+	return m.aiSys
+}
+
+// RunOne 每帧驱动，先执行基础系统再推进AI
+func (m *Monster) RunOne(now time.Time) {
+	m.BaseEntity.RunOne(now)
+	if m.aiSys != nil {
+		m.aiSys.RunOne(now)
+	}
+}
+
+// OnAttacked 受击后通知AI反击
+func (m *Monster) OnAttacked(attacker iface.IEntity, damage int64) {
+	m.BaseEntity.OnAttacked(attacker, damage)
+	if m.aiSys != nil && !m.IsDead() {
+		m.aiSys.OnAttacked(attacker)
+	}
+}
+
+// OnDie 怪物死亡处理（重写BaseEntity的方法）
+func (m *Monster) OnDie(killer iface.IEntity) {
+	m.BaseEntity.OnDie(killer)
+	m.dieTime = servertime.Now()
+	if m.aiSys != nil {
+		m.aiSys.Reset()
+	}
+
+	var killerHdl uint64
+	if killer != nil {
+		killerHdl = killer.GetHdl()
+	}
+	var ownerId uint64
+	if player, ok := killer.(iface.IPlayer); ok {
+		ownerId = player.GetId()
+		if m.exp > 0 {
+			if err := player.AddExp(m.exp); err != nil {
+				log.Warnf("monster %d grant exp to %d failed: %v", m.monsterId, killerHdl, err)
+			}
+		}
+	}
+	m.dropLoot(ownerId)
+	log.Debugf("monster %d (hdl=%d) killed by %d", m.monsterId, m.GetHdl(), killerHdl)
+}
+
+// dropLoot 记录副本击杀并按副本难度生成掉落，归属于击杀者
+func (m *Monster) dropLoot(ownerId uint64) {
+	scene, ok := entitymgr.GetSceneByEntity(m)
+	if !ok || scene == nil {
+		return
+	}
+	difficulty := uint32(1)
+	if fb := scene.GetFuBen(); fb != nil {
+		fb.AddKillCount(1)
+		difficulty = fb.GetDifficulty()
+	}
+	cfg := jsonconf.GetConfigManager().GetMonsterConfig(m.monsterId)
+	if cfg == nil {
+		return
+	}
+	if dropId := cfg.GetDropId(difficulty); dropId > 0 {
+		pos := m.GetPosition()
+		scene.DropLoot(pos.X, pos.Y, ownerId, dropId)
+	}
+}
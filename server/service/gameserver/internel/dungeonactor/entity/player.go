@@ -8,6 +8,7 @@ package entity
 
 import (
 	"context"
+	"maps"
 	"postapocgame/server/service/gameserver/internel/dungeonactor/iface"
 	"postapocgame/server/service/gameserver/internel/gshare"
 	"time"
@@ -25,6 +26,12 @@ type Player struct {
 	*BaseEntity
 	sessionId string
 	roleInfo  *protocol.PlayerSimpleData
+	// 等级基础属性（用于升级时按差值更新）
+	levelAttrs map[uint32]int64
+	// 外观：装备槽位 -> 外观ID
+	appearance map[uint32]uint32
+	// 进入副本实例前的位置，不在实例中时为 nil
+	returnPoint *iface.ReturnPoint
 	// 死亡相关
 	dieTime time.Time // 死亡时间（用于延迟复活）
 }
@@ -35,6 +42,7 @@ func NewPlayer(sessionId string, roleInfo *protocol.PlayerSimpleData, skillMap m
 		sessionId:  sessionId,
 		roleInfo:   roleInfo,
 	}
+	entity.setOwner(entity)
 
 	entity.GetAttrSys().SetAttrValue(attrdef.Level, int64(roleInfo.Level))
 
@@ -56,6 +64,11 @@ func (r *Player) GetSessionId() string {
 	return r.sessionId
 }
 
+// SetSessionId 断线重连后换绑到新会话
+func (r *Player) SetSessionId(sessionId string) {
+	r.sessionId = sessionId
+}
+
 func (r *Player) initSkills(skillMap map[uint32]uint32) {
 	if skillMap == nil {
 		return
@@ -82,6 +95,101 @@ func (r *Player) UpdateSkill(skillId, level uint32) error {
 	return r.GetFightSys().LearnSkill(skillId, level)
 }
 
+// ApplyLevel 应用等级及等级基础属性
+// 按与上次基础属性的差值增量修改，避免覆盖Buff等其他来源的属性修正
+func (r *Player) ApplyLevel(level uint32, attrs map[uint32]int64) {
+	attrSys := r.GetAttrSys()
+	if level > 0 {
+		attrSys.SetAttrValue(attrdef.Level, int64(level))
+	}
+	for attrType, old := range r.levelAttrs {
+		if _, ok := attrs[attrType]; !ok {
+			attrSys.AddAttrValue(attrType, -old)
+		}
+	}
+	levelAttrs := make(map[uint32]int64, len(attrs))
+	for attrType, value := range attrs {
+		attrSys.AddAttrValue(attrType, value-r.levelAttrs[attrType])
+		levelAttrs[attrType] = value
+	}
+	r.levelAttrs = levelAttrs
+
+	// 进入游戏/升级时回满
+	if !r.IsDead() {
+		r.SetHP(r.GetMaxHP())
+		r.SetMP(r.GetMaxMP())
+	}
+}
+
+// SetReturnPoint 设置副本实例返回点，nil 表示清除
+func (r *Player) SetReturnPoint(rp *iface.ReturnPoint) {
+	r.returnPoint = rp
+}
+
+// GetReturnPoint 获取副本实例返回点
+func (r *Player) GetReturnPoint() *iface.ReturnPoint {
+	return r.returnPoint
+}
+
+// ApplyEquip 应用装备属性与外观
+// 装备属性归入 SaEquip 属性系统，外观变化时通知视野内的玩家
+func (r *Player) ApplyEquip(attrs map[uint32]int64, appearance map[uint32]uint32) {
+	r.GetAttrSys().SetSysAttrs(uint32(protocol.SaAttrSys_SaEquip), attrs)
+	if r.GetHP() > r.GetMaxHP() {
+		r.SetHP(r.GetMaxHP())
+	}
+	if r.GetMP() > r.GetMaxMP() {
+		r.SetMP(r.GetMaxMP())
+	}
+
+	if maps.Equal(r.appearance, appearance) {
+		return
+	}
+	r.appearance = maps.Clone(appearance)
+	r.broadcastAppearance()
+}
+
+// broadcastAppearance 通知自己及 AOI 可见范围内的玩家外观变化
+func (r *Player) broadcastAppearance() {
+	msg := &protocol.S2CEntityAppearanceReq{
+		EntityHdl:  r.GetHdl(),
+		Appearance: r.appearance,
+	}
+	if err := r.SendProtoMessage(uint16(protocol.S2CProtocol_S2CEntityAppearance), msg); err != nil {
+		log.Warnf("send appearance to self failed: %v", err)
+	}
+	for _, et := range r.GetAOISys().GetVisibleEntities() {
+		if et.GetEntityType() != uint32(protocol.EntityType_EtPlayer) {
+			continue
+		}
+		_ = et.SendProtoMessage(uint16(protocol.S2CProtocol_S2CEntityAppearance), msg)
+	}
+}
+
+// BuildProtoEntitySt 在基础实体数据上附加外观
+func (r *Player) BuildProtoEntitySt() *protocol.EntitySt {
+	st := r.BaseEntity.BuildProtoEntitySt()
+	st.Appearance = r.appearance
+	return st
+}
+
+// AddExp 通知 PlayerActor 增加经验
+func (r *Player) AddExp(exp int64) error {
+	if exp <= 0 {
+		return nil
+	}
+	if r.sessionId == "" {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "session id is empty")
+	}
+	payload, err := proto.Marshal(&protocol.PAMAddExpReq{Exp: exp})
+	if err != nil {
+		return customerr.Wrap(err)
+	}
+	ctx := context.WithValue(context.Background(), gshare.ContextKeySession, r.sessionId)
+	actorMsg := actor.NewBaseMessage(ctx, uint16(protocol.PlayerActorMsgId_PAMAddExp), payload)
+	return gshare.SendMessageAsync(r.sessionId, actorMsg)
+}
+
 func (r *Player) SendMessage(protoId uint16, data []byte) error {
 	if r.sessionId == "" {
 		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "session id is empty")
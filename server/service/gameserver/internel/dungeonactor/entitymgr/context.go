@@ -0,0 +1,48 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/5
+ * @Desc: DungeonActor 分片消息上下文
+**/
+
+package entitymgr
+
+import (
+	"context"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/iface"
+	"postapocgame/server/service/gameserver/internel/gshare"
+)
+
+// WithFuBen 将处理消息的副本实例写入 Context，由 DungeonActor 分片在分发消息前调用
+func WithFuBen(ctx context.Context, fb iface.IFuBen) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, gshare.ContextKeyFuBen, fb)
+}
+
+// FuBenFromContext 获取处理消息的副本实例
+func FuBenFromContext(ctx context.Context) iface.IFuBen {
+	if ctx == nil {
+		return nil
+	}
+	fb, _ := ctx.Value(gshare.ContextKeyFuBen).(iface.IFuBen)
+	return fb
+}
+
+// FromContext 获取处理消息的副本实例的实体管理器，不在分片内时返回 nil
+func FromContext(ctx context.Context) iface.IEntityMgr {
+	fb := FuBenFromContext(ctx)
+	if fb == nil {
+		return nil
+	}
+	return fb.GetEntityMgr()
+}
+
+// GetBySession 在处理消息的副本实例内按 sessionId 查找实体
+func GetBySession(ctx context.Context, sessionId string) (iface.IEntity, bool) {
+	entityMgr := FromContext(ctx)
+	if entityMgr == nil {
+		return nil, false
+	}
+	return entityMgr.GetBySession(sessionId)
+}
@@ -6,14 +6,21 @@
 
 package entitymgr
 
-import "math"
+import (
+	"math"
+	"sync"
+)
 
 var (
+	// 句柄全局唯一，各 DungeonActor 分片并发创建实体
+	handleMu sync.Mutex
 	magicMap = make(map[uint32]uint16)
 	idxMap   = make(map[uint32]uint32)
 )
 
 func CreateEntityHandle(et uint32) uint64 {
+	handleMu.Lock()
+	defer handleMu.Unlock()
 	if _, ok := magicMap[et]; !ok {
 		magicMap[et] = 1
 	}
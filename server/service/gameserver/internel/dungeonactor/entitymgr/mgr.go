@@ -6,27 +6,23 @@ import (
 	"time"
 )
 
-// EntityMgr 全局实体管理器
+// EntityMgr 副本实例内的实体管理器
+// 每个副本实例持有一个，只在该实例所属的 DungeonActor 分片内访问，无需加锁
 type EntityMgr struct {
 	entities     map[uint64]iface2.IEntity // hdl -> entity
 	sessions     map[string]uint64         // sessionId -> entity hdl
 	entityScenes map[uint64]iface2.IScene
 }
 
-var (
-	globalEntityMgr *EntityMgr
-)
+var _ iface2.IEntityMgr = (*EntityMgr)(nil)
 
-// GetEntityMgr 获取全局实体管理器
-func GetEntityMgr() *EntityMgr {
-	if globalEntityMgr == nil {
-		globalEntityMgr = &EntityMgr{
-			entities:     make(map[uint64]iface2.IEntity),
-			sessions:     make(map[string]uint64),
-			entityScenes: make(map[uint64]iface2.IScene),
-		}
+// NewEntityMgr 创建实体管理器
+func NewEntityMgr() *EntityMgr {
+	return &EntityMgr{
+		entities:     make(map[uint64]iface2.IEntity),
+		sessions:     make(map[string]uint64),
+		entityScenes: make(map[uint64]iface2.IScene),
 	}
-	return globalEntityMgr
 }
 
 // Register 注册实体
@@ -126,11 +122,6 @@ func (m *EntityMgr) RunOne(now time.Time) {
 	}
 }
 
-// RunOne 遍历所有实体并执行单帧逻辑（全局入口）
-func RunOne(now time.Time) {
-	GetEntityMgr().RunOne(now)
-}
-
 // GetCount 获取实体数量
 func (m *EntityMgr) GetCount() int {
 	return len(m.entities)
@@ -139,5 +130,18 @@ func (m *EntityMgr) GetCount() int {
 // Clear 清空所有实体
 func (m *EntityMgr) Clear() {
 	m.entities = make(map[uint64]iface2.IEntity)
+	m.sessions = make(map[string]uint64)
 	m.entityScenes = make(map[uint64]iface2.IScene)
 }
+
+// GetSceneByEntity 获取实体所在场景，实体未进入场景时返回 false
+func GetSceneByEntity(et iface2.IEntity) (iface2.IScene, bool) {
+	if et == nil {
+		return nil, false
+	}
+	entityMgr := et.GetEntityMgr()
+	if entityMgr == nil {
+		return nil, false
+	}
+	return entityMgr.GetSceneByHandle(et.GetHdl())
+}
@@ -0,0 +1,103 @@
+/**
+ * @Author: zjj
+ * @Date: 2025/12/27
+ * @Desc: 怪物AI行为策略
+**/
+
+package entitysystem
+
+import (
+	"postapocgame/server/internal/jsonconf"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/iface"
+)
+
+// AIBehavior AI行为策略，负责索敌与技能选择，状态流转由 AISys 统一处理
+type AIBehavior interface {
+	// FindTarget 主动索敌，返回 nil 表示不主动攻击
+	FindTarget(ai *AISys) iface.IEntity
+	// SelectSkill 选择可对目标释放的技能，返回 0 表示本次不出手
+	SelectSkill(ai *AISys, target iface.IEntity, distance uint32) uint32
+}
+
+var aiBehaviors = make(map[protocol.AIType]AIBehavior)
+
+// RegisterAIBehavior 注册AI行为，怪物配置 ai.type 对应 protocol.AIType
+func RegisterAIBehavior(aiType protocol.AIType, behavior AIBehavior) {
+	aiBehaviors[aiType] = behavior
+}
+
+func getAIBehavior(aiType protocol.AIType) AIBehavior {
+	return aiBehaviors[aiType]
+}
+
+func init() {
+	RegisterAIBehavior(protocol.AIType_AITypePassive, &passiveBehavior{})
+	RegisterAIBehavior(protocol.AIType_AITypeAggressive, &aggressiveBehavior{})
+}
+
+// passiveBehavior 被动怪：不主动索敌，受击后反击
+type passiveBehavior struct{}
+
+func (b *passiveBehavior) FindTarget(*AISys) iface.IEntity {
+	return nil
+}
+
+// SelectSkill 在冷却完毕、距离足够且魔法值足够的单体技能中选伤害最高的
+func (b *passiveBehavior) SelectSkill(ai *AISys, target iface.IEntity, distance uint32) uint32 {
+	owner := ai.GetOwner()
+	fightSys := owner.GetFightSys()
+	configMgr := jsonconf.GetConfigManager()
+
+	var bestId uint32
+	var bestCfg *jsonconf.SkillConfig
+	for _, skillId := range fightSys.GetSkillIds() {
+		if !fightSys.IsSkillReady(skillId) {
+			continue
+		}
+		cfg := configMgr.GetSkillConfig(skillId)
+		if cfg == nil || cfg.TargetType != uint32(protocol.SkillTargetType_SkillTargetTypeSingle) {
+			continue
+		}
+		if distance > cfg.Range || !hasEnoughMP(owner, cfg) {
+			continue
+		}
+		if bestCfg == nil || cfg.Damage > bestCfg.Damage || (cfg.Damage == bestCfg.Damage && skillId < bestId) {
+			bestId, bestCfg = skillId, cfg
+		}
+	}
+	return bestId
+}
+
+// aggressiveBehavior 主动怪：索敌范围内选择最近的玩家
+type aggressiveBehavior struct {
+	passiveBehavior
+}
+
+func (b *aggressiveBehavior) FindTarget(ai *AISys) iface.IEntity {
+	aggroRange := ai.GetConfig().AggroRange
+	if aggroRange == 0 {
+		return nil
+	}
+	scene := ai.GetScene()
+	if scene == nil {
+		return nil
+	}
+
+	ownerPos := ai.GetOwner().GetPosition()
+	var nearest iface.IEntity
+	var nearestDist uint32
+	for _, et := range scene.GetEntitiesInRange(ownerPos) {
+		if et.GetEntityType() != uint32(protocol.EntityType_EtPlayer) || !ai.isValidTarget(et) {
+			continue
+		}
+		dist := tileDistance(ownerPos, et.GetPosition())
+		if dist > aggroRange {
+			continue
+		}
+		if nearest == nil || dist < nearestDist {
+			nearest, nearestDist = et, dist
+		}
+	}
+	return nearest
+}
@@ -0,0 +1,378 @@
+/**
+ * @Author: zjj
+ * @Date: 2025/12/27
+ * @Desc: 怪物AI状态机
+**/
+
+package entitysystem
+
+import (
+	"math"
+	"postapocgame/server/internal/argsdef"
+	"postapocgame/server/internal/attrdef"
+	"postapocgame/server/internal/jsonconf"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/entitymgr"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/iface"
+	"time"
+)
+
+const (
+	defaultAIThinkInterval = 200 * time.Millisecond
+	defaultAIAttackRange   = 1 // 没有可用技能配置时的攻击距离（格子）
+)
+
+// AISys 怪物AI状态机：待机 -> 巡逻 -> 追击 -> 攻击 -> 返回
+//
+// 通过组合 MoveSys/FightSys 实现行为，随实体 RunOne 在 DungeonActor 单线程中推进，不额外起协程
+type AISys struct {
+	owner    iface.IEntity
+	cfg      *jsonconf.MonsterAIConfig
+	behavior AIBehavior
+
+	state        protocol.AIState
+	stateEnterAt time.Time
+	nextThinkAt  time.Time
+
+	homePos     argsdef.Position // 出生点，脱战后返回
+	targetHdl   uint64
+	patrolIdx   int
+	attackRange uint32 // 懒计算，技能中最远的攻击距离
+}
+
+// NewAISys 创建AI，配置为空或AI类型未注册时返回 nil
+func NewAISys(owner iface.IEntity, cfg *jsonconf.MonsterAIConfig, homeX, homeY uint32) *AISys {
+	if owner == nil || cfg == nil {
+		return nil
+	}
+	behavior := getAIBehavior(protocol.AIType(cfg.Type))
+	if behavior == nil {
+		return nil
+	}
+	return &AISys{
+		owner:    owner,
+		cfg:      cfg,
+		behavior: behavior,
+		state:    protocol.AIState_AIStateIdle,
+		homePos:  argsdef.Position{X: homeX, Y: homeY},
+	}
+}
+
+// GetOwner 获取AI所属实体
+func (ai *AISys) GetOwner() iface.IEntity {
+	return ai.owner
+}
+
+// GetConfig 获取AI配置
+func (ai *AISys) GetConfig() *jsonconf.MonsterAIConfig {
+	return ai.cfg
+}
+
+// GetState 获取当前AI状态
+func (ai *AISys) GetState() protocol.AIState {
+	return ai.state
+}
+
+// GetScene 获取所属实体当前场景
+func (ai *AISys) GetScene() iface.IScene {
+	scene, ok := entitymgr.GetSceneByEntity(ai.owner)
+	if !ok {
+		return nil
+	}
+	return scene
+}
+
+// Reset 清除目标并回到待机，死亡或重生时调用
+func (ai *AISys) Reset() {
+	ai.targetHdl = 0
+	ai.patrolIdx = 0
+	ai.state = protocol.AIState_AIStateIdle
+	ai.stateEnterAt = time.Time{}
+	ai.nextThinkAt = time.Time{}
+}
+
+// OnAttacked 受击时反击，返回途中不响应
+func (ai *AISys) OnAttacked(attacker iface.IEntity) {
+	if attacker == nil || ai.owner.IsDead() {
+		return
+	}
+	switch ai.state {
+	case protocol.AIState_AIStateIdle, protocol.AIState_AIStatePatrol:
+		if ai.isValidTarget(attacker) {
+			ai.targetHdl = attacker.GetHdl()
+			ai.owner.GetMoveSys().StopMove(true, false)
+			ai.changeState(protocol.AIState_AIStateChase, ai.stateEnterAt)
+			ai.nextThinkAt = time.Time{}
+		}
+	}
+}
+
+// RunOne 推进移动并按决策间隔执行状态逻辑
+func (ai *AISys) RunOne(now time.Time) {
+	if ai.owner.IsDead() {
+		return
+	}
+	moveSys := ai.owner.GetMoveSys()
+	if moveSys.IsMoving() {
+		moveSys.MovingTime(false)
+	}
+
+	if now.Before(ai.nextThinkAt) {
+		return
+	}
+	ai.nextThinkAt = now.Add(ai.thinkInterval())
+	if ai.stateEnterAt.IsZero() {
+		ai.stateEnterAt = now
+	}
+
+	switch ai.state {
+	case protocol.AIState_AIStateIdle:
+		ai.onIdle(now)
+	case protocol.AIState_AIStatePatrol:
+		ai.onPatrol(now)
+	case protocol.AIState_AIStateChase:
+		ai.onChase(now)
+	case protocol.AIState_AIStateAttack:
+		ai.onAttack(now)
+	case protocol.AIState_AIStateReturning:
+		ai.onReturn(now)
+	}
+}
+
+func (ai *AISys) onIdle(now time.Time) {
+	if ai.tryEngage(now) {
+		return
+	}
+	if len(ai.cfg.PatrolPath) == 0 {
+		return
+	}
+	if now.Sub(ai.stateEnterAt) >= time.Duration(ai.cfg.PatrolWaitMs)*time.Millisecond {
+		ai.changeState(protocol.AIState_AIStatePatrol, now)
+	}
+}
+
+func (ai *AISys) onPatrol(now time.Time) {
+	if ai.tryEngage(now) {
+		return
+	}
+	if len(ai.cfg.PatrolPath) == 0 {
+		ai.changeState(protocol.AIState_AIStateIdle, now)
+		return
+	}
+
+	destX, destY := ai.patrolDest()
+	pos := ai.owner.GetPosition()
+	if pos.X == destX && pos.Y == destY {
+		ai.patrolIdx = (ai.patrolIdx + 1) % len(ai.cfg.PatrolPath)
+		ai.changeState(protocol.AIState_AIStateIdle, now)
+		return
+	}
+
+	moveSys := ai.owner.GetMoveSys()
+	if moveSys.IsMoving() {
+		return
+	}
+	if !moveSys.MoveToTile(destX, destY) {
+		// 巡逻点不可达，跳过
+		ai.patrolIdx = (ai.patrolIdx + 1) % len(ai.cfg.PatrolPath)
+		ai.changeState(protocol.AIState_AIStateIdle, now)
+	}
+}
+
+func (ai *AISys) onChase(now time.Time) {
+	target := ai.getTarget()
+	if target == nil || ai.isOutOfLeash() {
+		ai.startReturn(now)
+		return
+	}
+
+	moveSys := ai.owner.GetMoveSys()
+	if tileDistance(ai.owner.GetPosition(), target.GetPosition()) <= ai.getAttackRange() {
+		moveSys.StopMove(true, false)
+		ai.changeState(protocol.AIState_AIStateAttack, now)
+		ai.onAttack(now)
+		return
+	}
+
+	// 目标位置变化后重新发起移动
+	targetPos := target.GetPosition()
+	wantPx, wantPy := argsdef.TileCoordToPixel(targetPos.X, targetPos.Y)
+	destPx, destPy := moveSys.GetMoveDest()
+	if moveSys.IsMoving() && destPx == int32(wantPx) && destPy == int32(wantPy) {
+		return
+	}
+	moveSys.MoveToTile(targetPos.X, targetPos.Y)
+}
+
+func (ai *AISys) onAttack(now time.Time) {
+	target := ai.getTarget()
+	if target == nil {
+		// 当前目标失效，尝试切换目标，否则返回出生点
+		if !ai.tryEngage(now) {
+			ai.startReturn(now)
+		}
+		return
+	}
+	if ai.isOutOfLeash() {
+		ai.startReturn(now)
+		return
+	}
+
+	distance := tileDistance(ai.owner.GetPosition(), target.GetPosition())
+	if distance > ai.getAttackRange() {
+		ai.changeState(protocol.AIState_AIStateChase, now)
+		return
+	}
+
+	skillId := ai.behavior.SelectSkill(ai, target, distance)
+	if skillId == 0 {
+		return
+	}
+	targetPos := target.GetPosition()
+	ret := ai.owner.GetFightSys().UseSkill(&argsdef.SkillCastContext{
+		SkillId:   skillId,
+		TargetHdl: target.GetHdl(),
+		PosX:      targetPos.X,
+		PosY:      targetPos.Y,
+	})
+	if ret != int(protocol.SkillUseErr_SkillUseErrSuccess) {
+		log.Debugf("[AISys] entity=%d use skill %d failed ret=%d", ai.owner.GetHdl(), skillId, ret)
+	}
+}
+
+func (ai *AISys) onReturn(now time.Time) {
+	pos := ai.owner.GetPosition()
+	if pos.X == ai.homePos.X && pos.Y == ai.homePos.Y {
+		// 回到出生点，恢复满状态
+		ai.owner.SetHP(ai.owner.GetMaxHP())
+		ai.owner.SetMP(ai.owner.GetMaxMP())
+		ai.changeState(protocol.AIState_AIStateIdle, now)
+		return
+	}
+
+	moveSys := ai.owner.GetMoveSys()
+	if moveSys.IsMoving() || ai.owner.HasState(uint32(protocol.EntityStateFlag_EntityStateFlagCannotMove)) {
+		return
+	}
+	if !moveSys.MoveToTile(ai.homePos.X, ai.homePos.Y) {
+		// 无法走回时直接拉回出生点
+		if scene := ai.GetScene(); scene != nil {
+			if err := scene.EntityMove(ai.owner.GetHdl(), ai.homePos.X, ai.homePos.Y); err != nil {
+				log.Warnf("[AISys] entity=%d return home failed: %v", ai.owner.GetHdl(), err)
+				ai.changeState(protocol.AIState_AIStateIdle, now)
+			}
+		}
+	}
+}
+
+// tryEngage 通过行为策略索敌，找到目标后进入追击
+func (ai *AISys) tryEngage(now time.Time) bool {
+	target := ai.behavior.FindTarget(ai)
+	if target == nil {
+		return false
+	}
+	ai.targetHdl = target.GetHdl()
+	ai.owner.GetMoveSys().StopMove(true, false)
+	ai.changeState(protocol.AIState_AIStateChase, now)
+	return true
+}
+
+func (ai *AISys) startReturn(now time.Time) {
+	ai.targetHdl = 0
+	ai.owner.GetMoveSys().StopMove(true, false)
+	ai.changeState(protocol.AIState_AIStateReturning, now)
+}
+
+func (ai *AISys) changeState(state protocol.AIState, now time.Time) {
+	if ai.state == state {
+		return
+	}
+	ai.state = state
+	ai.stateEnterAt = now
+}
+
+func (ai *AISys) getTarget() iface.IEntity {
+	if ai.targetHdl == 0 {
+		return nil
+	}
+	entityMgr := ai.owner.GetEntityMgr()
+	if entityMgr == nil {
+		return nil
+	}
+	target, ok := entityMgr.GetByHdl(ai.targetHdl)
+	if !ok || !ai.isValidTarget(target) {
+		ai.targetHdl = 0
+		return nil
+	}
+	return target
+}
+
+// isValidTarget 目标需存活、可被攻击且与自己在同一场景
+func (ai *AISys) isValidTarget(target iface.IEntity) bool {
+	if target == nil || target.GetHdl() == ai.owner.GetHdl() || !target.CanBeAttacked() {
+		return false
+	}
+	ownerScene, ok1 := entitymgr.GetSceneByEntity(ai.owner)
+	targetScene, ok2 := entitymgr.GetSceneByEntity(target)
+	return ok1 && ok2 && ownerScene == targetScene
+}
+
+func (ai *AISys) isOutOfLeash() bool {
+	if ai.cfg.LeashRange == 0 {
+		return false
+	}
+	return tileDistance(ai.owner.GetPosition(), &ai.homePos) > ai.cfg.LeashRange
+}
+
+func (ai *AISys) patrolDest() (uint32, uint32) {
+	point := ai.cfg.PatrolPath[ai.patrolIdx%len(ai.cfg.PatrolPath)]
+	x := int64(ai.homePos.X) + int64(point.Dx)
+	y := int64(ai.homePos.Y) + int64(point.Dy)
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+	return uint32(x), uint32(y)
+}
+
+func (ai *AISys) getAttackRange() uint32 {
+	if ai.attackRange > 0 {
+		return ai.attackRange
+	}
+	configMgr := jsonconf.GetConfigManager()
+	for _, skillId := range ai.owner.GetFightSys().GetSkillIds() {
+		cfg := configMgr.GetSkillConfig(skillId)
+		if cfg == nil || cfg.TargetType != uint32(protocol.SkillTargetType_SkillTargetTypeSingle) {
+			continue
+		}
+		if cfg.Range > ai.attackRange {
+			ai.attackRange = cfg.Range
+		}
+	}
+	if ai.attackRange == 0 {
+		ai.attackRange = defaultAIAttackRange
+	}
+	return ai.attackRange
+}
+
+func (ai *AISys) thinkInterval() time.Duration {
+	if ai.cfg.ThinkIntervalMs == 0 {
+		return defaultAIThinkInterval
+	}
+	return time.Duration(ai.cfg.ThinkIntervalMs) * time.Millisecond
+}
+
+// tileDistance 两个格子坐标之间的距离（格子），与技能距离判定保持一致
+func tileDistance(a, b *argsdef.Position) uint32 {
+	dx := float64(int64(a.X) - int64(b.X))
+	dy := float64(int64(a.Y) - int64(b.Y))
+	return uint32(math.Sqrt(dx*dx + dy*dy))
+}
+
+// hasEnoughMP 施法者魔法值是否满足技能消耗
+func hasEnoughMP(caster iface.IEntity, cfg *jsonconf.SkillConfig) bool {
+	return caster.GetAttrSys().GetAttrValue(attrdef.MP) >= int64(cfg.ManaCost)
+}
@@ -8,7 +8,7 @@ import (
 // AOISys 负责跟踪实体可见列表与九宫格变化，供广播层增量同步。
 type AOISys struct {
 	entity          iface.IEntity
-	visibleEntities map[uint64]iface.IEntity // 可见的实体列表
+	visibleEntities map[uint64]iface.IEntity // 可见的实体列表 hdl -> entity
 	pendingEnter    []iface.IEntity
 	pendingLeave    []uint64
 }
@@ -31,19 +31,19 @@ func (aoi *AOISys) GetVisibleEntities() []iface.IEntity {
 
 // AddVisibleEntity 添加可见实体
 func (aoi *AOISys) AddVisibleEntity(entity iface.IEntity) {
-	aoi.visibleEntities[entity.GetId()] = entity
+	aoi.visibleEntities[entity.GetHdl()] = entity
 	aoi.pendingEnter = append(aoi.pendingEnter, entity)
 }
 
 // RemoveVisibleEntity 移除可见实体
-func (aoi *AOISys) RemoveVisibleEntity(entityId uint64) {
-	delete(aoi.visibleEntities, entityId)
-	aoi.pendingLeave = append(aoi.pendingLeave, entityId)
+func (aoi *AOISys) RemoveVisibleEntity(entityHdl uint64) {
+	delete(aoi.visibleEntities, entityHdl)
+	aoi.pendingLeave = append(aoi.pendingLeave, entityHdl)
 }
 
 // IsVisible 检查实体是否在视野内
-func (aoi *AOISys) IsVisible(entityId uint64) bool {
-	_, ok := aoi.visibleEntities[entityId]
+func (aoi *AOISys) IsVisible(entityHdl uint64) bool {
+	_, ok := aoi.visibleEntities[entityHdl]
 	return ok
 }
 
@@ -63,6 +63,20 @@ func (as *AttrSys) AddAttrValue(attrType uint32, delta int64) {
 	}
 }
 
+// SetSysAttrs 设置某个属性系统提供的战斗属性
+// 旧的系统属性先从汇总中扣除，再累加新值，不影响Buff等直接修改的属性
+func (as *AttrSys) SetSysAttrs(sysId uint32, attrs map[uint32]int64) {
+	calc := as.attrSet.GetIncAttr(sysId, true, false)
+	as.fightAttr.SubCalc(calc)
+	calc.Reset()
+	for attrType, value := range attrs {
+		if attrdef.IsFightAttr(attrType) {
+			calc.SetValue(attrType, value)
+		}
+	}
+	as.fightAttr.AddCalc(calc)
+}
+
 // RunOne 每帧更新（由实体 RunOne 调用）
 func (as *AttrSys) RunOne() {
 }
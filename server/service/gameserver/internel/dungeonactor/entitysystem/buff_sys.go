@@ -0,0 +1,310 @@
+/**
+ * @Author: zjj
+ * @Date: 2025/12/27
+ * @Desc: Buff系统实现
+**/
+
+package entitysystem
+
+import (
+	"postapocgame/server/internal/attrdef"
+	"postapocgame/server/internal/jsonconf"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/internal/servertime"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/entitymgr"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/iface"
+	"sort"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+var _ iface.IBuffSys = (*BuffSys)(nil)
+
+// buffSt 实体身上的单个Buff
+type buffSt struct {
+	cfg        *jsonconf.BuffConfig
+	casterHdl  uint64
+	stack      uint32
+	endAt      time.Time        // 零值表示永久
+	nextTickAt time.Time        // 下一次周期效果时间
+	attrDeltas map[uint32]int64 // 已作用到属性系统的修正值，移除时原样扣回
+}
+
+func (b *buffSt) toProto() *protocol.BuffSt {
+	st := &protocol.BuffSt{
+		BuffId:    b.cfg.BuffId,
+		CasterHdl: b.casterHdl,
+		Stack:     b.stack,
+	}
+	if !b.endAt.IsZero() {
+		st.EndTimeMs = b.endAt.UnixMilli()
+	}
+	return st
+}
+
+// BuffSys 管理实体身上的Buff：叠加规则、持续时间、周期伤害/治疗以及属性与状态修正
+type BuffSys struct {
+	et       iface.IEntity
+	buffs    map[uint32]*buffSt
+	stateRef map[uint32]uint32 // 状态引用计数，多个Buff施加同一状态时最后一个移除才清除
+}
+
+func NewBuffSys() *BuffSys {
+	return &BuffSys{
+		buffs:    make(map[uint32]*buffSt),
+		stateRef: make(map[uint32]uint32),
+	}
+}
+
+func (bs *BuffSys) SetEntity(et iface.IEntity) {
+	bs.et = et
+}
+
+// AddBuff 添加Buff，已存在时按叠加规则刷新或叠层
+func (bs *BuffSys) AddBuff(buffId uint32, caster iface.IEntity, durationMs uint32) bool {
+	cfg := jsonconf.GetConfigManager().GetBuffConfig(buffId)
+	if cfg == nil {
+		log.Warnf("[BuffSys] buff config not found: %d", buffId)
+		return false
+	}
+	if bs.et.IsDead() {
+		return false
+	}
+	if durationMs == 0 {
+		durationMs = cfg.Duration
+	}
+	var casterHdl uint64
+	if caster != nil {
+		casterHdl = caster.GetHdl()
+	}
+	now := servertime.Now()
+
+	if buff, ok := bs.buffs[buffId]; ok {
+		switch protocol.BuffStackRule(cfg.StackRule) {
+		case protocol.BuffStackRule_BuffStackRuleIgnore:
+			return false
+		case protocol.BuffStackRule_BuffStackRuleStack:
+			if buff.stack < cfg.MaxStack {
+				bs.removeAttrs(buff)
+				buff.stack++
+				bs.applyAttrs(buff)
+			}
+		}
+		buff.casterHdl = casterHdl
+		buff.endAt = calcBuffEndAt(now, durationMs)
+		bs.broadcast(uint16(protocol.S2CProtocol_S2CBuffRefresh), &protocol.S2CBuffRefreshReq{
+			EntityHdl: bs.et.GetHdl(),
+			Buff:      buff.toProto(),
+		})
+		return true
+	}
+
+	buff := &buffSt{
+		cfg:        cfg,
+		casterHdl:  casterHdl,
+		stack:      1,
+		endAt:      calcBuffEndAt(now, durationMs),
+		attrDeltas: make(map[uint32]int64),
+	}
+	if cfg.TickMs > 0 {
+		buff.nextTickAt = now.Add(time.Duration(cfg.TickMs) * time.Millisecond)
+	}
+	bs.buffs[buffId] = buff
+	bs.applyAttrs(buff)
+	bs.applyStates(buff)
+	bs.broadcast(uint16(protocol.S2CProtocol_S2CBuffAdd), &protocol.S2CBuffAddReq{
+		EntityHdl: bs.et.GetHdl(),
+		Buff:      buff.toProto(),
+	})
+	return true
+}
+
+// RemoveBuff 移除Buff并撤销其属性与状态修正
+func (bs *BuffSys) RemoveBuff(buffId uint32) bool {
+	buff, ok := bs.buffs[buffId]
+	if !ok {
+		return false
+	}
+	delete(bs.buffs, buffId)
+	bs.removeAttrs(buff)
+	bs.removeStates(buff)
+	bs.broadcast(uint16(protocol.S2CProtocol_S2CBuffRemove), &protocol.S2CBuffRemoveReq{
+		EntityHdl: bs.et.GetHdl(),
+		BuffId:    buffId,
+	})
+	return true
+}
+
+// ClearBuffs 移除所有Buff（死亡、离开场景时调用）
+func (bs *BuffSys) ClearBuffs() {
+	for buffId := range bs.buffs {
+		bs.RemoveBuff(buffId)
+	}
+}
+
+func (bs *BuffSys) HasBuff(buffId uint32) bool {
+	_, ok := bs.buffs[buffId]
+	return ok
+}
+
+func (bs *BuffSys) GetBuffStack(buffId uint32) uint32 {
+	if buff, ok := bs.buffs[buffId]; ok {
+		return buff.stack
+	}
+	return 0
+}
+
+// BuildProtoBuffs 构建下发给客户端的Buff列表（按BuffId排序）
+func (bs *BuffSys) BuildProtoBuffs() []*protocol.BuffSt {
+	if len(bs.buffs) == 0 {
+		return nil
+	}
+	list := make([]*protocol.BuffSt, 0, len(bs.buffs))
+	for _, buff := range bs.buffs {
+		list = append(list, buff.toProto())
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].BuffId < list[j].BuffId
+	})
+	return list
+}
+
+// RunOne 结算周期效果并移除到期Buff
+func (bs *BuffSys) RunOne(now time.Time) {
+	for buffId, buff := range bs.buffs {
+		if bs.et.IsDead() {
+			return
+		}
+		if buff.cfg.TickMs > 0 {
+			bs.tick(buff, now)
+		}
+		// 周期伤害可能导致死亡并清空Buff
+		if _, ok := bs.buffs[buffId]; !ok {
+			continue
+		}
+		if !buff.endAt.IsZero() && !now.Before(buff.endAt) {
+			bs.RemoveBuff(buffId)
+		}
+	}
+}
+
+// tick 补齐到当前时间为止的所有周期效果，持续时间结束时刻的那一跳也生效
+func (bs *BuffSys) tick(buff *buffSt, now time.Time) {
+	interval := time.Duration(buff.cfg.TickMs) * time.Millisecond
+	for !now.Before(buff.nextTickAt) {
+		if !buff.endAt.IsZero() && buff.nextTickAt.After(buff.endAt) {
+			return
+		}
+		buff.nextTickAt = buff.nextTickAt.Add(interval)
+		bs.applyTick(buff)
+		if bs.et.IsDead() {
+			return
+		}
+	}
+}
+
+func (bs *BuffSys) applyTick(buff *buffSt) {
+	stack := int64(buff.stack)
+	if heal := buff.cfg.TickHeal * stack; heal > 0 {
+		hp := bs.et.GetHP() + heal
+		if maxHP := bs.et.GetMaxHP(); hp > maxHP {
+			hp = maxHP
+		}
+		bs.et.SetHP(hp)
+	}
+	if damage := buff.cfg.TickDamage * stack; damage > 0 && !bs.et.IsInvincible() {
+		var caster iface.IEntity
+		if entityMgr := bs.et.GetEntityMgr(); buff.casterHdl != 0 && entityMgr != nil {
+			caster, _ = entityMgr.GetByHdl(buff.casterHdl)
+		}
+		bs.et.OnAttacked(caster, damage)
+	}
+}
+
+func (bs *BuffSys) applyAttrs(buff *buffSt) {
+	attrSys := bs.et.GetAttrSys()
+	stack := int64(buff.stack)
+	for _, attr := range buff.cfg.Attrs {
+		if attr == nil {
+			continue
+		}
+		base := attrSys.GetAttrValue(attr.Type)
+		delta := (attr.Value + base*attr.Rate/10000) * stack
+		if delta == 0 {
+			continue
+		}
+		attrSys.AddAttrValue(attr.Type, delta)
+		buff.attrDeltas[attr.Type] += delta
+	}
+	bs.clampHPMP()
+}
+
+func (bs *BuffSys) removeAttrs(buff *buffSt) {
+	if len(buff.attrDeltas) == 0 {
+		return
+	}
+	attrSys := bs.et.GetAttrSys()
+	for attrType, delta := range buff.attrDeltas {
+		attrSys.AddAttrValue(attrType, -delta)
+	}
+	buff.attrDeltas = make(map[uint32]int64)
+	bs.clampHPMP()
+}
+
+// clampHPMP 上限属性变化后修正当前生命/魔法
+func (bs *BuffSys) clampHPMP() {
+	if maxHP := bs.et.GetAttrSys().GetAttrValue(attrdef.MaxHP); bs.et.GetHP() > maxHP {
+		bs.et.SetHP(maxHP)
+	}
+	if maxMP := bs.et.GetAttrSys().GetAttrValue(attrdef.MaxMP); bs.et.GetMP() > maxMP {
+		bs.et.SetMP(maxMP)
+	}
+}
+
+func (bs *BuffSys) applyStates(buff *buffSt) {
+	for _, state := range buff.cfg.States {
+		bs.stateRef[state]++
+		if bs.stateRef[state] == 1 {
+			bs.et.AddState(state)
+		}
+		if state == uint32(protocol.EntityStateFlag_EntityStateFlagCannotMove) {
+			bs.et.GetMoveSys().StopMove(true, false)
+		}
+	}
+}
+
+func (bs *BuffSys) removeStates(buff *buffSt) {
+	for _, state := range buff.cfg.States {
+		if bs.stateRef[state] == 0 {
+			continue
+		}
+		bs.stateRef[state]--
+		if bs.stateRef[state] == 0 {
+			delete(bs.stateRef, state)
+			bs.et.RemoveState(state)
+		}
+	}
+}
+
+// broadcast 通知视野内的玩家（包括自己）
+func (bs *BuffSys) broadcast(protoId uint16, msg proto.Message) {
+	scene, ok := entitymgr.GetSceneByEntity(bs.et)
+	if !ok || scene == nil {
+		return
+	}
+	for _, et := range scene.GetEntitiesInRange(bs.et.GetPosition()) {
+		if et.GetEntityType() != uint32(protocol.EntityType_EtPlayer) {
+			continue
+		}
+		_ = et.SendProtoMessage(protoId, msg)
+	}
+}
+
+func calcBuffEndAt(now time.Time, durationMs uint32) time.Time {
+	if durationMs == 0 {
+		return time.Time{}
+	}
+	return now.Add(time.Duration(durationMs) * time.Millisecond)
+}
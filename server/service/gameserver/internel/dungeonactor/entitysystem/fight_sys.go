@@ -11,6 +11,7 @@ import (
 	"postapocgame/server/service/gameserver/internel/dungeonactor/entitymgr"
 	iface2 "postapocgame/server/service/gameserver/internel/dungeonactor/iface"
 	skill2 "postapocgame/server/service/gameserver/internel/dungeonactor/skill"
+	"postapocgame/server/service/gameserver/internel/gshare"
 	"time"
 
 	"google.golang.org/protobuf/proto"
@@ -57,6 +58,21 @@ func (s *FightSys) HasSkill(skillId uint32) bool {
 	return ok
 }
 
+// GetSkillIds 获取已学习的技能列表
+func (s *FightSys) GetSkillIds() []uint32 {
+	ids := make([]uint32, 0, len(s.skills))
+	for skillId := range s.skills {
+		ids = append(ids, skillId)
+	}
+	return ids
+}
+
+// IsSkillReady 技能是否已学习且不在冷却中
+func (s *FightSys) IsSkillReady(skillId uint32) bool {
+	sk := s.skills[skillId]
+	return sk != nil && sk.CheckCd()
+}
+
 func (s *FightSys) UseSkill(ctx *argsdef.SkillCastContext) int {
 	caster := s.et
 	log.Infof("=== Skill Cast Start === Caster=%d, SkillId=%d", caster.GetHdl(), ctx.SkillId)
@@ -67,6 +83,10 @@ func (s *FightSys) UseSkill(ctx *argsdef.SkillCastContext) int {
 		return int(protocol.SkillUseErr_ErrSkillNotLearned)
 	}
 
+	if caster.IsDead() || caster.HasState(uint32(protocol.EntityStateFlag_EntityStateFlagCannotAttack)) {
+		return int(protocol.SkillUseErr_ErrSkillCannotCast)
+	}
+
 	if !sk.CheckCd() {
 		return int(protocol.SkillUseErr_ErrSkillInCooldown)
 	}
@@ -84,9 +104,81 @@ func (s *FightSys) UseSkill(ctx *argsdef.SkillCastContext) int {
 	if result == nil {
 		return int(protocol.SkillUseErr_ErrSkillCannotCast)
 	}
+	if result.Success {
+		s.applyCastResult(skillCfg, result)
+		s.broadcastCastResult(skillId, result)
+	}
 	return int(result.ErrCode)
 }
 
+// broadcastCastResult 将命中结果广播给施法者视野内的玩家
+func (s *FightSys) broadcastCastResult(skillId uint32, result *protocol.SkillCastResult) {
+	scene, err := getSceneByEntity(s.et)
+	if err != nil {
+		return
+	}
+	data, err := proto.Marshal(&protocol.S2CSkillDamageReq{
+		CasterHdl: s.et.GetHdl(),
+		SkillId:   skillId,
+		Hits:      result.Hits,
+	})
+	if err != nil {
+		log.Errorf("marshal skill damage payload failed: %v", err)
+		return
+	}
+	for _, et := range scene.GetEntitiesInRange(s.et.GetPosition()) {
+		if et.GetEntityType() != uint32(protocol.EntityType_EtPlayer) {
+			continue
+		}
+		_ = et.SendMessage(uint16(protocol.S2CProtocol_S2CSkillDamage), data)
+	}
+}
+
+// applyCastResult 将技能结算结果作用到目标身上（扣血/治疗/Buff）
+func (s *FightSys) applyCastResult(skillCfg *jsonconf.SkillConfig, result *protocol.SkillCastResult) {
+	// 技能效果上配置的Buff持续时间
+	buffDurations := make(map[uint32]uint32)
+	for _, effect := range skillCfg.Effects {
+		if effect != nil && effect.Type == uint32(protocol.SkillResultType_SkillResultTypeAddBuff) {
+			buffDurations[effect.Value] = effect.Duration
+		}
+	}
+
+	entityMgr := s.et.GetEntityMgr()
+	if entityMgr == nil {
+		return
+	}
+	for _, hit := range result.Hits {
+		if hit == nil || !hit.IsHit || hit.IsDodge {
+			continue
+		}
+		target, ok := entityMgr.GetByHdl(hit.TargetHdl)
+		if !ok || target == nil || target.IsDead() {
+			continue
+		}
+		if hit.Heal > 0 {
+			hp := target.GetHP() + hit.Heal
+			if maxHP := target.GetMaxHP(); hp > maxHP {
+				hp = maxHP
+			}
+			target.SetHP(hp)
+		}
+		if hit.Damage > 0 {
+			target.OnAttacked(s.et, hit.Damage)
+		}
+		if target.IsDead() {
+			continue
+		}
+		buffSys := target.GetBuffSys()
+		for _, buffId := range hit.RemovedBuffs {
+			buffSys.RemoveBuff(buffId)
+		}
+		for _, buffId := range hit.AddedBuffs {
+			buffSys.AddBuff(buffId, s.et, buffDurations[buffId])
+		}
+	}
+}
+
 func (s *FightSys) RunOne(time.Time) {}
 
 const normalAttackSkillID = 1001
@@ -100,13 +192,13 @@ func HandleUseSkill(msg actor.IActorMessage) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	sessionId, _ := ctx.Value("session").(string)
+	sessionId, _ := ctx.Value(gshare.ContextKeySession).(string)
 	if sessionId == "" {
 		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "not found session")
 	}
 
 	// 获取实体
-	entityAny, ok := entitymgr.GetEntityMgr().GetBySession(sessionId)
+	entityAny, ok := entitymgr.GetBySession(ctx, sessionId)
 	if !ok || entityAny == nil {
 		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "entity not found for session")
 	}
@@ -149,7 +241,7 @@ func getSceneByEntity(entity iface2.IEntity) (iface2.IScene, error) {
 	if entity == nil {
 		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "entity missing")
 	}
-	scene, ok := entitymgr.GetEntityMgr().GetSceneByHandle(entity.GetHdl())
+	scene, ok := entitymgr.GetSceneByEntity(entity)
 	if !ok {
 		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "scene not bound")
 	}
@@ -0,0 +1,160 @@
+/**
+ * @Author: zjj
+ * @Date: 2025/12/30
+ * @Desc: 客户端移动校验：速度、穿墙与位置容忍度
+**/
+
+package entitysystem
+
+import (
+	"math"
+	"postapocgame/server/internal/argsdef"
+	"postapocgame/server/internal/pathfind"
+	"postapocgame/server/internal/servertime"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/iface"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 移动违规原因
+const (
+	MoveViolationSpeed    = "speed"     // 上报速度或位移超过属性速度
+	MoveViolationWall     = "wall"      // 移动线段穿过不可行走格子
+	MoveViolationStartPos = "start_pos" // 起步位置与服务端位置偏差过大
+	MoveViolationEndPos   = "end_pos"   // 停止位置与服务端位置偏差过大
+)
+
+const (
+	// moveSpeedTolerance 客户端上报速度允许超出属性速度的比例
+	moveSpeedTolerance = 0.1
+	// moveLatencyMs 位移校验给予的网络延迟容忍（毫秒）
+	moveLatencyMs = 500
+	// moveToleranceBudget 容忍度预算上限（像素），用于吸收网络抖动造成的瞬时超速
+	moveToleranceBudget = argsdef.TileSize * 2
+	// moveToleranceRegen 容忍度预算每秒恢复量（像素）
+	moveToleranceRegen = argsdef.TileSize / 2
+	// moveStartPosTolerance 起步/停止位置与服务端位置允许的偏差（像素）
+	moveStartPosTolerance = argsdef.TileSize
+)
+
+// moveGuard 单个实体的移动容忍度预算
+type moveGuard struct {
+	budget    float64
+	lastRegen time.Time
+}
+
+func newMoveGuard() *moveGuard {
+	return &moveGuard{budget: moveToleranceBudget}
+}
+
+// consume 超出允许距离的部分从预算中扣除，预算不足时视为违规
+func (g *moveGuard) consume(excess float64) bool {
+	now := servertime.Now()
+	if !g.lastRegen.IsZero() {
+		g.budget += now.Sub(g.lastRegen).Seconds() * moveToleranceRegen
+		if g.budget > moveToleranceBudget {
+			g.budget = moveToleranceBudget
+		}
+	}
+	g.lastRegen = now
+	if excess <= 0 {
+		return true
+	}
+	if excess > g.budget {
+		return false
+	}
+	g.budget -= excess
+	return true
+}
+
+// segmentWalkable 判断两个格子之间直线经过的格子是否都可行走
+func segmentWalkable(scene iface.IScene, fromX, fromY, toX, toY uint32) bool {
+	return pathfind.LineWalkable(sceneWalkGrid{scene: scene},
+		pathfind.Point{X: int32(fromX), Y: int32(fromY)},
+		pathfind.Point{X: int32(toX), Y: int32(toY)})
+}
+
+// sceneWalkGrid 以场景可行走判断适配寻路格子接口（线段检测只用到 IsWalkable）
+type sceneWalkGrid struct {
+	scene iface.IScene
+}
+
+func (g sceneWalkGrid) Width() int32  { return math.MaxInt32 }
+func (g sceneWalkGrid) Height() int32 { return math.MaxInt32 }
+func (g sceneWalkGrid) IsWalkable(x, y int32) bool {
+	return g.scene.IsWalkable(int(x), int(y))
+}
+
+// MoveSuspicion 玩家移动违规记录，供 GM 工具查询
+type MoveSuspicion struct {
+	RoleId     uint64
+	Count      uint32            // 违规总次数
+	Reasons    map[string]uint32 // 违规原因 -> 次数
+	LastReason string
+	LastAt     time.Time
+}
+
+var moveSuspicions = struct {
+	sync.Mutex
+	records map[uint64]*MoveSuspicion
+}{records: make(map[uint64]*MoveSuspicion)}
+
+func recordMoveSuspicion(roleId uint64, reason string) uint32 {
+	moveSuspicions.Lock()
+	defer moveSuspicions.Unlock()
+	record, ok := moveSuspicions.records[roleId]
+	if !ok {
+		record = &MoveSuspicion{RoleId: roleId, Reasons: make(map[string]uint32)}
+		moveSuspicions.records[roleId] = record
+	}
+	record.Count++
+	record.Reasons[reason]++
+	record.LastReason = reason
+	record.LastAt = servertime.Now()
+	return record.Count
+}
+
+// GetMoveSuspicion 查询玩家移动违规记录
+func GetMoveSuspicion(roleId uint64) (MoveSuspicion, bool) {
+	moveSuspicions.Lock()
+	defer moveSuspicions.Unlock()
+	record, ok := moveSuspicions.records[roleId]
+	if !ok {
+		return MoveSuspicion{}, false
+	}
+	return copyMoveSuspicion(record), true
+}
+
+// ListMoveSuspicions 按违规次数从高到低列出全部记录
+func ListMoveSuspicions() []MoveSuspicion {
+	moveSuspicions.Lock()
+	list := make([]MoveSuspicion, 0, len(moveSuspicions.records))
+	for _, record := range moveSuspicions.records {
+		list = append(list, copyMoveSuspicion(record))
+	}
+	moveSuspicions.Unlock()
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].RoleId < list[j].RoleId
+	})
+	return list
+}
+
+// ResetMoveSuspicion 清除玩家移动违规记录
+func ResetMoveSuspicion(roleId uint64) {
+	moveSuspicions.Lock()
+	defer moveSuspicions.Unlock()
+	delete(moveSuspicions.records, roleId)
+}
+
+func copyMoveSuspicion(record *MoveSuspicion) MoveSuspicion {
+	cp := *record
+	cp.Reasons = make(map[string]uint32, len(record.Reasons))
+	for reason, count := range record.Reasons {
+		cp.Reasons[reason] = count
+	}
+	return cp
+}
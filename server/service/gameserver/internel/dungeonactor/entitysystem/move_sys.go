@@ -6,6 +6,7 @@ import (
 	"math"
 	"postapocgame/server/service/gameserver/internel/dungeonactor/entitymgr"
 	"postapocgame/server/service/gameserver/internel/dungeonactor/iface"
+	"postapocgame/server/service/gameserver/internel/gshare"
 	"time"
 
 	"google.golang.org/protobuf/proto"
@@ -41,8 +42,14 @@ type MoveSys struct {
 	lastClientPx   int32   // 上次客户端上报的像素X坐标
 	lastClientPy   int32   // 上次客户端上报的像素Y坐标
 	lastReportTime time.Time
+	guard          *moveGuard // 客户端移动校验的容忍度预算
 
 	moveData *protocol.MoveData // 移动数据，包含目标像素坐标
+
+	// 服务端寻路移动剩余的路点（格子坐标），当前段走完后继续下一段
+	waypoints []*argsdef.Position
+	// pathMoving 当前移动由 MoveByPath 驱动，走完最后一段需要下发停止，客户端不会自行结束
+	pathMoving bool
 }
 
 func (ms *MoveSys) logContext() string {
@@ -62,9 +69,15 @@ func NewMoveSys(entity iface.IEntity) *MoveSys {
 	return &MoveSys{
 		entity:   entity,
 		moveData: &protocol.MoveData{},
+		guard:    newMoveGuard(),
 	}
 }
 
+// SetEntity 绑定外层具体实体（Player/Monster），下发给自己的消息需要走外层实体的 SendMessage
+func (ms *MoveSys) SetEntity(entity iface.IEntity) {
+	ms.entity = entity
+}
+
 // BindScene 绑定场景
 func (ms *MoveSys) BindScene(scene iface.IScene) {
 	ms.scene = scene
@@ -83,8 +96,12 @@ func (ms *MoveSys) IsMoving() bool {
 	return ms.lastTime != 0
 }
 
-// GetMoveDest 获取移动目标坐标（像素坐标）
+// GetMoveDest 获取移动最终目标坐标（像素坐标），沿路点移动时为最后一个路点
 func (ms *MoveSys) GetMoveDest() (int32, int32) {
+	if n := len(ms.waypoints); n > 0 {
+		px, py := argsdef.TileCoordToPixel(ms.waypoints[n-1].X, ms.waypoints[n-1].Y)
+		return int32(px), int32(py)
+	}
 	if ms.moveData == nil {
 		return 0, 0
 	}
@@ -93,6 +110,8 @@ func (ms *MoveSys) GetMoveDest() (int32, int32) {
 
 // ResetState 重置移动状态
 func (ms *MoveSys) ResetState() {
+	ms.waypoints = nil
+	ms.pathMoving = false
 	ms.ClearMoveData()
 }
 
@@ -129,7 +148,7 @@ func (ms *MoveSys) MovingTime(mustStop bool) bool {
 		return false
 	}
 
-	if !et.CanBeAttacked() {
+	if !et.CanBeAttacked() || et.HasState(uint32(protocol.EntityStateFlag_EntityStateFlagCannotMove)) {
 		ms.StopMove(true, false)
 		return false
 	}
@@ -191,15 +210,104 @@ func (ms *MoveSys) MovingTime(mustStop bool) bool {
 		}
 	}
 
+	if stop && !mustStop && len(ms.waypoints) > 0 {
+		// 到达中间路点，继续下一段
+		if !ms.moveToGrid(gridX, gridY) {
+			ms.StopMove(true, false)
+			return false
+		}
+		return ms.startNextWaypoint()
+	}
+
+	if stop && !mustStop && ms.pathMoving {
+		// 寻路走完最后一段，先落到终点格子再广播停止
+		ok := ms.moveToGrid(gridX, gridY)
+		ms.StopMove(true, true)
+		return ok
+	}
+
 	if stop || mustStop {
 		ms.StopMove(mustStop, mustStop)
-		return ms.scene.EntityMove(et.GetHdl(), gridX, gridY) == nil
+		return ms.moveToGrid(gridX, gridY)
 	}
 
+	// lastX/lastY 已推进到当前点，剩余距离同步扣减，保证下一帧按剩余路程插值
 	ms.lastTime = now
 	ms.lastX = newX
 	ms.lastY = newY
-	return ms.scene.EntityMove(et.GetHdl(), gridX, gridY) == nil
+	ms.moveLen -= totalMoved
+	return ms.moveToGrid(gridX, gridY)
+}
+
+// moveToGrid 服务端驱动时更新实体所在格子，格子未变化时不触发场景移动
+func (ms *MoveSys) moveToGrid(gridX, gridY uint32) bool {
+	et := ms.entity
+	pos := et.GetPosition()
+	if pos.X == gridX && pos.Y == gridY {
+		return true
+	}
+	if ms.scene.EntityMove(et.GetHdl(), gridX, gridY) != nil {
+		return false
+	}
+	ms.flushAOIChanges()
+	return true
+}
+
+// MoveToTile 服务端寻路移动到目标格子（AI等服务端逻辑使用），后续由 MovingTime 推进
+func (ms *MoveSys) MoveToTile(tileX, tileY uint32) bool {
+	if ms.scene == nil || ms.entity == nil {
+		return false
+	}
+	pos := ms.entity.GetPosition()
+	if pos.X == tileX && pos.Y == tileY {
+		return false
+	}
+	path, err := ms.scene.FindPath(pos.X, pos.Y, tileX, tileY)
+	if err != nil {
+		log.Debugf("[MoveSys] %s find path to (%d,%d) failed: %v", ms.logContext(), tileX, tileY, err)
+		return false
+	}
+	return ms.MoveByPath(path)
+}
+
+// MoveByPath 沿路点列表移动（格子坐标，不含起点），每段广播 S2CStartMove，走完最后一段向场景内玩家（含自己）广播 S2CStopMove
+func (ms *MoveSys) MoveByPath(path []*argsdef.Position) bool {
+	if ms.scene == nil || ms.entity == nil || len(path) == 0 {
+		return false
+	}
+	ms.waypoints = path
+	ms.pathMoving = true
+	if ms.IsMoving() {
+		// 打断当前移动时不下发停止，直接以新路径的起步覆盖
+		ms.ClearMoveData()
+	}
+	return ms.startNextWaypoint()
+}
+
+// startNextWaypoint 以当前格子中心为起点移动到下一个路点
+// 服务端移动不需要客户端位置校验，失败时清空路点并通知客户端停止
+func (ms *MoveSys) startNextWaypoint() bool {
+	if len(ms.waypoints) == 0 {
+		return false
+	}
+	next := ms.waypoints[0]
+	ms.waypoints = ms.waypoints[1:]
+
+	wasMoving := ms.IsMoving()
+	ms.ClearMoveData()
+	pos := ms.entity.GetPosition()
+	px, py := argsdef.TileCoordToPixel(pos.X, pos.Y)
+	speed := uint32(ms.entity.GetAttrSys().GetAttrValue(attrdef.Speed))
+	if ret := ms.HandMove(ms.scene, next.X, next.Y, int32(px), int32(py), speed); ret != 0 {
+		ms.waypoints = nil
+		ms.pathMoving = false
+		if wasMoving {
+			ms.BroadcastStopMove(px, py, true, false)
+		}
+		return false
+	}
+	ms.BroadcastStartMove(int32(px), int32(py))
+	return true
 }
 
 // HandleStartMove 处理客户端起步移动
@@ -208,11 +316,36 @@ func (ms *MoveSys) HandleStartMove(scene iface.IScene, req *protocol.C2SStartMov
 		return nil
 	}
 
+	// 客户端主动移动时放弃服务端寻路的剩余路点
+	ms.waypoints = nil
+	ms.pathMoving = false
+
 	// 将客户端发送的像素坐标转换为格子坐标
 	toTileX, toTileY := argsdef.PixelCoordToTile(req.ToX, req.ToY)
 	log.Infof("[MoveSys] %s startMove fromPx=(%d,%d) -> destTile=(%d,%d) speed=%d",
 		ms.logContext(), req.FromX, req.FromY, toTileX, toTileY, req.Speed)
 
+	maxSpeed := ms.maxSpeed()
+	if float64(req.Speed) > maxSpeed*(1+moveSpeedTolerance) {
+		ms.rejectMove(MoveViolationSpeed, "start speed=%d max=%.0f", req.Speed, maxSpeed)
+		return nil
+	}
+	if !ms.IsMoving() {
+		// 静止起步时起点必须在服务端位置附近；移动中起步由 HandMove 内的位置更新校验
+		if deviation := ms.deviationFromServer(int32(req.FromX), int32(req.FromY)); deviation > moveStartPosTolerance {
+			ms.rejectMove(MoveViolationStartPos, "fromPx=(%d,%d) deviation=%.2f", req.FromX, req.FromY, deviation)
+			return nil
+		}
+	}
+
+	// 整条移动线段都必须可行走，防止穿墙；在 HandMove 修改移动状态前校验
+	// 起点与服务端位置的偏差已在上面（静止）或 HandMove 的位置更新（移动中）校验
+	fromTileX, fromTileY := argsdef.PixelCoordToTile(req.FromX, req.FromY)
+	if !segmentWalkable(scene, fromTileX, fromTileY, toTileX, toTileY) {
+		ms.rejectMove(MoveViolationWall, "segment (%d,%d)->(%d,%d) blocked", fromTileX, fromTileY, toTileX, toTileY)
+		return nil
+	}
+
 	// 处理移动开始
 	ret := ms.HandMove(scene, toTileX, toTileY, int32(req.FromX), int32(req.FromY), req.Speed)
 	if ret != 0 {
@@ -233,7 +366,7 @@ func (ms *MoveSys) HandMove(scene iface.IScene, tileX, tileY uint32, cPx, cPy in
 	}
 
 	// 检查实体是否可以移动
-	if !ms.entity.CanBeAttacked() {
+	if !ms.entity.CanBeAttacked() || ms.entity.HasState(uint32(protocol.EntityStateFlag_EntityStateFlagCannotMove)) {
 		return int32(protocol.ErrorCode_Param_Invalid)
 	}
 
@@ -318,42 +451,36 @@ func (ms *MoveSys) LocationUpdate(cPx, cPy int32) bool {
 		return true
 	}
 
-	// 获取移动速度（像素/秒）
-	speed := int32(ms.speed)
-	if speed <= 0 {
-		speed = int32(et.GetAttrSys().GetAttrValue(attrdef.Speed))
-		if speed <= 0 {
-			speed = int32(defaultMaxMoveSpeed)
-		}
-	}
+	// 校验使用服务端属性速度，不信任客户端上报的速度
+	speed := ms.maxSpeed()
 
 	// 计算时间差（给与客户端50ping的容忍度）
 	milli := servertime.Now().UnixMilli()
-	// 计算从上次上报到现在的时间差，加上50ping的容忍度（500ms）
-	last := milli - ms.lastTime + 500 // 500ms = 50ping的容忍度
+	// 计算从上次上报到现在的时间差，加上延迟容忍度
+	last := milli - ms.lastTime + moveLatencyMs
 	// 计算允许移动的最大像素距离：速度(像素/秒) * 时间(秒) = 距离(像素)
-	pix := int32(float64(speed) * float64(last) / 1000) // 允许移动的最大像素距离
+	pix := speed * float64(last) / 1000 // 允许移动的最大像素距离
 
 	// 转换为格子坐标进行校验
 	gridX := argsdef.PixelCoordToTileX(uint32(cPx))
 	gridY := argsdef.PixelCoordToTileY(uint32(cPy))
 
-	// 同步的点不可走，直接拉回
-	if !scene.IsWalkable(int(gridX), int(gridY)) {
-		log.Warnf("[MoveSys] %s location update rejected: Grid(%d,%d) not walkable (clientPx=%d,%d)",
-			ms.logContext(), gridX, gridY, cPx, cPy)
+	// 同步的点不可走或与服务端位置之间有阻挡，直接拉回
+	pos := et.GetPosition()
+	if !segmentWalkable(scene, pos.X, pos.Y, gridX, gridY) {
+		ms.rejectMove(MoveViolationWall, "segment (%d,%d)->(%d,%d) blocked clientPx=(%d,%d)",
+			pos.X, pos.Y, gridX, gridY, cPx, cPy)
 		return false
 	}
 
 	// 计算客户端移动距离（像素）：本次位置 - 上次位置
-	delPx := cPx - ms.lastClientPx
-	delPy := cPy - ms.lastClientPy
-
-	// 校验移动速度：如果移动距离的平方 > 允许距离的平方，说明移动过快
-	// 使用平方比较避免开方运算（delPx^2 + delPy^2 > pix^2）
-	if delPx*delPx+delPy*delPy > pix*pix {
-		log.Warnf("[MoveSys] %s location update too fast last=%dms speed=%d (deltaPx=%d deltaPy=%d) limit=%d dest=(%d,%d) prev=(%d,%d)",
-			ms.logContext(), last, speed, delPx, delPy, pix, cPx, cPy, ms.lastClientPx, ms.lastClientPy)
+	delPx := float64(cPx - ms.lastClientPx)
+	delPy := float64(cPy - ms.lastClientPy)
+
+	// 校验移动速度：超出允许距离的部分从容忍度预算扣除，预算不足说明移动过快
+	if moved := math.Sqrt(delPx*delPx + delPy*delPy); !ms.guard.consume(moved - pix) {
+		ms.rejectMove(MoveViolationSpeed, "last=%dms speed=%.0f moved=%.2f limit=%.2f dest=(%d,%d) prev=(%d,%d)",
+			last, speed, moved, pix, cPx, cPy, ms.lastClientPx, ms.lastClientPy)
 		return false
 	}
 
@@ -409,36 +536,20 @@ func (ms *MoveSys) HandleUpdateMove(scene iface.IScene, req *protocol.C2SUpdateM
 	dy := float64(clientPy - serverPy)
 	distance := math.Sqrt(dx*dx + dy*dy)
 
-	// 获取移动速度（像素/秒）
-	speed := float64(ms.speed)
-	if speed <= 0 {
-		speed = float64(ms.entity.GetAttrSys().GetAttrValue(attrdef.Speed))
-		if speed <= 0 {
-			speed = defaultMaxMoveSpeed
-		}
-	}
-
 	// 支持1s的误差：允许的最大误差距离 = 速度(像素/秒) * 1秒
-	maxErrorDistance := speed * 1.0 // 1秒的误差容忍度
+	maxErrorDistance := ms.maxSpeed() * 1.0 // 1秒的误差容忍度
 
-	// 如果差距很大，结束移动（防止客户端位置异常）
+	// 如果差距很大，拉回服务端当前位置（防止客户端位置异常导致的瞬移）
 	if distance > maxErrorDistance {
-		log.Warnf("[MoveSys] %s updateMove deviation=%.2f max=%.2f, serverPx=(%d,%d) clientPx=(%d,%d)",
-			ms.logContext(), distance, maxErrorDistance, serverPx, serverPy, clientPx, clientPy)
-		// 结束移动，最终坐标为服务端当前位置（上一个点）
-		// 这样可以防止客户端位置异常导致的瞬移
-		ms.HandleEndMove(scene, &protocol.C2SEndMoveReq{
-			PosX: uint32(serverPx),
-			PosY: uint32(serverPy),
-			// Seq 字段已废弃，不再使用
-		})
+		ms.rejectMove(MoveViolationSpeed, "updateMove deviation=%.2f max=%.2f, serverPx=(%d,%d) clientPx=(%d,%d)",
+			distance, maxErrorDistance, serverPx, serverPy, clientPx, clientPy)
 		return nil
 	}
 
 	// 否则更新客户端给过来最新的坐标
-	// 调用 LocationUpdate 进行位置校验和更新
+	// 调用 LocationUpdate 进行位置校验和更新，校验不通过时已拉回客户端
 	if !ms.LocationUpdate(clientPx, clientPy) {
-		// 位置校验失败（移动过快、位置不可行走等），结束移动
+		// 位置更新失败，结束移动
 		// 最终坐标仍为服务端当前位置
 		ms.HandleEndMove(scene, &protocol.C2SEndMoveReq{
 			PosX: uint32(serverPx),
@@ -463,6 +574,17 @@ func (ms *MoveSys) HandleEndMove(scene iface.IScene, req *protocol.C2SEndMoveReq
 	tileX, tileY := argsdef.PixelCoordToTile(req.PosX, req.PosY)
 	log.Infof("[MoveSys] %s endMove reqPx=(%d,%d) -> tile=(%d,%d)", ms.logContext(), req.PosX, req.PosY, tileX, tileY)
 
+	cPx, cPy := int32(req.PosX), int32(req.PosY)
+	if ms.IsMoving() {
+		// 停止点按移动中的位置更新校验，校验不通过时已拉回客户端
+		if ms.deviationFromServer(cPx, cPy) > 0 {
+			ms.LocationUpdate(cPx, cPy)
+		}
+	} else if deviation := ms.deviationFromServer(cPx, cPy); deviation > moveStartPosTolerance {
+		ms.rejectMove(MoveViolationEndPos, "endPx=(%d,%d) deviation=%.2f", cPx, cPy, deviation)
+		return nil
+	}
+
 	// 停止移动
 	ms.StopMove(true, true)
 
@@ -472,6 +594,41 @@ func (ms *MoveSys) HandleEndMove(scene iface.IScene, req *protocol.C2SEndMoveReq
 	return nil
 }
 
+// maxSpeed 服务端认可的移动速度（像素/秒），取 Speed 属性，未配置时使用默认速度
+func (ms *MoveSys) maxSpeed() float64 {
+	speed := float64(ms.entity.GetAttrSys().GetAttrValue(attrdef.Speed))
+	if speed <= 0 {
+		speed = defaultMaxMoveSpeed
+	}
+	return speed
+}
+
+// deviationFromServer 客户端像素坐标与服务端当前格子中心的距离（像素）
+func (ms *MoveSys) deviationFromServer(cPx, cPy int32) float64 {
+	pos := ms.entity.GetPosition()
+	px, py := argsdef.TileCoordToPixel(pos.X, pos.Y)
+	dx := float64(cPx - int32(px))
+	dy := float64(cPy - int32(py))
+	return math.Sqrt(dx*dx + dy*dy)
+}
+
+// rejectMove 移动校验不通过：记录违规、停止移动并把客户端拉回服务端位置
+func (ms *MoveSys) rejectMove(reason string, format string, args ...interface{}) {
+	et := ms.entity
+	count := recordMoveSuspicion(et.GetId(), reason)
+	log.Warnf("[MoveSys] %s move rejected reason=%s suspicion=%d: %s",
+		ms.logContext(), reason, count, fmt.Sprintf(format, args...))
+
+	ms.StopMove(true, false)
+	pos := et.GetPosition()
+	px, py := argsdef.TileCoordToPixel(pos.X, pos.Y)
+	_ = et.SendProtoMessage(uint16(protocol.S2CProtocol_S2CPosCorrect), &protocol.S2CPosCorrectReq{
+		EntityHdl: et.GetHdl(),
+		PosX:      px,
+		PosY:      py,
+	})
+}
+
 // BroadcastStartMove 广播开始移动
 func (ms *MoveSys) BroadcastStartMove(posX, posY int32) {
 	if ms.scene == nil || ms.moveData == nil {
@@ -497,13 +654,10 @@ func (ms *MoveSys) BroadcastStartMove(posX, posY int32) {
 		return
 	}
 
-	// 广播给场景内所有实体
+	// 广播给场景内所有实体（含自己）
 	for _, et := range ms.scene.GetAllEntities() {
 		_ = et.SendMessage(uint16(protocol.S2CProtocol_S2CStartMove), data)
 	}
-
-	// 也发送给自己
-	_ = ms.entity.SendMessage(uint16(protocol.S2CProtocol_S2CStartMove), data)
 }
 
 func (ms *MoveSys) BroadcastStopMove(posX, posY uint32, broadcast, sendToSelf bool) {
@@ -523,13 +677,13 @@ func (ms *MoveSys) BroadcastStopMove(posX, posY uint32, broadcast, sendToSelf bo
 		return
 	}
 
-	if sendToSelf {
-		ms.entity.SendMessage(uint16(protocol.S2CProtocol_S2CStopMove), data)
-	}
-
 	if !broadcast {
+		if sendToSelf {
+			_ = ms.entity.SendMessage(uint16(protocol.S2CProtocol_S2CStopMove), data)
+		}
 		return
 	}
+	// 场景广播已包含自己
 	for _, et := range ms.scene.GetAllEntities() {
 		if et.GetEntityType() != uint32(protocol.EntityType_EtPlayer) {
 			continue
@@ -540,6 +694,8 @@ func (ms *MoveSys) BroadcastStopMove(posX, posY uint32, broadcast, sendToSelf bo
 
 // StopMove 停止移动
 func (ms *MoveSys) StopMove(broadcast, sendToSelf bool) {
+	ms.waypoints = nil
+	ms.pathMoving = false
 	if !ms.IsMoving() {
 		return
 	}
@@ -630,12 +786,12 @@ func HandleStartMove(msg actor.IActorMessage) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	sessionId, _ := ctx.Value("session").(string)
+	sessionId, _ := ctx.Value(gshare.ContextKeySession).(string)
 	if sessionId == "" {
 		return nil
 	}
 
-	entity, ok := entitymgr.GetEntityMgr().GetBySession(sessionId)
+	entity, ok := entitymgr.GetBySession(ctx, sessionId)
 	if !ok || entity == nil {
 		return nil
 	}
@@ -665,12 +821,12 @@ func HandleUpdateMove(msg actor.IActorMessage) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	sessionId, _ := ctx.Value("session").(string)
+	sessionId, _ := ctx.Value(gshare.ContextKeySession).(string)
 	if sessionId == "" {
 		return nil
 	}
 
-	entity, ok := entitymgr.GetEntityMgr().GetBySession(sessionId)
+	entity, ok := entitymgr.GetBySession(ctx, sessionId)
 	if !ok || entity == nil {
 		return nil
 	}
@@ -700,12 +856,12 @@ func HandleEndMove(msg actor.IActorMessage) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	sessionId, _ := ctx.Value("session").(string)
+	sessionId, _ := ctx.Value(gshare.ContextKeySession).(string)
 	if sessionId == "" {
 		return nil
 	}
 
-	entity, ok := entitymgr.GetEntityMgr().GetBySession(sessionId)
+	entity, ok := entitymgr.GetBySession(ctx, sessionId)
 	if !ok || entity == nil {
 		return nil
 	}
@@ -7,35 +7,50 @@
 package fbmgr
 
 import (
+	"postapocgame/server/internal/actor"
 	"postapocgame/server/internal/jsonconf"
 	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
 	"postapocgame/server/pkg/log"
 	fuben2 "postapocgame/server/service/gameserver/internel/dungeonactor/fuben"
 	"postapocgame/server/service/gameserver/internel/dungeonactor/iface"
+	"sync"
 	"time"
 )
 
 // FuBenMgr 副本管理器
+// 同时作为 DungeonActor 分片的注册表与路由：每个副本实例由 key 为实例ID 的分片 Actor 独占驱动，
+// 玩家会话的消息按路由投递到其所在实例的分片
 type FuBenMgr struct {
+	mu     sync.RWMutex
 	fubens map[uint32]iface.IFuBen
+	routes map[string]uint32 // sessionId -> fbId
 
-	// 未来如需扩展多实例副本，可重新引入计数器与定期清理。
+	// 副本实例ID分配，0 保留给默认副本
+	nextInstanceId uint32
+	// 投递消息到副本实例所属分片，由 DungeonActor 设置
+	sender func(fbId uint32, msg actor.IActorMessage) error
 }
 
 var (
-	globalFuBenMgr *FuBenMgr
+	globalFuBenMgr = &FuBenMgr{
+		fubens: make(map[uint32]iface.IFuBen),
+		routes: make(map[string]uint32),
+	}
 )
 
 // GetFuBenMgr 获取全局副本管理器
 func GetFuBenMgr() *FuBenMgr {
-	if globalFuBenMgr == nil {
-		globalFuBenMgr = &FuBenMgr{
-			fubens: make(map[uint32]iface.IFuBen),
-		}
-	}
 	return globalFuBenMgr
 }
 
+// SetSender 设置分片投递函数
+func (m *FuBenMgr) SetSender(sender func(fbId uint32, msg actor.IActorMessage) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sender = sender
+}
+
 // CreateDefaultFuBen 创建默认副本
 func (m *FuBenMgr) CreateDefaultFuBen() error {
 	// 创建 fbId=0 的默认副本
@@ -70,41 +85,174 @@ func (m *FuBenMgr) CreateDefaultFuBen() error {
 	return nil
 }
 
+// CreateInstance 按副本配置创建限时副本实例，实例由 key 为实例ID 的分片驱动
+func (m *FuBenMgr) CreateInstance(cfg *jsonconf.FuBenConfig) (iface.IFuBen, error) {
+	if cfg == nil {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_FuBen_NotFound), "fuben config is nil")
+	}
+	configMgr := jsonconf.GetConfigManager()
+	sceneConfigs := make([]jsonconf.SceneConfig, 0, len(cfg.SceneIds))
+	for _, sceneId := range cfg.SceneIds {
+		sceneCfg := configMgr.GetSceneConfig(sceneId)
+		if sceneCfg == nil {
+			return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Scene_NotFound), "fuben %d scene %d not found", cfg.FuBenId, sceneId)
+		}
+		sceneConfigs = append(sceneConfigs, *sceneCfg)
+	}
+
+	instanceId := m.allocInstanceId()
+	maxDuration := time.Duration(cfg.MaxDurationSec) * time.Second
+	fb := fuben2.NewFuBenSt(instanceId, cfg.Name, uint32(protocol.FuBenType_FuBenTypeTimed), cfg.MaxPlayers, maxDuration)
+	fb.SetConfigId(cfg.FuBenId)
+	if cfg.Difficulty > 0 {
+		fb.SetDifficulty(cfg.Difficulty)
+	}
+	fb.InitScenes(sceneConfigs)
+	m.AddFuBen(fb)
+
+	log.Infof("FuBen instance created: fbId=%d fubenId=%d", instanceId, cfg.FuBenId)
+	return fb, nil
+}
+
+// allocInstanceId 分配未被占用的实例ID
+func (m *FuBenMgr) allocInstanceId() uint32 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for {
+		m.nextInstanceId++
+		if m.nextInstanceId == 0 {
+			continue
+		}
+		if _, exists := m.fubens[m.nextInstanceId]; !exists {
+			// 先占位，避免并发分配到同一ID
+			m.fubens[m.nextInstanceId] = nil
+			return m.nextInstanceId
+		}
+	}
+}
+
 // AddFuBen 添加副本
 func (m *FuBenMgr) AddFuBen(fb *fuben2.FuBenSt) {
+	m.mu.Lock()
 	m.fubens[fb.GetFbId()] = fb
+	m.mu.Unlock()
 	log.Infof("FuBen added: fbId=%d", fb.GetFbId())
 }
 
-// RemoveFuBen 移除副本
+// RemoveFuBen 注销副本，之后发往该实例的消息回落到默认副本
+// 副本的关闭由所属分片在注销前完成
 func (m *FuBenMgr) RemoveFuBen(fbId uint32) {
-	if fb, ok := m.fubens[fbId]; ok {
-		fb.Close()
-		delete(m.fubens, fbId)
-		log.Infof("FuBen removed: fbId=%d", fbId)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.fubens[fbId]; !ok {
+		return
 	}
+	delete(m.fubens, fbId)
+	for sessionId, routeFbId := range m.routes {
+		if routeFbId == fbId {
+			delete(m.routes, sessionId)
+		}
+	}
+	log.Infof("FuBen removed: fbId=%d", fbId)
 }
 
 // GetFuBen 获取副本
+// 其他分片只能读取副本的只读信息与原子状态，实例内数据只能由所属分片访问
 func (m *FuBenMgr) GetFuBen(fbId uint32) (iface.IFuBen, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	fb, ok := m.fubens[fbId]
-	return fb, ok
-}
-
-// RunOne 驱动所有副本的常驻逻辑
-func (m *FuBenMgr) RunOne(now time.Time) {
-	for _, fb := range m.fubens {
-		if fb != nil {
-			fb.RunOne(now)
-		}
+	if !ok || fb == nil {
+		return nil, false
 	}
+	return fb, true
 }
 
 // GetAllFubens 获取所有副本
 func (m *FuBenMgr) GetAllFubens() []iface.IFuBen {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	fubens := make([]iface.IFuBen, 0, len(m.fubens))
 	for _, fb := range m.fubens {
-		fubens = append(fubens, fb)
+		if fb != nil {
+			fubens = append(fubens, fb)
+		}
 	}
 	return fubens
 }
+
+// BindRoute 记录玩家会话所在的副本实例
+func (m *FuBenMgr) BindRoute(sessionId string, fbId uint32) {
+	if sessionId == "" {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.routes[sessionId] = fbId
+}
+
+// UnbindRoute 玩家离开副本实例时解除路由，路由已指向其他实例时不处理
+func (m *FuBenMgr) UnbindRoute(sessionId string, fbId uint32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if routeFbId, ok := m.routes[sessionId]; ok && routeFbId == fbId {
+		delete(m.routes, sessionId)
+	}
+}
+
+// GetRoute 获取玩家会话所在的副本实例
+func (m *FuBenMgr) GetRoute(sessionId string) (uint32, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	fbId, ok := m.routes[sessionId]
+	return fbId, ok
+}
+
+// SendToSession 将玩家会话的消息投递到其所在实例的分片，没有路由时投递到默认副本
+func (m *FuBenMgr) SendToSession(sessionId string, msg actor.IActorMessage) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.sendLocked(m.routes[sessionId], msg)
+}
+
+// SendToFuBen 将消息投递到指定实例的分片，实例不存在时投递到默认副本
+func (m *FuBenMgr) SendToFuBen(fbId uint32, msg actor.IActorMessage) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.sendLocked(fbId, msg)
+}
+
+// MoveSession 将玩家会话的路由切换到目标实例，并投递转移消息
+// 路由切换与投递在同一把写锁内完成，保证之后该会话的消息排在转移消息之后
+func (m *FuBenMgr) MoveSession(sessionId string, fbId uint32, msg actor.IActorMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if fb, ok := m.fubens[fbId]; !ok || fb == nil {
+		fbId = 0
+	}
+	m.routes[sessionId] = fbId
+	return m.sendLocked(fbId, msg)
+}
+
+// RebindSession 断线重连后将原会话的路由转给新会话，并投递换绑消息
+// 路由切换与投递在同一把写锁内完成，保证之后新会话的消息排在换绑消息之后
+func (m *FuBenMgr) RebindSession(oldSessionId, newSessionId string, msg actor.IActorMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	fbId := m.routes[oldSessionId]
+	delete(m.routes, oldSessionId)
+	if newSessionId != "" {
+		m.routes[newSessionId] = fbId
+	}
+	return m.sendLocked(fbId, msg)
+}
+
+func (m *FuBenMgr) sendLocked(fbId uint32, msg actor.IActorMessage) error {
+	if m.sender == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "dungeon actor sender not set")
+	}
+	if fb, ok := m.fubens[fbId]; !ok || fb == nil {
+		fbId = 0
+	}
+	return m.sender(fbId, msg)
+}
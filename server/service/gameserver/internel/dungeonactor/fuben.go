@@ -0,0 +1,293 @@
+/**
+ * @Author: zjj
+ * @Date: 2025/12/31
+ * @Desc: 副本实例进出与场景切换
+**/
+
+package dungeonactor
+
+import (
+	"context"
+	"postapocgame/server/internal/actor"
+	"postapocgame/server/internal/jsonconf"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/entitymgr"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/fbmgr"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/iface"
+	"postapocgame/server/service/gameserver/internel/gshare"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// handleEnterFuBen 处理进入副本请求，只能从常驻副本进入副本实例
+// 入口：protocol.DungeonActorMsgId_DAMEnterFuBen
+func handleEnterFuBen(msg actor.IActorMessage) error {
+	player, err := getPlayerFromMsg(msg)
+	if err != nil {
+		return err
+	}
+	var req protocol.C2SEnterFuBenReq
+	if err := proto.Unmarshal(msg.GetData(), &req); err != nil {
+		return customerr.Wrap(err)
+	}
+	if err := enterFuBen(player, req.FubenId, req.InstanceId); err != nil {
+		sendErrorToPlayer(player, err)
+		return err
+	}
+	return nil
+}
+
+// handleLeaveFuBen 处理离开副本请求，回到进入副本前的位置
+// 入口：protocol.DungeonActorMsgId_DAMLeaveFuBen
+func handleLeaveFuBen(msg actor.IActorMessage) error {
+	player, err := getPlayerFromMsg(msg)
+	if err != nil {
+		return err
+	}
+	fb := getPlayerFuBen(player)
+	if fb == nil || fb.GetFbType() != uint32(protocol.FuBenType_FuBenTypeTimed) {
+		err := customerr.NewErrorByCode(int32(protocol.ErrorCode_FuBen_NotInInstance), "player not in fuben instance")
+		sendErrorToPlayer(player, err)
+		return err
+	}
+	if err := returnFromFuBen(player); err != nil {
+		sendErrorToPlayer(player, err)
+		return err
+	}
+	return nil
+}
+
+// handleTransferScene 处理同副本内的场景切换
+// 入口：protocol.DungeonActorMsgId_DAMTransferScene
+func handleTransferScene(msg actor.IActorMessage) error {
+	player, err := getPlayerFromMsg(msg)
+	if err != nil {
+		return err
+	}
+	var req protocol.C2STransferSceneReq
+	if err := proto.Unmarshal(msg.GetData(), &req); err != nil {
+		return customerr.Wrap(err)
+	}
+	if err := transferScene(player, req.SceneId); err != nil {
+		sendErrorToPlayer(player, err)
+		return err
+	}
+	return nil
+}
+
+// enterFuBen 进入副本实例：在当前分片完成前置校验与实例分配，再把玩家转移到实例所属分片
+// 目标实例的状态与人数由其他分片维护，这里只做预检，最终以目标分片 OnPlayerEnter 为准
+func enterFuBen(player iface.IPlayer, fubenId, instanceId uint32) error {
+	if player.IsDead() {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "dead player cannot enter fuben")
+	}
+	fromScene, ok := entitymgr.GetSceneByEntity(player)
+	if !ok || fromScene == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "player scene not found")
+	}
+	if fromScene.GetFuBen().GetFbType() != uint32(protocol.FuBenType_FuBenTypePermanent) {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_FuBen_InInstance), "player already in fuben instance")
+	}
+	cfg := jsonconf.GetConfigManager().GetFuBenConfig(fubenId)
+	if cfg == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_FuBen_NotFound), "fuben config %d not found", fubenId)
+	}
+
+	fbMgr := fbmgr.GetFuBenMgr()
+	var fb iface.IFuBen
+	created := false
+	if instanceId != 0 {
+		fb, ok = fbMgr.GetFuBen(instanceId)
+		if !ok || fb == nil || fb.GetConfigId() != fubenId {
+			return customerr.NewErrorByCode(int32(protocol.ErrorCode_FuBen_NotFound), "fuben instance %d not found", instanceId)
+		}
+		if fb.GetState() != uint32(protocol.FuBenState_FuBenStateNormal) || fb.IsExpired() {
+			return customerr.NewErrorByCode(int32(protocol.ErrorCode_FuBen_Closed), "fuben instance %d closed", instanceId)
+		}
+		if !fb.CanEnter() {
+			return customerr.NewErrorByCode(int32(protocol.ErrorCode_FuBen_Full), "fuben instance %d is full", instanceId)
+		}
+	} else {
+		var err error
+		if fb, err = fbMgr.CreateInstance(cfg); err != nil {
+			return err
+		}
+		created = true
+	}
+
+	entrySceneId := cfg.SceneIds[0]
+	if fb.GetScene(entrySceneId) == nil {
+		if created {
+			fbMgr.RemoveFuBen(fb.GetFbId())
+		}
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Scene_NotFound), "fuben %d entry scene missing", fubenId)
+	}
+
+	pos := player.GetPosition()
+	player.SetReturnPoint(&iface.ReturnPoint{FbId: fromScene.GetFuBenId(), SceneId: fromScene.GetSceneId(), X: pos.X, Y: pos.Y})
+	if err := handoffPlayer(player, handoffTarget{fbId: fb.GetFbId(), sceneId: entrySceneId}); err != nil {
+		return err
+	}
+	log.Infof("[dungeon-actor] role %d entering fuben %d instance %d", player.GetId(), fubenId, fb.GetFbId())
+	return nil
+}
+
+// returnFromFuBen 离开副本实例，转移回返回点；返回点失效时由目标分片送回默认副本入口场景
+// 副本实例关闭时也通过该函数送回实例内的玩家
+func returnFromFuBen(player iface.IPlayer) error {
+	target := handoffTarget{fbId: 0}
+	if rp := player.GetReturnPoint(); rp != nil {
+		target = handoffTarget{fbId: rp.FbId, sceneId: rp.SceneId, x: rp.X, y: rp.Y, usePos: true}
+	}
+	player.SetReturnPoint(nil)
+	return handoffPlayer(player, target)
+}
+
+// evictFromFuBen 副本实例关闭时将玩家送回，由实例所属分片在关闭前调用
+func evictFromFuBen(fb iface.IFuBen, sessionId string) {
+	et, ok := fb.GetEntityMgr().GetBySession(sessionId)
+	if !ok || et == nil {
+		return
+	}
+	player, ok := et.(iface.IPlayer)
+	if !ok {
+		return
+	}
+	if err := returnFromFuBen(player); err != nil {
+		log.Errorf("[dungeon-actor] evict role %d from fuben failed: %v", player.GetId(), err)
+	}
+}
+
+func transferScene(player iface.IPlayer, sceneId uint32) error {
+	if player.IsDead() {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "dead player cannot transfer")
+	}
+	fromScene, ok := entitymgr.GetSceneByEntity(player)
+	if !ok || fromScene == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "player scene not found")
+	}
+	if fromScene.GetSceneId() == sceneId {
+		return nil
+	}
+	toScene := fromScene.GetFuBen().GetScene(sceneId)
+	if toScene == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Scene_NotFound), "scene %d not in current fuben", sceneId)
+	}
+	x, y := toScene.GetSpawnPos()
+	return changeScene(player, toScene, x, y)
+}
+
+// changeScene 将玩家移到同一副本实例内的目标场景 (x, y)，跨实例使用 handoffPlayer
+func changeScene(player iface.IPlayer, toScene iface.IScene, x, y uint32) error {
+	if fromScene, ok := entitymgr.GetSceneByEntity(player); ok && fromScene != nil {
+		notifyLeaveScene(fromScene, player)
+		if err := fromScene.RemoveEntity(player.GetHdl()); err != nil {
+			log.Warnf("[dungeon-actor] remove player from scene %d failed: %v", fromScene.GetSceneId(), err)
+		}
+	}
+
+	player.SetPosition(x, y)
+	return enterScene(player, toScene)
+}
+
+// enterScene 将已设置好位置的玩家加入场景，下发场景快照并通知场景内其他玩家
+func enterScene(player iface.IPlayer, scene iface.IScene) error {
+	if err := scene.AddEntity(player); err != nil {
+		return customerr.Wrap(err)
+	}
+	scene.GetFuBen().GetEntityMgr().BindSession(player.GetSessionId(), player.GetHdl())
+	fbmgr.GetFuBenMgr().BindRoute(player.GetSessionId(), scene.GetFuBenId())
+
+	enterSceneMsg := &protocol.S2CEnterSceneReq{
+		EntityData: player.BuildProtoEntitySt(),
+	}
+	if err := player.SendProtoMessage(uint16(protocol.S2CProtocol_S2CEnterScene), enterSceneMsg); err != nil {
+		log.Warnf("[dungeon-actor] send enter scene failed: %v", err)
+	}
+
+	fb := scene.GetFuBen()
+	fbInfo := &protocol.S2CFuBenInfoReq{
+		InstanceId: fb.GetFbId(),
+		FubenId:    fb.GetConfigId(),
+	}
+	if expireAt := fb.GetExpireTime(); !expireAt.IsZero() {
+		fbInfo.ExpireAtMs = expireAt.UnixMilli()
+	}
+	if err := player.SendProtoMessage(uint16(protocol.S2CProtocol_S2CFuBenInfo), fbInfo); err != nil {
+		log.Warnf("[dungeon-actor] send fuben info failed: %v", err)
+	}
+
+	for _, et := range scene.GetAllEntities() {
+		if et == nil || et.GetHdl() == player.GetHdl() {
+			continue
+		}
+
+		appear := &protocol.S2CEntityAppearReq{
+			Entity: et.BuildProtoEntitySt(),
+		}
+		if err := player.SendProtoMessage(uint16(protocol.S2CProtocol_S2CEntityAppear), appear); err != nil {
+			log.Warnf("[dungeon-actor] notify appear to player failed: %v", err)
+		}
+
+		if et.GetEntityType() == uint32(protocol.EntityType_EtPlayer) {
+			back := &protocol.S2CEntityAppearReq{Entity: player.BuildProtoEntitySt()}
+			_ = et.SendProtoMessage(uint16(protocol.S2CProtocol_S2CEntityAppear), back)
+		}
+	}
+	return nil
+}
+
+// notifyLeaveScene 通知场景内其他玩家该玩家消失
+func notifyLeaveScene(scene iface.IScene, player iface.IPlayer) {
+	disappear := &protocol.S2CEntityDisappearReq{EntityHdl: player.GetHdl()}
+	for _, et := range scene.GetAllEntities() {
+		if et == nil || et.GetHdl() == player.GetHdl() || et.GetEntityType() != uint32(protocol.EntityType_EtPlayer) {
+			continue
+		}
+		_ = et.SendProtoMessage(uint16(protocol.S2CProtocol_S2CEntityDisappear), disappear)
+	}
+}
+
+// getEntryScene 获取副本入口场景（场景ID最小的场景）
+func getEntryScene(fb iface.IFuBen) iface.IScene {
+	var entry iface.IScene
+	for _, sc := range fb.GetAllScenes() {
+		if entry == nil || sc.GetSceneId() < entry.GetSceneId() {
+			entry = sc
+		}
+	}
+	return entry
+}
+
+func getPlayerFuBen(player iface.IPlayer) iface.IFuBen {
+	scene, ok := entitymgr.GetSceneByEntity(player)
+	if !ok || scene == nil {
+		return nil
+	}
+	return scene.GetFuBen()
+}
+
+func getPlayerFromMsg(msg actor.IActorMessage) (iface.IPlayer, error) {
+	if msg == nil {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "nil message")
+	}
+	ctx := msg.GetContext()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	sessionId, _ := ctx.Value(gshare.ContextKeySession).(string)
+	if sessionId == "" {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "not found session")
+	}
+	et, ok := entitymgr.GetBySession(ctx, sessionId)
+	if !ok || et == nil {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "player entity not found")
+	}
+	player, ok := et.(iface.IPlayer)
+	if !ok {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "entity is not player")
+	}
+	return player, nil
+}
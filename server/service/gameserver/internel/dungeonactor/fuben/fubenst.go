@@ -10,19 +10,24 @@ import (
 	"postapocgame/server/service/gameserver/internel/dungeonactor/iface"
 	"postapocgame/server/service/gameserver/internel/dungeonactor/scene"
 	"postapocgame/server/service/gameserver/internel/dungeonactor/scenemgr"
+	"sync/atomic"
 	"time"
 )
 
 // FuBenSt 副本结构
+// 副本实例由所属的 DungeonActor 分片独占驱动；状态与人数会被其他分片在转移玩家前读取，使用原子变量
 type FuBenSt struct {
 	fbId       uint32
+	configId   uint32 // 副本配置ID，默认副本为 0
 	name       string
 	fbType     uint32
-	state      uint32
+	state      atomic.Uint32
 	difficulty uint32 // 难度: 1=普通 2=精英 3=地狱
 
-	// 场景管理
+	// 场景管理，InitScenes 之后只读
 	sceneMgr *scenemgr.SceneStMgr
+	// 实例内实体管理
+	entityMgr *entitymgr.EntityMgr
 
 	// 限时副本相关
 	createTime  time.Time
@@ -30,7 +35,7 @@ type FuBenSt struct {
 	maxDuration time.Duration // 最大存在时间
 
 	// 玩家相关
-	playerCount int
+	playerCount atomic.Int32
 	maxPlayers  int // 最大玩家数，0表示无限制
 
 	// 结算相关
@@ -49,18 +54,18 @@ func NewFuBenSt(fbId uint32, name string, fbType uint32, maxPlayers int, maxDura
 		fbId:            fbId,
 		name:            name,
 		fbType:          fbType,
-		state:           uint32(protocol.FuBenState_FuBenStateNormal),
 		difficulty:      1, // 默认普通难度
 		sceneMgr:        scenemgr.NewSceneStMgr(),
+		entityMgr:       entitymgr.NewEntityMgr(),
 		createTime:      servertime.Now(),
 		maxPlayers:      maxPlayers,
 		maxDuration:     maxDuration,
-		playerCount:     0,
 		startTime:       servertime.Now(),
 		killCount:       0,
 		playerSessions:  make(map[string]bool),
 		nextCycleUpdate: servertime.Now().Add(5 * time.Minute),
 	}
+	fb.state.Store(uint32(protocol.FuBenState_FuBenStateNormal))
 
 	// 如果是限时副本，设置过期时间
 	if fbType == uint32(protocol.FuBenType_FuBenTypeTimed) {
@@ -78,7 +83,7 @@ func (fb *FuBenSt) InitScenes(sceneConfigs []jsonconf.SceneConfig) {
 		sc := scene.NewSceneSt(fb, cfg.SceneId, fb.fbId, cfg.Name, cfg.Width, cfg.Height, cfg.GameMap, cfg.BornArea)
 		fb.sceneMgr.AddScene(sc)
 
-		// 场景怪物初始化在需要时动态生成
+		// 场景怪物由场景刷怪器在 RunOne 中按配置刷出
 
 		log.Infof("FuBen %d: Scene %d initialized", fb.fbId, cfg.SceneId)
 	}
@@ -97,12 +102,12 @@ func (fb *FuBenSt) GetAllScenes() []iface.IScene {
 // CanEnter 检查是否可以进入副本
 func (fb *FuBenSt) CanEnter() bool {
 	// 检查副本状态
-	if fb.state != uint32(protocol.FuBenState_FuBenStateNormal) {
+	if fb.state.Load() != uint32(protocol.FuBenState_FuBenStateNormal) {
 		return false
 	}
 
 	// 检查人数限制
-	if fb.maxPlayers > 0 && fb.playerCount >= fb.maxPlayers {
+	if fb.maxPlayers > 0 && int(fb.playerCount.Load()) >= fb.maxPlayers {
 		return false
 	}
 
@@ -116,42 +121,67 @@ func (fb *FuBenSt) CanEnter() bool {
 
 // OnPlayerEnter 玩家进入
 func (fb *FuBenSt) OnPlayerEnter(sessionId string) error {
-	if fb.state != uint32(protocol.FuBenState_FuBenStateNormal) {
+	if fb.state.Load() != uint32(protocol.FuBenState_FuBenStateNormal) {
 		return customerr.NewError("fuben is not available")
 	}
 
-	if fb.maxPlayers > 0 && fb.playerCount >= fb.maxPlayers {
+	if fb.maxPlayers > 0 && int(fb.playerCount.Load()) >= fb.maxPlayers {
 		return customerr.NewError("fuben is full")
 	}
 
-	fb.playerCount++
+	playerCount := fb.playerCount.Add(1)
 	fb.playerSessions[sessionId] = true
 
 	// 如果是第一个玩家进入，记录开始时间
-	if fb.playerCount == 1 {
+	if playerCount == 1 {
 		fb.startTime = servertime.Now()
 	}
 
-	log.Infof("Player entered FuBen %d, current players: %d", fb.fbId, fb.playerCount)
+	log.Infof("Player entered FuBen %d, current players: %d", fb.fbId, playerCount)
 
 	return nil
 }
 
 // OnPlayerLeave 玩家离开
 func (fb *FuBenSt) OnPlayerLeave(sessionId string) {
-	if fb.playerCount > 0 {
-		fb.playerCount--
+	if !fb.playerSessions[sessionId] {
+		return
 	}
 	delete(fb.playerSessions, sessionId)
+	playerCount := fb.playerCount.Add(-1)
 
-	log.Infof("Player left FuBen %d, current players: %d", fb.fbId, fb.playerCount)
+	log.Infof("Player left FuBen %d, current players: %d", fb.fbId, playerCount)
 
 	// 限时副本没人时标记为可关闭
-	if fb.fbType == uint32(protocol.FuBenType_FuBenTypeTimed) && fb.playerCount == 0 {
-		fb.state = uint32(protocol.FuBenState_FuBenStateClosing)
+	if fb.fbType == uint32(protocol.FuBenType_FuBenTypeTimed) && playerCount == 0 {
+		fb.state.CompareAndSwap(uint32(protocol.FuBenState_FuBenStateNormal), uint32(protocol.FuBenState_FuBenStateClosing))
 	}
 }
 
+// SetConfigId 设置副本配置ID（副本实例使用）
+func (fb *FuBenSt) SetConfigId(configId uint32) {
+	fb.configId = configId
+}
+
+// GetConfigId 获取副本配置ID
+func (fb *FuBenSt) GetConfigId() uint32 {
+	return fb.configId
+}
+
+// GetExpireTime 获取过期时间，零值表示不限时
+func (fb *FuBenSt) GetExpireTime() time.Time {
+	return fb.expireTime
+}
+
+// GetPlayerSessions 获取副本内所有玩家的 Session
+func (fb *FuBenSt) GetPlayerSessions() []string {
+	sessions := make([]string, 0, len(fb.playerSessions))
+	for sessionId := range fb.playerSessions {
+		sessions = append(sessions, sessionId)
+	}
+	return sessions
+}
+
 // SetDifficulty 设置难度
 func (fb *FuBenSt) SetDifficulty(difficulty uint32) {
 	fb.difficulty = difficulty
@@ -174,7 +204,7 @@ func (fb *FuBenSt) GetKillCount() uint32 {
 
 // GetPlayerCount 获取玩家数量
 func (fb *FuBenSt) GetPlayerCount() int {
-	return fb.playerCount
+	return int(fb.playerCount.Load())
 }
 
 // IsExpired 检查是否过期
@@ -186,47 +216,34 @@ func (fb *FuBenSt) IsExpired() bool {
 	return servertime.Now().After(fb.expireTime)
 }
 
-// Close 关闭副本
+// Close 关闭副本，清理实例内的全部实体
+// 玩家应已由所属分片转移出实例，残留的玩家实体直接移除
+// 场景管理器保留（其他分片可能仍在并发读取场景列表），实例随后从 FuBenMgr 注销
 func (fb *FuBenSt) Close() {
-	fb.state = uint32(protocol.FuBenState_FuBenStateClosed)
+	fb.state.Store(uint32(protocol.FuBenState_FuBenStateClosed))
 
-	// 踢出所有玩家（将玩家移回默认副本）
-	entityMgr := entitymgr.GetEntityMgr()
 	for sessionId := range fb.playerSessions {
-		roleEntity, ok := entityMgr.GetBySession(sessionId)
+		roleEntity, ok := fb.entityMgr.GetBySession(sessionId)
 		if !ok || roleEntity == nil {
-			log.Warnf("Entity not found for session %s when closing FuBen", sessionId)
 			continue
 		}
-
-		// 从当前场景移除实体
-		if currentScene, ok := entityMgr.GetSceneByHandle(roleEntity.GetHdl()); ok && currentScene != nil {
-			if err := currentScene.RemoveEntity(roleEntity.GetHdl()); err != nil {
-				log.Warnf("remove entity from scene failed: %v", err)
-			}
-		}
+		log.Warnf("Player entity %d still in FuBen %d when closing, removed", roleEntity.GetId(), fb.fbId)
+		fb.entityMgr.UnbindSession(sessionId)
 	}
 
 	// 清理场景数据
-	if fb.sceneMgr != nil {
-		allScenes := fb.sceneMgr.GetAllScenes()
-		for _, sc := range allScenes {
-			// 清理场景中的所有实体
-			allEntities := sc.GetAllEntities()
-			for _, et := range allEntities {
-				if et.GetEntityType() == uint32(protocol.EntityType_EtPlayer) {
-					continue
-				}
-				sc.RemoveEntity(et.GetHdl())
+	for _, sc := range fb.sceneMgr.GetAllScenes() {
+		for _, et := range sc.GetAllEntities() {
+			if err := sc.RemoveEntity(et.GetHdl()); err != nil {
+				log.Warnf("remove entity from scene failed: %v", err)
 			}
 		}
-		// 清空场景管理器
-		fb.sceneMgr = scenemgr.NewSceneStMgr()
 	}
+	fb.entityMgr.Clear()
 
 	// 清空玩家列表
 	fb.playerSessions = make(map[string]bool)
-	fb.playerCount = 0
+	fb.playerCount.Store(0)
 
 	log.Infof("FuBen %d closed, all players kicked and scenes cleared", fb.fbId)
 }
@@ -248,7 +265,12 @@ func (fb *FuBenSt) GetFbType() uint32 {
 
 // GetState 获取副本状态
 func (fb *FuBenSt) GetState() uint32 {
-	return fb.state
+	return fb.state.Load()
+}
+
+// GetEntityMgr 获取副本实例内的实体管理器
+func (fb *FuBenSt) GetEntityMgr() iface.IEntityMgr {
+	return fb.entityMgr
 }
 
 // RunOne 副本常驻逻辑
@@ -256,17 +278,20 @@ func (fb *FuBenSt) RunOne(now time.Time) {
 	// 限时副本过期检查
 	if fb.fbType == uint32(protocol.FuBenType_FuBenTypeTimed) {
 		if !fb.expireTime.IsZero() && now.After(fb.expireTime) {
-			// 副本已过期，踢出所有玩家
-			if fb.state == uint32(protocol.FuBenState_FuBenStateNormal) {
-				fb.state = uint32(protocol.FuBenState_FuBenStateClosing)
+			// 副本已过期，由 FuBenMgr 将玩家送回进入前的位置并回收实例
+			if fb.state.CompareAndSwap(uint32(protocol.FuBenState_FuBenStateNormal), uint32(protocol.FuBenState_FuBenStateClosing)) {
 				log.Infof("FuBen %d expired, kicking all players", fb.fbId)
-
-				// 通知所有玩家副本已过期
-				// TODO: 实现踢出玩家的逻辑
 			}
 		}
 	}
 
+	// 驱动场景逻辑（刷怪等）
+	if fb.state.Load() != uint32(protocol.FuBenState_FuBenStateClosed) {
+		for _, sc := range fb.sceneMgr.GetAllScenes() {
+			sc.RunOne(now)
+		}
+	}
+
 	// 世界周期切换
 	if fb.nextCycleUpdate.IsZero() {
 		fb.nextCycleUpdate = now.Add(5 * time.Minute)
@@ -0,0 +1,158 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/11
+ * @Desc: GM 指令中需要在 DungeonActor 执行的部分（传送、刷怪、清怪、移动违规记录）
+**/
+
+package dungeonactor
+
+import (
+	"fmt"
+	"postapocgame/server/internal/actor"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/entitymgr"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/entitysystem"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/iface"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// maxMoveSuspicionList movesuspicion 列表最多返回的记录数
+const maxMoveSuspicionList = 20
+
+// handleGmTeleport 传送到当前副本内指定场景的坐标，权限已在 PlayerActor 校验
+// 入口：protocol.DungeonActorMsgId_DAMGmTeleport
+func handleGmTeleport(msg actor.IActorMessage) error {
+	player, err := getPlayerFromMsg(msg)
+	if err != nil {
+		return err
+	}
+	var req protocol.DAMGmTeleportReq
+	if err := proto.Unmarshal(msg.GetData(), &req); err != nil {
+		return customerr.Wrap(err)
+	}
+	if err := gmTeleport(player, req.SceneId, req.X, req.Y); err != nil {
+		sendErrorToPlayer(player, err)
+		return err
+	}
+	return nil
+}
+
+func gmTeleport(player iface.IPlayer, sceneId, x, y uint32) error {
+	fromScene, ok := entitymgr.GetSceneByEntity(player)
+	if !ok || fromScene == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "player scene not found")
+	}
+	toScene := fromScene
+	if sceneId != 0 && sceneId != fromScene.GetSceneId() {
+		toScene = fromScene.GetFuBen().GetScene(sceneId)
+		if toScene == nil {
+			return customerr.NewErrorByCode(int32(protocol.ErrorCode_Scene_NotFound), "scene %d not in current fuben", sceneId)
+		}
+	}
+	if !toScene.IsWalkable(int(x), int(y)) {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Gm_ArgInvalid), "(%d,%d) not walkable in scene %d", x, y, toScene.GetSceneId())
+	}
+	return changeScene(player, toScene, x, y)
+}
+
+// handleGmSpawnMonster 在玩家位置附近刷出怪物
+// 入口：protocol.DungeonActorMsgId_DAMGmSpawnMonster
+func handleGmSpawnMonster(msg actor.IActorMessage) error {
+	player, err := getPlayerFromMsg(msg)
+	if err != nil {
+		return err
+	}
+	var req protocol.DAMGmSpawnMonsterReq
+	if err := proto.Unmarshal(msg.GetData(), &req); err != nil {
+		return customerr.Wrap(err)
+	}
+	scene, ok := entitymgr.GetSceneByEntity(player)
+	if !ok || scene == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "player scene not found")
+	}
+	pos := player.GetPosition()
+	for i := uint32(0); i < req.Count; i++ {
+		if _, err := scene.SpawnMonster(req.MonsterId, pos.X, pos.Y); err != nil {
+			sendErrorToPlayer(player, err)
+			return err
+		}
+	}
+	log.Infof("[dungeon-actor] gm spawned monster %d x%d in scene %d", req.MonsterId, req.Count, scene.GetSceneId())
+	return nil
+}
+
+// handleGmKillAll 击杀玩家所在场景的全部怪物，经验与掉落归属该玩家
+// 入口：protocol.DungeonActorMsgId_DAMGmKillAll
+func handleGmKillAll(msg actor.IActorMessage) error {
+	player, err := getPlayerFromMsg(msg)
+	if err != nil {
+		return err
+	}
+	scene, ok := entitymgr.GetSceneByEntity(player)
+	if !ok || scene == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "player scene not found")
+	}
+	killed := scene.KillAllMonsters(player)
+	log.Infof("[dungeon-actor] gm killed %d monsters in scene %d", killed, scene.GetSceneId())
+	return nil
+}
+
+// handleGmMoveSuspicion 查询或清除移动违规记录，记录保存在 DungeonActor 所在进程，结果直接下发给 GM
+// 入口：protocol.DungeonActorMsgId_DAMGmMoveSuspicion
+func handleGmMoveSuspicion(msg actor.IActorMessage) error {
+	player, err := getPlayerFromMsg(msg)
+	if err != nil {
+		return err
+	}
+	var req protocol.DAMGmMoveSuspicionReq
+	if err := proto.Unmarshal(msg.GetData(), &req); err != nil {
+		return customerr.Wrap(err)
+	}
+	return player.SendProtoMessage(uint16(protocol.S2CProtocol_S2CGmCommand), &protocol.S2CGmCommandReq{
+		Name:    req.Name,
+		Success: true,
+		Msg:     gmMoveSuspicion(req.RoleId, req.Clear),
+	})
+}
+
+func gmMoveSuspicion(roleId uint64, reset bool) string {
+	if reset {
+		entitysystem.ResetMoveSuspicion(roleId)
+		log.Infof("[dungeon-actor] gm reset move suspicion of role %d", roleId)
+		return fmt.Sprintf("move suspicion of role %d cleared", roleId)
+	}
+	if roleId != 0 {
+		record, ok := entitysystem.GetMoveSuspicion(roleId)
+		if !ok {
+			return fmt.Sprintf("role %d has no move suspicion", roleId)
+		}
+		return formatMoveSuspicion(record)
+	}
+	list := entitysystem.ListMoveSuspicions()
+	if len(list) == 0 {
+		return "no move suspicion"
+	}
+	lines := make([]string, 0, min(len(list), maxMoveSuspicionList)+1)
+	lines = append(lines, fmt.Sprintf("%d roles with move suspicion", len(list)))
+	for i := 0; i < len(list) && i < maxMoveSuspicionList; i++ {
+		lines = append(lines, formatMoveSuspicion(list[i]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatMoveSuspicion 如 role 1001 count=3 last=wall@15:04:05 reasons=speed:1,wall:2
+func formatMoveSuspicion(record entitysystem.MoveSuspicion) string {
+	reasons := make([]string, 0, len(record.Reasons))
+	for reason, count := range record.Reasons {
+		reasons = append(reasons, fmt.Sprintf("%s:%d", reason, count))
+	}
+	sort.Strings(reasons)
+	return fmt.Sprintf("role %d count=%d last=%s@%s reasons=%s",
+		record.RoleId, record.Count, record.LastReason, record.LastAt.Format(time.TimeOnly), strings.Join(reasons, ","))
+}
@@ -1,9 +1,14 @@
 package dungeonactor
 
 import (
+	"context"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/routine"
 	"postapocgame/server/service/gameserver/internel/dungeonactor/entitymgr"
 	"postapocgame/server/service/gameserver/internel/dungeonactor/fbmgr"
-	"sync"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/iface"
+	"postapocgame/server/service/gameserver/internel/gshare"
+	"strconv"
 
 	"postapocgame/server/internal/actor"
 	"postapocgame/server/internal/servertime"
@@ -12,40 +17,125 @@ import (
 
 var _ actor.IActorHandler = (*Handler)(nil)
 
+// Handler DungeonActor 分片消息处理器
+// 每个副本实例一个分片，Actor key 为副本实例ID；实例内的实体、场景只在本分片的 goroutine 上访问
+// 消息处理函数注册在所有分片共享的 BaseActorHandler 上
 type Handler struct {
 	*actor.BaseActorHandler
-	inLoop sync.Mutex
+	actorCtx actor.IActorContext
+	fbId     uint32
+	fb       iface.IFuBen
+	closed   bool
 }
 
-// NewDungeonActorHandler 创建 DungeonActor 消息处理器
-func NewDungeonActorHandler() *Handler {
-	h := &Handler{
-		BaseActorHandler: actor.NewBaseActorHandler("dungeon_actor_handler"),
+// newShardHandler 创建分片消息处理器
+func newShardHandler(base *actor.BaseActorHandler) *Handler {
+	return &Handler{
+		BaseActorHandler: base,
 	}
-	h.OnInit()
+}
 
-	// 初始化默认副本
-	if err := fbmgr.GetFuBenMgr().CreateDefaultFuBen(); err != nil {
-		log.Errorf("[dungeon-actor] failed to create default fuben: %v", err)
+// SetActorContext 绑定分片 Actor，key 即副本实例ID
+// 此时可能持有 FuBenMgr 的锁，副本实例在分片 goroutine 上首次使用时再解析
+func (h *Handler) SetActorContext(ctx actor.IActorContext) {
+	h.actorCtx = ctx
+	fbId, err := strconv.ParseUint(ctx.GetID(), 10, 32)
+	if err != nil {
+		log.Errorf("[dungeon-actor] invalid shard key %s: %v", ctx.GetID(), err)
+		return
 	}
+	h.fbId = uint32(fbId)
+}
 
-	return h
+func (h *Handler) getFuBen() iface.IFuBen {
+	if h.fb == nil && !h.closed {
+		if fb, ok := fbmgr.GetFuBenMgr().GetFuBen(h.fbId); ok {
+			h.fb = fb
+		}
+	}
+	return h.fb
 }
 
-// Loop Actor 单线程循环，驱动实体和副本管理
-func (h *Handler) Loop() {
-	if h == nil {
+// OnStart 注册分片定时驱动，没有消息的实例也能按时刷怪、结算 Buff 与过期
+func (h *Handler) OnStart() {
+	h.actorCtx.Every(shardTickInterval, h.tick)
+}
+
+// tick 在分片 goroutine 上驱动本实例的实体与副本逻辑，实例进入关闭状态时送回玩家并回收分片
+func (h *Handler) tick() {
+	if h == nil || h.closed {
+		return
+	}
+	fb := h.getFuBen()
+	if fb == nil {
 		return
 	}
-	h.inLoop.Lock()
-	defer h.inLoop.Unlock()
 
 	now := servertime.Now()
-	entitymgr.RunOne(now)
-	fbmgr.GetFuBenMgr().RunOne(now)
+	fb.GetEntityMgr().RunOne(now)
+	fb.RunOne(now)
+	if fb.GetState() == uint32(protocol.FuBenState_FuBenStateClosing) {
+		h.closeFuBen(fb)
+	}
+}
+
+// closeFuBen 将实例内的玩家送回进入前的位置，然后关闭实例并回收分片
+func (h *Handler) closeFuBen(fb iface.IFuBen) {
+	h.closed = true
+	for _, sessionId := range fb.GetPlayerSessions() {
+		evictFromFuBen(fb, sessionId)
+	}
+	fb.Close()
+	fbmgr.GetFuBenMgr().RemoveFuBen(fb.GetFbId())
+
+	// 停止分片会等待分片 goroutine 退出，不能在分片内同步调用
+	key := h.actorCtx.GetID()
+	routine.GoV2(func() error {
+		return GetDungeonActor().removeShard(key)
+	})
 }
 
 // HandleMessage 处理 Actor 消息
+// 玩家已转移到其他实例时，残留在本分片邮箱中的该玩家消息转交给其当前所在分片
 func (h *Handler) HandleMessage(msg actor.IActorMessage) {
-	h.BaseActorHandler.HandleMessage(msg)
+	fb := h.getFuBen()
+	if fb == nil {
+		if msg.GetMsgId() != uint16(protocol.DungeonActorMsgId_DAMLinkLost) {
+			log.Warnf("[dungeon-actor] shard %d has no fuben, drop msgId=%d", h.fbId, msg.GetMsgId())
+		}
+		return
+	}
+	if h.forward(fb, msg) {
+		return
+	}
+	ctx := entitymgr.WithFuBen(msg.GetContext(), fb)
+	h.BaseActorHandler.HandleMessage(actor.NewBaseMessage(ctx, msg.GetMsgId(), msg.GetData()))
+}
+
+// forward 会话实体不在本分片且路由指向其他分片时转交消息
+func (h *Handler) forward(fb iface.IFuBen, msg actor.IActorMessage) bool {
+	switch protocol.DungeonActorMsgId(msg.GetMsgId()) {
+	case protocol.DungeonActorMsgId_DAMLinkLost, protocol.DungeonActorMsgId_DAMHandoff,
+		protocol.DungeonActorMsgId_DAMPickUpResult:
+		return false
+	}
+	ctx := msg.GetContext()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	sessionId, _ := ctx.Value(gshare.ContextKeySession).(string)
+	if sessionId == "" {
+		return false
+	}
+	if _, ok := fb.GetEntityMgr().GetBySession(sessionId); ok {
+		return false
+	}
+	fbId, ok := fbmgr.GetFuBenMgr().GetRoute(sessionId)
+	if !ok || fbId == h.fbId {
+		return false
+	}
+	if err := fbmgr.GetFuBenMgr().SendToFuBen(fbId, msg); err != nil {
+		log.Warnf("[dungeon-actor] forward msgId=%d to fuben %d failed: %v", msg.GetMsgId(), fbId, err)
+	}
+	return true
 }
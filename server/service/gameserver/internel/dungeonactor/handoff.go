@@ -0,0 +1,147 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/5
+ * @Desc: 玩家在副本实例分片之间转移
+**/
+
+package dungeonactor
+
+import (
+	"context"
+	"postapocgame/server/internal/actor"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/entitymgr"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/fbmgr"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/iface"
+	"postapocgame/server/service/gameserver/internel/gshare"
+)
+
+// handoffTarget 玩家转移的目标位置
+type handoffTarget struct {
+	fbId    uint32
+	sceneId uint32 // 0 表示副本入口场景
+	x, y    uint32
+	usePos  bool // 为 false 或目标点不可行走时使用场景出生点
+}
+
+// handoff 转移中的玩家，只在 DungeonActor 进程内传递
+// 源分片发出后不再访问该玩家，由目标分片接管
+type handoff struct {
+	player    iface.IPlayer
+	target    handoffTarget
+	linkEpoch uint64
+}
+
+type handoffCtxKey struct{}
+
+// handoffPlayer 将玩家移出当前副本实例，并投递到目标实例的分片
+// 会话路由与转移消息一起切换，之后该会话的消息都排在转移消息之后
+func handoffPlayer(player iface.IPlayer, target handoffTarget) error {
+	leaveCurrentScene(player)
+
+	sessionId := player.GetSessionId()
+	ctx := context.WithValue(context.Background(), gshare.ContextKeySession, sessionId)
+	ctx = context.WithValue(ctx, handoffCtxKey{}, &handoff{
+		player:    player,
+		target:    target,
+		linkEpoch: linkEpoch.Load(),
+	})
+	msg := actor.NewBaseMessage(ctx, uint16(protocol.DungeonActorMsgId_DAMHandoff), nil)
+	if err := fbmgr.GetFuBenMgr().MoveSession(sessionId, target.fbId, msg); err != nil {
+		return customerr.Wrap(err)
+	}
+	return nil
+}
+
+// handleHandoff 目标分片接管转移中的玩家
+// 目标实例已关闭、已满或场景缺失时，送回返回点，返回点也不可用时送回默认副本
+// 入口：protocol.DungeonActorMsgId_DAMHandoff
+func handleHandoff(msg actor.IActorMessage) error {
+	h, ok := msg.GetContext().Value(handoffCtxKey{}).(*handoff)
+	if !ok || h == nil || h.player == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "handoff player missing")
+	}
+	fb := entitymgr.FuBenFromContext(msg.GetContext())
+	if fb == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "fuben not found in context")
+	}
+	player := h.player
+	if h.linkEpoch != linkEpoch.Load() {
+		// 转移途中 GameServer 链路已断开，玩家会在重连后重新进入
+		fbmgr.GetFuBenMgr().UnbindRoute(player.GetSessionId(), fb.GetFbId())
+		log.Warnf("[dungeon-actor] drop handoff of role %d after link lost", player.GetId())
+		return nil
+	}
+
+	err := enterTarget(fb, player, h.target)
+	if err == nil {
+		return nil
+	}
+	sendErrorToPlayer(player, err)
+
+	fallback := handoffTarget{fbId: 0}
+	if rp := player.GetReturnPoint(); rp != nil {
+		fallback = handoffTarget{fbId: rp.FbId, sceneId: rp.SceneId, x: rp.X, y: rp.Y, usePos: true}
+		player.SetReturnPoint(nil)
+	} else if fb.GetFbId() == 0 {
+		// 默认副本也无法进入，放弃该玩家，等待 PlayerActor 重新进入
+		fbmgr.GetFuBenMgr().UnbindRoute(player.GetSessionId(), fb.GetFbId())
+		return customerr.Wrap(err)
+	}
+	log.Warnf("[dungeon-actor] handoff role %d to fuben %d failed, fallback to fuben %d: %v", player.GetId(), h.target.fbId, fallback.fbId, err)
+	return handoffPlayer(player, fallback)
+}
+
+// enterTarget 将玩家加入本分片副本实例的目标场景
+func enterTarget(fb iface.IFuBen, player iface.IPlayer, target handoffTarget) error {
+	if fb.GetFbId() != target.fbId || fb.GetState() != uint32(protocol.FuBenState_FuBenStateNormal) {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_FuBen_Closed), "fuben instance %d closed", target.fbId)
+	}
+	var scene iface.IScene
+	if target.sceneId != 0 {
+		scene = fb.GetScene(target.sceneId)
+	}
+	if scene == nil {
+		scene = getEntryScene(fb)
+	}
+	if scene == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Scene_NotFound), "fuben %d scenes missing", target.fbId)
+	}
+	x, y := target.x, target.y
+	if !target.usePos || !scene.IsWalkable(int(x), int(y)) {
+		x, y = scene.GetSpawnPos()
+	}
+
+	sessionId := player.GetSessionId()
+	if err := fb.OnPlayerEnter(sessionId); err != nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_FuBen_Full), "enter fuben %d failed: %v", target.fbId, err)
+	}
+	player.SetPosition(x, y)
+	if err := enterScene(player, scene); err != nil {
+		fb.OnPlayerLeave(sessionId)
+		return err
+	}
+	return nil
+}
+
+// leaveCurrentScene 将玩家移出当前场景与副本实例，并解除实例内的会话绑定
+func leaveCurrentScene(player iface.IPlayer) {
+	entityMgr := player.GetEntityMgr()
+	if entityMgr == nil {
+		return
+	}
+	sessionId := player.GetSessionId()
+	if scene, ok := entityMgr.GetSceneByHandle(player.GetHdl()); ok && scene != nil {
+		notifyLeaveScene(scene, player)
+		if err := scene.RemoveEntity(player.GetHdl()); err != nil {
+			log.Warnf("[dungeon-actor] remove player from scene %d failed: %v", scene.GetSceneId(), err)
+		}
+		scene.GetFuBen().OnPlayerLeave(sessionId)
+	} else {
+		entityMgr.Unregister(player.GetHdl())
+		player.SetEntityMgr(nil)
+	}
+	entityMgr.UnbindSession(sessionId)
+}
@@ -9,8 +9,8 @@ package iface
 type IAOISys interface {
 	GetVisibleEntities() []IEntity
 	AddVisibleEntity(entity IEntity)
-	RemoveVisibleEntity(entityId uint64)
-	IsVisible(entityId uint64) bool
+	RemoveVisibleEntity(entityHdl uint64)
+	IsVisible(entityHdl uint64) bool
 	ClearVisibleEntities()
 	ConsumeVisibilityChanges() (enter []IEntity, leave []uint64)
 }
@@ -17,6 +17,9 @@ type IAttrSys interface {
 	// AddAttrValue 增加属性值
 	AddAttrValue(attrType uint32, delta int64)
 
+	// SetSysAttrs 设置某个属性系统（见 protocol.SaAttrSys）提供的战斗属性，按与旧值的差值更新
+	SetSysAttrs(sysId uint32, attrs map[uint32]int64)
+
 	// RunOne 每帧更新（由实体 RunOne 调用）
 	RunOne()
 }
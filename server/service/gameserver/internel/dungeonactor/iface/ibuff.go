@@ -0,0 +1,31 @@
+/**
+ * @Author: zjj
+ * @Date: 2025/12/27
+ * @Desc: Buff系统接口
+**/
+
+package iface
+
+import (
+	"postapocgame/server/internal/protocol"
+	"time"
+)
+
+// IBuffSys Buff系统接口
+type IBuffSys interface {
+	// AddBuff 添加Buff，durationMs 为 0 时使用配置的持续时间
+	AddBuff(buffId uint32, caster IEntity, durationMs uint32) bool
+
+	// RemoveBuff 移除Buff
+	RemoveBuff(buffId uint32) bool
+
+	// ClearBuffs 移除所有Buff
+	ClearBuffs()
+
+	HasBuff(buffId uint32) bool
+	GetBuffStack(buffId uint32) uint32
+	BuildProtoBuffs() []*protocol.BuffSt
+
+	// RunOne 每帧更新（由实体 RunOne 调用）
+	RunOne(now time.Time)
+}
@@ -0,0 +1,29 @@
+/**
+ * @Author: zjj
+ * @Date: 2025/12/29
+ * @Desc:
+**/
+
+package iface
+
+import (
+	"postapocgame/server/internal/protocol"
+	"time"
+)
+
+// IDropItem 地面掉落物
+type IDropItem interface {
+	IEntity
+
+	GetItem() *protocol.ItemAmount
+	GetOwnerId() uint64
+	// CanPickUp 归属保护期内仅归属者可拾取
+	CanPickUp(roleId uint64, now time.Time) bool
+	IsExpired(now time.Time) bool
+	// IsPicking 是否已锁定等待 PlayerActor 入包
+	IsPicking() bool
+	// LockPicking 锁定直到入包结果返回或掉落物过期回收，不按时间解锁：
+	// PlayerActor 可能已入包但结果仍在途中，提前解锁会让他人再次拾取导致道具重复
+	LockPicking()
+	UnlockPicking()
+}
@@ -26,6 +26,9 @@ type IEntity interface {
 	SetSceneId(sceneId uint32)
 	GetFuBenId() uint32
 	SetFuBenId(fuBenId uint32)
+	// GetEntityMgr 实体所在副本实例的实体管理器，未进入场景时为 nil
+	GetEntityMgr() IEntityMgr
+	SetEntityMgr(entityMgr IEntityMgr)
 	GetLevel() uint32
 	GetStateFlags() uint64
 	BuildProtoEntitySt() *protocol.EntitySt
@@ -47,6 +50,7 @@ type IEntity interface {
 	GetAOISys() IAOISys
 	GetAttrSys() IAttrSys
 	GetMoveSys() IMoveSys
+	GetBuffSys() IBuffSys
 
 	// 战斗相关
 	OnAttacked(attacker IEntity, damage int64)
@@ -0,0 +1,26 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/5
+ * @Desc:
+**/
+
+package iface
+
+import "time"
+
+// IEntityMgr 副本实例内的实体管理器，只允许在副本实例所属的 DungeonActor 分片内访问
+type IEntityMgr interface {
+	Register(entity IEntity) error
+	Unregister(hdl uint64)
+	BindSession(sessionId string, hdl uint64)
+	UnbindSession(sessionId string)
+	GetBySession(sessionId string) (IEntity, bool)
+	BindScene(hdl uint64, scene IScene)
+	UnbindScene(hdl uint64)
+	GetSceneByHandle(hdl uint64) (IScene, bool)
+	GetByHdl(hdl uint64) (IEntity, bool)
+	GetById(Id uint64) []IEntity
+	GetAll() []IEntity
+	GetCount() int
+	RunOne(now time.Time)
+}
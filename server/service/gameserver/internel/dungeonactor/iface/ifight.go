@@ -13,6 +13,8 @@ import (
 type IFightSys interface {
 	LearnSkill(skillId, skillLv uint32) error
 	HasSkill(skillId uint32) bool
+	GetSkillIds() []uint32
+	IsSkillReady(skillId uint32) bool
 
 	UseSkill(ctx *argsdef.SkillCastContext) int
 }
@@ -15,13 +15,21 @@ type IFuBen interface {
 	Close()
 	InitScenes(sceneConfigs []jsonconf.SceneConfig)
 	SetDifficulty(difficulty uint32)
+	CanEnter() bool
 	OnPlayerEnter(sessionId string) error
+	OnPlayerLeave(sessionId string)
+	GetPlayerSessions() []string
 	GetScene(sceneId uint32) IScene
 	GetAllScenes() []IScene
 	GetFbId() uint32
+	GetConfigId() uint32
+	GetExpireTime() time.Time
 	IsExpired() bool
 	GetFbType() uint32
 	GetState() uint32
 	GetPlayerCount() int
+	GetDifficulty() uint32
+	GetEntityMgr() IEntityMgr
+	AddKillCount(count uint32)
 	RunOne(now time.Time)
 }
@@ -0,0 +1,21 @@
+/**
+ * @Author: zjj
+ * @Date: 2025/12/26
+ * @Desc:
+**/
+
+package iface
+
+import (
+	"postapocgame/server/internal/argsdef"
+	"time"
+)
+
+type IMonster interface {
+	IEntity
+
+	GetMonsterId() uint32
+	GetSpawnId() uint32
+	GetBornPos() *argsdef.Position
+	GetDieTime() time.Time
+}
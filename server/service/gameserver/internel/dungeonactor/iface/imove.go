@@ -1,6 +1,7 @@
 package iface
 
 import (
+	"postapocgame/server/internal/argsdef"
 	"postapocgame/server/internal/protocol"
 )
 
@@ -13,6 +14,13 @@ type IMoveSys interface {
 	HandleUpdateMove(scene IScene, req *protocol.C2SUpdateMoveReq) error
 	HandleEndMove(scene IScene, req *protocol.C2SEndMoveReq) error
 
+	// MoveToTile 服务端寻路移动（AI使用），MovingTime 每帧推进
+	MoveToTile(tileX, tileY uint32) bool
+	// MoveByPath 沿路点列表移动（格子坐标，不含起点）
+	MoveByPath(path []*argsdef.Position) bool
+	MovingTime(mustStop bool) bool
+	GetMoveDest() (int32, int32)
+
 	StopMove(broadcast, sendToSelf bool)
 	ResetState()
 	IsMoving() bool
@@ -10,4 +10,21 @@ type IPlayer interface {
 	IEntity
 
 	GetSessionId() string
+	SetSessionId(sessionId string)
+	ApplyLevel(level uint32, attrs map[uint32]int64)
+	UpdateSkill(skillId, level uint32) error
+	AddExp(exp int64) error
+	ApplyEquip(attrs map[uint32]int64, appearance map[uint32]uint32)
+
+	// 副本实例返回点
+	SetReturnPoint(rp *ReturnPoint)
+	GetReturnPoint() *ReturnPoint
+}
+
+// ReturnPoint 进入副本实例前所在的副本、场景与格子坐标，离开实例时恢复
+type ReturnPoint struct {
+	FbId    uint32
+	SceneId uint32
+	X       uint32
+	Y       uint32
 }
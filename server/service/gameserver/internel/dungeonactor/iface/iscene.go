@@ -6,17 +6,35 @@
 
 package iface
 
+import (
+	"postapocgame/server/internal/argsdef"
+	"time"
+)
+
 type IScene interface {
 	AddEntity(IEntity) error
 	RemoveEntity(hdl uint64) error
 	GetEntity(hdl uint64) (IEntity, bool)
 	GetAllEntities() []IEntity
+	GetEntitiesInRange(pos *argsdef.Position) []IEntity
 	EntityMove(hdl uint64, newX, newY uint32) error
 	IsWalkable(x, y int) bool
 	GetRandomWalkablePos() (uint32, uint32)
 	GetSpawnPos() (uint32, uint32)
+	// FindPath 寻路，返回平滑后的路点（不含起点，含终点）
+	FindPath(fromX, fromY, toX, toY uint32) ([]*argsdef.Position, error)
 
 	GetSceneId() uint32
 	GetFuBenId() uint32
 	GetFuBen() IFuBen
+
+	DropLoot(x, y uint32, ownerId uint64, dropId uint32)
+	RemoveDrop(hdl uint64)
+
+	// SpawnMonster 在 (x, y) 附近刷出不重生的怪物（GM 指令使用）
+	SpawnMonster(monsterId, x, y uint32) (IMonster, error)
+	// KillAllMonsters 击杀场景内全部存活怪物，返回击杀数量
+	KillAllMonsters(killer IEntity) int
+
+	RunOne(now time.Time)
 }
@@ -0,0 +1,114 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/3
+ * @Desc: 玩家离开 DungeonActor：登出、断线以及 GameServer 链路断开；断线重连的会话换绑
+**/
+
+package dungeonactor
+
+import (
+	"postapocgame/server/internal/actor"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/entitymgr"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/entitysystem"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/fbmgr"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/iface"
+	"sync/atomic"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// linkEpoch GameServer 链路断开次数，链路断开前发出的玩家转移在目标分片直接丢弃
+var linkEpoch atomic.Uint64
+
+// handleLeaveGame 玩家登出或断线，移除其实体并清除移动违规记录
+// 链路断开等重新进入的情况不经过这里，记录保留
+// 入口：protocol.DungeonActorMsgId_DAMLeaveGame
+func handleLeaveGame(msg actor.IActorMessage) error {
+	player, err := getPlayerFromMsg(msg)
+	if err != nil {
+		// 玩家可能从未成功进入 DungeonActor，直接忽略
+		return nil
+	}
+	leaveGame(player)
+	entitysystem.ResetMoveSuspicion(player.GetId())
+	return nil
+}
+
+// handleLinkLost GameServer 链路断开，移除本分片的全部玩家实体
+// 该消息广播到所有分片，GameServer 重连后会为在线玩家重新发送 DAMEnterGame
+// 入口：protocol.DungeonActorMsgId_DAMLinkLost
+func handleLinkLost(msg actor.IActorMessage) error {
+	players := getAllPlayers(entitymgr.FromContext(msg.GetContext()))
+	for _, player := range players {
+		leaveGame(player)
+	}
+	if len(players) > 0 {
+		log.Warnf("[dungeon-actor] gameserver link lost, removed %d players", len(players))
+	}
+	return nil
+}
+
+// leaveGame 将玩家移出场景与副本并解除会话绑定与路由
+// 已锁定但未回复结果的掉落物保持锁定，直到过期清理，避免 PlayerActor 已入包后重复拾取
+func leaveGame(player iface.IPlayer) {
+	sessionId := player.GetSessionId()
+	fbId := player.GetFuBenId()
+	leaveCurrentScene(player)
+	fbmgr.GetFuBenMgr().UnbindRoute(sessionId, fbId)
+	log.Infof("[dungeon-actor] role %d left game, session=%s", player.GetId(), sessionId)
+}
+
+// handleRebindSession 断线重连后玩家实体换绑到新会话，位置、状态与视野保持不变
+// 路由已在投递时切换，这里只更新实体与副本实例内的会话绑定
+// 入口：protocol.DungeonActorMsgId_DAMRebindSession
+func handleRebindSession(msg actor.IActorMessage) error {
+	var req protocol.DAMRebindSessionReq
+	if err := proto.Unmarshal(msg.GetData(), &req); err != nil {
+		return customerr.Wrap(err)
+	}
+	if req.GetNewSessionId() == "" {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "new session id missing")
+	}
+	player, err := getPlayerFromMsg(msg)
+	if err != nil {
+		return customerr.Wrap(err)
+	}
+	entityMgr := entitymgr.FromContext(msg.GetContext())
+	if entityMgr == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "entity mgr not found in context")
+	}
+	oldSessionId := player.GetSessionId()
+	entityMgr.UnbindSession(oldSessionId)
+	player.SetSessionId(req.GetNewSessionId())
+	entityMgr.BindSession(req.GetNewSessionId(), player.GetHdl())
+	log.Infof("[dungeon-actor] role %d rebound session %s -> %s", player.GetId(), oldSessionId, req.GetNewSessionId())
+	return nil
+}
+
+// removeStalePlayers 移除本分片内同一会话或同一角色残留的玩家实体
+func removeStalePlayers(entityMgr iface.IEntityMgr, sessionId string, roleId uint64) {
+	for _, player := range getAllPlayers(entityMgr) {
+		if player.GetSessionId() == sessionId || player.GetId() == roleId {
+			leaveGame(player)
+		}
+	}
+}
+
+func getAllPlayers(entityMgr iface.IEntityMgr) []iface.IPlayer {
+	if entityMgr == nil {
+		return nil
+	}
+	var players []iface.IPlayer
+	for _, et := range entityMgr.GetAll() {
+		if et == nil || et.GetEntityType() != uint32(protocol.EntityType_EtPlayer) {
+			continue
+		}
+		if player, ok := et.(iface.IPlayer); ok {
+			players = append(players, player)
+		}
+	}
+	return players
+}
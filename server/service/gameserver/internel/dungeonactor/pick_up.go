@@ -0,0 +1,185 @@
+package dungeonactor
+
+import (
+	"context"
+	"postapocgame/server/internal/actor"
+	"postapocgame/server/internal/argsdef"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/internal/servertime"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/entitymgr"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/fbmgr"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/iface"
+	"postapocgame/server/service/gameserver/internel/gshare"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// pickUpRange 拾取距离（格子）
+const pickUpRange = 2
+
+// handlePickUp 处理客户端拾取请求
+// 入口：protocol.DungeonActorMsgId_DAMPickUp
+// 校验通过后锁定掉落物并交给 PlayerActor 入包，入包结果回来前其他人无法拾取，避免道具重复
+func handlePickUp(msg actor.IActorMessage) error {
+	if msg == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "nil message")
+	}
+
+	ctx := msg.GetContext()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	sessionId, _ := ctx.Value(gshare.ContextKeySession).(string)
+	if sessionId == "" {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "not found session")
+	}
+
+	var req protocol.C2SPickUpReq
+	if err := proto.Unmarshal(msg.GetData(), &req); err != nil {
+		return customerr.Wrap(err)
+	}
+
+	et, ok := entitymgr.GetBySession(ctx, sessionId)
+	if !ok || et == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "player entity not found")
+	}
+
+	if err := lockDrop(et, req.DropHdl); err != nil {
+		sendErrorToPlayer(et, err)
+		return err
+	}
+	return nil
+}
+
+// lockDrop 校验拾取条件，锁定掉落物并通知 PlayerActor 入包
+func lockDrop(et iface.IEntity, dropHdl uint64) error {
+	if et.IsDead() {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "dead player cannot pick up")
+	}
+	entityMgr := et.GetEntityMgr()
+	if entityMgr == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "player not in scene")
+	}
+	dropEt, ok := entityMgr.GetByHdl(dropHdl)
+	if !ok || dropEt == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Drop_NotFound), "drop %d not found", dropHdl)
+	}
+	drop, ok := dropEt.(iface.IDropItem)
+	if !ok {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Drop_NotFound), "entity %d is not drop", dropHdl)
+	}
+
+	playerScene, ok1 := entityMgr.GetSceneByHandle(et.GetHdl())
+	dropScene, ok2 := entityMgr.GetSceneByHandle(dropHdl)
+	if !ok1 || !ok2 || playerScene != dropScene {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Drop_NotFound), "drop %d not in player scene", dropHdl)
+	}
+	if tileDistance(et.GetPosition(), drop.GetPosition()) > pickUpRange {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Drop_TooFar), "drop %d too far", dropHdl)
+	}
+	now := servertime.Now()
+	if drop.IsPicking() {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Drop_Picking), "drop %d is picking", dropHdl)
+	}
+	if !drop.CanPickUp(et.GetId(), now) {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Drop_Protected), "drop %d is protected", dropHdl)
+	}
+
+	player, ok := et.(iface.IPlayer)
+	if !ok {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "entity is not player")
+	}
+	payload, err := proto.Marshal(&protocol.PAMPickUpReq{
+		DropHdl: dropHdl,
+		Item:    drop.GetItem(),
+		FbId:    et.GetFuBenId(),
+	})
+	if err != nil {
+		return customerr.Wrap(err)
+	}
+
+	drop.LockPicking()
+	sessionId := player.GetSessionId()
+	msgCtx := context.WithValue(context.Background(), gshare.ContextKeySession, sessionId)
+	if err := gshare.SendMessageAsync(sessionId, actor.NewBaseMessage(msgCtx, uint16(protocol.PlayerActorMsgId_PAMPickUp), payload)); err != nil {
+		// 消息未送达 PlayerActor，可以安全解锁
+		drop.UnlockPicking()
+		return customerr.Wrap(err)
+	}
+	return nil
+}
+
+// handlePickUpResult 处理 PlayerActor 入包结果
+// 入口：protocol.DungeonActorMsgId_DAMPickUpResult
+// 结果按玩家会话路由，玩家在此期间转移到其他副本实例时转交回掉落物所在实例
+func handlePickUpResult(msg actor.IActorMessage) error {
+	if msg == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "nil message")
+	}
+
+	var req protocol.DAMPickUpResultReq
+	if err := proto.Unmarshal(msg.GetData(), &req); err != nil {
+		return customerr.Wrap(err)
+	}
+
+	fb := entitymgr.FuBenFromContext(msg.GetContext())
+	if fb == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "fuben not found in context")
+	}
+	if fb.GetFbId() != req.FbId {
+		if _, ok := fbmgr.GetFuBenMgr().GetFuBen(req.FbId); !ok {
+			// 副本实例已关闭，掉落物随之回收
+			log.Warnf("[dungeon-actor] pick up result for closed fuben %d, drop=%d success=%v", req.FbId, req.DropHdl, req.Success)
+			return nil
+		}
+		return fbmgr.GetFuBenMgr().SendToFuBen(req.FbId, msg)
+	}
+
+	entityMgr := fb.GetEntityMgr()
+	dropEt, ok := entityMgr.GetByHdl(req.DropHdl)
+	if !ok || dropEt == nil {
+		// 掉落物已到期回收
+		log.Warnf("[dungeon-actor] pick up result for missing drop %d, success=%v", req.DropHdl, req.Success)
+		return nil
+	}
+	drop, ok := dropEt.(iface.IDropItem)
+	if !ok {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "entity %d is not drop", req.DropHdl)
+	}
+
+	if !req.Success {
+		drop.UnlockPicking()
+		return nil
+	}
+	scene, ok := entityMgr.GetSceneByHandle(req.DropHdl)
+	if !ok || scene == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "drop %d scene not found", req.DropHdl)
+	}
+	scene.RemoveDrop(req.DropHdl)
+	return nil
+}
+
+func sendErrorToPlayer(et iface.IEntity, err error) {
+	sErr := et.SendProtoMessage(uint16(protocol.S2CProtocol_S2CError), &protocol.ErrorData{
+		Code: customerr.GetErrCode(err),
+		Msg:  customerr.GetErrMsgByErr(err),
+	})
+	if sErr != nil {
+		log.Warnf("[dungeon-actor] send error to player failed: %v", sErr)
+	}
+}
+
+// tileDistance 格子切比雪夫距离
+func tileDistance(a, b *argsdef.Position) uint32 {
+	dx := int64(a.X) - int64(b.X)
+	dy := int64(a.Y) - int64(b.Y)
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy < 0 {
+		dy = -dy
+	}
+	return uint32(max(dx, dy))
+}
@@ -18,9 +18,26 @@ func init() {
 			return
 		}
 
+		RegisterShardHandlers(facade)
 		RegisterEnterGameHandler(facade)
+		RegisterLeaveGameHandlers(facade)
+		RegisterLevelHandler(facade)
+		RegisterEquipHandler(facade)
+		RegisterBuffHandler(facade)
 		RegisterMoveHandlers(facade)
 		RegisterFightHandlers(facade)
+		RegisterPickUpHandlers(facade)
+		RegisterFuBenHandlers(facade)
+		RegisterGmHandlers(facade)
+		RegisterChatHandlers(facade)
+	})
+}
+
+func RegisterShardHandlers(facade gshare.IDungeonActorFacade) {
+	facade.RegisterHandler(uint16(protocol.DungeonActorMsgId_DAMHandoff), func(msg actor.IActorMessage) {
+		if err := handleHandoff(msg); err != nil {
+			log.Errorf("[dungeon-actor] handleHandoff failed: %v", err)
+		}
 	})
 }
 
@@ -32,6 +49,48 @@ func RegisterEnterGameHandler(facade gshare.IDungeonActorFacade) {
 	})
 }
 
+func RegisterLeaveGameHandlers(facade gshare.IDungeonActorFacade) {
+	facade.RegisterHandler(uint16(protocol.DungeonActorMsgId_DAMLeaveGame), func(msg actor.IActorMessage) {
+		if err := handleLeaveGame(msg); err != nil {
+			log.Errorf("[dungeon-actor] handleLeaveGame failed: %v", err)
+		}
+	})
+	facade.RegisterHandler(uint16(protocol.DungeonActorMsgId_DAMRebindSession), func(msg actor.IActorMessage) {
+		if err := handleRebindSession(msg); err != nil {
+			log.Errorf("[dungeon-actor] handleRebindSession failed: %v", err)
+		}
+	})
+	facade.RegisterHandler(uint16(protocol.DungeonActorMsgId_DAMLinkLost), func(msg actor.IActorMessage) {
+		if err := handleLinkLost(msg); err != nil {
+			log.Errorf("[dungeon-actor] handleLinkLost failed: %v", err)
+		}
+	})
+}
+
+func RegisterLevelHandler(facade gshare.IDungeonActorFacade) {
+	facade.RegisterHandler(uint16(protocol.DungeonActorMsgId_DAMSyncLevel), func(msg actor.IActorMessage) {
+		if err := handleSyncLevel(msg); err != nil {
+			log.Errorf("[dungeon-actor] handleSyncLevel failed: %v", err)
+		}
+	})
+}
+
+func RegisterEquipHandler(facade gshare.IDungeonActorFacade) {
+	facade.RegisterHandler(uint16(protocol.DungeonActorMsgId_DAMSyncEquip), func(msg actor.IActorMessage) {
+		if err := handleSyncEquip(msg); err != nil {
+			log.Errorf("[dungeon-actor] handleSyncEquip failed: %v", err)
+		}
+	})
+}
+
+func RegisterBuffHandler(facade gshare.IDungeonActorFacade) {
+	facade.RegisterHandler(uint16(protocol.DungeonActorMsgId_DAMAddBuff), func(msg actor.IActorMessage) {
+		if err := handleAddBuff(msg); err != nil {
+			log.Errorf("[dungeon-actor] handleAddBuff failed: %v", err)
+		}
+	})
+}
+
 func RegisterMoveHandlers(facade gshare.IDungeonActorFacade) {
 	facade.RegisterHandler(uint16(protocol.DungeonActorMsgId_DAMStartMove), func(msg actor.IActorMessage) {
 		if err := entitysystem.HandleStartMove(msg); err != nil {
@@ -57,3 +116,70 @@ func RegisterFightHandlers(facade gshare.IDungeonActorFacade) {
 		}
 	})
 }
+
+func RegisterPickUpHandlers(facade gshare.IDungeonActorFacade) {
+	facade.RegisterHandler(uint16(protocol.DungeonActorMsgId_DAMPickUp), func(msg actor.IActorMessage) {
+		if err := handlePickUp(msg); err != nil {
+			log.Errorf("[dungeon-actor] handlePickUp failed: %v", err)
+		}
+	})
+	facade.RegisterHandler(uint16(protocol.DungeonActorMsgId_DAMPickUpResult), func(msg actor.IActorMessage) {
+		if err := handlePickUpResult(msg); err != nil {
+			log.Errorf("[dungeon-actor] handlePickUpResult failed: %v", err)
+		}
+	})
+}
+
+func RegisterFuBenHandlers(facade gshare.IDungeonActorFacade) {
+	facade.RegisterHandler(uint16(protocol.DungeonActorMsgId_DAMEnterFuBen), func(msg actor.IActorMessage) {
+		if err := handleEnterFuBen(msg); err != nil {
+			log.Errorf("[dungeon-actor] handleEnterFuBen failed: %v", err)
+		}
+	})
+	facade.RegisterHandler(uint16(protocol.DungeonActorMsgId_DAMLeaveFuBen), func(msg actor.IActorMessage) {
+		if err := handleLeaveFuBen(msg); err != nil {
+			log.Errorf("[dungeon-actor] handleLeaveFuBen failed: %v", err)
+		}
+	})
+	facade.RegisterHandler(uint16(protocol.DungeonActorMsgId_DAMTransferScene), func(msg actor.IActorMessage) {
+		if err := handleTransferScene(msg); err != nil {
+			log.Errorf("[dungeon-actor] handleTransferScene failed: %v", err)
+		}
+	})
+}
+
+func RegisterGmHandlers(facade gshare.IDungeonActorFacade) {
+	facade.RegisterHandler(uint16(protocol.DungeonActorMsgId_DAMGmTeleport), func(msg actor.IActorMessage) {
+		if err := handleGmTeleport(msg); err != nil {
+			log.Errorf("[dungeon-actor] handleGmTeleport failed: %v", err)
+		}
+	})
+	facade.RegisterHandler(uint16(protocol.DungeonActorMsgId_DAMGmSpawnMonster), func(msg actor.IActorMessage) {
+		if err := handleGmSpawnMonster(msg); err != nil {
+			log.Errorf("[dungeon-actor] handleGmSpawnMonster failed: %v", err)
+		}
+	})
+	facade.RegisterHandler(uint16(protocol.DungeonActorMsgId_DAMGmKillAll), func(msg actor.IActorMessage) {
+		if err := handleGmKillAll(msg); err != nil {
+			log.Errorf("[dungeon-actor] handleGmKillAll failed: %v", err)
+		}
+	})
+	facade.RegisterHandler(uint16(protocol.DungeonActorMsgId_DAMGmMoveSuspicion), func(msg actor.IActorMessage) {
+		if err := handleGmMoveSuspicion(msg); err != nil {
+			log.Errorf("[dungeon-actor] handleGmMoveSuspicion failed: %v", err)
+		}
+	})
+}
+
+func RegisterChatHandlers(facade gshare.IDungeonActorFacade) {
+	facade.RegisterHandler(uint16(protocol.DungeonActorMsgId_DAMChatScene), func(msg actor.IActorMessage) {
+		if err := handleChatScene(msg); err != nil {
+			log.Errorf("[dungeon-actor] handleChatScene failed: %v", err)
+		}
+	})
+	facade.RegisterHandler(uint16(protocol.DungeonActorMsgId_DAMChatTeam), func(msg actor.IActorMessage) {
+		if err := handleChatTeam(msg); err != nil {
+			log.Errorf("[dungeon-actor] handleChatTeam failed: %v", err)
+		}
+	})
+}
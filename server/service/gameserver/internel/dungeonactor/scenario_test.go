@@ -0,0 +1,522 @@
+package dungeonactor
+
+import (
+	"context"
+	"postapocgame/server/internal/actor"
+	"postapocgame/server/internal/actor/actortest"
+	"postapocgame/server/internal/argsdef"
+	"postapocgame/server/internal/attrdef"
+	"postapocgame/server/internal/jsonconf"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/entitysystem"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/fbmgr"
+	"postapocgame/server/service/gameserver/internel/gatewaylink"
+	"postapocgame/server/service/gameserver/internel/gshare"
+	"postapocgame/server/service/gameserver/internel/playeractor"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// setupScenario 以同步驱动启动 PlayerActor 与 DungeonActor，客户端消息由 Harness 捕获
+func setupScenario(t *testing.T) (*actortest.Harness, *DungeonActor) {
+	t.Helper()
+	h := actortest.New(t)
+	if err := jsonconf.GetConfigManager().Init("../../../../output/config"); err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	gatewaylink.GetMessageSender().SetConn(h.Client())
+
+	players := playeractor.NewPlayerRoleActor(actor.ModePerKey)
+	players.RegisterHandler(uint16(protocol.PlayerActorMsgId_PAMSendToClient), func(msg actor.IActorMessage) {
+		var req protocol.PAMSendToClientReq
+		if err := proto.Unmarshal(msg.GetData(), &req); err != nil {
+			t.Errorf("unmarshal PAMSendToClientReq: %v", err)
+			return
+		}
+		sessionId, _ := msg.GetContext().Value(gshare.ContextKeySession).(string)
+		if err := gatewaylink.SendToSession(sessionId, uint16(req.MsgId), req.Data); err != nil {
+			t.Errorf("send to session: %v", err)
+		}
+	})
+
+	d := NewDungeonActor(actor.ModePerKey)
+	facade := gshare.GetDungeonActorFacade()
+	RegisterShardHandlers(facade)
+	RegisterEnterGameHandler(facade)
+	RegisterLeaveGameHandlers(facade)
+
+	ctx := context.Background()
+	if err := players.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = d.Stop(ctx)
+		_ = players.Stop(ctx)
+	})
+	return h, d
+}
+
+func enterGame(t *testing.T, d *DungeonActor, sessionId string, roleId uint64) {
+	t.Helper()
+	enterGameWith(t, d, &protocol.DAMEnterGameReq{
+		SessionId: sessionId,
+		Role:      &protocol.PlayerSimpleData{RoleId: roleId, RoleName: sessionId, Job: 1, Level: 1},
+		Level:     1,
+	})
+}
+
+func enterGameWith(t *testing.T, d *DungeonActor, req *protocol.DAMEnterGameReq) {
+	t.Helper()
+	sessionId := req.SessionId
+	data, err := proto.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.AsyncCall(context.Background(), sessionId, uint16(protocol.DungeonActorMsgId_DAMEnterGame), data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTwoPlayersSeeEachOtherInDefaultScene(t *testing.T) {
+	h, d := setupScenario(t)
+
+	enterGame(t, d, "s1", 1001)
+	h.Run()
+	enterGame(t, d, "s2", 1002)
+	h.Run()
+
+	enter1, ok := h.Client().Last("s1", protocol.S2CProtocol_S2CEnterScene)
+	if !ok {
+		t.Fatal("s1 did not receive S2CEnterScene")
+	}
+	enter2, ok := h.Client().Last("s2", protocol.S2CProtocol_S2CEnterScene)
+	if !ok {
+		t.Fatal("s2 did not receive S2CEnterScene")
+	}
+	hdl1 := enter1.Msg.(*protocol.S2CEnterSceneReq).GetEntityData().GetHdl()
+	hdl2 := enter2.Msg.(*protocol.S2CEnterSceneReq).GetEntityData().GetHdl()
+
+	if !appeared(h, "s1", hdl2) {
+		t.Fatalf("s1 did not see s2 (hdl=%d) appear", hdl2)
+	}
+	if !appeared(h, "s2", hdl1) {
+		t.Fatalf("s2 did not see s1 (hdl=%d) appear", hdl1)
+	}
+
+	// 推进虚拟时间驱动分片定时器，场景中的玩家保持在线
+	h.Advance(time.Second)
+
+	if err := d.AsyncCall(context.Background(), "s2", uint16(protocol.DungeonActorMsgId_DAMLeaveGame), nil); err != nil {
+		t.Fatal(err)
+	}
+	h.Run()
+	gone := false
+	for _, m := range h.Client().Find("s1", protocol.S2CProtocol_S2CEntityDisappear) {
+		if m.Msg.(*protocol.S2CEntityDisappearReq).GetEntityHdl() == hdl2 {
+			gone = true
+		}
+	}
+	if !gone {
+		t.Fatalf("s1 did not see s2 (hdl=%d) disappear after leave", hdl2)
+	}
+}
+
+func appeared(h *actortest.Harness, sessionId string, hdl uint64) bool {
+	for _, m := range h.Client().Find(sessionId, protocol.S2CProtocol_S2CEntityAppear) {
+		if m.Msg.(*protocol.S2CEntityAppearReq).GetEntity().GetHdl() == hdl {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRebindSessionKeepsPlayerInScene(t *testing.T) {
+	h, d := setupScenario(t)
+
+	enterGame(t, d, "s1", 1001)
+	h.Run()
+	enterGame(t, d, "s2", 1002)
+	h.Run()
+	enter1, ok := h.Client().Last("s1", protocol.S2CProtocol_S2CEnterScene)
+	if !ok {
+		t.Fatal("s1 did not receive S2CEnterScene")
+	}
+	hdl1 := enter1.Msg.(*protocol.S2CEnterSceneReq).GetEntityData().GetHdl()
+
+	// s1 断线后以 s1b 重连，实体换绑到新会话
+	data, err := proto.Marshal(&protocol.DAMRebindSessionReq{NewSessionId: "s1b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.AsyncCall(context.Background(), "s1", uint16(protocol.DungeonActorMsgId_DAMRebindSession), data); err != nil {
+		t.Fatal(err)
+	}
+	h.Run()
+	for _, m := range h.Client().Find("s2", protocol.S2CProtocol_S2CEntityDisappear) {
+		if m.Msg.(*protocol.S2CEntityDisappearReq).GetEntityHdl() == hdl1 {
+			t.Fatal("s2 saw the rebinding player disappear")
+		}
+	}
+
+	// 原会话不再对应任何实体，新会话离开时实体移除
+	if err := d.AsyncCall(context.Background(), "s1", uint16(protocol.DungeonActorMsgId_DAMLeaveGame), nil); err != nil {
+		t.Fatal(err)
+	}
+	h.Run()
+	if len(h.Client().Find("s2", protocol.S2CProtocol_S2CEntityDisappear)) != 0 {
+		t.Fatal("leave on the old session removed the rebound player")
+	}
+	if err := d.AsyncCall(context.Background(), "s1b", uint16(protocol.DungeonActorMsgId_DAMLeaveGame), nil); err != nil {
+		t.Fatal(err)
+	}
+	h.Run()
+	gone := false
+	for _, m := range h.Client().Find("s2", protocol.S2CProtocol_S2CEntityDisappear) {
+		if m.Msg.(*protocol.S2CEntityDisappearReq).GetEntityHdl() == hdl1 {
+			gone = true
+		}
+	}
+	if !gone {
+		t.Fatalf("s2 did not see s1b (hdl=%d) disappear after leave", hdl1)
+	}
+}
+
+func TestGmSpawnedMonstersAreKilledAndCollected(t *testing.T) {
+	h, d := setupScenario(t)
+	RegisterGmHandlers(gshare.GetDungeonActorFacade())
+
+	enterGame(t, d, "s1", 1001)
+	h.Run()
+	before := len(h.Client().Find("s1", protocol.S2CProtocol_S2CEntityAppear))
+
+	data, err := proto.Marshal(&protocol.DAMGmSpawnMonsterReq{MonsterId: 10001, Count: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.AsyncCall(context.Background(), "s1", uint16(protocol.DungeonActorMsgId_DAMGmSpawnMonster), data); err != nil {
+		t.Fatal(err)
+	}
+	h.Run()
+	spawned := make(map[uint64]bool)
+	for _, m := range h.Client().Find("s1", protocol.S2CProtocol_S2CEntityAppear)[before:] {
+		entity := m.Msg.(*protocol.S2CEntityAppearReq).GetEntity()
+		if entity.GetEt() == uint32(protocol.EntityType_EtMonster) && entity.GetId() == 10001 {
+			spawned[entity.GetHdl()] = true
+		}
+	}
+	if len(spawned) != 3 {
+		t.Fatalf("s1 saw %d gm monsters appear, want 3", len(spawned))
+	}
+
+	if err := d.AsyncCall(context.Background(), "s1", uint16(protocol.DungeonActorMsgId_DAMGmKillAll), nil); err != nil {
+		t.Fatal(err)
+	}
+	h.Run()
+	// 尸体保留时长过后由场景回收
+	h.Advance(3 * time.Second)
+	for _, m := range h.Client().Find("s1", protocol.S2CProtocol_S2CEntityDisappear) {
+		delete(spawned, m.Msg.(*protocol.S2CEntityDisappearReq).GetEntityHdl())
+	}
+	if len(spawned) != 0 {
+		t.Fatalf("%d gm monsters not collected after killall", len(spawned))
+	}
+}
+
+func TestSceneChatReachesPlayersInScene(t *testing.T) {
+	h, d := setupScenario(t)
+	RegisterChatHandlers(gshare.GetDungeonActorFacade())
+
+	enterGame(t, d, "s1", 1001)
+	enterGame(t, d, "s2", 1002)
+	h.Run()
+
+	msg := &protocol.ChatMsgSt{Channel: protocol.ChatChannel_ChatChannelScene, SenderId: 1001, Content: "hello"}
+	data, err := proto.Marshal(&protocol.DAMChatSceneReq{Msg: msg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.AsyncCall(context.Background(), "s1", uint16(protocol.DungeonActorMsgId_DAMChatScene), data); err != nil {
+		t.Fatal(err)
+	}
+	h.Run()
+	for _, sessionId := range []string{"s1", "s2"} {
+		got := h.Client().Find(sessionId, protocol.S2CProtocol_S2CChatMessage)
+		if len(got) != 1 || got[0].Msg.(*protocol.S2CChatMessageReq).GetMsg().GetContent() != "hello" {
+			t.Fatalf("%s got %d scene chat messages", sessionId, len(got))
+		}
+	}
+}
+
+func TestAoeSkillDamagesOtherPlayer(t *testing.T) {
+	h, d := setupScenario(t)
+	RegisterFightHandlers(gshare.GetDungeonActorFacade())
+
+	const whirlwind uint32 = 3002 // 旋风斩，范围伤害
+	attrs := map[uint32]int64{
+		attrdef.MaxHP: 1000, attrdef.HP: 1000,
+		attrdef.MaxMP: 1000, attrdef.MP: 1000,
+	}
+	for i, sessionId := range []string{"s1", "s2"} {
+		enterGameWith(t, d, &protocol.DAMEnterGameReq{
+			SessionId: sessionId,
+			Role:      &protocol.PlayerSimpleData{RoleId: uint64(1001 + i), RoleName: sessionId, Job: 3, Level: 1},
+			Level:     1,
+			SkillMap:  map[uint32]uint32{whirlwind: 1},
+			Attrs:     attrs,
+		})
+		h.Run()
+	}
+	enter1, ok := h.Client().Last("s1", protocol.S2CProtocol_S2CEnterScene)
+	if !ok {
+		t.Fatal("s1 did not receive S2CEnterScene")
+	}
+	enter2, ok := h.Client().Last("s2", protocol.S2CProtocol_S2CEnterScene)
+	if !ok {
+		t.Fatal("s2 did not receive S2CEnterScene")
+	}
+	hdl1 := enter1.Msg.(*protocol.S2CEnterSceneReq).GetEntityData().GetHdl()
+	target := enter2.Msg.(*protocol.S2CEnterSceneReq).GetEntityData()
+
+	// 以 s2 所在格子为中心释放
+	px, py := argsdef.TileCoordToPixel(target.GetPosX(), target.GetPosY())
+	data, err := proto.Marshal(&protocol.C2SUseSkillReq{SkillId: whirlwind, PosX: px, PosY: py})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.AsyncCall(context.Background(), "s1", uint16(protocol.DungeonActorMsgId_DAMUseSkill), data); err != nil {
+		t.Fatal(err)
+	}
+	h.Run()
+
+	got := h.Client().Find("s2", protocol.S2CProtocol_S2CSkillDamage)
+	if len(got) != 1 {
+		t.Fatalf("s2 got %d S2CSkillDamage, want 1", len(got))
+	}
+	resp := got[0].Msg.(*protocol.S2CSkillDamageReq)
+	if resp.GetCasterHdl() != hdl1 || resp.GetSkillId() != whirlwind {
+		t.Fatalf("unexpected skill damage caster=%d skill=%d", resp.GetCasterHdl(), resp.GetSkillId())
+	}
+	hit := false
+	for _, hr := range resp.GetHits() {
+		if hr.GetTargetHdl() == target.GetHdl() {
+			hit = true
+		}
+	}
+	if !hit {
+		t.Fatalf("s2 (hdl=%d) not in hits %v", target.GetHdl(), resp.GetHits())
+	}
+}
+
+func TestPathMoveBroadcastsStopAtDestination(t *testing.T) {
+	h, d := setupScenario(t)
+
+	enterGame(t, d, "s1", 1001)
+	h.Run()
+	enterGame(t, d, "s2", 1002)
+	h.Run()
+
+	fb, ok := fbmgr.GetFuBenMgr().GetFuBen(0)
+	if !ok {
+		t.Fatal("default fuben not found")
+	}
+	et, ok := fb.GetEntityMgr().GetBySession("s1")
+	if !ok {
+		t.Fatal("s1 entity not found")
+	}
+	scene, ok := fb.GetEntityMgr().GetSceneByHandle(et.GetHdl())
+	if !ok {
+		t.Fatal("s1 scene not found")
+	}
+
+	// 服务端寻路到附近可走格子，再按虚拟时间推进到终点
+	pos := et.GetPosition()
+	moveSys := et.GetMoveSys()
+	var destX, destY uint32
+	for dx := uint32(3); dx >= 1 && !moveSys.IsMoving(); dx-- {
+		destX, destY = pos.X+dx, pos.Y
+		if scene.IsWalkable(int(destX), int(destY)) {
+			moveSys.MoveToTile(destX, destY)
+		}
+	}
+	if !moveSys.IsMoving() {
+		t.Skipf("no walkable tile near (%d,%d)", pos.X, pos.Y)
+	}
+	for i := 0; i < 20 && moveSys.IsMoving(); i++ {
+		h.Advance(200 * time.Millisecond)
+		moveSys.MovingTime(false)
+	}
+	if moveSys.IsMoving() {
+		t.Fatal("path move did not finish")
+	}
+	h.Run()
+
+	wantPx, wantPy := argsdef.TileCoordToPixel(destX, destY)
+	for _, sessionId := range []string{"s1", "s2"} {
+		stop, ok := h.Client().Last(sessionId, protocol.S2CProtocol_S2CStopMove)
+		if !ok {
+			t.Fatalf("%s did not receive S2CStopMove at path end", sessionId)
+		}
+		resp := stop.Msg.(*protocol.S2CEndMoveReq)
+		if resp.GetEntityHdl() != et.GetHdl() || resp.GetPosX() != wantPx || resp.GetPosY() != wantPy {
+			t.Fatalf("%s stop move hdl=%d pos=(%d,%d), want hdl=%d pos=(%d,%d)",
+				sessionId, resp.GetEntityHdl(), resp.GetPosX(), resp.GetPosY(), et.GetHdl(), wantPx, wantPy)
+		}
+	}
+}
+
+func TestGmMoveSuspicionListsAndClearsOnLeave(t *testing.T) {
+	h, d := setupScenario(t)
+	facade := gshare.GetDungeonActorFacade()
+	RegisterMoveHandlers(facade)
+	RegisterGmHandlers(facade)
+
+	enterGame(t, d, "s1", 1001)
+	h.Run()
+	enter, ok := h.Client().Last("s1", protocol.S2CProtocol_S2CEnterScene)
+	if !ok {
+		t.Fatal("s1 did not receive S2CEnterScene")
+	}
+	entity := enter.Msg.(*protocol.S2CEnterSceneReq).GetEntityData()
+	px, py := argsdef.TileCoordToPixel(entity.GetPosX(), entity.GetPosY())
+
+	// 超速起步被拒绝，记一次违规
+	call := func(sessionId string, msgId protocol.DungeonActorMsgId, req proto.Message) {
+		t.Helper()
+		data, err := proto.Marshal(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := d.AsyncCall(context.Background(), sessionId, uint16(msgId), data); err != nil {
+			t.Fatal(err)
+		}
+		h.Run()
+	}
+	call("s1", protocol.DungeonActorMsgId_DAMStartMove, &protocol.C2SStartMoveReq{FromX: px, FromY: py, ToX: px + 64, ToY: py, Speed: 1 << 20})
+	if _, ok := h.Client().Last("s1", protocol.S2CProtocol_S2CPosCorrect); !ok {
+		t.Fatal("overspeed start move was not corrected")
+	}
+
+	call("s1", protocol.DungeonActorMsgId_DAMGmMoveSuspicion, &protocol.DAMGmMoveSuspicionReq{Name: "movesuspicion", RoleId: 1001})
+	got, ok := h.Client().Last("s1", protocol.S2CProtocol_S2CGmCommand)
+	if !ok {
+		t.Fatal("s1 did not receive movesuspicion result")
+	}
+	if msg := got.Msg.(*protocol.S2CGmCommandReq).GetMsg(); !strings.HasPrefix(msg, "role 1001 count=1 last=speed@") {
+		t.Fatalf("unexpected movesuspicion result %q", msg)
+	}
+
+	if err := d.AsyncCall(context.Background(), "s1", uint16(protocol.DungeonActorMsgId_DAMLeaveGame), nil); err != nil {
+		t.Fatal(err)
+	}
+	h.Run()
+	if _, ok := entitysystem.GetMoveSuspicion(1001); ok {
+		t.Fatal("move suspicion should be cleared when role leaves game")
+	}
+}
+
+func TestTeamChatReachesPlayersInSameInstance(t *testing.T) {
+	h, d := setupScenario(t)
+	facade := gshare.GetDungeonActorFacade()
+	RegisterFuBenHandlers(facade)
+	RegisterChatHandlers(facade)
+
+	for i, sessionId := range []string{"s1", "s2", "s3"} {
+		enterGame(t, d, sessionId, uint64(1001+i))
+	}
+	h.Run()
+
+	call := func(sessionId string, msgId protocol.DungeonActorMsgId, req proto.Message) {
+		t.Helper()
+		data, err := proto.Marshal(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := d.AsyncCall(context.Background(), sessionId, uint16(msgId), data); err != nil {
+			t.Fatal(err)
+		}
+		h.Run()
+	}
+	// s1 创建实例，s2 加入同一实例，s3 留在默认副本
+	const fubenId = 2
+	call("s1", protocol.DungeonActorMsgId_DAMEnterFuBen, &protocol.C2SEnterFuBenReq{FubenId: fubenId})
+	info, ok := h.Client().Last("s1", protocol.S2CProtocol_S2CFuBenInfo)
+	if !ok {
+		t.Fatal("s1 did not enter fuben instance")
+	}
+	instanceId := info.Msg.(*protocol.S2CFuBenInfoReq).GetInstanceId()
+	call("s2", protocol.DungeonActorMsgId_DAMEnterFuBen, &protocol.C2SEnterFuBenReq{FubenId: fubenId, InstanceId: instanceId})
+	if info, ok := h.Client().Last("s2", protocol.S2CProtocol_S2CFuBenInfo); !ok || info.Msg.(*protocol.S2CFuBenInfoReq).GetInstanceId() != instanceId {
+		t.Fatal("s2 did not join s1's fuben instance")
+	}
+
+	msg := &protocol.ChatMsgSt{Channel: protocol.ChatChannel_ChatChannelTeam, SenderId: 1001, Content: "team"}
+	call("s1", protocol.DungeonActorMsgId_DAMChatTeam, &protocol.DAMChatTeamReq{Msg: msg})
+	for _, sessionId := range []string{"s1", "s2"} {
+		got := h.Client().Find(sessionId, protocol.S2CProtocol_S2CChatMessage)
+		if len(got) != 1 || got[0].Msg.(*protocol.S2CChatMessageReq).GetMsg().GetContent() != "team" {
+			t.Fatalf("%s got %d team chat messages", sessionId, len(got))
+		}
+	}
+	if got := h.Client().Find("s3", protocol.S2CProtocol_S2CChatMessage); len(got) != 0 {
+		t.Fatalf("s3 outside the instance got %d team chat messages", len(got))
+	}
+
+	// 默认副本内没有队伍
+	msg = &protocol.ChatMsgSt{Channel: protocol.ChatChannel_ChatChannelTeam, SenderId: 1003, Content: "alone"}
+	call("s3", protocol.DungeonActorMsgId_DAMChatTeam, &protocol.DAMChatTeamReq{Msg: msg})
+	errMsg, ok := h.Client().Last("s3", protocol.S2CProtocol_S2CError)
+	if !ok || errMsg.Msg.(*protocol.ErrorData).GetCode() != int32(protocol.ErrorCode_Chat_NoChannel) {
+		t.Fatal("team chat outside instance should return Chat_NoChannel")
+	}
+	if got := h.Client().Find("s3", protocol.S2CProtocol_S2CChatMessage); len(got) != 0 {
+		t.Fatalf("s3 team chat without team was delivered %d times", len(got))
+	}
+}
+
+func TestItemAddBuffRepliesResultToPlayerActor(t *testing.T) {
+	h, d := setupScenario(t)
+	RegisterBuffHandler(gshare.GetDungeonActorFacade())
+	var results []*protocol.PAMAddBuffResultReq
+	gshare.RegisterHandler(uint16(protocol.PlayerActorMsgId_PAMAddBuffResult), func(msg actor.IActorMessage) {
+		var req protocol.PAMAddBuffResultReq
+		if err := proto.Unmarshal(msg.GetData(), &req); err != nil {
+			t.Errorf("unmarshal PAMAddBuffResultReq: %v", err)
+			return
+		}
+		results = append(results, &req)
+	})
+
+	enterGame(t, d, "s1", 1001)
+	h.Run()
+
+	addBuff := func(req *protocol.DAMAddBuffReq) {
+		data, err := proto.Marshal(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := d.AsyncCall(context.Background(), "s1", uint16(protocol.DungeonActorMsgId_DAMAddBuff), data); err != nil {
+			t.Fatal(err)
+		}
+		h.Run()
+	}
+	addBuff(&protocol.DAMAddBuffReq{BuffId: 2001, ItemId: 7, UseSeq: 1})
+	addBuff(&protocol.DAMAddBuffReq{BuffId: 999999, ItemId: 7, UseSeq: 2})
+	// 非道具发起的加Buff不回传结果
+	addBuff(&protocol.DAMAddBuffReq{BuffId: 2002})
+
+	if len(results) != 2 {
+		t.Fatalf("want 2 results, got %d", len(results))
+	}
+	if !results[0].Success || results[0].UseSeq != 1 || results[0].ItemId != 7 {
+		t.Fatalf("first result %v, want success for seq 1", results[0])
+	}
+	if results[1].Success || results[1].UseSeq != 2 {
+		t.Fatalf("second result %v, want failure for seq 2", results[1])
+	}
+}
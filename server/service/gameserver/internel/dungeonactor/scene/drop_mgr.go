@@ -0,0 +1,158 @@
+package scene
+
+import (
+	"math/rand"
+	"postapocgame/server/internal/jsonconf"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/internal/servertime"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/entity"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/iface"
+	"time"
+)
+
+const (
+	// defaultDropProtect 默认归属保护时间，保护期内只有归属者可以拾取
+	defaultDropProtect = 30 * time.Second
+	// defaultDropExpire 默认地面存在时间
+	defaultDropExpire = 2 * time.Minute
+	// dropScatterRadius 掉落物散落半径（格子）
+	dropScatterRadius = 2
+)
+
+// DropMgr 场景掉落管理器，负责生成地面掉落物并在到期后回收
+// 只在 DungeonActor 单线程中由场景 RunOne 驱动，不做加锁
+type DropMgr struct {
+	scene *SceneSt
+	drops map[uint64]iface.IDropItem // hdl -> drop
+}
+
+// NewDropMgr 创建掉落管理器
+func NewDropMgr(sc *SceneSt) *DropMgr {
+	return &DropMgr{
+		scene: sc,
+		drops: make(map[uint64]iface.IDropItem),
+	}
+}
+
+// Drop 按掉落配置在 (x, y) 附近生成掉落物
+func (dm *DropMgr) Drop(x, y uint32, ownerId uint64, dropId uint32) {
+	cfg := jsonconf.GetConfigManager().GetDropConfig(dropId)
+	if cfg == nil {
+		log.Errorf("scene %d drop config not found: %d", dm.scene.sceneId, dropId)
+		return
+	}
+	protect, expire := defaultDropProtect, defaultDropExpire
+	if cfg.ProtectMs > 0 {
+		protect = time.Duration(cfg.ProtectMs) * time.Millisecond
+	}
+	if cfg.ExpireMs > 0 {
+		expire = time.Duration(cfg.ExpireMs) * time.Millisecond
+	}
+
+	now := servertime.Now()
+	for _, item := range RollDrop(cfg) {
+		dx, dy := dm.pickDropPos(x, y)
+		drop := entity.NewDropItem(item, ownerId, now.Add(protect), now.Add(expire), dx, dy)
+		if err := dm.scene.AddEntity(drop); err != nil {
+			log.Errorf("scene %d add drop item %d failed: %v", dm.scene.sceneId, item.ItemId, err)
+			continue
+		}
+		dm.scene.broadcastAppear(drop)
+		dm.drops[drop.GetHdl()] = drop
+	}
+}
+
+// Remove 移除掉落物（拾取完成）
+func (dm *DropMgr) Remove(hdl uint64) {
+	drop, ok := dm.drops[hdl]
+	if !ok {
+		return
+	}
+	dm.scene.broadcastDisappear(drop)
+	if err := dm.scene.RemoveEntity(hdl); err != nil {
+		log.Warnf("scene %d remove drop failed: %v", dm.scene.sceneId, err)
+	}
+	delete(dm.drops, hdl)
+}
+
+// RunOne 回收到期的掉落物
+// 拾取中的掉落物同样回收：入包结果回来时找不到掉落物只会丢弃结果，不会导致道具重复
+func (dm *DropMgr) RunOne(now time.Time) {
+	for hdl, drop := range dm.drops {
+		if drop.IsExpired(now) {
+			dm.Remove(hdl)
+		}
+	}
+}
+
+// pickDropPos 在掉落点附近随机可行走格子，找不到时落在掉落点
+func (dm *DropMgr) pickDropPos(x, y uint32) (uint32, uint32) {
+	const maxAttempts = 8
+	for i := 0; i < maxAttempts; i++ {
+		dx := int(x) + rand.Intn(2*dropScatterRadius+1) - dropScatterRadius
+		dy := int(y) + rand.Intn(2*dropScatterRadius+1) - dropScatterRadius
+		if dm.scene.IsWalkable(dx, dy) {
+			return uint32(dx), uint32(dy)
+		}
+	}
+	return x, y
+}
+
+// RollDrop 按掉落配置随机掉落道具：必掉全部产出，权重池按 Rolls 次数独立抽取
+func RollDrop(cfg *jsonconf.DropConfig) []*protocol.ItemAmount {
+	var items []*protocol.ItemAmount
+	for _, dropItem := range cfg.Guaranteed {
+		if item := rollDropItem(dropItem); item != nil {
+			items = append(items, item)
+		}
+	}
+
+	var totalWeight uint32
+	for _, dropItem := range cfg.Pool {
+		if dropItem != nil {
+			totalWeight += dropItem.Weight
+		}
+	}
+	if totalWeight == 0 {
+		return items
+	}
+	for i := uint32(0); i < cfg.Rolls; i++ {
+		hit := uint32(rand.Int63n(int64(totalWeight)))
+		for _, dropItem := range cfg.Pool {
+			if dropItem == nil {
+				continue
+			}
+			if hit < dropItem.Weight {
+				if item := rollDropItem(dropItem); item != nil {
+					items = append(items, item)
+				}
+				break
+			}
+			hit -= dropItem.Weight
+		}
+	}
+	return items
+}
+
+func rollDropItem(dropItem *jsonconf.DropItem) *protocol.ItemAmount {
+	if dropItem == nil || dropItem.ItemId == 0 {
+		return nil
+	}
+	count := dropItem.MinCount
+	if dropItem.MaxCount > count {
+		count += uint32(rand.Intn(int(dropItem.MaxCount-count) + 1))
+	}
+	if count == 0 {
+		return nil
+	}
+	itemCfg := jsonconf.GetConfigManager().GetItemConfig(dropItem.ItemId)
+	if itemCfg == nil {
+		return nil
+	}
+	return &protocol.ItemAmount{
+		ItemType: itemCfg.Type,
+		ItemId:   dropItem.ItemId,
+		Count:    int64(count),
+	}
+}
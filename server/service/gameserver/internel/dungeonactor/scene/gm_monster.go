@@ -0,0 +1,79 @@
+package scene
+
+import (
+	"math/rand"
+	"postapocgame/server/internal/jsonconf"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/entity"
+	iface2 "postapocgame/server/service/gameserver/internel/dungeonactor/iface"
+	"time"
+)
+
+// gmSpawnRadius GM 刷怪在目标点附近随机的半径（格）
+const gmSpawnRadius = 2
+
+// SpawnMonster 在 (x, y) 附近刷出一只怪物（GM 指令使用），不属于任何刷怪点，死亡后回收且不重生
+func (s *SceneSt) SpawnMonster(monsterId, x, y uint32) (iface2.IMonster, error) {
+	cfg := jsonconf.GetConfigManager().GetMonsterConfig(monsterId)
+	if cfg == nil {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "monster %d not configured", monsterId)
+	}
+	x, y = s.pickNearbyPos(x, y, gmSpawnRadius)
+	monster := entity.NewMonster(cfg, 0, x, y)
+	if err := s.AddEntity(monster); err != nil {
+		return nil, err
+	}
+	s.broadcastAppear(monster)
+	if s.gmMonsters == nil {
+		s.gmMonsters = make(map[uint64]iface2.IMonster)
+	}
+	s.gmMonsters[monster.GetHdl()] = monster
+	return monster, nil
+}
+
+// KillAllMonsters 击杀场景内全部存活怪物，经验与掉落归属 killer，返回击杀数量
+func (s *SceneSt) KillAllMonsters(killer iface2.IEntity) int {
+	alive := make([]iface2.IMonster, 0)
+	for _, e := range s.entities {
+		if monster, ok := e.(iface2.IMonster); ok && !monster.IsDead() {
+			alive = append(alive, monster)
+		}
+	}
+	// 先收集再击杀：死亡掉落会向场景添加实体
+	for _, monster := range alive {
+		monster.OnAttacked(killer, monster.GetHP())
+	}
+	return len(alive)
+}
+
+// collectGmMonsters 回收尸体保留时长已过的 GM 怪物
+func (s *SceneSt) collectGmMonsters(now time.Time) {
+	for hdl, monster := range s.gmMonsters {
+		if !monster.IsDead() || now.Sub(monster.GetDieTime()) < monsterCorpseDuration {
+			continue
+		}
+		s.broadcastDisappear(monster)
+		if err := s.RemoveEntity(hdl); err != nil {
+			log.Warnf("scene %d remove dead gm monster failed: %v", s.sceneId, err)
+		}
+		delete(s.gmMonsters, hdl)
+	}
+}
+
+// pickNearbyPos 在 (x, y) 半径内随机可行走格子，找不到时退化为原点或场景随机点
+func (s *SceneSt) pickNearbyPos(x, y uint32, radius int) (uint32, uint32) {
+	const maxAttempts = 16
+	for i := 0; i < maxAttempts; i++ {
+		nx := int(x) + rand.Intn(2*radius+1) - radius
+		ny := int(y) + rand.Intn(2*radius+1) - radius
+		if s.IsWalkable(nx, ny) {
+			return uint32(nx), uint32(ny)
+		}
+	}
+	if s.IsWalkable(int(x), int(y)) {
+		return x, y
+	}
+	return s.GetRandomWalkablePos()
+}
@@ -0,0 +1,141 @@
+package scene
+
+import (
+	"math/rand"
+	"postapocgame/server/internal/jsonconf"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/entity"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/iface"
+	"time"
+)
+
+// monsterCorpseDuration 怪物死亡后尸体保留时长，留给客户端播放死亡表现
+const monsterCorpseDuration = 2 * time.Second
+
+// MonsterSpawner 场景刷怪器，按 monstersceneconfig 维护各刷怪点的存活数量与重生
+// 只在 DungeonActor 单线程中由场景 RunOne 驱动，不做加锁
+type MonsterSpawner struct {
+	scene  *SceneSt
+	points []*spawnPointSt
+}
+
+type spawnPointSt struct {
+	cfg       *jsonconf.MonsterSpawnPoint
+	alive     map[uint64]iface.IMonster // hdl -> monster
+	respawnAt []time.Time               // 待重生时间队列
+}
+
+// NewMonsterSpawner 创建刷怪器，场景未配置刷怪时返回 nil
+func NewMonsterSpawner(sc *SceneSt, cfg *jsonconf.MonsterSceneConfig) *MonsterSpawner {
+	if sc == nil || cfg == nil || len(cfg.Spawns) == 0 {
+		return nil
+	}
+	spawner := &MonsterSpawner{
+		scene:  sc,
+		points: make([]*spawnPointSt, 0, len(cfg.Spawns)),
+	}
+	for _, spawnCfg := range cfg.Spawns {
+		if spawnCfg == nil || spawnCfg.MaxAlive == 0 {
+			continue
+		}
+		point := &spawnPointSt{
+			cfg:       spawnCfg,
+			alive:     make(map[uint64]iface.IMonster),
+			respawnAt: make([]time.Time, spawnCfg.MaxAlive), // 零值时间，首帧全部刷出
+		}
+		spawner.points = append(spawner.points, point)
+	}
+	return spawner
+}
+
+// RunOne 清理尸体并刷出到期的怪物
+func (ms *MonsterSpawner) RunOne(now time.Time) {
+	for _, point := range ms.points {
+		ms.collectDead(point, now)
+		ms.respawnDue(point, now)
+	}
+}
+
+// GetAliveCount 获取刷怪点当前存活（含尸体）数量
+func (ms *MonsterSpawner) GetAliveCount(spawnId uint32) int {
+	for _, point := range ms.points {
+		if point.cfg.SpawnId == spawnId {
+			return len(point.alive)
+		}
+	}
+	return 0
+}
+
+func (ms *MonsterSpawner) collectDead(point *spawnPointSt, now time.Time) {
+	for hdl, monster := range point.alive {
+		if !monster.IsDead() {
+			continue
+		}
+		if now.Sub(monster.GetDieTime()) < monsterCorpseDuration {
+			continue
+		}
+		ms.scene.broadcastDisappear(monster)
+		if err := ms.scene.RemoveEntity(hdl); err != nil {
+			log.Warnf("scene %d remove dead monster failed: %v", ms.scene.sceneId, err)
+		}
+		delete(point.alive, hdl)
+		delay := time.Duration(point.cfg.RespawnMs) * time.Millisecond
+		point.respawnAt = append(point.respawnAt, monster.GetDieTime().Add(delay))
+	}
+}
+
+func (ms *MonsterSpawner) respawnDue(point *spawnPointSt, now time.Time) {
+	if len(point.respawnAt) == 0 {
+		return
+	}
+	remain := point.respawnAt[:0]
+	for _, at := range point.respawnAt {
+		if now.Before(at) || uint32(len(point.alive)) >= point.cfg.MaxAlive {
+			remain = append(remain, at)
+			continue
+		}
+		monster := ms.spawn(point)
+		if monster == nil {
+			// 刷新失败，下一帧重试
+			remain = append(remain, at)
+			continue
+		}
+		point.alive[monster.GetHdl()] = monster
+	}
+	point.respawnAt = remain
+}
+
+func (ms *MonsterSpawner) spawn(point *spawnPointSt) iface.IMonster {
+	cfg := jsonconf.GetConfigManager().GetMonsterConfig(point.cfg.MonsterId)
+	if cfg == nil {
+		log.Errorf("scene %d spawn %d monster config not found: %d", ms.scene.sceneId, point.cfg.SpawnId, point.cfg.MonsterId)
+		return nil
+	}
+	x, y := ms.pickSpawnPos(point.cfg)
+	monster := entity.NewMonster(cfg, point.cfg.SpawnId, x, y)
+	if err := ms.scene.AddEntity(monster); err != nil {
+		log.Errorf("scene %d add monster %d failed: %v", ms.scene.sceneId, point.cfg.MonsterId, err)
+		return nil
+	}
+	ms.scene.broadcastAppear(monster)
+	return monster
+}
+
+// pickSpawnPos 在刷怪点半径内随机可行走格子，找不到时退化为中心点或场景随机点
+func (ms *MonsterSpawner) pickSpawnPos(cfg *jsonconf.MonsterSpawnPoint) (uint32, uint32) {
+	radius := int(cfg.Radius)
+	if radius > 0 {
+		const maxAttempts = 16
+		for i := 0; i < maxAttempts; i++ {
+			x := int(cfg.X) + rand.Intn(2*radius+1) - radius
+			y := int(cfg.Y) + rand.Intn(2*radius+1) - radius
+			if ms.scene.IsWalkable(x, y) {
+				return uint32(x), uint32(y)
+			}
+		}
+	}
+	if ms.scene.IsWalkable(int(cfg.X), int(cfg.Y)) {
+		return cfg.X, cfg.Y
+	}
+	return ms.scene.GetRandomWalkablePos()
+}
@@ -7,7 +7,7 @@ import (
 
 // AOIManager AOI管理器（场景级别）
 type AOIManager struct {
-	grIds map[argsdef.GrIdSt]map[uint64]iface.IEntity // grIdId -> entityId -> entity
+	grIds map[argsdef.GrIdSt]map[uint64]iface.IEntity // grIdId -> entityHdl -> entity
 }
 
 // NewAOIManager 创建AOI管理器
@@ -26,7 +26,7 @@ func (am *AOIManager) AddEntity(entity iface.IEntity) {
 		if _, ok := am.grIds[grId]; !ok {
 			am.grIds[grId] = make(map[uint64]iface.IEntity)
 		}
-		am.grIds[grId][entity.GetId()] = entity
+		am.grIds[grId][entity.GetHdl()] = entity
 	}
 
 	// 更新该实体的可见列表
@@ -40,7 +40,7 @@ func (am *AOIManager) RemoveEntity(entity iface.IEntity) {
 
 	for _, grId := range grIds {
 		if entities, ok := am.grIds[grId]; ok {
-			delete(entities, entity.GetId())
+			delete(entities, entity.GetHdl())
 			if len(entities) == 0 {
 				delete(am.grIds, grId)
 			}
@@ -87,7 +87,7 @@ func (am *AOIManager) UpdateEntity(entity iface.IEntity, oldPos, newPos *argsdef
 
 	for _, grId := range toLeave {
 		if entities, ok := am.grIds[grId]; ok {
-			delete(entities, entity.GetId())
+			delete(entities, entity.GetHdl())
 			if len(entities) == 0 {
 				delete(am.grIds, grId)
 			}
@@ -98,7 +98,7 @@ func (am *AOIManager) UpdateEntity(entity iface.IEntity, oldPos, newPos *argsdef
 		if _, ok := am.grIds[grId]; !ok {
 			am.grIds[grId] = make(map[uint64]iface.IEntity)
 		}
-		am.grIds[grId][entity.GetId()] = entity
+		am.grIds[grId][entity.GetHdl()] = entity
 	}
 
 	// 更新可见列表（无锁）
@@ -116,7 +116,7 @@ func (am *AOIManager) updateEntityVisibility(entity iface.IEntity) {
 		if entities, ok := am.grIds[grId]; ok {
 			for Id, e := range entities {
 				// 不包括自己
-				if Id != entity.GetId() {
+				if Id != entity.GetHdl() {
 					visibleEntities[Id] = e
 				}
 			}
@@ -135,8 +135,8 @@ func (am *AOIManager) updateEntityVisibility(entity iface.IEntity) {
 	// 找出离开视野的实体
 	currentVisible := aoi.GetVisibleEntities()
 	for _, e := range currentVisible {
-		if _, ok := visibleEntities[e.GetId()]; !ok {
-			aoi.RemoveVisibleEntity(e.GetId())
+		if _, ok := visibleEntities[e.GetHdl()]; !ok {
+			aoi.RemoveVisibleEntity(e.GetHdl())
 		}
 	}
 }
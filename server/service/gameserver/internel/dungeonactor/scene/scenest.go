@@ -4,10 +4,12 @@ import (
 	"math/rand"
 	"postapocgame/server/internal/argsdef"
 	"postapocgame/server/internal/jsonconf"
+	"postapocgame/server/internal/pathfind"
+	"postapocgame/server/internal/protocol"
 	"postapocgame/server/pkg/customerr"
 	"postapocgame/server/pkg/log"
-	"postapocgame/server/service/gameserver/internel/dungeonactor/entitymgr"
 	iface2 "postapocgame/server/service/gameserver/internel/dungeonactor/iface"
+	"time"
 )
 
 // SceneSt 场景结构
@@ -31,6 +33,13 @@ type SceneSt struct {
 	gameMap     *jsonconf.GameMap
 	walkableMap [][]bool // fallback 使用
 
+	// 刷怪器（未配置刷怪时为 nil）
+	spawner *MonsterSpawner
+	// GM 指令刷出的怪物（不重生）
+	gmMonsters map[uint64]iface2.IMonster
+	// 掉落管理器
+	dropMgr *DropMgr
+
 	nextEntityId uint64
 }
 
@@ -53,9 +62,32 @@ func NewSceneSt(fuBen iface2.IFuBen, sceneId, fuBenId uint32, name string, wIdth
 	// 初始化地图
 	scene.initMap()
 
+	// 初始化刷怪器，怪物在首次 RunOne 时刷出
+	scene.spawner = NewMonsterSpawner(scene, jsonconf.GetConfigManager().GetMonsterSceneConfig(sceneId))
+	scene.dropMgr = NewDropMgr(scene)
+
 	return scene
 }
 
+// RunOne 场景单帧逻辑，由副本 RunOne 驱动
+func (s *SceneSt) RunOne(now time.Time) {
+	if s.spawner != nil {
+		s.spawner.RunOne(now)
+	}
+	s.collectGmMonsters(now)
+	s.dropMgr.RunOne(now)
+}
+
+// DropLoot 按掉落配置在 (x, y) 附近生成地面掉落物，ownerId 为归属角色ID
+func (s *SceneSt) DropLoot(x, y uint32, ownerId uint64, dropId uint32) {
+	s.dropMgr.Drop(x, y, ownerId, dropId)
+}
+
+// RemoveDrop 移除地面掉落物并通知视野内玩家
+func (s *SceneSt) RemoveDrop(hdl uint64) {
+	s.dropMgr.Remove(hdl)
+}
+
 func (s *SceneSt) GetFuBen() iface2.IFuBen {
 	return s.fuBen
 }
@@ -101,6 +133,37 @@ func (s *SceneSt) IsWalkable(x, y int) bool {
 	return s.walkableMap[y][x]
 }
 
+// FindPath 寻路，返回平滑后的路点格子坐标（不含起点，含终点）
+func (s *SceneSt) FindPath(fromX, fromY, toX, toY uint32) ([]*argsdef.Position, error) {
+	var grid pathfind.Grid = sceneGrid{scene: s}
+	if s.gameMap != nil {
+		grid = s.gameMap
+	}
+	points, err := pathfind.FindPath(grid,
+		pathfind.Point{X: int32(fromX), Y: int32(fromY)},
+		pathfind.Point{X: int32(toX), Y: int32(toY)},
+		pathfind.DefaultMaxNodes)
+	if err != nil {
+		return nil, err
+	}
+	path := make([]*argsdef.Position, 0, len(points))
+	for _, p := range points {
+		path = append(path, &argsdef.Position{X: uint32(p.X), Y: uint32(p.Y)})
+	}
+	return path, nil
+}
+
+// sceneGrid 未配置地图数据的场景使用 fallback 可行走表寻路
+type sceneGrid struct {
+	scene *SceneSt
+}
+
+func (g sceneGrid) Width() int32  { return int32(g.scene.width) }
+func (g sceneGrid) Height() int32 { return int32(g.scene.height) }
+func (g sceneGrid) IsWalkable(x, y int32) bool {
+	return g.scene.IsWalkable(int(x), int(y))
+}
+
 // GetRandomWalkablePos 获取随机可行走位置
 // 返回：格子坐标（不是像素坐标）
 func (s *SceneSt) GetRandomWalkablePos() (uint32, uint32) {
@@ -189,12 +252,13 @@ func (s *SceneSt) AddEntity(e iface2.IEntity) error {
 		moveSys.BindScene(s)
 	}
 
-	// 注册到全局EntityMgr
-	entityMgr := entitymgr.GetEntityMgr()
+	// 注册到副本实例的EntityMgr
+	entityMgr := s.fuBen.GetEntityMgr()
 	if err := entityMgr.Register(e); err != nil {
 		log.Warnf("Register entity to EntityMgr failed: %v", err)
 	}
 	entityMgr.BindScene(hdl, s)
+	e.SetEntityMgr(entityMgr)
 
 	// 添加到AOI管理器
 	s.aoiMgr.AddEntity(e)
@@ -226,10 +290,11 @@ func (s *SceneSt) RemoveEntity(hdl uint64) error {
 
 	delete(s.entities, hdl)
 
-	// 从全局EntityMgr注销
-	entityMgr := entitymgr.GetEntityMgr()
+	// 从副本实例的EntityMgr注销
+	entityMgr := s.fuBen.GetEntityMgr()
 	entityMgr.UnbindScene(hdl)
 	entityMgr.Unregister(hdl)
+	e.SetEntityMgr(nil)
 
 	log.Infof("Entity %d (hdl) left scene %d", hdl, s.sceneId)
 
@@ -275,6 +340,36 @@ func (s *SceneSt) EntityMove(hdl uint64, newX, newY uint32) error {
 	return nil
 }
 
+// GetEntitiesInRange 获取坐标所在九宫格内的实体
+func (s *SceneSt) GetEntitiesInRange(pos *argsdef.Position) []iface2.IEntity {
+	return s.aoiMgr.GetEntitiesInRange(pos)
+}
+
+// broadcastAppear 通知视野内的玩家实体出现
+func (s *SceneSt) broadcastAppear(e iface2.IEntity) {
+	// 非玩家实体不需要下发视野变化，丢弃 AOI 累积的增量
+	e.GetAOISys().ConsumeVisibilityChanges()
+
+	appear := &protocol.S2CEntityAppearReq{Entity: e.BuildProtoEntitySt()}
+	for _, observer := range s.aoiMgr.GetEntitiesInRange(e.GetPosition()) {
+		if observer.GetHdl() == e.GetHdl() || observer.GetEntityType() != uint32(protocol.EntityType_EtPlayer) {
+			continue
+		}
+		_ = observer.SendProtoMessage(uint16(protocol.S2CProtocol_S2CEntityAppear), appear)
+	}
+}
+
+// broadcastDisappear 通知视野内的玩家实体消失，需在 RemoveEntity 之前调用
+func (s *SceneSt) broadcastDisappear(e iface2.IEntity) {
+	disappear := &protocol.S2CEntityDisappearReq{EntityHdl: e.GetHdl()}
+	for _, observer := range s.aoiMgr.GetEntitiesInRange(e.GetPosition()) {
+		if observer.GetHdl() == e.GetHdl() || observer.GetEntityType() != uint32(protocol.EntityType_EtPlayer) {
+			continue
+		}
+		_ = observer.SendProtoMessage(uint16(protocol.S2CProtocol_S2CEntityDisappear), disappear)
+	}
+}
+
 // GetSceneId 获取场景Id
 func (s *SceneSt) GetSceneId() uint32 {
 	return s.sceneId
@@ -3,7 +3,7 @@ package skill
 
 import (
 	"math"
-	"postapocgame/server/service/gameserver/internel/dungeonactor/entitymgr"
+	"math/rand"
 	"postapocgame/server/service/gameserver/internel/dungeonactor/iface"
 	"time"
 
@@ -53,7 +53,10 @@ func (s *Skill) FindSkillTargets(ctx *argsdef.SkillCastContext, caster iface.IEn
 	}
 
 	var targets []iface.IEntity
-	entityMgr := entitymgr.GetEntityMgr()
+	entityMgr := caster.GetEntityMgr()
+	if entityMgr == nil {
+		return nil, protocol.SkillUseErr_ErrSkillTargetInvalId
+	}
 
 	switch skillCfg.TargetType {
 	case uint32(protocol.SkillTargetType_SkillTargetTypeSingle):
@@ -128,7 +131,10 @@ func (s *Skill) calculateDistance(pos1, pos2 *argsdef.Position) uint32 {
 // findAOETargets 查找AOE范围内的目标
 // 注意：posX、posY 是格子坐标，radius 是格子距离
 func (s *Skill) findAOETargets(caster iface.IEntity, posX, posY, radius uint32, maxCount int) []iface.IEntity {
-	entityMgr := entitymgr.GetEntityMgr()
+	entityMgr := caster.GetEntityMgr()
+	if entityMgr == nil {
+		return nil
+	}
 	allEntities := entityMgr.GetAll()
 
 	targets := make([]iface.IEntity, 0)
@@ -215,6 +221,9 @@ func (s *Skill) Use(ctx *argsdef.SkillCastContext, caster iface.IEntity) *protoc
 		}
 
 		for _, effect := range skillCfg.Effects {
+			if !rollEffectRate(effect.Rate) {
+				continue
+			}
 			switch protocol.SkillResultType(effect.Type) {
 			case protocol.SkillResultType_SkillResultTypeDamage:
 				damage, isCrit, _ := damageCalc.Calculate(caster, target, ctx.SkillId)
@@ -231,6 +240,8 @@ func (s *Skill) Use(ctx *argsdef.SkillCastContext, caster iface.IEntity) *protoc
 				hit.AddedBuffs = append(hit.AddedBuffs, effect.Value)
 				hit.ResultType = protocol.SkillResultType_SkillResultTypeAddBuff
 			case protocol.SkillResultType_SkillResultTypeRemoveBuff:
+				hit.RemovedBuffs = append(hit.RemovedBuffs, effect.Value)
+				hit.ResultType = protocol.SkillResultType_SkillResultTypeRemoveBuff
 			}
 		}
 
@@ -255,6 +266,14 @@ func (s *Skill) Use(ctx *argsdef.SkillCastContext, caster iface.IEntity) *protoc
 	return result
 }
 
+// rollEffectRate 技能效果触发概率判定，rate<=0 或 >=1 视为必定触发
+func rollEffectRate(rate float32) bool {
+	if rate <= 0 || rate >= 1 {
+		return true
+	}
+	return rand.Float32() < rate
+}
+
 func buildDamageFlags(cfg *jsonconf.SkillConfig, resultType protocol.SkillResultType) uint64 {
 	switch resultType {
 	case protocol.SkillResultType_SkillResultTypeHeal:
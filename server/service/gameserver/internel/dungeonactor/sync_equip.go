@@ -0,0 +1,48 @@
+package dungeonactor
+
+import (
+	"context"
+	"postapocgame/server/internal/actor"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/entitymgr"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/iface"
+	"postapocgame/server/service/gameserver/internel/gshare"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// handleSyncEquip 处理 PlayerActor → DungeonActor 的装备属性与外观同步
+// 入口：protocol.DungeonActorMsgId_DAMSyncEquip
+func handleSyncEquip(msg actor.IActorMessage) error {
+	if msg == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "nil message")
+	}
+
+	ctx := msg.GetContext()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	sessionId, _ := ctx.Value(gshare.ContextKeySession).(string)
+	if sessionId == "" {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "not found session")
+	}
+
+	var req protocol.DAMSyncEquipReq
+	if err := proto.Unmarshal(msg.GetData(), &req); err != nil {
+		return customerr.Wrap(err)
+	}
+
+	et, ok := entitymgr.GetBySession(ctx, sessionId)
+	if !ok || et == nil {
+		// 尚未进入场景，进入时会携带最新装备
+		return nil
+	}
+	player, ok := et.(iface.IPlayer)
+	if !ok {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "entity is not player")
+	}
+
+	player.ApplyEquip(req.Attrs, req.Appearance)
+	return nil
+}
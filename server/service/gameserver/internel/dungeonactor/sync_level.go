@@ -0,0 +1,54 @@
+package dungeonactor
+
+import (
+	"context"
+	"postapocgame/server/internal/actor"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/entitymgr"
+	"postapocgame/server/service/gameserver/internel/dungeonactor/iface"
+	"postapocgame/server/service/gameserver/internel/gshare"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// handleSyncLevel 处理 PlayerActor → DungeonActor 的等级同步
+// 入口：protocol.DungeonActorMsgId_DAMSyncLevel
+func handleSyncLevel(msg actor.IActorMessage) error {
+	if msg == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "nil message")
+	}
+
+	ctx := msg.GetContext()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	sessionId, _ := ctx.Value(gshare.ContextKeySession).(string)
+	if sessionId == "" {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "not found session")
+	}
+
+	var req protocol.DAMSyncLevelReq
+	if err := proto.Unmarshal(msg.GetData(), &req); err != nil {
+		return customerr.Wrap(err)
+	}
+
+	et, ok := entitymgr.GetBySession(ctx, sessionId)
+	if !ok || et == nil {
+		// 尚未进入场景，进入时会携带最新等级
+		return nil
+	}
+	player, ok := et.(iface.IPlayer)
+	if !ok {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "entity is not player")
+	}
+
+	player.ApplyLevel(req.Level, req.Attrs)
+	for skillId, skillLv := range req.UnlockSkills {
+		if err := player.UpdateSkill(skillId, skillLv); err != nil {
+			log.Warnf("[dungeon-actor] sync level learn skill %d failed: %v", skillId, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/3
+ * @Desc: 独立部署的 DungeonServer 链路（GameServer 侧）
+**/
+
+package dungeonlink
+
+import (
+	"context"
+	"postapocgame/server/internal/actor"
+	"postapocgame/server/internal/network"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/gshare"
+	"postapocgame/server/service/gameserver/internel/manager"
+)
+
+// DungeonClient 连接远程 DungeonServer，实现 gshare.IDungeonActorFacade
+// PlayerActor → DungeonActor 的消息与 DungeonActor → PlayerActor 的消息均以 MsgTypeClient 转发消息传输：
+// ForwardMessage{SessionId, Payload: ClientMessage{MsgId: Actor消息ID, Data}}
+type DungeonClient struct {
+	addr   string
+	client network.ITCPClient
+	codec  *network.Codec
+}
+
+var _ gshare.IDungeonActorFacade = (*DungeonClient)(nil)
+
+// NewDungeonClient 创建 DungeonServer 客户端
+func NewDungeonClient(addr string) *DungeonClient {
+	dc := &DungeonClient{
+		addr:  addr,
+		codec: network.DefaultCodec(),
+	}
+	dc.client = network.NewTCPClient(
+		network.WithTCPClientOptionOnConn(dc.onConnected),
+		network.WithTCPClientOptionOnDisConn(func(conn network.IConnection) {
+			log.Warnf("[dungeonlink] disconnected from dungeon server: %s", addr)
+		}),
+		network.WithTCPClientOptionNetworkMessageHandler(dc),
+	)
+	return dc
+}
+
+// Connect 连接 DungeonServer（断线后自动重连）
+func (dc *DungeonClient) Connect(ctx context.Context) error {
+	return dc.client.Connect(ctx, dc.addr)
+}
+
+// Close 关闭链路
+func (dc *DungeonClient) Close() error {
+	return dc.client.Close()
+}
+
+// RegisterHandler DungeonActor 处理器注册在 DungeonServer 进程内，这里无需注册
+func (dc *DungeonClient) RegisterHandler(uint16, actor.HandlerMessageFunc) {}
+
+// SendMessageAsync 将 DungeonActor 消息发往 DungeonServer，会话从消息 ctx 中获取
+func (dc *DungeonClient) SendMessageAsync(_ string, message actor.IActorMessage) error {
+	var sessionId string
+	if ctx := message.GetContext(); ctx != nil {
+		sessionId, _ = ctx.Value(gshare.ContextKeySession).(string)
+	}
+	return dc.AsyncCall(message.GetContext(), sessionId, message.GetMsgId(), message.GetData())
+}
+
+// AsyncCall 实现 DungeonServerGateway 的投递语义
+// 链路断开期间直接返回错误，不做缓存：重连后由 PlayerActor 重新进入 DungeonActor 同步状态
+func (dc *DungeonClient) AsyncCall(_ context.Context, sessionId string, msgId uint16, data []byte) error {
+	if !dc.client.IsConnected() {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Rpc_ConnClosed), "dungeon server %s not connected, msgId=%d", dc.addr, msgId)
+	}
+	conn := dc.client.GetConnection()
+	if conn == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Rpc_ConnClosed), "dungeon server %s not connected, msgId=%d", dc.addr, msgId)
+	}
+	return network.NewBaseMessageSenderWithCodec(conn, dc.codec).SendToClient(sessionId, msgId, data)
+}
+
+// HandleMessage 处理 DungeonServer 发来的消息，投递到对应玩家的 PlayerActor
+func (dc *DungeonClient) HandleMessage(ctx context.Context, _ network.IConnection, msg *network.Message) error {
+	switch msg.Type {
+	case network.MsgTypeClient:
+		fwdMsg, err := dc.codec.DecodeForwardMessage(msg.Payload)
+		if err != nil {
+			return customerr.Wrap(err)
+		}
+		clientMsg, err := dc.codec.DecodeClientMessage(fwdMsg.Payload)
+		if err != nil {
+			return customerr.Wrap(err)
+		}
+		newCtx := context.WithValue(context.WithoutCancel(ctx), gshare.ContextKeySession, fwdMsg.SessionId)
+		if err := gshare.SendMessageAsync(fwdMsg.SessionId, actor.NewBaseMessage(newCtx, clientMsg.MsgId, clientMsg.Data)); err != nil {
+			log.Warnf("[dungeonlink] deliver to player actor failed: session=%s msgId=%d err=%v", fwdMsg.SessionId, clientMsg.MsgId, err)
+		}
+		return nil
+	case network.MsgTypeHeartbeat:
+		return nil
+	default:
+		log.Warnf("[dungeonlink] unknown message type: %d", msg.Type)
+		return nil
+	}
+}
+
+// onConnected 链路建立（含重连）后，让在线玩家重新进入 DungeonActor
+// DungeonServer 在链路断开时已清理全部玩家实体，断线期间的移动、战斗等消息直接丢弃
+func (dc *DungeonClient) onConnected(network.IConnection) {
+	log.Infof("[dungeonlink] connected to dungeon server: %s", dc.addr)
+	for _, playerRole := range manager.GetPlayerRoleManager().GetAll() {
+		sessionId := playerRole.GetSessionId()
+		ctx := context.WithValue(context.Background(), gshare.ContextKeySession, sessionId)
+		message := actor.NewBaseMessage(ctx, uint16(protocol.PlayerActorMsgId_PAMReEnterDungeon), nil)
+		if err := gshare.SendMessageAsync(sessionId, message); err != nil {
+			log.Warnf("[dungeonlink] re-enter dungeon failed: roleId=%d err=%v", playerRole.GetPlayerRoleId(), err)
+		}
+	}
+}
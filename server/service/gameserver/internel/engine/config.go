@@ -31,8 +31,13 @@ type ServerConfig struct {
 	ActorPoolSize    int             `json:"actor_pool_size"`    // Actor池大小
 	ActorMailboxSize int             `json:"actor_mailbox_size"` // Actor邮箱大小
 
-	// DungeonServer配置
+	// DungeonServer配置（为空时 DungeonActor 运行在 GameServer 进程内）
 	DungeonServerAddrMap map[uint8]string `json:"dungeon_server_addr_map"` // DungeonServer地址映射 [srvType]addr
+
+	// 运维接口配置（供 admin-server 调用，ops_addr 为空时不启动）
+	OpsAddr     string   `json:"ops_addr"`      // 运维接口监听地址
+	OpsSecret   string   `json:"ops_secret"`    // 请求签名密钥
+	OpsAllowIPs []string `json:"ops_allow_ips"` // 允许访问运维接口的IP列表
 }
 
 const (
@@ -68,8 +73,11 @@ func (c *ServerConfig) Validate() error {
 	if c.ActorPoolSize <= 0 {
 		return customerr.NewError("actor_pool_size must be greater than 0")
 	}
-	// InProcess DungeonActor 模式下，DungeonServerAddrMap 可为空；
-	// 如需远程 DungeonServer，可在配置中补充并复用现有校验逻辑。
+	// InProcess DungeonActor 模式下，DungeonServerAddrMap 为空；
+	// 远程模式目前只有一个全局 DungeonActor，只允许配置一个 DungeonServer。
+	if len(c.DungeonServerAddrMap) > 1 {
+		return customerr.NewError("dungeon_server_addr_map supports only one dungeon server, got %d", len(c.DungeonServerAddrMap))
+	}
 	if len(c.DungeonServerAddrMap) > 0 {
 		for srvType, addr := range c.DungeonServerAddrMap {
 			if addr == "" {
@@ -85,9 +93,25 @@ func (c *ServerConfig) Validate() error {
 			return customerr.NewError("invalid tcp_addr: %v", err)
 		}
 	}
+	if c.OpsAddr != "" {
+		if err := validateAddr(c.OpsAddr); err != nil {
+			return customerr.NewError("invalid ops_addr: %v", err)
+		}
+		if c.OpsSecret == "" {
+			return customerr.NewError("ops_secret is required when ops_addr is set")
+		}
+	}
 	return nil
 }
 
+// GetDungeonServerAddr 获取远程 DungeonServer 地址，未配置时返回 false（进程内模式）
+func (c *ServerConfig) GetDungeonServerAddr() (string, bool) {
+	for _, addr := range c.DungeonServerAddrMap {
+		return addr, true
+	}
+	return "", false
+}
+
 func validateAddr(addr string) error {
 	if addr == "" {
 		return customerr.NewError("address is empty")
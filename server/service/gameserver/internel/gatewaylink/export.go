@@ -14,6 +14,14 @@ import (
 var singleSrv *NetworkHandler
 var once sync.Once
 
+// TransferSession 重连后将原会话的账号与角色信息转给新会话
+func TransferSession(oldSessionId, newSessionId string) {
+	if singleSrv == nil {
+		return
+	}
+	singleSrv.TransferSession(oldSessionId, newSessionId)
+}
+
 func GetSession(sessionId string) iface.ISession {
 	if singleSrv == nil {
 		return nil
@@ -11,7 +11,6 @@ import (
 	"postapocgame/server/pkg/log"
 	"postapocgame/server/service/gameserver/internel/gshare"
 	"postapocgame/server/service/gameserver/internel/iface"
-	"postapocgame/server/service/gameserver/internel/manager"
 	"sync"
 )
 
@@ -42,12 +41,17 @@ func (h *NetworkHandler) HandleMessage(ctx context.Context, conn network.IConnec
 		return customerr.NewError("message sender is nil")
 	}
 	sender.SetConn(conn)
+	GetRPCClient().SetSender(conn)
 
 	switch msg.Type {
 	case network.MsgTypeSessionEvent:
 		return h.handleSessionEvent(msg)
 	case network.MsgTypeClient:
 		return h.handleClientMsg(ctx, msg)
+	case network.MsgTypeRPCRequest:
+		return GetRPCServer().HandleRequest(ctx, conn, msg)
+	case network.MsgTypeRPCResponse:
+		return GetRPCClient().HandleResponse(msg)
 	case network.MsgTypeHeartbeat:
 		return nil
 	default:
@@ -95,18 +99,10 @@ func (h *NetworkHandler) handleSessionClose(event *network.SessionEvent) error {
 	delete(h.sessions, event.SessionId)
 	h.sessionsMu.Unlock()
 
-	// 执行玩家断线与登出清理
-	if playerRole := manager.GetPlayerRoleManager().GetBySession(event.SessionId); playerRole != nil {
-		playerRole.OnDisconnect()
-		if err := playerRole.Close(); err != nil {
-			log.Errorf("player close on session close failed: roleId=%d err=%v", playerRole.GetPlayerRoleId(), err)
-		}
-		manager.GetPlayerRoleManager().Remove(playerRole.GetPlayerRoleId())
-	}
-
-	// 移除玩家Actor
-	err := gshare.RemoveActor(event.SessionId)
-	if err != nil {
+	// 断线与登出交给玩家Actor执行，走高优先级通道，不会因邮箱积压被丢弃，完成后由Actor移除自身
+	ctx := context.WithValue(context.Background(), gshare.ContextKeySession, event.SessionId)
+	message := actor.NewBaseMessage(ctx, uint16(protocol.PlayerActorMsgId_PAMLogout), nil)
+	if err := gshare.SendMessageAsync(event.SessionId, message); err != nil {
 		return customerr.Wrap(err)
 	}
 
@@ -141,6 +137,25 @@ func (h *NetworkHandler) handleClientMsg(ctx context.Context, msg *network.Messa
 	return nil
 }
 
+// TransferSession 新会话继承原会话的账号、角色与 token，原会话被 Gateway 回收后不会再收到关闭事件，这里一并删除
+func (h *NetworkHandler) TransferSession(oldSessionId, newSessionId string) {
+	h.sessionsMu.Lock()
+	defer h.sessionsMu.Unlock()
+	oldInfo, ok := h.sessions[oldSessionId]
+	if !ok {
+		return
+	}
+	delete(h.sessions, oldSessionId)
+	newInfo, ok := h.sessions[newSessionId]
+	if !ok {
+		newInfo = &argsdef.SessionInfo{SessionId: newSessionId, CreatedAt: servertime.Now().Unix()}
+		h.sessions[newSessionId] = newInfo
+	}
+	newInfo.AccountID = oldInfo.AccountID
+	newInfo.RoleId = oldInfo.RoleId
+	newInfo.Token = oldInfo.Token
+}
+
 // GetSession 获取会话
 func (h *NetworkHandler) GetSession(sessionId string) iface.ISession {
 	h.sessionsMu.RLock()
@@ -0,0 +1,49 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/2
+ * @Desc: Gateway ↔ GameServer 的跨进程RPC
+**/
+
+package gatewaylink
+
+import (
+	"context"
+	"postapocgame/server/internal/network"
+	"postapocgame/server/internal/protocol"
+	"sync"
+)
+
+var (
+	rpcServer     *network.RPCServer
+	rpcClient     *network.RPCClient
+	rpcClientOnce sync.Once
+	rpcServerOnce sync.Once
+)
+
+// GetRPCServer 获取 GameServer 对 Gateway 提供的RPC服务，处理函数通过 network.RegisterRPCProtoHandler 注册
+func GetRPCServer() *network.RPCServer {
+	rpcServerOnce.Do(func() {
+		rpcServer = network.NewRPCServer()
+	})
+	return rpcServer
+}
+
+// GetRPCClient 获取调用 Gateway RPC 的客户端，连接随 Gateway 消息更新
+func GetRPCClient() *network.RPCClient {
+	rpcClientOnce.Do(func() {
+		rpcClient = network.NewRPCClient(nil)
+	})
+	return rpcClient
+}
+
+// KickSession 通知 Gateway 断开客户端会话；reason 非 KickNil 时客户端先收到 S2CKick
+func KickSession(ctx context.Context, sessionId string, reason protocol.KickReason, msg string) error {
+	return GetRPCClient().CallProto(ctx, sessionId, uint16(protocol.RpcMsgId_RpcKickSession),
+		&protocol.RpcKickSessionReq{SessionId: sessionId, Reason: reason, Msg: msg}, nil)
+}
+
+// ResumeSession 通知 Gateway 由新会话接管原会话，原会话断线期间缓存的消息按顺序补发给新会话
+func ResumeSession(ctx context.Context, oldSessionId, newSessionId string) error {
+	return GetRPCClient().CallProto(ctx, newSessionId, uint16(protocol.RpcMsgId_RpcResumeSession),
+		&protocol.RpcResumeSessionReq{OldSessionId: oldSessionId, NewSessionId: newSessionId}, nil)
+}
@@ -13,4 +13,7 @@ const (
 	// 玩家登录相关
 	OnPlayerLogin event.Type = iota + 1000
 	OnPlayerLogout
+
+	// 玩家成长相关
+	OnPlayerLevelUp // 升级 args: oldLevel, newLevel uint32
 )
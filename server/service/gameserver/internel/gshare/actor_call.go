@@ -0,0 +1,43 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/12
+ * @Desc: Actor 外部协程（如运维接口）同步调用 PlayerActor：投递消息后等待处理函数应答
+**/
+
+package gshare
+
+import (
+	"context"
+	"postapocgame/server/internal/actor"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+)
+
+type actorReply struct {
+	data []byte
+	err  error
+}
+
+// CallActor 向会话对应的 PlayerActor 投递消息并等待处理函数调用 Reply；
+// 只能对在线角色的会话调用（ModePerKey 下向不存在的 key 投递会新建 Actor），且不能在 PlayerActor 内调用
+func CallActor(ctx context.Context, sessionId string, msgId uint16, data []byte) ([]byte, error) {
+	replyCh := make(chan actorReply, 1)
+	msgCtx := context.WithValue(context.Background(), ContextKeySession, sessionId)
+	msgCtx = context.WithValue(msgCtx, ContextKeyReply, replyCh)
+	if err := SendMessageAsync(sessionId, actor.NewBaseMessage(msgCtx, msgId, data)); err != nil {
+		return nil, customerr.Wrap(err)
+	}
+	select {
+	case r := <-replyCh:
+		return r.data, r.err
+	case <-ctx.Done():
+		return nil, customerr.Wrap(ctx.Err(), int32(protocol.ErrorCode_Network_Timeout))
+	}
+}
+
+// Reply 应答 CallActor，消息不是经 CallActor 投递时忽略
+func Reply(msgCtx context.Context, data []byte, err error) {
+	if replyCh, ok := msgCtx.Value(ContextKeyReply).(chan actorReply); ok {
+		replyCh <- actorReply{data: data, err: err}
+	}
+}
@@ -14,4 +14,8 @@ const (
 	ContextKeyRole ContextKey = "playerRole"
 	// ContextKeySession 用于在 Context 中存储 Session ID
 	ContextKeySession ContextKey = "playerRoleSession"
+	// ContextKeyFuBen 用于在 DungeonActor 消息 Context 中存储处理该消息的副本实例
+	ContextKeyFuBen ContextKey = "dungeonFuBen"
+	// ContextKeyReply 用于在经 CallActor 投递的消息 Context 中存储应答通道
+	ContextKeyReply ContextKey = "actorReply"
 )
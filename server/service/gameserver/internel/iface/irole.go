@@ -34,6 +34,10 @@ type IPlayerRole interface {
 
 	GetJob() uint32                                  // 获取职业ID
 	GetPlayerSimpleData() *protocol.PlayerSimpleData // 获取角色信息
+	GetGMLevel() uint32                              // 获取 GM 等级（0 为普通玩家）
+	GetMuteUntil() int64                             // 获取禁言截止时间（秒），0 未禁言，-1 永久禁言
+	SetMuteUntil(until int64)                        // 设置禁言截止时间
+	IsMuted() bool                                   // 是否处于禁言期
 
 	GetSysMgr() ISystemMgr
 	GetSystem(sysId uint32) ISystem
@@ -45,6 +49,7 @@ type IPlayerRole interface {
 
 	SaveToDB() error
 	RunOne()
+	TimeSync()
 	OnNewHour(ctx context.Context)
 	OnNewDay(ctx context.Context)
 	OnNewWeek(ctx context.Context)
@@ -59,4 +64,6 @@ type IPlayerEvent interface {
 type IPlayerSiDataRepository interface {
 	GetLevelData() *protocol.SiLevelData
 	GetSkillData() *protocol.SiSkillData
+	GetBagData() *protocol.SiBagData
+	GetEquipData() *protocol.SiEquipData
 }
@@ -11,10 +11,16 @@ var (
 	ErrLevelDataNotFound = customerr.NewError("level data not found")
 	// ErrSkillDataNotFound 技能数据不存在
 	ErrSkillDataNotFound = customerr.NewError("skill data not found")
+	// ErrBagDataNotFound 背包数据不存在
+	ErrBagDataNotFound = customerr.NewError("bag data not found")
+	// ErrEquipDataNotFound 装备数据不存在
+	ErrEquipDataNotFound = customerr.NewError("equip data not found")
 )
 
 // PlayerRepository 玩家数据访问接口（Domain 层定义）
 type PlayerRepository interface {
 	GetLevelData(ctx context.Context) (*protocol.SiLevelData, error)
 	GetSkillData(ctx context.Context) (*protocol.SiSkillData, error)
+	GetBagData(ctx context.Context) (*protocol.SiBagData, error)
+	GetEquipData(ctx context.Context) (*protocol.SiEquipData, error)
 }
@@ -21,6 +21,9 @@ type IPlayerRoleManager interface {
 	// GetBySession 通过 SessionID 获取玩家角色（O(1) 查找）
 	GetBySession(sessionId string) IPlayerRole
 
+	// GetByReconnectKey 通过重连密钥获取玩家角色
+	GetByReconnectKey(reconnectKey string) IPlayerRole
+
 	// UpdateSession 更新角色的 SessionID 索引（用于重连等场景）
 	UpdateSession(roleId uint64, oldSessionId, newSessionId string)
 
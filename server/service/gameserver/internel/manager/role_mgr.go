@@ -107,6 +107,23 @@ func (m *PlayerRoleManager) UpdateSession(roleId uint64, oldSessionId, newSessio
 	}
 }
 
+// GetByReconnectKey 通过重连密钥获取玩家角色
+// 密钥在角色创建时生成且不再修改，可以跨 Actor 读取
+func (m *PlayerRoleManager) GetByReconnectKey(reconnectKey string) iface.IPlayerRole {
+	if reconnectKey == "" {
+		return nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, playerRole := range m.roleMgr {
+		if playerRole.GetReconnectKey() == reconnectKey {
+			return playerRole
+		}
+	}
+	return nil
+}
+
 // GetBySession 通过 SessionID 获取玩家角色（O(1) 查找）
 func (m *PlayerRoleManager) GetBySession(sessionId string) iface.IPlayerRole {
 	if sessionId == "" {
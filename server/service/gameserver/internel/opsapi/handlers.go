@@ -0,0 +1,309 @@
+package opsapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"postapocgame/server/internal/database"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/internal/servertime"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/gatewaylink"
+	"postapocgame/server/service/gameserver/internel/gshare"
+	"postapocgame/server/service/gameserver/internel/manager"
+	"strconv"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gorm.io/gorm"
+)
+
+// maxAnnouncementLen 公告内容长度上限（字符）
+const maxAnnouncementLen = 512
+
+// OnlineRole 在线角色
+type OnlineRole struct {
+	RoleId    uint64 `json:"role_id"`
+	RoleName  string `json:"role_name"`
+	Job       uint32 `json:"job"`
+	Level     uint32 `json:"level"`
+	GmLevel   uint32 `json:"gm_level"`
+	SessionId string `json:"session_id"`
+}
+
+// handleOnline 在线角色列表：简要信息在各角色 Actor 内取快照，查询期间下线的角色不计入
+func (s *Server) handleOnline(ctx context.Context, _ *http.Request, _ []byte) (interface{}, error) {
+	roles := manager.GetPlayerRoleManager().GetAll()
+	list := make([]*OnlineRole, 0, len(roles))
+	for _, role := range roles {
+		sessionId := role.GetSessionId()
+		data, err := gshare.CallActor(ctx, sessionId, uint16(protocol.PlayerActorMsgId_PAMOpsSummary), nil)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, customerr.Wrap(ctx.Err())
+			}
+			log.Debugf("[ops] read online role %d summary failed: %v", role.GetPlayerRoleId(), err)
+			continue
+		}
+		var simple protocol.PlayerSimpleData
+		if err := proto.Unmarshal(data, &simple); err != nil {
+			return nil, customerr.Wrap(err)
+		}
+		list = append(list, &OnlineRole{
+			RoleId:    simple.RoleId,
+			RoleName:  simple.RoleName,
+			Job:       simple.Job,
+			Level:     simple.Level,
+			GmLevel:   simple.GmLevel,
+			SessionId: sessionId,
+		})
+	}
+	return map[string]interface{}{"total": len(list), "list": list}, nil
+}
+
+type kickReq struct {
+	RoleId uint64 `json:"role_id"`
+	Msg    string `json:"msg"`
+}
+
+// handleKick 踢在线角色下线
+func (s *Server) handleKick(ctx context.Context, _ *http.Request, body []byte) (interface{}, error) {
+	var req kickReq
+	if err := decodeBody(body, &req); err != nil {
+		return nil, err
+	}
+	role := manager.GetPlayerRole(req.RoleId)
+	if role == nil {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Player_NotFound), "role %d is offline", req.RoleId)
+	}
+	if err := gatewaylink.KickSession(ctx, role.GetSessionId(), protocol.KickReason_KickByGm, req.Msg); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"role_id": req.RoleId}, nil
+}
+
+type muteReq struct {
+	RoleId  uint64 `json:"role_id"`
+	Seconds int64  `json:"seconds"` // 禁言时长，0 解除，负数永久
+}
+
+// handleMute 禁言 / 解除禁言，离线角色下次进入游戏时生效
+func (s *Server) handleMute(ctx context.Context, _ *http.Request, body []byte) (interface{}, error) {
+	var req muteReq
+	if err := decodeBody(body, &req); err != nil {
+		return nil, err
+	}
+	if _, err := getPlayer(req.RoleId); err != nil {
+		return nil, err
+	}
+	until := untilFromSeconds(req.Seconds)
+	if err := database.UpdatePlayerMuteUntil(uint(req.RoleId), until); err != nil {
+		return nil, customerr.Wrap(err)
+	}
+	if role := manager.GetPlayerRole(req.RoleId); role != nil {
+		data, err := proto.Marshal(&protocol.PAMOpsMuteReq{MuteUntil: until})
+		if err != nil {
+			return nil, customerr.Wrap(err)
+		}
+		if _, err := gshare.CallActor(ctx, role.GetSessionId(), uint16(protocol.PlayerActorMsgId_PAMOpsMute), data); err != nil {
+			// 已落库，角色下次进入游戏时生效
+			log.Warnf("[ops] update online mute failed: roleId=%d err=%v", req.RoleId, err)
+		}
+	}
+	return map[string]interface{}{"role_id": req.RoleId, "mute_until": until}, nil
+}
+
+type banReq struct {
+	AccountId uint64 `json:"account_id"` // 为 0 时按 RoleId 查找所属账号
+	RoleId    uint64 `json:"role_id"`
+	Seconds   int64  `json:"seconds"` // 封禁时长，0 解封，负数永久
+	Reason    string `json:"reason"`
+}
+
+// handleBan 封禁 / 解封账号，封禁时踢下线该账号的在线角色
+func (s *Server) handleBan(ctx context.Context, _ *http.Request, body []byte) (interface{}, error) {
+	var req banReq
+	if err := decodeBody(body, &req); err != nil {
+		return nil, err
+	}
+	accountId := req.AccountId
+	if accountId == 0 {
+		player, err := getPlayer(req.RoleId)
+		if err != nil {
+			return nil, err
+		}
+		accountId = uint64(player.AccountID)
+	}
+	if _, err := database.GetAccountByID(uint(accountId)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "account %d not found", accountId)
+		}
+		return nil, customerr.Wrap(err)
+	}
+	until := untilFromSeconds(req.Seconds)
+	if err := database.UpdateAccountBan(uint(accountId), until, req.Reason); err != nil {
+		return nil, customerr.Wrap(err)
+	}
+
+	kicked := make([]uint64, 0)
+	if until != 0 {
+		players, err := database.GetPlayersByAccountID(uint(accountId))
+		if err != nil {
+			return nil, customerr.Wrap(err)
+		}
+		for _, player := range players {
+			role := manager.GetPlayerRole(uint64(player.ID))
+			if role == nil {
+				continue
+			}
+			if err := gatewaylink.KickSession(ctx, role.GetSessionId(), protocol.KickReason_KickAccountBanned, req.Reason); err != nil {
+				log.Warnf("[ops] kick banned role failed: roleId=%d err=%v", player.ID, err)
+				continue
+			}
+			kicked = append(kicked, uint64(player.ID))
+		}
+	}
+	return map[string]interface{}{"account_id": accountId, "ban_until": until, "kicked": kicked}, nil
+}
+
+type mailReq struct {
+	RoleIds []uint64    `json:"role_ids"` // 为空表示全服
+	Title   string      `json:"title"`
+	Content string      `json:"content"`
+	Items   []*mailItem `json:"items"`
+}
+
+type mailItem struct {
+	ItemId uint32 `json:"item_id"`
+	Count  int64  `json:"count"`
+}
+
+// handleMail 发送系统邮件
+func (s *Server) handleMail(ctx context.Context, _ *http.Request, body []byte) (interface{}, error) {
+	var req mailReq
+	if err := decodeBody(body, &req); err != nil {
+		return nil, err
+	}
+	if req.Title == "" {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "mail title is empty")
+	}
+	mail := &SystemMail{RoleIds: req.RoleIds, Title: req.Title, Content: req.Content}
+	for _, item := range req.Items {
+		if item == nil || item.ItemId == 0 || item.Count <= 0 {
+			return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "invalid mail item")
+		}
+		mail.Items = append(mail.Items, &protocol.ItemAmount{ItemId: item.ItemId, Count: item.Count})
+	}
+	sender := getMailSender()
+	if sender == nil {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Ops_NotAvailable), "mail system not available")
+	}
+	if err := sender(ctx, mail); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"role_ids": req.RoleIds}, nil
+}
+
+type announceReq struct {
+	Content string `json:"content"`
+}
+
+// handleAnnounce 向全部在线角色广播公告
+func (s *Server) handleAnnounce(_ context.Context, _ *http.Request, body []byte) (interface{}, error) {
+	var req announceReq
+	if err := decodeBody(body, &req); err != nil {
+		return nil, err
+	}
+	if req.Content == "" || utf8.RuneCountInString(req.Content) > maxAnnouncementLen {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "announcement length must be in [1, %d]", maxAnnouncementLen)
+	}
+	msg := &protocol.S2CAnnouncementReq{Content: req.Content, SendTime: servertime.Now().Unix()}
+	sent := 0
+	for _, role := range manager.GetPlayerRoleManager().GetAll() {
+		if err := gatewaylink.SendToSessionProto(role.GetSessionId(), uint16(protocol.S2CProtocol_S2CAnnouncement), msg); err != nil {
+			log.Warnf("[ops] send announcement to role %d failed: %v", role.GetPlayerRoleId(), err)
+			continue
+		}
+		sent++
+	}
+	return map[string]interface{}{"sent": sent}, nil
+}
+
+// RoleData 角色数据，RoleData 为 PlayerRoleData 的 protojson 编码
+type RoleData struct {
+	RoleId    uint64          `json:"role_id"`
+	AccountId uint64          `json:"account_id"`
+	RoleName  string          `json:"role_name"`
+	GmLevel   uint32          `json:"gm_level"`
+	MuteUntil int64           `json:"mute_until"`
+	Online    bool            `json:"online"`
+	RoleData  json.RawMessage `json:"role_data"`
+}
+
+// handleRole 查看角色数据：在线角色在 Actor 内取快照，离线角色读库
+func (s *Server) handleRole(ctx context.Context, r *http.Request, _ []byte) (interface{}, error) {
+	roleId, err := strconv.ParseUint(r.URL.Query().Get("role_id"), 10, 64)
+	if err != nil {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "invalid role_id")
+	}
+	player, err := getPlayer(roleId)
+	if err != nil {
+		return nil, err
+	}
+	result := &RoleData{
+		RoleId:    roleId,
+		AccountId: uint64(player.AccountID),
+		RoleName:  player.RoleName,
+		GmLevel:   player.GmLevel,
+		MuteUntil: player.MuteUntil,
+	}
+
+	roleData := &protocol.PlayerRoleData{}
+	if role := manager.GetPlayerRole(roleId); role != nil {
+		data, err := gshare.CallActor(ctx, role.GetSessionId(), uint16(protocol.PlayerActorMsgId_PAMOpsSnapshot), nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := proto.Unmarshal(data, roleData); err != nil {
+			return nil, customerr.Wrap(err)
+		}
+		result.Online = true
+	} else {
+		if roleData.MainData, err = database.GetPlayerMainData(uint(roleId)); err != nil {
+			return nil, customerr.Wrap(err)
+		}
+		if roleData.BinaryData, err = database.GetPlayerBinaryData(uint(roleId)); err != nil {
+			return nil, customerr.Wrap(err)
+		}
+	}
+	if result.RoleData, err = protojson.Marshal(roleData); err != nil {
+		return nil, customerr.Wrap(err)
+	}
+	return result, nil
+}
+
+func getPlayer(roleId uint64) (*database.Player, error) {
+	player, err := database.GetPlayerByID(uint(roleId))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Player_NotFound), "role %d not found", roleId)
+		}
+		return nil, customerr.Wrap(err)
+	}
+	return player, nil
+}
+
+// untilFromSeconds 时长转截止时间：正数为当前时间加时长，0 为 0（解除），负数为 -1（永久）
+func untilFromSeconds(seconds int64) int64 {
+	switch {
+	case seconds > 0:
+		return servertime.Now().Unix() + seconds
+	case seconds < 0:
+		return -1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,36 @@
+package opsapi
+
+import (
+	"context"
+	"postapocgame/server/internal/protocol"
+	"sync"
+)
+
+// SystemMail 运维接口发送的系统邮件
+type SystemMail struct {
+	RoleIds []uint64 // 收件角色，为空表示全服
+	Title   string
+	Content string
+	Items   []*protocol.ItemAmount
+}
+
+// MailSender 系统邮件发送实现，由邮件系统在启动时注册
+type MailSender func(ctx context.Context, mail *SystemMail) error
+
+var (
+	mailSender   MailSender
+	mailSenderMu sync.RWMutex
+)
+
+// SetMailSender 注册系统邮件发送实现；未注册时发送邮件接口返回 Ops_NotAvailable
+func SetMailSender(sender MailSender) {
+	mailSenderMu.Lock()
+	defer mailSenderMu.Unlock()
+	mailSender = sender
+}
+
+func getMailSender() MailSender {
+	mailSenderMu.RLock()
+	defer mailSenderMu.RUnlock()
+	return mailSender
+}
@@ -0,0 +1,41 @@
+package opsapi
+
+import (
+	"sync"
+	"time"
+)
+
+// nonceCache 记录 ttl 内出现过的 nonce，拦截时间窗口内的签名重放
+// ttl 不小于时间戳允许偏差的两倍，过期的 nonce 对应的时间戳已无法通过校验
+type nonceCache struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	seen      map[string]time.Time // nonce -> 过期时间
+	nextSweep time.Time
+}
+
+func newNonceCache(ttl time.Duration) *nonceCache {
+	return &nonceCache{
+		ttl:  ttl,
+		seen: make(map[string]time.Time),
+	}
+}
+
+// add 登记 nonce，ttl 内已出现过时返回 false
+func (c *nonceCache) add(nonce string, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if now.After(c.nextSweep) {
+		for n, expireAt := range c.seen {
+			if now.After(expireAt) {
+				delete(c.seen, n)
+			}
+		}
+		c.nextSweep = now.Add(c.ttl)
+	}
+	if expireAt, ok := c.seen[nonce]; ok && !now.After(expireAt) {
+		return false
+	}
+	c.seen[nonce] = now.Add(c.ttl)
+	return true
+}
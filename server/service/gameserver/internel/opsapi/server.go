@@ -0,0 +1,215 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/12
+ * @Desc: 运维接口：供 admin-server 调用的内网 HTTP JSON 服务，IP 白名单 + HMAC 签名鉴权
+**/
+
+package opsapi
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/pkg/routine"
+	"strconv"
+	"time"
+)
+
+const (
+	// HeaderTimestamp 请求时间戳（秒）
+	HeaderTimestamp = "X-Ops-Timestamp"
+	// HeaderNonce 请求随机串，参与签名，2×signWindow 内不可重复使用
+	HeaderNonce = "X-Ops-Nonce"
+	// HeaderSignature 请求签名，见 Sign
+	HeaderSignature = "X-Ops-Signature"
+
+	// signWindow 时间戳允许的偏差，超出视为重放；窗口内的重放由 nonce 拦截
+	signWindow = 60 * time.Second
+	// maxNonceLen nonce 长度上限
+	maxNonceLen = 64
+	// maxBodySize 请求体上限
+	maxBodySize = 1 << 20
+	// callTimeout 单个请求内调用 Actor / Gateway 的超时
+	callTimeout = 3 * time.Second
+)
+
+// Config 运维接口配置
+type Config struct {
+	Addr     string   // 监听地址
+	Secret   string   // 签名密钥，与 admin-server 的 GameOps.Secret 一致
+	AllowIPs []string // 允许访问的 IP（为空则不限制）
+}
+
+// Response 统一响应，Code 为 ErrorCode，0 表示成功
+type Response struct {
+	Code int32       `json:"code"`
+	Msg  string      `json:"msg"`
+	Data interface{} `json:"data,omitempty"`
+}
+
+// handlerFunc 接口处理函数，返回值作为 Response.Data
+type handlerFunc func(ctx context.Context, r *http.Request, body []byte) (interface{}, error)
+
+// Server 运维接口服务
+type Server struct {
+	config     *Config
+	mux        *http.ServeMux
+	httpServer *http.Server
+	now        func() time.Time // 校验签名时间戳用真实时间，不受 GM settime 影响
+	nonces     *nonceCache
+}
+
+// NewServer 创建运维接口服务
+func NewServer(config *Config) *Server {
+	s := &Server{
+		config: config,
+		mux:    http.NewServeMux(),
+		now:    time.Now,
+		nonces: newNonceCache(2 * signWindow),
+	}
+	s.handle(http.MethodGet, "/ops/online", s.handleOnline)
+	s.handle(http.MethodPost, "/ops/kick", s.handleKick)
+	s.handle(http.MethodPost, "/ops/mute", s.handleMute)
+	s.handle(http.MethodPost, "/ops/ban", s.handleBan)
+	s.handle(http.MethodPost, "/ops/mail", s.handleMail)
+	s.handle(http.MethodPost, "/ops/announce", s.handleAnnounce)
+	s.handle(http.MethodGet, "/ops/role", s.handleRole)
+	return s
+}
+
+// Start 监听端口并在后台处理请求
+func (s *Server) Start(_ context.Context) error {
+	ln, err := net.Listen("tcp", s.config.Addr)
+	if err != nil {
+		return customerr.Wrap(err)
+	}
+	s.httpServer = &http.Server{
+		Handler:      s.mux,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+	routine.GoV2(func() error {
+		if err := s.httpServer.Serve(ln); err != nil && err != http.ErrServerClosed {
+			log.Errorf("ops api server error: %v", err)
+			return err
+		}
+		return nil
+	})
+	log.Infof("ops api server started on %s", s.config.Addr)
+	return nil
+}
+
+// Stop 停止服务，等待处理中的请求结束
+func (s *Server) Stop(ctx context.Context) error {
+	if s.httpServer == nil {
+		return nil
+	}
+	return s.httpServer.Shutdown(ctx)
+}
+
+// Sign 计算请求签名：hex(HMAC-SHA256(secret, timestamp\nnonce\nmethod\nrequestURI\nbody))
+// requestURI 为路径加查询串，如 /ops/role?role_id=1
+func Sign(secret, timestamp, nonce, method, requestURI string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + nonce + "\n" + method + "\n" + requestURI + "\n"))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *Server) handle(method, path string, h handlerFunc) {
+	s.mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			writeJSON(w, http.StatusMethodNotAllowed, &Response{Code: int32(protocol.ErrorCode_Param_Invalid), Msg: "method not allowed"})
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, &Response{Code: int32(protocol.ErrorCode_Param_Invalid), Msg: "read body failed"})
+			return
+		}
+		if err := s.authorize(r, body); err != nil {
+			log.Warnf("[ops] reject %s %s from %s: %v", r.Method, r.URL.Path, r.RemoteAddr, err)
+			writeJSON(w, http.StatusUnauthorized, &Response{Code: customerr.GetErrCode(err), Msg: customerr.GetErrMsgByErr(err)})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), callTimeout)
+		defer cancel()
+		data, err := h(ctx, r, body)
+		if err != nil {
+			log.Warnf("[ops] %s %s failed: %v", r.Method, r.URL.RequestURI(), err)
+			writeJSON(w, http.StatusOK, &Response{Code: customerr.GetErrCode(err), Msg: customerr.GetErrMsgByErr(err)})
+			return
+		}
+		log.Infof("[ops] %s %s from %s ok", r.Method, r.URL.RequestURI(), r.RemoteAddr)
+		writeJSON(w, http.StatusOK, &Response{Msg: "success", Data: data})
+	})
+}
+
+// authorize 校验来源 IP、时间戳、签名与 nonce
+func (s *Server) authorize(r *http.Request, body []byte) error {
+	if !s.isIPAllowed(r.RemoteAddr) {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Ops_Unauthorized), "ip not allowed")
+	}
+	timestamp := r.Header.Get(HeaderTimestamp)
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Ops_Unauthorized), "invalid timestamp")
+	}
+	if d := s.now().Sub(time.Unix(ts, 0)); d > signWindow || d < -signWindow {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Ops_Unauthorized), "timestamp out of window")
+	}
+	nonce := r.Header.Get(HeaderNonce)
+	if nonce == "" || len(nonce) > maxNonceLen {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Ops_Unauthorized), "invalid nonce")
+	}
+	want := Sign(s.config.Secret, timestamp, nonce, r.Method, r.URL.RequestURI(), body)
+	if !hmac.Equal([]byte(want), []byte(r.Header.Get(HeaderSignature))) {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Ops_Unauthorized), "signature mismatch")
+	}
+	// 签名通过后才登记 nonce，未签名的请求无法占用缓存
+	if !s.nonces.add(nonce, s.now()) {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Ops_Unauthorized), "nonce reused")
+	}
+	return nil
+}
+
+func (s *Server) isIPAllowed(remoteAddr string) bool {
+	if len(s.config.AllowIPs) == 0 {
+		return true
+	}
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range s.config.AllowIPs {
+		if host == allowed || allowed == "0.0.0.0" {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, resp *Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Warnf("[ops] write response failed: %v", err)
+	}
+}
+
+// decodeBody 解析 JSON 请求体
+func decodeBody(body []byte, v interface{}) error {
+	if err := json.Unmarshal(body, v); err != nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "invalid json body: %v", err)
+	}
+	return nil
+}
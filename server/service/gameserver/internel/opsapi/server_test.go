@@ -0,0 +1,155 @@
+package opsapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"postapocgame/server/internal/protocol"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testSecret = "ops-secret"
+
+func newTestServer() *Server {
+	s := NewServer(&Config{Secret: testSecret, AllowIPs: []string{"10.0.0.1"}})
+	s.now = func() time.Time { return time.Unix(1_700_000_000, 0) }
+	return s
+}
+
+func doRequest(t *testing.T, s *Server, method, uri string, body []byte, remote string, sign func(r *http.Request)) (int, *Response) {
+	t.Helper()
+	r := httptest.NewRequest(method, uri, bytes.NewReader(body))
+	r.RemoteAddr = remote
+	sign(r)
+	w := httptest.NewRecorder()
+	s.mux.ServeHTTP(w, r)
+	var resp Response
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response %q: %v", w.Body.String(), err)
+	}
+	return w.Code, &resp
+}
+
+var testNonce atomic.Int64
+
+// signWith 每次签名使用新的 nonce
+func signWith(secret string, ts int64, body []byte) func(r *http.Request) {
+	return signWithNonce(secret, ts, strconv.FormatInt(testNonce.Add(1), 10), body)
+}
+
+func signWithNonce(secret string, ts int64, nonce string, body []byte) func(r *http.Request) {
+	return func(r *http.Request) {
+		timestamp := strconv.FormatInt(ts, 10)
+		r.Header.Set(HeaderTimestamp, timestamp)
+		r.Header.Set(HeaderNonce, nonce)
+		r.Header.Set(HeaderSignature, Sign(secret, timestamp, nonce, r.Method, r.URL.RequestURI(), body))
+	}
+}
+
+func TestAuthorizeRejectsBadRequests(t *testing.T) {
+	s := newTestServer()
+	now := s.now().Unix()
+	body := []byte(`{"content":"hi"}`)
+
+	cases := []struct {
+		name   string
+		remote string
+		sign   func(r *http.Request)
+	}{
+		{"ip not allowed", "10.0.0.2:1234", signWith(testSecret, now, body)},
+		{"wrong secret", "10.0.0.1:1234", signWith("other", now, body)},
+		{"stale timestamp", "10.0.0.1:1234", signWith(testSecret, now-int64(2*signWindow/time.Second), body)},
+		{"body tampered", "10.0.0.1:1234", signWith(testSecret, now, []byte(`{"content":"bye"}`))},
+		{"unsigned", "10.0.0.1:1234", func(*http.Request) {}},
+	}
+	for _, c := range cases {
+		status, resp := doRequest(t, s, http.MethodPost, "/ops/announce", body, c.remote, c.sign)
+		if status != http.StatusUnauthorized || resp.Code != int32(protocol.ErrorCode_Ops_Unauthorized) {
+			t.Fatalf("%s: got status %d code %d", c.name, status, resp.Code)
+		}
+	}
+}
+
+func TestReplayedRequestRejected(t *testing.T) {
+	s := newTestServer()
+	start := s.now()
+	body := []byte(`{"content":""}`)
+	sign := signWithNonce(testSecret, start.Unix(), "n-1", body)
+
+	// 参数校验失败说明已通过鉴权
+	if _, resp := doRequest(t, s, http.MethodPost, "/ops/announce", body, "10.0.0.1:1234", sign); resp.Code != int32(protocol.ErrorCode_Param_Invalid) {
+		t.Fatalf("first request got code %d", resp.Code)
+	}
+	// 时间窗口内原样重放
+	s.now = func() time.Time { return start.Add(signWindow / 2) }
+	if status, resp := doRequest(t, s, http.MethodPost, "/ops/announce", body, "10.0.0.1:1234", sign); status != http.StatusUnauthorized || resp.Code != int32(protocol.ErrorCode_Ops_Unauthorized) {
+		t.Fatalf("replay got status %d code %d", status, resp.Code)
+	}
+	// 缺少 nonce
+	noNonce := func(r *http.Request) {
+		sign(r)
+		r.Header.Del(HeaderNonce)
+	}
+	if status, _ := doRequest(t, s, http.MethodPost, "/ops/announce", body, "10.0.0.1:1234", noNonce); status != http.StatusUnauthorized {
+		t.Fatalf("request without nonce got status %d", status)
+	}
+	// nonce 过期后旧时间戳已超出窗口，新时间戳可复用 nonce
+	later := start.Add(3 * signWindow)
+	s.now = func() time.Time { return later }
+	if _, resp := doRequest(t, s, http.MethodPost, "/ops/announce", body, "10.0.0.1:1234", signWithNonce(testSecret, later.Unix(), "n-1", body)); resp.Code != int32(protocol.ErrorCode_Param_Invalid) {
+		t.Fatalf("expired nonce reuse got code %d", resp.Code)
+	}
+}
+
+func TestSignedRequestReachesHandler(t *testing.T) {
+	s := newTestServer()
+	now := s.now().Unix()
+
+	// 参数校验失败说明已通过鉴权
+	body := []byte(`{"content":""}`)
+	status, resp := doRequest(t, s, http.MethodPost, "/ops/announce", body, "10.0.0.1:1234", signWith(testSecret, now, body))
+	if status != http.StatusOK || resp.Code != int32(protocol.ErrorCode_Param_Invalid) {
+		t.Fatalf("announce got status %d code %d", status, resp.Code)
+	}
+
+	// 查询串参与签名
+	status, resp = doRequest(t, s, http.MethodGet, "/ops/role?role_id=x", nil, "10.0.0.1:1234", signWith(testSecret, now, nil))
+	if status != http.StatusOK || resp.Code != int32(protocol.ErrorCode_Param_Invalid) {
+		t.Fatalf("role got status %d code %d", status, resp.Code)
+	}
+
+	status, _ = doRequest(t, s, http.MethodGet, "/ops/announce", nil, "10.0.0.1:1234", signWith(testSecret, now, nil))
+	if status != http.StatusMethodNotAllowed {
+		t.Fatalf("wrong method got status %d", status)
+	}
+}
+
+func TestMailDelegatesToRegisteredSender(t *testing.T) {
+	s := newTestServer()
+	now := s.now().Unix()
+	t.Cleanup(func() { SetMailSender(nil) })
+
+	body := []byte(`{"role_ids":[7],"title":"补偿","items":[{"item_id":1001,"count":2}]}`)
+	_, resp := doRequest(t, s, http.MethodPost, "/ops/mail", body, "10.0.0.1:1234", signWith(testSecret, now, body))
+	if resp.Code != int32(protocol.ErrorCode_Ops_NotAvailable) {
+		t.Fatalf("mail without sender got code %d", resp.Code)
+	}
+
+	var got *SystemMail
+	SetMailSender(func(_ context.Context, mail *SystemMail) error {
+		got = mail
+		return nil
+	})
+	_, resp = doRequest(t, s, http.MethodPost, "/ops/mail", body, "10.0.0.1:1234", signWith(testSecret, now, body))
+	if resp.Code != 0 || got == nil {
+		t.Fatalf("mail got code %d, sent %v", resp.Code, got)
+	}
+	if got.Title != "补偿" || len(got.RoleIds) != 1 || got.RoleIds[0] != 7 || len(got.Items) != 1 || got.Items[0].Count != 2 {
+		t.Fatalf("unexpected mail %+v", got)
+	}
+}
@@ -3,6 +3,7 @@ package playeractor
 import (
 	"context"
 	"postapocgame/server/internal/actor"
+	"postapocgame/server/internal/protocol"
 	"postapocgame/server/pkg/customerr"
 	"postapocgame/server/service/gameserver/internel/gshare"
 )
@@ -21,7 +22,18 @@ func NewPlayerRoleActor(mode actor.ActorMode) *PlayerRoleActor {
 		mode:          mode,
 		playerHandler: defaultHandler,
 	}
-	p.actorMgr = actor.NewActorManager(mode, 1000, p.NewPlayerHandlerFactory)
+	// 邮箱满时拒绝并把错误返回给发送方；登出与 DungeonActor 回传的结算消息走高优先级通道
+	p.actorMgr = actor.NewActorManager(mode, 1000, p.NewPlayerHandlerFactory,
+		actor.WithOverflowPolicy(actor.OverflowReject, 0),
+		actor.WithPriorityMsgIds(
+			uint16(protocol.PlayerActorMsgId_PAMLogout),
+			uint16(protocol.PlayerActorMsgId_PAMAddExp),
+			uint16(protocol.PlayerActorMsgId_PAMPickUp),
+			uint16(protocol.PlayerActorMsgId_PAMAddBuffResult),
+			uint16(protocol.PlayerActorMsgId_PAMReEnterDungeon),
+			uint16(protocol.PlayerActorMsgId_PAMResumeSession),
+		),
+	)
 
 	// 🔧 使用接口方式注册
 	gshare.SetActorFacade(p)
@@ -57,6 +69,11 @@ func (p *PlayerRoleActor) Start(ctx context.Context) error {
 	return p.actorMgr.Start(ctx)
 }
 
+// GetAllMetrics 获取所有玩家Actor的运行指标
+func (p *PlayerRoleActor) GetAllMetrics() []actor.ActorMetrics {
+	return p.actorMgr.GetAllMetrics()
+}
+
 // Stop 停止Actor系统
 func (p *PlayerRoleActor) Stop(ctx context.Context) error {
 	return p.actorMgr.Stop(ctx)
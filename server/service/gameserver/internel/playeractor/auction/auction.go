@@ -0,0 +1,278 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/12
+ * @Desc: 拍卖行：一口价上架、搜索、购买与下架；道具与金币均托管在数据库中，背包变更与上架记录同事务落库
+**/
+
+package auction
+
+import (
+	"context"
+	"errors"
+	"postapocgame/server/internal/database"
+	"postapocgame/server/internal/jsonconf"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/internal/servertime"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/iface"
+	"postapocgame/server/service/gameserver/internel/playeractor/bag"
+
+	"gorm.io/gorm"
+)
+
+// Sell 上架背包格子中的道具：扣除道具与上架手续费，并与上架记录同事务落库
+func Sell(ctx context.Context, playerRole iface.IPlayerRole, req *protocol.C2SAuctionSellReq) (*protocol.AuctionListingSt, error) {
+	cfg, err := getConfig()
+	if err != nil {
+		return nil, err
+	}
+	bagSys, err := getBagSys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	item := bagSys.GetSlotItem(ctx, req.Slot)
+	if item == nil {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Item_NotFound), "slot %d is empty", req.Slot)
+	}
+	count := req.Count
+	if count == 0 {
+		count = item.Count
+	}
+	if count > item.Count {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Item_NotEnough), "item not enough, slot=%d", req.Slot)
+	}
+	itemCfg := jsonconf.GetConfigManager().GetItemConfig(item.ItemId)
+	if itemCfg == nil {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Item_NotFound), "item config not found: %d", item.ItemId)
+	}
+	if !canSell(item, itemCfg) {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Auction_ItemBound), "item %d cannot be sold", item.ItemId)
+	}
+	if req.Price < cfg.MinPrice || req.Price > cfg.MaxPrice {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Auction_PriceInvalid), "price must be in [%d, %d]", cfg.MinPrice, cfg.MaxPrice)
+	}
+	hours, ok := cfg.ResolveDuration(req.DurationHours)
+	if !ok {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "invalid duration %d", req.DurationHours)
+	}
+
+	roleId := playerRole.GetPlayerRoleId()
+	if cfg.MaxListings > 0 {
+		active, err := database.CountActiveAuctionListingsBySeller(roleId)
+		if err != nil {
+			return nil, customerr.Wrap(err)
+		}
+		if active >= int64(cfg.MaxListings) {
+			return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Auction_ListingLimit), "listing limit %d reached", cfg.MaxListings)
+		}
+	}
+	fee := cfg.CalcFee(req.Price)
+	if bagSys.GetItemCount(ctx, cfg.CurrencyItemId) < uint64(fee) {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Gold_NotEnough), "gold not enough for fee %d", fee)
+	}
+
+	now := servertime.Now().Unix()
+	listing := &database.AuctionListing{
+		SellerId:   roleId,
+		SellerName: playerRole.GetPlayerSimpleData().GetRoleName(),
+		ItemId:     item.ItemId,
+		ItemType:   itemCfg.Type,
+		Count:      count,
+		Price:      req.Price,
+		UnitPrice:  req.Price / int64(count),
+		Fee:        fee,
+		Status:     database.AuctionStatusActive,
+		ExpireAt:   now + int64(hours)*3600,
+	}
+	change := &bag.ItemChange{TakeSlots: []*bag.SlotAmount{{Slot: req.Slot, Count: count}}}
+	if fee > 0 {
+		change.Remove = []*protocol.ItemAmount{{ItemId: cfg.CurrencyItemId, Count: fee}}
+	}
+	err = bagSys.ApplyWithPersist(ctx, change, func(binaryData *protocol.PlayerRoleBinaryData) error {
+		return customerr.Wrap(database.CreateAuctionListingWithBinaryData(listing, binaryData))
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("[auction] sell: listing=%d roleId=%d item=%d count=%d price=%d fee=%d", listing.ID, roleId, listing.ItemId, count, req.Price, fee)
+	return toListingSt(listing), nil
+}
+
+// Buy 一口价购买：扣除金币、放入道具并与售出标记同事务落库，卖家所得由拍卖行通过邮件发放
+func Buy(ctx context.Context, playerRole iface.IPlayerRole, listingId uint64) (*protocol.AuctionListingSt, error) {
+	cfg, err := getConfig()
+	if err != nil {
+		return nil, err
+	}
+	bagSys, err := getBagSys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	listing, err := getActiveListing(listingId)
+	if err != nil {
+		return nil, err
+	}
+	buyerId := playerRole.GetPlayerRoleId()
+	if listing.SellerId == buyerId {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Auction_OwnListing), "cannot buy own listing %d", listingId)
+	}
+	if bagSys.GetItemCount(ctx, cfg.CurrencyItemId) < uint64(listing.Price) {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Gold_NotEnough), "gold not enough, need %d", listing.Price)
+	}
+
+	now := servertime.Now().Unix()
+	change := &bag.ItemChange{
+		Remove: []*protocol.ItemAmount{{ItemId: cfg.CurrencyItemId, Count: listing.Price}},
+		Add:    []*protocol.ItemAmount{{ItemId: listing.ItemId, Count: int64(listing.Count)}},
+	}
+	err = bagSys.ApplyWithPersist(ctx, change, func(binaryData *protocol.PlayerRoleBinaryData) error {
+		sold, err := database.BuyAuctionListingWithBinaryData(listing.ID, buyerId, now, binaryData)
+		if err != nil {
+			return customerr.Wrap(err)
+		}
+		if !sold {
+			return customerr.NewErrorByCode(int32(protocol.ErrorCode_Auction_NotFound), "listing %d is no longer on sale", listingId)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	listing.Status = database.AuctionStatusSold
+	listing.BuyerId = buyerId
+	listing.SoldAt = now
+	log.Infof("[auction] buy: listing=%d buyer=%d seller=%d item=%d count=%d price=%d", listing.ID, buyerId, listing.SellerId, listing.ItemId, listing.Count, listing.Price)
+
+	auditSettlement(listing, cfg.Suspicious)
+	wakeSweeper()
+	return toListingSt(listing), nil
+}
+
+// Cancel 卖家下架在售道具，道具由拍卖行通过邮件退回，上架手续费不退还
+func Cancel(playerRole iface.IPlayerRole, listingId uint64) error {
+	if _, err := getConfig(); err != nil {
+		return err
+	}
+	ok, err := database.CancelAuctionListing(uint(listingId), playerRole.GetPlayerRoleId())
+	if err != nil {
+		return customerr.Wrap(err)
+	}
+	if !ok {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Auction_NotFound), "listing %d is not on sale", listingId)
+	}
+	log.Infof("[auction] cancel: listing=%d roleId=%d", listingId, playerRole.GetPlayerRoleId())
+	wakeSweeper()
+	return nil
+}
+
+// Search 分页搜索在售道具
+func Search(req *protocol.C2SAuctionSearchReq) (*protocol.S2CAuctionSearchReq, error) {
+	cfg, err := getConfig()
+	if err != nil {
+		return nil, err
+	}
+	page := max(req.Page, 1)
+	pageSize := req.PageSize
+	if pageSize == 0 || pageSize > cfg.MaxPageSize {
+		pageSize = cfg.MaxPageSize
+	}
+	filter := &database.AuctionFilter{
+		ItemType: req.ItemType,
+		ItemId:   req.ItemId,
+		MinPrice: req.MinPrice,
+		MaxPrice: req.MaxPrice,
+	}
+	switch req.Sort {
+	case protocol.AuctionSort_AuctionSortPriceAsc:
+		filter.OrderBy = "unit_price ASC"
+	case protocol.AuctionSort_AuctionSortPriceDesc:
+		filter.OrderBy = "unit_price DESC"
+	}
+	listings, total, err := database.SearchAuctionListings(filter, servertime.Now().Unix(), int((page-1)*pageSize), int(pageSize))
+	if err != nil {
+		return nil, customerr.Wrap(err)
+	}
+	resp := &protocol.S2CAuctionSearchReq{
+		Listings: make([]*protocol.AuctionListingSt, 0, len(listings)),
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}
+	for _, listing := range listings {
+		resp.Listings = append(resp.Listings, toListingSt(listing))
+	}
+	return resp, nil
+}
+
+// Mine 查询自己在售的道具
+func Mine(playerRole iface.IPlayerRole) ([]*protocol.AuctionListingSt, error) {
+	if _, err := getConfig(); err != nil {
+		return nil, err
+	}
+	listings, err := database.GetActiveAuctionListingsBySeller(playerRole.GetPlayerRoleId())
+	if err != nil {
+		return nil, customerr.Wrap(err)
+	}
+	result := make([]*protocol.AuctionListingSt, 0, len(listings))
+	for _, listing := range listings {
+		result = append(result, toListingSt(listing))
+	}
+	return result, nil
+}
+
+// canSell 绑定道具、任务道具与货币不能上架
+func canSell(item *protocol.BagItemSt, itemCfg *jsonconf.ItemConfig) bool {
+	if item.Bind == 1 || itemCfg.Bind == 1 {
+		return false
+	}
+	switch protocol.ItemType(itemCfg.Type) {
+	case protocol.ItemType_ItemTypeQuest, protocol.ItemType_ItemTypeCurrency:
+		return false
+	}
+	return true
+}
+
+func getActiveListing(listingId uint64) (*database.AuctionListing, error) {
+	listing, err := database.GetAuctionListing(uint(listingId))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Auction_NotFound), "listing %d not found", listingId)
+		}
+		return nil, customerr.Wrap(err)
+	}
+	if listing.Status != database.AuctionStatusActive || listing.ExpireAt <= servertime.Now().Unix() {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Auction_NotFound), "listing %d is not on sale", listingId)
+	}
+	return listing, nil
+}
+
+func getConfig() (*jsonconf.AuctionConfig, error) {
+	cfg := jsonconf.GetConfigManager().GetAuctionConfig()
+	if cfg == nil {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Auction_Closed), "auction house not configured")
+	}
+	return cfg, nil
+}
+
+func getBagSys(ctx context.Context) (*bag.SystemAdapter, error) {
+	bagSys := bag.GetBagSys(ctx)
+	if bagSys == nil {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_System_NotEnabled), "bag system not opened")
+	}
+	return bagSys, nil
+}
+
+func toListingSt(listing *database.AuctionListing) *protocol.AuctionListingSt {
+	return &protocol.AuctionListingSt{
+		ListingId:  uint64(listing.ID),
+		SellerId:   listing.SellerId,
+		SellerName: listing.SellerName,
+		ItemId:     listing.ItemId,
+		ItemType:   listing.ItemType,
+		Count:      listing.Count,
+		Price:      listing.Price,
+		ExpireAt:   listing.ExpireAt,
+		Status:     protocol.AuctionStatus(listing.Status),
+	}
+}
@@ -0,0 +1,83 @@
+package auction
+
+import (
+	"fmt"
+	"postapocgame/server/internal/database"
+	"postapocgame/server/internal/jsonconf"
+	"postapocgame/server/pkg/log"
+	"sort"
+	"strings"
+)
+
+// auditSettlement 成交后写交易审计表，价格异常或买卖双方同账号时标记为可疑
+func auditSettlement(listing *database.AuctionListing, cfg *jsonconf.AuctionSuspiciousConfig) {
+	var reasons []string
+	if cfg != nil && cfg.SampleSize > 0 {
+		refs, err := database.GetRecentTransactionAuditsByItem(database.TransactionTypeAuctionBuy, listing.ItemId, int(cfg.SampleSize))
+		if err != nil {
+			log.Warnf("[auction] load reference prices failed: item=%d err=%v", listing.ItemId, err)
+		}
+		unitPrices := make([]int64, 0, len(refs))
+		for _, ref := range refs {
+			if ref.Count > 0 {
+				unitPrices = append(unitPrices, ref.Price/int64(ref.Count))
+			}
+		}
+		if reason := checkPrice(listing.UnitPrice, unitPrices, cfg); reason != "" {
+			reasons = append(reasons, reason)
+		}
+	}
+	if sameAccount(listing.SellerId, listing.BuyerId) {
+		reasons = append(reasons, "买卖双方为同一账号")
+	}
+
+	status := database.TransactionStatusSuccess
+	if len(reasons) > 0 {
+		status = database.TransactionStatusSuspicious
+		log.Warnf("[auction] suspicious settlement: listing=%d buyer=%d seller=%d reasons=%v", listing.ID, listing.BuyerId, listing.SellerId, reasons)
+	}
+	reason := fmt.Sprintf("listing=%d unit_price=%d", listing.ID, listing.UnitPrice)
+	if len(reasons) > 0 {
+		reason += "; " + strings.Join(reasons, "; ")
+	}
+	if err := database.SaveTransactionAudit(database.TransactionTypeAuctionBuy, listing.BuyerId, listing.SellerId,
+		listing.ItemId, listing.Count, listing.Price, status, reason); err != nil {
+		log.Errorf("[auction] save transaction audit failed: listing=%d err=%v", listing.ID, err)
+	}
+}
+
+// checkPrice 成交单价与近期成交单价中位数比较，偏离超过配置比例时返回原因；参考记录不足时不判定
+func checkPrice(unitPrice int64, refUnitPrices []int64, cfg *jsonconf.AuctionSuspiciousConfig) string {
+	if len(refUnitPrices) == 0 || len(refUnitPrices) < int(cfg.MinSamples) {
+		return ""
+	}
+	sorted := append([]int64(nil), refUnitPrices...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	median := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		median = (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+	}
+	if median <= 0 {
+		return ""
+	}
+	if cfg.HighRate > 0 && unitPrice*10000 > median*int64(cfg.HighRate) {
+		return fmt.Sprintf("单价 %d 远高于近期中位数 %d", unitPrice, median)
+	}
+	if cfg.LowRate > 0 && unitPrice*10000 < median*int64(cfg.LowRate) {
+		return fmt.Sprintf("单价 %d 远低于近期中位数 %d", unitPrice, median)
+	}
+	return ""
+}
+
+// sameAccount 买卖双方是否属于同一账号（小号互转金币）
+func sameAccount(sellerId, buyerId uint64) bool {
+	seller, err := database.GetPlayerByID(uint(sellerId))
+	if err != nil {
+		return false
+	}
+	buyer, err := database.GetPlayerByID(uint(buyerId))
+	if err != nil {
+		return false
+	}
+	return seller.AccountID == buyer.AccountID
+}
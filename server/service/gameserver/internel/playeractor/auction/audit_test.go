@@ -0,0 +1,49 @@
+package auction
+
+import (
+	"postapocgame/server/internal/database"
+	"postapocgame/server/internal/jsonconf"
+	"testing"
+)
+
+func TestCheckPrice(t *testing.T) {
+	cfg := &jsonconf.AuctionSuspiciousConfig{SampleSize: 10, MinSamples: 3, HighRate: 50000, LowRate: 2000}
+	refs := []int64{90, 100, 110, 100}
+
+	cases := []struct {
+		name       string
+		unitPrice  int64
+		refs       []int64
+		suspicious bool
+	}{
+		{"normal", 120, refs, false},
+		{"five times median is allowed", 500, refs, false},
+		{"far above median", 501, refs, true},
+		{"far below median", 19, refs, true},
+		{"too few samples", 10000, refs[:2], false},
+		{"no samples", 10000, nil, false},
+	}
+	for _, c := range cases {
+		got := checkPrice(c.unitPrice, c.refs, cfg) != ""
+		if got != c.suspicious {
+			t.Fatalf("%s: got suspicious=%v", c.name, got)
+		}
+	}
+}
+
+func TestBuildDelivery(t *testing.T) {
+	cfg := &jsonconf.AuctionConfig{CurrencyItemId: 1, TaxRate: 500}
+	listing := &database.AuctionListing{SellerId: 7, ItemId: 2001, Count: 3, Price: 1000}
+
+	listing.Status = database.AuctionStatusSold
+	d := buildDelivery(listing, cfg)
+	if d.RoleId != 7 || len(d.Items) != 1 || d.Items[0].ItemId != 1 || d.Items[0].Count != 950 {
+		t.Fatalf("sold delivery %+v", d)
+	}
+
+	listing.Status = database.AuctionStatusExpired
+	d = buildDelivery(listing, cfg)
+	if len(d.Items) != 1 || d.Items[0].ItemId != 2001 || d.Items[0].Count != 3 {
+		t.Fatalf("expired delivery %+v", d)
+	}
+}
@@ -0,0 +1,174 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"postapocgame/server/internal/database"
+	"postapocgame/server/internal/event"
+	"postapocgame/server/internal/jsonconf"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/internal/servertime"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/pkg/routine"
+	"postapocgame/server/service/gameserver/internel/gevent"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// sweepInterval 过期检查与补发的间隔
+	sweepInterval = 10 * time.Second
+	// deliverBatchSize 每批发放的记录数
+	deliverBatchSize = 100
+)
+
+// Delivery 拍卖行发放给卖家的邮件：售出所得或过期/下架退回的道具
+type Delivery struct {
+	RoleId  uint64
+	Title   string
+	Content string
+	Items   []*protocol.ItemAmount
+}
+
+// Deliverer 邮件发放实现，由邮件系统在启动时注册
+type Deliverer interface {
+	// DeliverTx 在 tx 内写入邮件，与上架记录的发放标记同事务提交
+	DeliverTx(tx *gorm.DB, delivery *Delivery) error
+	// OnDelivered 事务提交后调用，用于通知在线收件人
+	OnDelivered(delivery *Delivery)
+}
+
+var (
+	deliverer   Deliverer
+	delivererMu sync.RWMutex
+
+	wakeCh = make(chan struct{}, 1)
+	stopCh chan struct{}
+)
+
+// SetDeliverer 注册邮件发放实现；未注册时已结束的上架记录保留在库中，注册后由定时任务补发
+func SetDeliverer(d Deliverer) {
+	delivererMu.Lock()
+	defer delivererMu.Unlock()
+	deliverer = d
+}
+
+func getDeliverer() Deliverer {
+	delivererMu.RLock()
+	defer delivererMu.RUnlock()
+	return deliverer
+}
+
+// wakeSweeper 售出或下架后立即触发一次发放
+func wakeSweeper() {
+	select {
+	case wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+func startSweeper() {
+	stopCh = make(chan struct{})
+	stop := stopCh
+	routine.GoV2(func() error {
+		ticker := time.NewTicker(sweepInterval)
+		defer ticker.Stop()
+		for {
+			sweep()
+			select {
+			case <-stop:
+				return nil
+			case <-ticker.C:
+			case <-wakeCh:
+			}
+		}
+	})
+}
+
+func stopSweeper() {
+	if stopCh != nil {
+		close(stopCh)
+		stopCh = nil
+	}
+}
+
+// sweep 将到期的上架记录标记为过期，并发放已结束记录的卖家所得或退回道具
+func sweep() {
+	cfg := jsonconf.GetConfigManager().GetAuctionConfig()
+	if cfg == nil {
+		return
+	}
+	if n, err := database.ExpireAuctionListings(servertime.Now().Unix()); err != nil {
+		log.Errorf("[auction] expire listings failed: %v", err)
+	} else if n > 0 {
+		log.Infof("[auction] %d listings expired", n)
+	}
+
+	d := getDeliverer()
+	if d == nil {
+		return
+	}
+	for {
+		listings, err := database.GetUndeliveredAuctionListings(deliverBatchSize)
+		if err != nil {
+			log.Errorf("[auction] load undelivered listings failed: %v", err)
+			return
+		}
+		for _, listing := range listings {
+			delivery := buildDelivery(listing, cfg)
+			delivered, err := database.DeliverAuctionListing(listing.ID, func(tx *gorm.DB) error {
+				return d.DeliverTx(tx, delivery)
+			})
+			if err != nil {
+				// 发放失败的记录保持未发放，下次继续重试；本轮不再处理后续批次，避免反复失败
+				log.Errorf("[auction] deliver listing %d failed: %v", listing.ID, err)
+				return
+			}
+			if delivered {
+				d.OnDelivered(delivery)
+			}
+		}
+		if len(listings) < deliverBatchSize {
+			return
+		}
+	}
+}
+
+// buildDelivery 售出时发放扣除成交税后的金币，过期或下架时退回道具
+func buildDelivery(listing *database.AuctionListing, cfg *jsonconf.AuctionConfig) *Delivery {
+	itemName := fmt.Sprintf("道具%d", listing.ItemId)
+	if itemCfg := jsonconf.GetConfigManager().GetItemConfig(listing.ItemId); itemCfg != nil {
+		itemName = itemCfg.Name
+	}
+	delivery := &Delivery{RoleId: listing.SellerId}
+	switch listing.Status {
+	case database.AuctionStatusSold:
+		tax := cfg.CalcTax(listing.Price)
+		income := listing.Price - tax
+		delivery.Title = "拍卖行出售成功"
+		delivery.Content = fmt.Sprintf("您上架的 %s x%d 已以 %d 金币售出，扣除成交税 %d 后获得 %d 金币。", itemName, listing.Count, listing.Price, tax, income)
+		if income > 0 {
+			delivery.Items = []*protocol.ItemAmount{{ItemId: cfg.CurrencyItemId, Count: income}}
+		}
+	case database.AuctionStatusExpired:
+		delivery.Title = "拍卖行道具过期退回"
+		delivery.Content = fmt.Sprintf("您上架的 %s x%d 到期未售出，现退回给您。", itemName, listing.Count)
+		delivery.Items = []*protocol.ItemAmount{{ItemId: listing.ItemId, Count: int64(listing.Count)}}
+	default:
+		delivery.Title = "拍卖行道具下架退回"
+		delivery.Content = fmt.Sprintf("您下架的 %s x%d 现退回给您。", itemName, listing.Count)
+		delivery.Items = []*protocol.ItemAmount{{ItemId: listing.ItemId, Count: int64(listing.Count)}}
+	}
+	return delivery
+}
+
+func init() {
+	gevent.Subscribe(gevent.OnSrvStart, func(ctx context.Context, _ *event.Event) {
+		startSweeper()
+	})
+	gevent.Subscribe(gevent.OnSrvStop, func(ctx context.Context, _ *event.Event) {
+		stopSweeper()
+	})
+}
@@ -0,0 +1,250 @@
+/**
+ * @Author: zjj
+ * @Date: 2025/12/28
+ * @Desc: 背包格子操作
+**/
+
+package bag
+
+import (
+	"math"
+	"postapocgame/server/internal/jsonconf"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"sort"
+)
+
+// DefaultBagCapacity 默认背包格子数
+const DefaultBagCapacity = 100
+
+// bagContainer 背包格子操作，记录变更过的格子用于增量同步
+// 批量操作在背包副本上执行，全部成功后再提交，保证原子性
+type bagContainer struct {
+	data    *protocol.SiBagData
+	changed map[uint32]struct{}
+}
+
+func newBagContainer(data *protocol.SiBagData) *bagContainer {
+	if data.Items == nil {
+		data.Items = make(map[uint32]*protocol.BagItemSt)
+	}
+	if data.Capacity == 0 {
+		data.Capacity = DefaultBagCapacity
+	}
+	return &bagContainer{
+		data:    data,
+		changed: make(map[uint32]struct{}),
+	}
+}
+
+func (c *bagContainer) markChanged(slot uint32) {
+	c.changed[slot] = struct{}{}
+}
+
+// getItem 获取格子上的道具
+func (c *bagContainer) getItem(slot uint32) *protocol.BagItemSt {
+	return c.data.Items[slot]
+}
+
+// countItem 统计道具总数（含绑定与非绑定）
+func (c *bagContainer) countItem(itemId uint32) uint64 {
+	var total uint64
+	for _, item := range c.data.Items {
+		if item.ItemId == itemId {
+			total += uint64(item.Count)
+		}
+	}
+	return total
+}
+
+// addItem 放入道具：先叠加到已有同类格子，再占用空格子
+func (c *bagContainer) addItem(itemId uint32, count uint32, bind uint32) error {
+	if count == 0 {
+		return nil
+	}
+	cfg := jsonconf.GetConfigManager().GetItemConfig(itemId)
+	if cfg == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Item_NotFound), "item config not found: %d", itemId)
+	}
+	if cfg.Bind == 1 {
+		bind = 1
+	}
+	stackLimit := cfg.GetStackLimit()
+
+	if stackLimit > 1 {
+		for _, slot := range c.sortedSlots() {
+			item := c.data.Items[slot]
+			if item.ItemId != itemId || item.Bind != bind || item.Count >= stackLimit {
+				continue
+			}
+			add := min(stackLimit-item.Count, count)
+			item.Count += add
+			count -= add
+			c.markChanged(slot)
+			if count == 0 {
+				return nil
+			}
+		}
+	}
+
+	for count > 0 {
+		slot, ok := c.freeSlot()
+		if !ok {
+			return customerr.NewErrorByCode(int32(protocol.ErrorCode_Bag_Full), "bag full, itemId=%d remain=%d", itemId, count)
+		}
+		add := min(stackLimit, count)
+		c.data.Items[slot] = &protocol.BagItemSt{Slot: slot, ItemId: itemId, Count: add, Bind: bind}
+		count -= add
+		c.markChanged(slot)
+	}
+	return nil
+}
+
+// removeItem 扣除道具，优先扣除绑定道具
+func (c *bagContainer) removeItem(itemId uint32, count uint32) error {
+	if count == 0 {
+		return nil
+	}
+	if c.countItem(itemId) < uint64(count) {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Item_NotEnough), "item not enough, itemId=%d need=%d", itemId, count)
+	}
+	slots := c.sortedSlots()
+	for _, wantBind := range []uint32{1, 0} {
+		for _, slot := range slots {
+			item := c.data.Items[slot]
+			if item == nil || item.ItemId != itemId || item.Bind != wantBind {
+				continue
+			}
+			sub := min(item.Count, count)
+			c.removeFromSlot(slot, sub)
+			count -= sub
+			if count == 0 {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// removeFromSlot 从指定格子扣除数量，扣完后清空格子
+func (c *bagContainer) removeFromSlot(slot uint32, count uint32) {
+	item := c.data.Items[slot]
+	if item == nil {
+		return
+	}
+	if item.Count <= count {
+		delete(c.data.Items, slot)
+	} else {
+		item.Count -= count
+	}
+	c.markChanged(slot)
+}
+
+// splitItem 拆分格子，拆出的道具放入新的空格子
+func (c *bagContainer) splitItem(slot uint32, count uint32) error {
+	item := c.data.Items[slot]
+	if item == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Item_NotFound), "slot %d is empty", slot)
+	}
+	if count == 0 || count >= item.Count {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "invalid split count %d", count)
+	}
+	newSlot, ok := c.freeSlot()
+	if !ok {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Bag_Full), "bag full, cannot split")
+	}
+	item.Count -= count
+	c.data.Items[newSlot] = &protocol.BagItemSt{Slot: newSlot, ItemId: item.ItemId, Count: count, Bind: item.Bind}
+	c.markChanged(slot)
+	c.markChanged(newSlot)
+	return nil
+}
+
+// sortItems 整理背包：合并可叠加的同类道具，按类型、道具ID、绑定状态重新排列
+func (c *bagContainer) sortItems() {
+	type stackKey struct {
+		itemId uint32
+		bind   uint32
+	}
+	configMgr := jsonconf.GetConfigManager()
+	totals := make(map[stackKey]uint64)
+	var keys []stackKey
+	for slot, item := range c.data.Items {
+		key := stackKey{itemId: item.ItemId, bind: item.Bind}
+		if _, ok := totals[key]; !ok {
+			keys = append(keys, key)
+		}
+		totals[key] += uint64(item.Count)
+		c.markChanged(slot)
+	}
+
+	itemType := func(itemId uint32) uint32 {
+		if cfg := configMgr.GetItemConfig(itemId); cfg != nil {
+			return cfg.Type
+		}
+		return math.MaxUint32
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ti, tj := itemType(keys[i].itemId), itemType(keys[j].itemId)
+		if ti != tj {
+			return ti < tj
+		}
+		if keys[i].itemId != keys[j].itemId {
+			return keys[i].itemId < keys[j].itemId
+		}
+		return keys[i].bind > keys[j].bind
+	})
+
+	items := make(map[uint32]*protocol.BagItemSt, len(c.data.Items))
+	var slot uint32
+	for _, key := range keys {
+		stackLimit := uint64(1)
+		if cfg := configMgr.GetItemConfig(key.itemId); cfg != nil {
+			stackLimit = uint64(cfg.GetStackLimit())
+		}
+		for remain := totals[key]; remain > 0; slot++ {
+			count := min(stackLimit, remain)
+			items[slot] = &protocol.BagItemSt{Slot: slot, ItemId: key.itemId, Count: uint32(count), Bind: key.bind}
+			remain -= count
+			c.markChanged(slot)
+		}
+	}
+	c.data.Items = items
+}
+
+// freeSlot 获取编号最小的空格子
+func (c *bagContainer) freeSlot() (uint32, bool) {
+	for slot := uint32(0); slot < c.data.Capacity; slot++ {
+		if _, ok := c.data.Items[slot]; !ok {
+			return slot, true
+		}
+	}
+	return 0, false
+}
+
+func (c *bagContainer) sortedSlots() []uint32 {
+	slots := make([]uint32, 0, len(c.data.Items))
+	for slot := range c.data.Items {
+		slots = append(slots, slot)
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i] < slots[j] })
+	return slots
+}
+
+// buildUpdate 根据变更格子构造增量同步消息
+func (c *bagContainer) buildUpdate() *protocol.S2CBagUpdateReq {
+	resp := &protocol.S2CBagUpdateReq{}
+	slots := make([]uint32, 0, len(c.changed))
+	for slot := range c.changed {
+		slots = append(slots, slot)
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i] < slots[j] })
+	for _, slot := range slots {
+		if item, ok := c.data.Items[slot]; ok {
+			resp.Items = append(resp.Items, item)
+		} else {
+			resp.RemovedSlots = append(resp.RemovedSlots, slot)
+		}
+	}
+	return resp
+}
@@ -0,0 +1,488 @@
+package bag
+
+import (
+	"context"
+	"math"
+	"postapocgame/server/internal/jsonconf"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/internal/servertime"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/gshare"
+	"postapocgame/server/service/gameserver/internel/iface"
+	"postapocgame/server/service/gameserver/internel/playeractor/deps"
+	"postapocgame/server/service/gameserver/internel/playeractor/entitysystem"
+	"postapocgame/server/service/gameserver/internel/playeractor/sysbase"
+	"sort"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+var _ iface.ISystem = (*SystemAdapter)(nil)
+
+type SystemAdapter struct {
+	*sysbase.BaseSystem
+	rt *deps.Runtime
+
+	pendingUse *pendingItemUse // 等待其他 Actor 回传结果的道具使用，期间该道具不能再使用或移出背包
+	useSeq     uint32
+}
+
+// pendingItemUse 等待结果的道具使用，道具仍留在原格子上
+type pendingItemUse struct {
+	seq    uint32
+	slot   uint32
+	itemId uint32
+	at     time.Time
+}
+
+// pendingUseTimeout 道具使用结果迟迟未回传（如 DungeonActor 链路中断）时解除锁定
+// 迟到的成功结果仍按序号扣除道具
+const pendingUseTimeout = 10 * time.Second
+
+// NewBagSystemAdapter 创建背包系统适配器
+func NewBagSystemAdapter(rt *deps.Runtime) *SystemAdapter {
+	return &SystemAdapter{
+		BaseSystem: sysbase.NewBaseSystem(uint32(protocol.SystemId_SysBag)),
+		rt:         rt,
+	}
+}
+
+// OnInit 系统初始化，修正背包容量
+func (a *SystemAdapter) OnInit(ctx context.Context) {
+	bagData, err := a.rt.PlayerRepo().GetBagData(ctx)
+	if err != nil {
+		log.Errorf("bag sys OnInit get bag data err:%v", err)
+		return
+	}
+	newBagContainer(bagData)
+}
+
+// OnRoleLogin 登录下发背包数据
+func (a *SystemAdapter) OnRoleLogin(ctx context.Context) {
+	if err := a.SendBagData(ctx); err != nil {
+		log.Errorf("bag sys OnRoleLogin send bag data err:%v", err)
+	}
+}
+
+// SendBagData 下发全量背包数据
+func (a *SystemAdapter) SendBagData(ctx context.Context) error {
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	bagData, err := a.rt.PlayerRepo().GetBagData(ctx)
+	if err != nil {
+		return err
+	}
+	resp := &protocol.S2CBagDataReq{
+		Capacity: bagData.Capacity,
+		Items:    make([]*protocol.BagItemSt, 0, len(bagData.Items)),
+	}
+	for _, item := range bagData.Items {
+		resp.Items = append(resp.Items, item)
+	}
+	sort.Slice(resp.Items, func(i, j int) bool { return resp.Items[i].Slot < resp.Items[j].Slot })
+	return playerRole.SendProtoMessage(uint16(protocol.S2CProtocol_S2CBagData), resp)
+}
+
+// CanAddItems 检查背包能否放下全部道具
+func (a *SystemAdapter) CanAddItems(ctx context.Context, items []*protocol.ItemAmount) bool {
+	_, err := a.tryAddItems(ctx, items)
+	return err == nil
+}
+
+// AddItems 发放道具（对外接口），背包放不下时整体失败并返回 Bag_Full
+func (a *SystemAdapter) AddItems(ctx context.Context, items []*protocol.ItemAmount) error {
+	c, err := a.tryAddItems(ctx, items)
+	if err != nil {
+		return err
+	}
+	return a.commit(ctx, c)
+}
+
+// RemoveItems 扣除道具（对外接口），数量不足时整体失败并返回 Item_NotEnough
+func (a *SystemAdapter) RemoveItems(ctx context.Context, items []*protocol.ItemAmount) error {
+	c, err := a.cloneContainer(ctx)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		count, err := checkCount(item)
+		if err != nil {
+			return err
+		}
+		if err := c.removeItem(item.ItemId, count); err != nil {
+			return err
+		}
+	}
+	return a.commit(ctx, c)
+}
+
+// GetItemCount 获取道具总数
+func (a *SystemAdapter) GetItemCount(ctx context.Context, itemId uint32) uint64 {
+	bagData, err := a.rt.PlayerRepo().GetBagData(ctx)
+	if err != nil {
+		return 0
+	}
+	return newBagContainer(bagData).countItem(itemId)
+}
+
+// GetSlotItem 获取格子上的道具，空格子返回 nil
+func (a *SystemAdapter) GetSlotItem(ctx context.Context, slot uint32) *protocol.BagItemSt {
+	bagData, err := a.rt.PlayerRepo().GetBagData(ctx)
+	if err != nil {
+		return nil
+	}
+	return bagData.Items[slot]
+}
+
+// ExchangeSlotItem 取出格子上的整格道具，并在该格子放入 put（为 nil 时格子清空）
+// 用于穿戴装备时与已穿戴的装备交换位置
+func (a *SystemAdapter) ExchangeSlotItem(ctx context.Context, slot uint32, put *protocol.BagItemSt) (*protocol.BagItemSt, error) {
+	c, err := a.cloneContainer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	taken := c.getItem(slot)
+	if taken == nil {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Item_NotFound), "slot %d is empty", slot)
+	}
+	delete(c.data.Items, slot)
+	if put != nil {
+		c.data.Items[slot] = &protocol.BagItemSt{Slot: slot, ItemId: put.ItemId, Count: put.Count, Bind: put.Bind}
+	}
+	c.markChanged(slot)
+	if err := a.commit(ctx, c); err != nil {
+		return nil, err
+	}
+	return taken, nil
+}
+
+// UseItem 使用格子上的道具
+func (a *SystemAdapter) UseItem(ctx context.Context, slot uint32, count uint32) error {
+	if count == 0 {
+		count = 1
+	}
+	c, err := a.cloneContainer(ctx)
+	if err != nil {
+		return err
+	}
+	item := c.getItem(slot)
+	if item == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Item_NotFound), "slot %d is empty", slot)
+	}
+	if item.Count < count {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Item_NotEnough), "item not enough, slot=%d", slot)
+	}
+	itemId := item.ItemId
+	cfg, effect, err := getItemUseEffect(itemId)
+	if err != nil {
+		return err
+	}
+	if err := a.checkPendingUse(itemId); err != nil {
+		return err
+	}
+	if effect.request != nil {
+		return a.requestItemUse(ctx, slot, cfg, effect, count)
+	}
+
+	c.removeFromSlot(slot, count)
+	if effect.fill != nil {
+		if err := effect.fill(c, cfg, count); err != nil {
+			return err
+		}
+	}
+	if effect.check != nil {
+		if err := effect.check(ctx, cfg, count); err != nil {
+			return err
+		}
+	}
+	if err := a.commit(ctx, c); err != nil {
+		return err
+	}
+	if effect.apply != nil {
+		if err := effect.apply(ctx, cfg, count); err != nil {
+			log.Errorf("bag use item %d apply effect failed: %v", itemId, err)
+		}
+	}
+
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	return playerRole.SendProtoMessage(uint16(protocol.S2CProtocol_S2CBagUseItem), &protocol.S2CBagUseItemReq{
+		ItemId: itemId,
+		Count:  count,
+	})
+}
+
+// requestItemUse 道具效果交给其他 Actor 执行，道具锁定在格子上，等 OnItemUseResult 成功后再扣除
+func (a *SystemAdapter) requestItemUse(ctx context.Context, slot uint32, cfg *jsonconf.ItemConfig, effect *itemUseEffect, count uint32) error {
+	if effect.check != nil {
+		if err := effect.check(ctx, cfg, count); err != nil {
+			return err
+		}
+	}
+	a.useSeq++
+	pending := &pendingItemUse{seq: a.useSeq, slot: slot, itemId: cfg.ItemId, at: servertime.Now()}
+	if err := effect.request(ctx, cfg, pending.seq); err != nil {
+		return err
+	}
+	a.pendingUse = pending
+	return nil
+}
+
+// OnItemUseResult 处理道具使用结果：成功则扣除一个道具，失败则只解除锁定
+// 超时后迟到的成功结果同样扣除道具，保证效果与消耗一一对应
+func (a *SystemAdapter) OnItemUseResult(ctx context.Context, itemId, seq uint32, success bool) error {
+	pending := a.pendingUse
+	if pending != nil && pending.seq == seq {
+		a.pendingUse = nil
+	}
+	if !success {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Item_CannotUse), "item %d use failed", itemId)
+	}
+
+	c, err := a.cloneContainer(ctx)
+	if err != nil {
+		return err
+	}
+	// 优先扣除发起使用的格子，道具被整理到其他格子时按道具ID扣除
+	if pending != nil && pending.seq == seq {
+		if item := c.getItem(pending.slot); item != nil && item.ItemId == itemId {
+			c.removeFromSlot(pending.slot, 1)
+		} else if err := c.removeItem(itemId, 1); err != nil {
+			return err
+		}
+	} else if err := c.removeItem(itemId, 1); err != nil {
+		return err
+	}
+	if err := a.commit(ctx, c); err != nil {
+		return err
+	}
+
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	return playerRole.SendProtoMessage(uint16(protocol.S2CProtocol_S2CBagUseItem), &protocol.S2CBagUseItemReq{
+		ItemId: itemId,
+		Count:  1,
+	})
+}
+
+// checkPendingUse 道具使用结果回传前，同种道具不能再使用或移出背包
+func (a *SystemAdapter) checkPendingUse(itemId uint32) error {
+	pending := a.pendingUse
+	if pending == nil || pending.itemId != itemId {
+		return nil
+	}
+	if servertime.Now().Sub(pending.at) >= pendingUseTimeout {
+		log.Warnf("bag item %d use seq %d result timeout", itemId, pending.seq)
+		a.pendingUse = nil
+		return nil
+	}
+	return customerr.NewErrorByCode(int32(protocol.ErrorCode_Item_Using), "item %d is in use", itemId)
+}
+
+// SplitItem 拆分格子
+func (a *SystemAdapter) SplitItem(ctx context.Context, slot uint32, count uint32) error {
+	c, err := a.cloneContainer(ctx)
+	if err != nil {
+		return err
+	}
+	if err := c.splitItem(slot, count); err != nil {
+		return err
+	}
+	return a.commit(ctx, c)
+}
+
+// SortBag 整理背包
+func (a *SystemAdapter) SortBag(ctx context.Context) error {
+	c, err := a.cloneContainer(ctx)
+	if err != nil {
+		return err
+	}
+	c.sortItems()
+	return a.commit(ctx, c)
+}
+
+// DiscardItem 丢弃格子上的道具，count 为 0 表示整格丢弃
+func (a *SystemAdapter) DiscardItem(ctx context.Context, slot uint32, count uint32) error {
+	c, err := a.cloneContainer(ctx)
+	if err != nil {
+		return err
+	}
+	item := c.getItem(slot)
+	if item == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Item_NotFound), "slot %d is empty", slot)
+	}
+	if err := a.checkPendingUse(item.ItemId); err != nil {
+		return err
+	}
+	if cfg := getItemConfig(item.ItemId); cfg == nil || !cfg.Discard {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "item %d cannot be discarded", item.ItemId)
+	}
+	if count == 0 {
+		count = item.Count
+	}
+	if item.Count < count {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Item_NotEnough), "item not enough, slot=%d", slot)
+	}
+	c.removeFromSlot(slot, count)
+	return a.commit(ctx, c)
+}
+
+// SlotAmount 从指定格子取出的数量
+type SlotAmount struct {
+	Slot  uint32
+	Count uint32
+}
+
+// ItemChange 背包批量变更，按 TakeSlots、Remove、Add 的顺序执行
+type ItemChange struct {
+	TakeSlots []*SlotAmount          // 从格子取出
+	Remove    []*protocol.ItemAmount // 按道具扣除
+	Add       []*protocol.ItemAmount // 放入道具
+}
+
+// ApplyWithPersist 在背包副本上执行变更，把应用了变更的 BinaryData 副本交给 persist 落库，persist 成功后才提交到内存
+// 用于拍卖行等道具进出背包必须与其他记录同事务落库的场景，避免宕机回档导致道具复制或丢失
+func (a *SystemAdapter) ApplyWithPersist(ctx context.Context, change *ItemChange, persist func(binaryData *protocol.PlayerRoleBinaryData) error) error {
+	c, err := a.cloneContainer(ctx)
+	if err != nil {
+		return err
+	}
+	for _, take := range change.TakeSlots {
+		item := c.getItem(take.Slot)
+		if item == nil {
+			return customerr.NewErrorByCode(int32(protocol.ErrorCode_Item_NotFound), "slot %d is empty", take.Slot)
+		}
+		if take.Count == 0 || item.Count < take.Count {
+			return customerr.NewErrorByCode(int32(protocol.ErrorCode_Item_NotEnough), "item not enough, slot=%d", take.Slot)
+		}
+		if err := a.checkPendingUse(item.ItemId); err != nil {
+			return err
+		}
+		c.removeFromSlot(take.Slot, take.Count)
+	}
+	for _, item := range change.Remove {
+		count, err := checkCount(item)
+		if err != nil {
+			return err
+		}
+		if err := a.checkPendingUse(item.ItemId); err != nil {
+			return err
+		}
+		if err := c.removeItem(item.ItemId, count); err != nil {
+			return err
+		}
+	}
+	for _, item := range change.Add {
+		count, err := checkCount(item)
+		if err != nil {
+			return err
+		}
+		if err := c.addItem(item.ItemId, count, item.Bind); err != nil {
+			return err
+		}
+	}
+
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	binaryData := proto.Clone(playerRole.GetBinaryData()).(*protocol.PlayerRoleBinaryData)
+	binaryData.BagData = c.data
+	if err := persist(binaryData); err != nil {
+		return err
+	}
+	return a.commit(ctx, c)
+}
+
+func (a *SystemAdapter) tryAddItems(ctx context.Context, items []*protocol.ItemAmount) (*bagContainer, error) {
+	c, err := a.cloneContainer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		count, err := checkCount(item)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.addItem(item.ItemId, count, item.Bind); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// cloneContainer 在背包副本上操作，commit 前不影响真实数据
+func (a *SystemAdapter) cloneContainer(ctx context.Context) (*bagContainer, error) {
+	bagData, err := a.rt.PlayerRepo().GetBagData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newBagContainer(proto.Clone(bagData).(*protocol.SiBagData)), nil
+}
+
+// commit 提交背包副本并下发增量变化
+func (a *SystemAdapter) commit(ctx context.Context, c *bagContainer) error {
+	bagData, err := a.rt.PlayerRepo().GetBagData(ctx)
+	if err != nil {
+		return err
+	}
+	bagData.Capacity = c.data.Capacity
+	bagData.Items = c.data.Items
+	if len(c.changed) == 0 {
+		return nil
+	}
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if err := playerRole.SendProtoMessage(uint16(protocol.S2CProtocol_S2CBagUpdate), c.buildUpdate()); err != nil {
+		log.Warnf("send bag update failed: %v", err)
+	}
+	return nil
+}
+
+func checkCount(item *protocol.ItemAmount) (uint32, error) {
+	if item == nil || item.Count <= 0 || item.Count > math.MaxUint32 {
+		return 0, customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "invalid item amount")
+	}
+	return uint32(item.Count), nil
+}
+
+// GetBagSys 获取背包系统
+func GetBagSys(ctx context.Context) *SystemAdapter {
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		log.Errorf("get player role error:%v", err)
+		return nil
+	}
+	system := playerRole.GetSystem(uint32(protocol.SystemId_SysBag))
+	if system == nil {
+		log.Errorf("not found system [%v]", protocol.SystemId_SysBag)
+		return nil
+	}
+	sys, ok := system.(*SystemAdapter)
+	if !ok {
+		log.Errorf("invalid system type for [%v]", protocol.SystemId_SysBag)
+		return nil
+	}
+	if sys == nil || !sys.IsOpened() {
+		log.Errorf("get player role system [%v] error", protocol.SystemId_SysBag)
+		return nil
+	}
+	return sys
+}
+
+// RegisterSystemFactory 注册背包系统工厂（由 register.RegisterAll 调用）
+func RegisterSystemFactory(rt *deps.Runtime) {
+	entitysystem.RegisterSystemFactory(uint32(protocol.SystemId_SysBag), func() iface.ISystem {
+		return NewBagSystemAdapter(rt)
+	})
+}
@@ -0,0 +1,119 @@
+/**
+ * @Author: zjj
+ * @Date: 2025/12/28
+ * @Desc: 道具使用效果
+**/
+
+package bag
+
+import (
+	"context"
+	"math"
+	"postapocgame/server/internal/jsonconf"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/service/gameserver/internel/gshare"
+	"postapocgame/server/service/gameserver/internel/playeractor/level"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// itemUseEffect 道具使用效果
+// fill 在已扣除道具的背包副本上放入产出（如礼包），失败则整体回滚
+// check 提交前的前置检查
+// apply 背包变更提交后执行的效果（如加经验）
+// request 交给其他 Actor 执行的效果（如加Buff），道具在结果回传成功后才扣除，seq 随结果原样带回
+type itemUseEffect struct {
+	fill    func(c *bagContainer, cfg *jsonconf.ItemConfig, count uint32) error
+	check   func(ctx context.Context, cfg *jsonconf.ItemConfig, count uint32) error
+	apply   func(ctx context.Context, cfg *jsonconf.ItemConfig, count uint32) error
+	request func(ctx context.Context, cfg *jsonconf.ItemConfig, seq uint32) error
+}
+
+var itemUseEffects = make(map[protocol.ItemUseType]*itemUseEffect)
+
+// registerItemUseEffect 注册道具使用效果，道具配置 use.type 对应 protocol.ItemUseType
+func registerItemUseEffect(useType protocol.ItemUseType, effect *itemUseEffect) {
+	itemUseEffects[useType] = effect
+}
+
+func init() {
+	registerItemUseEffect(protocol.ItemUseType_ItemUseTypeExp, &itemUseEffect{
+		check: func(ctx context.Context, _ *jsonconf.ItemConfig, _ uint32) error {
+			if level.GetLevelSys(ctx) == nil {
+				return customerr.NewErrorByCode(int32(protocol.ErrorCode_Item_CannotUse), "level system not opened")
+			}
+			return nil
+		},
+		apply: func(ctx context.Context, cfg *jsonconf.ItemConfig, count uint32) error {
+			return level.GetLevelSys(ctx).AddExp(ctx, uint64(cfg.Use.Value)*uint64(count))
+		},
+	})
+	registerItemUseEffect(protocol.ItemUseType_ItemUseTypeGift, &itemUseEffect{
+		fill: func(c *bagContainer, cfg *jsonconf.ItemConfig, count uint32) error {
+			for _, gift := range cfg.Use.Items {
+				if gift == nil {
+					continue
+				}
+				giftCfg := getItemConfig(gift.ItemId)
+				if giftCfg == nil {
+					return customerr.NewErrorByCode(int32(protocol.ErrorCode_Item_NotFound), "item config not found: %d", gift.ItemId)
+				}
+				// 批量开启的产出总数不超过背包能容纳的上限，同时避免 uint32 溢出
+				total := uint64(gift.Count) * uint64(count)
+				if total > min(uint64(giftCfg.GetStackLimit())*uint64(c.data.Capacity), math.MaxUint32) {
+					return customerr.NewErrorByCode(int32(protocol.ErrorCode_Bag_Full), "gift item %d count %d exceeds bag limit", gift.ItemId, total)
+				}
+				// 礼包产出继承礼包的绑定规则
+				if err := c.addItem(gift.ItemId, uint32(total), cfg.Bind); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+	registerItemUseEffect(protocol.ItemUseType_ItemUseTypeAddBuff, &itemUseEffect{
+		check: func(_ context.Context, _ *jsonconf.ItemConfig, count uint32) error {
+			if count != 1 {
+				return customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "buff item can only be used one at a time")
+			}
+			return nil
+		},
+		request: func(ctx context.Context, cfg *jsonconf.ItemConfig, seq uint32) error {
+			playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+			if err != nil {
+				return err
+			}
+			data, err := proto.Marshal(&protocol.DAMAddBuffReq{
+				BuffId:   uint32(cfg.Use.Value),
+				Duration: cfg.Use.Duration,
+				ItemId:   cfg.ItemId,
+				UseSeq:   seq,
+			})
+			if err != nil {
+				return err
+			}
+			return playerRole.CallDungeonActor(ctx, uint16(protocol.DungeonActorMsgId_DAMAddBuff), data)
+		},
+	})
+}
+
+func getItemConfig(itemId uint32) *jsonconf.ItemConfig {
+	return jsonconf.GetConfigManager().GetItemConfig(itemId)
+}
+
+// getItemUseEffect 获取道具配置及其使用效果，不可使用时返回 Item_CannotUse
+func getItemUseEffect(itemId uint32) (*jsonconf.ItemConfig, *itemUseEffect, error) {
+	cfg := getItemConfig(itemId)
+	if cfg == nil {
+		return nil, nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Item_NotFound), "item config not found: %d", itemId)
+	}
+	if cfg.Use == nil {
+		return nil, nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Item_CannotUse), "item %d cannot be used", itemId)
+	}
+	effect := itemUseEffects[protocol.ItemUseType(cfg.Use.Type)]
+	if effect == nil {
+		return nil, nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Item_CannotUse), "item %d use type %d not supported", itemId, cfg.Use.Type)
+	}
+	return cfg, effect, nil
+}
@@ -0,0 +1,291 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/12
+ * @Desc: 聊天：世界、场景、队伍、公会与私聊频道；禁言、频道冷却与敏感词过滤在 PlayerActor 内完成，场景与队伍频道交由 DungeonActor 广播
+**/
+
+package chat
+
+import (
+	"context"
+	"errors"
+	"postapocgame/server/internal/database"
+	"postapocgame/server/internal/event"
+	"postapocgame/server/internal/jsonconf"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/internal/servertime"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/gatewaylink"
+	"postapocgame/server/service/gameserver/internel/gevent"
+	"postapocgame/server/service/gameserver/internel/gshare"
+	"postapocgame/server/service/gameserver/internel/iface"
+	"postapocgame/server/service/gameserver/internel/manager"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/proto"
+	"gorm.io/gorm"
+)
+
+// GroupResolver 返回角色所在公会的成员角色ID（含自己），未加入时返回空
+type GroupResolver func(roleId uint64) []uint64
+
+var (
+	groupResolvers   = make(map[protocol.ChatChannel]GroupResolver)
+	groupResolversMu sync.RWMutex
+
+	// readyAt 角色在各频道冷却结束的时间，取自 time.Now（单调时钟），不受 GM 调整 servertime 影响
+	// 登出不清除，重登无法绕过冷却；冷却结束的记录由 markSent 定期清理
+	readyAt     = make(map[uint64]map[protocol.ChatChannel]time.Time)
+	readyAtMu   sync.Mutex
+	nextSweepAt time.Time
+
+	worldHistory historyRing
+
+	filterMu     sync.Mutex
+	filterCfg    *jsonconf.ChatConfig
+	cachedFilter *wordFilter
+)
+
+// SetGroupResolver 注册公会频道的成员查询，由公会系统在启动时注册；未注册时频道返回 Chat_NoChannel
+// 目前尚无公会系统，公会频道只是预留接口，默认配置不开放；队伍频道按副本实例由 DungeonActor 广播
+func SetGroupResolver(channel protocol.ChatChannel, resolver GroupResolver) {
+	groupResolversMu.Lock()
+	defer groupResolversMu.Unlock()
+	if resolver == nil {
+		delete(groupResolvers, channel)
+		return
+	}
+	groupResolvers[channel] = resolver
+}
+
+func getGroupResolver(channel protocol.ChatChannel) GroupResolver {
+	groupResolversMu.RLock()
+	defer groupResolversMu.RUnlock()
+	return groupResolvers[channel]
+}
+
+// Send 发送聊天消息
+func Send(ctx context.Context, playerRole iface.IPlayerRole, req *protocol.C2SChatSendReq) error {
+	cfg := jsonconf.GetConfigManager().GetChatConfig()
+	if cfg == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Chat_Closed), "chat not configured")
+	}
+	channelCfg := cfg.GetChannel(uint32(req.Channel))
+	if channelCfg == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Chat_NoChannel), "channel %d not available", req.Channel)
+	}
+	if playerRole.IsMuted() {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Chat_Muted), "role %d is muted", playerRole.GetPlayerRoleId())
+	}
+	content := strings.TrimSpace(req.Content)
+	if content == "" || utf8.RuneCountInString(content) > int(cfg.MaxLength) {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Chat_ContentInvalid), "content length must be in [1, %d]", cfg.MaxLength)
+	}
+	roleId := playerRole.GetPlayerRoleId()
+	now := time.Now()
+	if !checkCooldown(roleId, req.Channel, now) {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Chat_Cooldown), "channel %d in cooldown", req.Channel)
+	}
+	// 通过校验即进入冷却，投递失败（如私聊目标不存在）同样计入，避免借失败请求刷库
+	markSent(roleId, req.Channel, now, time.Duration(channelCfg.CooldownMs)*time.Millisecond)
+
+	filtered, hit := getFilter(cfg).Replace(content, cfg.Replacement)
+	if hit {
+		log.Infof("[chat] sensitive words filtered: roleId=%d channel=%d", roleId, req.Channel)
+	}
+	msg := &protocol.ChatMsgSt{
+		Channel:    req.Channel,
+		SenderId:   roleId,
+		SenderName: playerRole.GetPlayerSimpleData().GetRoleName(),
+		Content:    filtered,
+		SendTime:   servertime.Now().UnixMilli(),
+	}
+
+	var err error
+	switch req.Channel {
+	case protocol.ChatChannel_ChatChannelWorld:
+		err = sendWorld(msg, int(cfg.HistorySize))
+	case protocol.ChatChannel_ChatChannelScene:
+		err = sendScene(ctx, playerRole, msg)
+	case protocol.ChatChannel_ChatChannelTeam:
+		err = sendTeam(ctx, playerRole, msg)
+	case protocol.ChatChannel_ChatChannelGuild:
+		err = sendGroup(msg)
+	case protocol.ChatChannel_ChatChannelWhisper:
+		err = sendWhisper(playerRole, msg, req.ToRoleName)
+	default:
+		err = customerr.NewErrorByCode(int32(protocol.ErrorCode_Chat_NoChannel), "channel %d not available", req.Channel)
+	}
+	return err
+}
+
+// sendWorld 广播给全部在线角色并记入世界频道历史
+func sendWorld(msg *protocol.ChatMsgSt, historySize int) error {
+	data, err := proto.Marshal(&protocol.S2CChatMessageReq{Msg: msg})
+	if err != nil {
+		return customerr.Wrap(err)
+	}
+	for _, role := range manager.GetPlayerRoleManager().GetAll() {
+		if err := gatewaylink.SendToSession(role.GetSessionId(), uint16(protocol.S2CProtocol_S2CChatMessage), data); err != nil {
+			log.Warnf("[chat] send world message to role %d failed: %v", role.GetPlayerRoleId(), err)
+		}
+	}
+	worldHistory.Add(msg, historySize)
+	return nil
+}
+
+// sendScene 交由 DungeonActor 广播给发送者所在场景的玩家
+func sendScene(ctx context.Context, playerRole iface.IPlayerRole, msg *protocol.ChatMsgSt) error {
+	data, err := proto.Marshal(&protocol.DAMChatSceneReq{Msg: msg})
+	if err != nil {
+		return customerr.Wrap(err)
+	}
+	return playerRole.CallDungeonActor(ctx, uint16(protocol.DungeonActorMsgId_DAMChatScene), data)
+}
+
+// sendTeam 交由 DungeonActor 广播给发送者所在副本实例的玩家，不在限时副本时由 DungeonActor 下发 Chat_NoChannel
+func sendTeam(ctx context.Context, playerRole iface.IPlayerRole, msg *protocol.ChatMsgSt) error {
+	data, err := proto.Marshal(&protocol.DAMChatTeamReq{Msg: msg})
+	if err != nil {
+		return customerr.Wrap(err)
+	}
+	return playerRole.CallDungeonActor(ctx, uint16(protocol.DungeonActorMsgId_DAMChatTeam), data)
+}
+
+// sendGroup 发给公会的在线成员
+func sendGroup(msg *protocol.ChatMsgSt) error {
+	resolver := getGroupResolver(msg.Channel)
+	if resolver == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Chat_NoChannel), "channel %d not available", msg.Channel)
+	}
+	members := resolver(msg.SenderId)
+	if len(members) == 0 {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Chat_NoChannel), "role %d has no channel %d", msg.SenderId, msg.Channel)
+	}
+	data, err := proto.Marshal(&protocol.S2CChatMessageReq{Msg: msg})
+	if err != nil {
+		return customerr.Wrap(err)
+	}
+	for _, memberId := range members {
+		role := manager.GetPlayerRole(memberId)
+		if role == nil {
+			continue
+		}
+		if err := gatewaylink.SendToSession(role.GetSessionId(), uint16(protocol.S2CProtocol_S2CChatMessage), data); err != nil {
+			log.Warnf("[chat] send channel %d message to role %d failed: %v", msg.Channel, memberId, err)
+		}
+	}
+	return nil
+}
+
+// sendWhisper 按角色名私聊在线角色，并回显给发送者
+func sendWhisper(playerRole iface.IPlayerRole, msg *protocol.ChatMsgSt, toRoleName string) error {
+	toRoleName = strings.TrimSpace(toRoleName)
+	if toRoleName == "" {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "whisper target is empty")
+	}
+	player, err := database.GetPlayerByRoleName(toRoleName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return customerr.NewErrorByCode(int32(protocol.ErrorCode_Chat_TargetOffline), "role %s not found", toRoleName)
+		}
+		return customerr.Wrap(err)
+	}
+	targetId := uint64(player.ID)
+	if targetId == msg.SenderId {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "cannot whisper to self")
+	}
+	target := manager.GetPlayerRole(targetId)
+	if target == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Chat_TargetOffline), "role %s is offline", toRoleName)
+	}
+	msg.TargetId = targetId
+	msg.TargetName = player.RoleName
+	resp := &protocol.S2CChatMessageReq{Msg: msg}
+	if err := gatewaylink.SendToSessionProto(target.GetSessionId(), uint16(protocol.S2CProtocol_S2CChatMessage), resp); err != nil {
+		return customerr.Wrap(err)
+	}
+	return playerRole.SendProtoMessage(uint16(protocol.S2CProtocol_S2CChatMessage), resp)
+}
+
+// cooldownSweepInterval 清理已结束冷却记录的间隔
+const cooldownSweepInterval = time.Minute
+
+// checkCooldown 该频道的发言冷却是否已结束
+func checkCooldown(roleId uint64, channel protocol.ChatChannel, now time.Time) bool {
+	readyAtMu.Lock()
+	defer readyAtMu.Unlock()
+	ready, ok := readyAt[roleId][channel]
+	return !ok || !now.Before(ready)
+}
+
+// markSent 记录发言，cooldown 后才能在该频道再次发言
+func markSent(roleId uint64, channel protocol.ChatChannel, now time.Time, cooldown time.Duration) {
+	readyAtMu.Lock()
+	defer readyAtMu.Unlock()
+	if !now.Before(nextSweepAt) {
+		sweepCooldown(now)
+		nextSweepAt = now.Add(cooldownSweepInterval)
+	}
+	if cooldown <= 0 {
+		return
+	}
+	channels, ok := readyAt[roleId]
+	if !ok {
+		channels = make(map[protocol.ChatChannel]time.Time)
+		readyAt[roleId] = channels
+	}
+	channels[channel] = now.Add(cooldown)
+}
+
+// sweepCooldown 清理冷却已结束的记录，调用方持有 readyAtMu
+func sweepCooldown(now time.Time) {
+	for roleId, channels := range readyAt {
+		for channel, ready := range channels {
+			if !now.Before(ready) {
+				delete(channels, channel)
+			}
+		}
+		if len(channels) == 0 {
+			delete(readyAt, roleId)
+		}
+	}
+}
+
+// getFilter 按当前配置获取敏感词过滤器，配置重载后重建
+func getFilter(cfg *jsonconf.ChatConfig) *wordFilter {
+	filterMu.Lock()
+	defer filterMu.Unlock()
+	if cachedFilter == nil || filterCfg != cfg {
+		cachedFilter = newWordFilter(cfg.SensitiveWords)
+		filterCfg = cfg
+	}
+	return cachedFilter
+}
+
+// handleChatOnPlayerLogin 登录时下发世界频道最近消息
+func handleChatOnPlayerLogin(ctx context.Context, _ *event.Event) {
+	if jsonconf.GetConfigManager().GetChatConfig() == nil {
+		return
+	}
+	msgs := worldHistory.List()
+	if len(msgs) == 0 {
+		return
+	}
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		log.Errorf("[chat] get player role on login failed: %v", err)
+		return
+	}
+	if err := playerRole.SendProtoMessage(uint16(protocol.S2CProtocol_S2CChatHistory), &protocol.S2CChatHistoryReq{Msgs: msgs}); err != nil {
+		log.Warnf("[chat] send history to role %d failed: %v", playerRole.GetPlayerRoleId(), err)
+	}
+}
+
+func init() {
+	gevent.SubscribePlayerEvent(gevent.OnPlayerLogin, handleChatOnPlayerLogin)
+}
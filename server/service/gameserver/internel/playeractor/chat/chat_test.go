@@ -0,0 +1,90 @@
+package chat
+
+import (
+	"postapocgame/server/internal/protocol"
+	"testing"
+	"time"
+)
+
+func TestWordFilter(t *testing.T) {
+	f := newWordFilter([]string{"外挂", "外挂软件", "FUCK", " "})
+	cases := []struct {
+		in   string
+		want string
+		hit  bool
+	}{
+		{"正常聊天", "正常聊天", false},
+		{"出售外挂", "出售**", true},
+		{"出售外挂软件便宜", "出售****便宜", true},
+		{"what the Fuck", "what the ****", true},
+		{"外外挂挂", "外**挂", true},
+	}
+	for _, c := range cases {
+		got, hit := f.Replace(c.in, "*")
+		if got != c.want || hit != c.hit {
+			t.Fatalf("Replace(%q) = %q, %v; want %q, %v", c.in, got, hit, c.want, c.hit)
+		}
+	}
+}
+
+func TestHistoryRing(t *testing.T) {
+	var h historyRing
+	if len(h.List()) != 0 {
+		t.Fatal("empty ring should list nothing")
+	}
+	for i := 1; i <= 5; i++ {
+		h.Add(&protocol.ChatMsgSt{SendTime: int64(i)}, 3)
+	}
+	got := h.List()
+	if len(got) != 3 || got[0].SendTime != 3 || got[2].SendTime != 5 {
+		t.Fatalf("unexpected history %v", got)
+	}
+	h.Add(&protocol.ChatMsgSt{SendTime: 6}, 0)
+	if len(h.List()) != 0 {
+		t.Fatal("history size 0 should keep nothing")
+	}
+}
+
+func TestCooldown(t *testing.T) {
+	const roleId = 42
+	t.Cleanup(func() { delete(readyAt, roleId) })
+	world := protocol.ChatChannel_ChatChannelWorld
+	now := time.Now()
+	if !checkCooldown(roleId, world, now) {
+		t.Fatal("first message should pass")
+	}
+	markSent(roleId, world, now, 500*time.Millisecond)
+	if checkCooldown(roleId, world, now.Add(499*time.Millisecond)) {
+		t.Fatal("message within cooldown should be rejected")
+	}
+	if !checkCooldown(roleId, protocol.ChatChannel_ChatChannelWhisper, now.Add(499*time.Millisecond)) {
+		t.Fatal("cooldown is per channel")
+	}
+	if !checkCooldown(roleId, world, now.Add(500*time.Millisecond)) {
+		t.Fatal("message after cooldown should pass")
+	}
+}
+
+func TestCooldownSwept(t *testing.T) {
+	const roleId, other = 43, 44
+	t.Cleanup(func() {
+		delete(readyAt, roleId)
+		delete(readyAt, other)
+		nextSweepAt = time.Time{}
+	})
+	readyAtMu.Lock()
+	nextSweepAt = time.Time{}
+	readyAtMu.Unlock()
+	world := protocol.ChatChannel_ChatChannelWorld
+	now := time.Now()
+	markSent(roleId, world, now, time.Second)
+	// 冷却结束的记录在下一次清理时移除，冷却中的记录保留
+	markSent(other, world, now.Add(2*cooldownSweepInterval), time.Second)
+	readyAtMu.Lock()
+	_, expired := readyAt[roleId]
+	_, active := readyAt[other]
+	readyAtMu.Unlock()
+	if expired || !active {
+		t.Fatalf("sweep kept expired=%v active=%v", expired, active)
+	}
+}
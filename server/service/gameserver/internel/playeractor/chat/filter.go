@@ -0,0 +1,81 @@
+package chat
+
+import (
+	"strings"
+	"unicode"
+)
+
+// wordFilter 敏感词过滤，按字构建前缀树，不区分大小写，最长匹配
+type wordFilter struct {
+	root *trieNode
+}
+
+type trieNode struct {
+	children map[rune]*trieNode
+	end      bool
+}
+
+func newWordFilter(words []string) *wordFilter {
+	f := &wordFilter{root: &trieNode{}}
+	for _, word := range words {
+		word = strings.TrimSpace(word)
+		if word == "" {
+			continue
+		}
+		node := f.root
+		for _, r := range word {
+			r = unicode.ToLower(r)
+			if node.children == nil {
+				node.children = make(map[rune]*trieNode)
+			}
+			next, ok := node.children[r]
+			if !ok {
+				next = &trieNode{}
+				node.children[r] = next
+			}
+			node = next
+		}
+		node.end = true
+	}
+	return f
+}
+
+// Replace 将命中的敏感词按字替换为 replacement，返回替换后的文本与是否命中
+func (f *wordFilter) Replace(text string, replacement string) (string, bool) {
+	runes := []rune(text)
+	var sb strings.Builder
+	hit := false
+	for i := 0; i < len(runes); {
+		if n := f.match(runes[i:]); n > 0 {
+			for j := 0; j < n; j++ {
+				sb.WriteString(replacement)
+			}
+			i += n
+			hit = true
+			continue
+		}
+		sb.WriteRune(runes[i])
+		i++
+	}
+	if !hit {
+		return text, false
+	}
+	return sb.String(), true
+}
+
+// match 返回从 runes 开头起最长敏感词的长度，未命中返回 0
+func (f *wordFilter) match(runes []rune) int {
+	node := f.root
+	matched := 0
+	for i, r := range runes {
+		next, ok := node.children[unicode.ToLower(r)]
+		if !ok {
+			break
+		}
+		node = next
+		if node.end {
+			matched = i + 1
+		}
+	}
+	return matched
+}
@@ -0,0 +1,42 @@
+package chat
+
+import (
+	"postapocgame/server/internal/protocol"
+	"sync"
+)
+
+// historyRing 频道最近消息的环形缓冲，供登录的角色补看
+type historyRing struct {
+	mu    sync.RWMutex
+	buf   []*protocol.ChatMsgSt
+	next  int
+	count int
+}
+
+// Add 追加消息，容量变化时丢弃旧消息重新开始
+func (h *historyRing) Add(msg *protocol.ChatMsgSt, size int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if size <= 0 {
+		h.buf, h.next, h.count = nil, 0, 0
+		return
+	}
+	if len(h.buf) != size {
+		h.buf, h.next, h.count = make([]*protocol.ChatMsgSt, size), 0, 0
+	}
+	h.buf[h.next] = msg
+	h.next = (h.next + 1) % size
+	h.count = min(h.count+1, size)
+}
+
+// List 按时间顺序返回保留的消息
+func (h *historyRing) List() []*protocol.ChatMsgSt {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	result := make([]*protocol.ChatMsgSt, 0, h.count)
+	start := (h.next - h.count + len(h.buf)) % max(len(h.buf), 1)
+	for i := 0; i < h.count; i++ {
+		result = append(result, h.buf[(start+i)%len(h.buf)])
+	}
+	return result
+}
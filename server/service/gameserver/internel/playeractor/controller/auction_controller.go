@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"context"
+	"postapocgame/server/internal/network"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/service/gameserver/internel/gshare"
+	"postapocgame/server/service/gameserver/internel/playeractor/auction"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// AuctionController 拍卖行协议处理
+type AuctionController struct{}
+
+// NewAuctionController 创建拍卖行控制器
+func NewAuctionController() *AuctionController {
+	return &AuctionController{}
+}
+
+// HandleSell 处理 C2SAuctionSell 请求
+func (c *AuctionController) HandleSell(ctx context.Context, msg *network.ClientMessage) error {
+	var req protocol.C2SAuctionSellReq
+	if err := proto.Unmarshal(msg.Data, &req); err != nil {
+		return customerr.Wrap(err)
+	}
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	listing, err := auction.Sell(ctx, playerRole, &req)
+	if err != nil {
+		return err
+	}
+	return playerRole.SendProtoMessage(uint16(protocol.S2CProtocol_S2CAuctionSell), &protocol.S2CAuctionSellReq{Listing: listing})
+}
+
+// HandleSearch 处理 C2SAuctionSearch 请求
+func (c *AuctionController) HandleSearch(ctx context.Context, msg *network.ClientMessage) error {
+	var req protocol.C2SAuctionSearchReq
+	if err := proto.Unmarshal(msg.Data, &req); err != nil {
+		return customerr.Wrap(err)
+	}
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	resp, err := auction.Search(&req)
+	if err != nil {
+		return err
+	}
+	return playerRole.SendProtoMessage(uint16(protocol.S2CProtocol_S2CAuctionSearch), resp)
+}
+
+// HandleBuy 处理 C2SAuctionBuy 请求
+func (c *AuctionController) HandleBuy(ctx context.Context, msg *network.ClientMessage) error {
+	var req protocol.C2SAuctionBuyReq
+	if err := proto.Unmarshal(msg.Data, &req); err != nil {
+		return customerr.Wrap(err)
+	}
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	listing, err := auction.Buy(ctx, playerRole, req.ListingId)
+	if err != nil {
+		return err
+	}
+	return playerRole.SendProtoMessage(uint16(protocol.S2CProtocol_S2CAuctionBuy), &protocol.S2CAuctionBuyReq{Listing: listing})
+}
+
+// HandleCancel 处理 C2SAuctionCancel 请求
+func (c *AuctionController) HandleCancel(ctx context.Context, msg *network.ClientMessage) error {
+	var req protocol.C2SAuctionCancelReq
+	if err := proto.Unmarshal(msg.Data, &req); err != nil {
+		return customerr.Wrap(err)
+	}
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if err := auction.Cancel(playerRole, req.ListingId); err != nil {
+		return err
+	}
+	return playerRole.SendProtoMessage(uint16(protocol.S2CProtocol_S2CAuctionCancel), &protocol.S2CAuctionCancelReq{ListingId: req.ListingId})
+}
+
+// HandleMine 处理 C2SAuctionMine 请求
+func (c *AuctionController) HandleMine(ctx context.Context, _ *network.ClientMessage) error {
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	listings, err := auction.Mine(playerRole)
+	if err != nil {
+		return err
+	}
+	return playerRole.SendProtoMessage(uint16(protocol.S2CProtocol_S2CAuctionMine), &protocol.S2CAuctionMineReq{Listings: listings})
+}
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"context"
+	"postapocgame/server/internal/actor"
+	"postapocgame/server/internal/event"
+	"postapocgame/server/internal/network"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/gevent"
+	"postapocgame/server/service/gameserver/internel/gshare"
+	"postapocgame/server/service/gameserver/internel/playeractor/bag"
+	"postapocgame/server/service/gameserver/internel/playeractor/deps"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// BagController 背包协议处理
+type BagController struct{}
+
+// NewBagController 创建背包控制器
+func NewBagController() *BagController {
+	return &BagController{}
+}
+
+// HandleBagList 处理 C2SBagList 请求
+func (c *BagController) HandleBagList(ctx context.Context, _ *network.ClientMessage) error {
+	bagSys, err := getBagSys(ctx)
+	if err != nil {
+		return err
+	}
+	return bagSys.SendBagData(ctx)
+}
+
+// HandleUseItem 处理 C2SBagUseItem 请求
+func (c *BagController) HandleUseItem(ctx context.Context, msg *network.ClientMessage) error {
+	var req protocol.C2SBagUseItemReq
+	if err := proto.Unmarshal(msg.Data, &req); err != nil {
+		return customerr.Wrap(err)
+	}
+	bagSys, err := getBagSys(ctx)
+	if err != nil {
+		return err
+	}
+	return bagSys.UseItem(ctx, req.Slot, req.Count)
+}
+
+// HandleSplitItem 处理 C2SBagSplitItem 请求
+func (c *BagController) HandleSplitItem(ctx context.Context, msg *network.ClientMessage) error {
+	var req protocol.C2SBagSplitItemReq
+	if err := proto.Unmarshal(msg.Data, &req); err != nil {
+		return customerr.Wrap(err)
+	}
+	bagSys, err := getBagSys(ctx)
+	if err != nil {
+		return err
+	}
+	return bagSys.SplitItem(ctx, req.Slot, req.Count)
+}
+
+// HandleSort 处理 C2SBagSort 请求
+func (c *BagController) HandleSort(ctx context.Context, _ *network.ClientMessage) error {
+	bagSys, err := getBagSys(ctx)
+	if err != nil {
+		return err
+	}
+	return bagSys.SortBag(ctx)
+}
+
+// HandleDiscardItem 处理 C2SBagDiscardItem 请求
+func (c *BagController) HandleDiscardItem(ctx context.Context, msg *network.ClientMessage) error {
+	var req protocol.C2SBagDiscardItemReq
+	if err := proto.Unmarshal(msg.Data, &req); err != nil {
+		return customerr.Wrap(err)
+	}
+	bagSys, err := getBagSys(ctx)
+	if err != nil {
+		return err
+	}
+	return bagSys.DiscardItem(ctx, req.Slot, req.Count)
+}
+
+func getBagSys(ctx context.Context) (*bag.SystemAdapter, error) {
+	bagSys := bag.GetBagSys(ctx)
+	if bagSys == nil {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_System_NotEnabled), "bag system not opened")
+	}
+	return bagSys, nil
+}
+
+// HandleAddBuffResult 处理 DungeonActor 回传的道具加Buff结果，成功后扣除道具
+func HandleAddBuffResult(message actor.IActorMessage) {
+	var req protocol.PAMAddBuffResultReq
+	if err := proto.Unmarshal(message.GetData(), &req); err != nil {
+		log.Errorf("[player-bag] handleAddBuffResult: unmarshal failed: %v", err)
+		return
+	}
+
+	sessionID, _ := message.GetContext().Value(gshare.ContextKeySession).(string)
+	iPlayerRole := deps.GetPlayerRoleManager().GetBySession(sessionID)
+	if iPlayerRole == nil {
+		log.Warnf("[player-bag] handleAddBuffResult: player role not found, session=%s item=%d success=%v", sessionID, req.ItemId, req.Success)
+		return
+	}
+
+	roleCtx := iPlayerRole.WithContext(context.Background())
+	bagSys := bag.GetBagSys(roleCtx)
+	if bagSys == nil {
+		return
+	}
+	if err := bagSys.OnItemUseResult(roleCtx, req.ItemId, req.UseSeq, req.Success); err != nil {
+		log.Infof("[player-bag] handleAddBuffResult: item=%d err=%v", req.ItemId, err)
+		_ = iPlayerRole.SendProtoMessage(uint16(protocol.S2CProtocol_S2CError), &protocol.ErrorData{
+			Code: customerr.GetErrCode(err),
+			Msg:  customerr.GetErrMsgByErr(err),
+		})
+	}
+}
+
+func init() {
+	gevent.Subscribe(gevent.OnSrvStart, func(ctx context.Context, _ *event.Event) {
+		gshare.RegisterHandler(uint16(protocol.PlayerActorMsgId_PAMAddBuffResult), HandleAddBuffResult)
+	})
+}
@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"context"
+	"postapocgame/server/internal/network"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/service/gameserver/internel/gshare"
+	"postapocgame/server/service/gameserver/internel/playeractor/chat"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ChatController 聊天协议处理
+type ChatController struct{}
+
+// NewChatController 创建聊天控制器
+func NewChatController() *ChatController {
+	return &ChatController{}
+}
+
+// HandleSend 处理 C2SChatSend 请求，消息经 S2CChatMessage 下发给频道内的角色
+func (c *ChatController) HandleSend(ctx context.Context, msg *network.ClientMessage) error {
+	var req protocol.C2SChatSendReq
+	if err := proto.Unmarshal(msg.Data, &req); err != nil {
+		return customerr.Wrap(err)
+	}
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	return chat.Send(ctx, playerRole, &req)
+}
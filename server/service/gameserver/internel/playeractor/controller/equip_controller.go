@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"context"
+	"postapocgame/server/internal/network"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/service/gameserver/internel/playeractor/equip"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// EquipController 装备协议处理
+type EquipController struct{}
+
+// NewEquipController 创建装备控制器
+func NewEquipController() *EquipController {
+	return &EquipController{}
+}
+
+// HandleWear 处理 C2SEquipWear 请求
+func (c *EquipController) HandleWear(ctx context.Context, msg *network.ClientMessage) error {
+	var req protocol.C2SEquipWearReq
+	if err := proto.Unmarshal(msg.Data, &req); err != nil {
+		return customerr.Wrap(err)
+	}
+	equipSys, err := getEquipSys(ctx)
+	if err != nil {
+		return err
+	}
+	return equipSys.Wear(ctx, req.BagSlot)
+}
+
+// HandleTakeOff 处理 C2SEquipTakeOff 请求
+func (c *EquipController) HandleTakeOff(ctx context.Context, msg *network.ClientMessage) error {
+	var req protocol.C2SEquipTakeOffReq
+	if err := proto.Unmarshal(msg.Data, &req); err != nil {
+		return customerr.Wrap(err)
+	}
+	equipSys, err := getEquipSys(ctx)
+	if err != nil {
+		return err
+	}
+	return equipSys.TakeOff(ctx, req.EquipSlot)
+}
+
+func getEquipSys(ctx context.Context) (*equip.SystemAdapter, error) {
+	equipSys := equip.GetEquipSys(ctx)
+	if equipSys == nil {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_System_NotEnabled), "equip system not opened")
+	}
+	return equipSys, nil
+}
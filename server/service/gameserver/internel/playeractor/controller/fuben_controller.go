@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"context"
+	"postapocgame/server/internal/actor"
+	"postapocgame/server/internal/network"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/service/gameserver/internel/gshare"
+)
+
+// FuBenController 负责将客户端副本进出与场景切换请求转发给 DungeonActor
+type FuBenController struct{}
+
+// NewFuBenController 创建副本控制器
+func NewFuBenController() *FuBenController {
+	return &FuBenController{}
+}
+
+// HandleEnterFuBen 处理 C2SEnterFuBen 请求
+func (c *FuBenController) HandleEnterFuBen(ctx context.Context, msg *network.ClientMessage) error {
+	return c.forward(ctx, protocol.DungeonActorMsgId_DAMEnterFuBen, msg)
+}
+
+// HandleLeaveFuBen 处理 C2SLeaveFuBen 请求
+func (c *FuBenController) HandleLeaveFuBen(ctx context.Context, msg *network.ClientMessage) error {
+	return c.forward(ctx, protocol.DungeonActorMsgId_DAMLeaveFuBen, msg)
+}
+
+// HandleTransferScene 处理 C2STransferScene 请求
+func (c *FuBenController) HandleTransferScene(ctx context.Context, msg *network.ClientMessage) error {
+	return c.forward(ctx, protocol.DungeonActorMsgId_DAMTransferScene, msg)
+}
+
+func (c *FuBenController) forward(ctx context.Context, msgId protocol.DungeonActorMsgId, msg *network.ClientMessage) error {
+	sessionID, err := gshare.GetSessionIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	ctxWithSession := context.WithValue(ctx, gshare.ContextKeySession, sessionID)
+	actorMsg := actor.NewBaseMessage(ctxWithSession, uint16(msgId), msg.Data)
+	return gshare.SendDungeonMessageAsync("global", actorMsg)
+}
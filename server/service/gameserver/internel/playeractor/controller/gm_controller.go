@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"context"
+	"postapocgame/server/internal/network"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/service/gameserver/internel/gshare"
+	"postapocgame/server/service/gameserver/internel/playeractor/gm"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// GmController GM 指令协议处理
+type GmController struct{}
+
+// NewGmController 创建 GM 控制器
+func NewGmController() *GmController {
+	return &GmController{}
+}
+
+// HandleGmCommand 处理 C2SGmCommand 请求，失败时由路由下发 S2CError
+func (c *GmController) HandleGmCommand(ctx context.Context, msg *network.ClientMessage) error {
+	var req protocol.C2SGmCommandReq
+	if err := proto.Unmarshal(msg.Data, &req); err != nil {
+		return customerr.Wrap(err)
+	}
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	result, err := gm.Execute(ctx, playerRole, req.Name, req.Args)
+	if err != nil {
+		return err
+	}
+	return playerRole.SendProtoMessage(uint16(protocol.S2CProtocol_S2CGmCommand), &protocol.S2CGmCommandReq{
+		Name:    req.Name,
+		Success: true,
+		Msg:     result,
+	})
+}
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"context"
+	"postapocgame/server/internal/actor"
+	"postapocgame/server/internal/event"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/gevent"
+	"postapocgame/server/service/gameserver/internel/gshare"
+	"postapocgame/server/service/gameserver/internel/playeractor/deps"
+	"postapocgame/server/service/gameserver/internel/playeractor/level"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// HandleAddExp 处理 DungeonActor 结算的经验
+func HandleAddExp(message actor.IActorMessage) {
+	var req protocol.PAMAddExpReq
+	if err := proto.Unmarshal(message.GetData(), &req); err != nil {
+		log.Errorf("[player-level] handleAddExp: unmarshal failed: %v", err)
+		return
+	}
+	if req.Exp <= 0 {
+		return
+	}
+
+	sessionID, _ := message.GetContext().Value(gshare.ContextKeySession).(string)
+	iPlayerRole := deps.GetPlayerRoleManager().GetBySession(sessionID)
+	if iPlayerRole == nil {
+		log.Warnf("[player-level] handleAddExp: player role not found, session=%s", sessionID)
+		return
+	}
+
+	roleCtx := iPlayerRole.WithContext(context.Background())
+	levelSys := level.GetLevelSys(roleCtx)
+	if levelSys == nil {
+		return
+	}
+	if err := levelSys.AddExp(roleCtx, uint64(req.Exp)); err != nil {
+		log.Errorf("[player-level] handleAddExp: add exp failed: %v", err)
+	}
+}
+
+func init() {
+	gevent.Subscribe(gevent.OnSrvStart, func(ctx context.Context, _ *event.Event) {
+		gshare.RegisterHandler(uint16(protocol.PlayerActorMsgId_PAMAddExp), HandleAddExp)
+	})
+}
@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"context"
+	"postapocgame/server/internal/network"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/service/gameserver/internel/gshare"
+	"postapocgame/server/service/gameserver/internel/playeractor/mail"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// MailController 邮件协议处理
+type MailController struct{}
+
+// NewMailController 创建邮件控制器
+func NewMailController() *MailController {
+	return &MailController{}
+}
+
+// HandleList 处理 C2SMailList 请求
+func (c *MailController) HandleList(ctx context.Context, _ *network.ClientMessage) error {
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	mails, err := mail.List(playerRole)
+	if err != nil {
+		return err
+	}
+	return playerRole.SendProtoMessage(uint16(protocol.S2CProtocol_S2CMailList), &protocol.S2CMailListReq{Mails: mails})
+}
+
+// HandleRead 处理 C2SMailRead 请求
+func (c *MailController) HandleRead(ctx context.Context, msg *network.ClientMessage) error {
+	var req protocol.C2SMailReadReq
+	if err := proto.Unmarshal(msg.Data, &req); err != nil {
+		return customerr.Wrap(err)
+	}
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	st, err := mail.Read(playerRole, req.MailId)
+	if err != nil {
+		return err
+	}
+	return playerRole.SendProtoMessage(uint16(protocol.S2CProtocol_S2CMailRead), &protocol.S2CMailReadReq{Mail: st})
+}
+
+// HandleClaim 处理 C2SMailClaim 请求
+func (c *MailController) HandleClaim(ctx context.Context, msg *network.ClientMessage) error {
+	var req protocol.C2SMailClaimReq
+	if err := proto.Unmarshal(msg.Data, &req); err != nil {
+		return customerr.Wrap(err)
+	}
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	items, err := mail.Claim(ctx, playerRole, req.MailId)
+	if err != nil {
+		return err
+	}
+	return playerRole.SendProtoMessage(uint16(protocol.S2CProtocol_S2CMailClaim), &protocol.S2CMailClaimReq{MailIds: []uint64{req.MailId}, Items: items})
+}
+
+// HandleClaimAll 处理 C2SMailClaimAll 请求
+func (c *MailController) HandleClaimAll(ctx context.Context, _ *network.ClientMessage) error {
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	mailIds, items, err := mail.ClaimAll(ctx, playerRole)
+	if err != nil {
+		return err
+	}
+	return playerRole.SendProtoMessage(uint16(protocol.S2CProtocol_S2CMailClaim), &protocol.S2CMailClaimReq{MailIds: mailIds, Items: items})
+}
+
+// HandleDelete 处理 C2SMailDelete 请求
+func (c *MailController) HandleDelete(ctx context.Context, msg *network.ClientMessage) error {
+	var req protocol.C2SMailDeleteReq
+	if err := proto.Unmarshal(msg.Data, &req); err != nil {
+		return customerr.Wrap(err)
+	}
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	mailIds, err := mail.Delete(playerRole, req.MailIds)
+	if err != nil {
+		return err
+	}
+	return playerRole.SendProtoMessage(uint16(protocol.S2CProtocol_S2CMailDelete), &protocol.S2CMailDeleteReq{MailIds: mailIds})
+}
+
+// HandleSend 处理 C2SMailSend 请求
+func (c *MailController) HandleSend(ctx context.Context, msg *network.ClientMessage) error {
+	var req protocol.C2SMailSendReq
+	if err := proto.Unmarshal(msg.Data, &req); err != nil {
+		return customerr.Wrap(err)
+	}
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	mailId, err := mail.Send(ctx, playerRole, &req)
+	if err != nil {
+		return err
+	}
+	return playerRole.SendProtoMessage(uint16(protocol.S2CProtocol_S2CMailSend), &protocol.S2CMailSendReq{MailId: mailId})
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"context"
+	"postapocgame/server/internal/actor"
+	"postapocgame/server/internal/event"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/service/gameserver/internel/gevent"
+	"postapocgame/server/service/gameserver/internel/gshare"
+	"postapocgame/server/service/gameserver/internel/iface"
+	"postapocgame/server/service/gameserver/internel/playeractor/deps"
+	"postapocgame/server/service/gameserver/internel/playeractor/entity"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// HandleOpsMute 运维接口禁言：在角色 Actor 内更新内存中的禁言状态（持久化由运维接口完成）
+func HandleOpsMute(message actor.IActorMessage) {
+	msgCtx := message.GetContext()
+	var req protocol.PAMOpsMuteReq
+	if err := proto.Unmarshal(message.GetData(), &req); err != nil {
+		gshare.Reply(msgCtx, nil, customerr.Wrap(err))
+		return
+	}
+	playerRole, err := opsRoleFromContext(msgCtx)
+	if err != nil {
+		gshare.Reply(msgCtx, nil, err)
+		return
+	}
+	playerRole.SetMuteUntil(req.MuteUntil)
+	gshare.Reply(msgCtx, nil, nil)
+}
+
+// HandleOpsSnapshot 运维接口查看角色：在角色 Actor 内序列化 PlayerRoleData，避免与业务逻辑并发读写
+func HandleOpsSnapshot(message actor.IActorMessage) {
+	msgCtx := message.GetContext()
+	playerRole, err := opsRoleFromContext(msgCtx)
+	if err != nil {
+		gshare.Reply(msgCtx, nil, err)
+		return
+	}
+	roleData := &protocol.PlayerRoleData{BinaryData: playerRole.GetBinaryData()}
+	if pr, ok := playerRole.(*entity.PlayerRole); ok {
+		roleData.MainData = pr.MainData
+	}
+	data, err := proto.Marshal(roleData)
+	gshare.Reply(msgCtx, data, customerr.Wrap(err))
+}
+
+// HandleOpsSummary 运维接口在线列表：在角色 Actor 内序列化 PlayerSimpleData，等级等字段由 Actor 内的系统修改
+func HandleOpsSummary(message actor.IActorMessage) {
+	msgCtx := message.GetContext()
+	playerRole, err := opsRoleFromContext(msgCtx)
+	if err != nil {
+		gshare.Reply(msgCtx, nil, err)
+		return
+	}
+	data, err := proto.Marshal(playerRole.GetPlayerSimpleData())
+	gshare.Reply(msgCtx, data, customerr.Wrap(err))
+}
+
+func opsRoleFromContext(ctx context.Context) (iface.IPlayerRole, error) {
+	sessionId, err := sessionIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	playerRole := deps.GetPlayerRoleManager().GetBySession(sessionId)
+	if playerRole == nil {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Player_NotFound), "role of session %s is offline", sessionId)
+	}
+	return playerRole, nil
+}
+
+func init() {
+	gevent.Subscribe(gevent.OnSrvStart, func(ctx context.Context, _ *event.Event) {
+		gshare.RegisterHandler(uint16(protocol.PlayerActorMsgId_PAMOpsMute), HandleOpsMute)
+		gshare.RegisterHandler(uint16(protocol.PlayerActorMsgId_PAMOpsSnapshot), HandleOpsSnapshot)
+		gshare.RegisterHandler(uint16(protocol.PlayerActorMsgId_PAMOpsSummary), HandleOpsSummary)
+	})
+}
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"context"
+	"postapocgame/server/internal/actor"
+	"postapocgame/server/internal/event"
+	"postapocgame/server/internal/network"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/gevent"
+	"postapocgame/server/service/gameserver/internel/gshare"
+	"postapocgame/server/service/gameserver/internel/playeractor/bag"
+	"postapocgame/server/service/gameserver/internel/playeractor/deps"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// PickUpController 负责将客户端拾取请求转发给 DungeonActor
+type PickUpController struct{}
+
+// NewPickUpController 创建拾取控制器
+func NewPickUpController() *PickUpController {
+	return &PickUpController{}
+}
+
+// HandlePickUp 处理 C2SPickUp 请求
+func (c *PickUpController) HandlePickUp(ctx context.Context, msg *network.ClientMessage) error {
+	sessionID, err := gshare.GetSessionIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(msg.Data) == 0 {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "empty C2SPickUp payload")
+	}
+
+	ctxWithSession := context.WithValue(ctx, gshare.ContextKeySession, sessionID)
+	actorMsg := actor.NewBaseMessage(ctxWithSession, uint16(protocol.DungeonActorMsgId_DAMPickUp), msg.Data)
+	return gshare.SendDungeonMessageAsync("global", actorMsg)
+}
+
+// HandlePickUpItem 处理 DungeonActor 锁定的掉落物入包
+// 无论成功与否都必须回复 DungeonActor，失败时掉落物解锁留在地面
+func HandlePickUpItem(message actor.IActorMessage) {
+	var req protocol.PAMPickUpReq
+	if err := proto.Unmarshal(message.GetData(), &req); err != nil {
+		log.Errorf("[player-pickup] handlePickUpItem: unmarshal failed: %v", err)
+		return
+	}
+
+	sessionID, _ := message.GetContext().Value(gshare.ContextKeySession).(string)
+	success := pickUpItem(sessionID, &req)
+
+	payload, err := proto.Marshal(&protocol.DAMPickUpResultReq{DropHdl: req.DropHdl, Success: success, FbId: req.FbId})
+	if err != nil {
+		log.Errorf("[player-pickup] handlePickUpItem: marshal result failed: %v", err)
+		return
+	}
+	ctx := context.WithValue(context.Background(), gshare.ContextKeySession, sessionID)
+	actorMsg := actor.NewBaseMessage(ctx, uint16(protocol.DungeonActorMsgId_DAMPickUpResult), payload)
+	if err := gshare.SendDungeonMessageAsync("global", actorMsg); err != nil {
+		log.Errorf("[player-pickup] handlePickUpItem: send result failed: drop=%d success=%v err=%v", req.DropHdl, success, err)
+	}
+}
+
+func pickUpItem(sessionID string, req *protocol.PAMPickUpReq) bool {
+	if req.Item == nil {
+		return false
+	}
+	iPlayerRole := deps.GetPlayerRoleManager().GetBySession(sessionID)
+	if iPlayerRole == nil {
+		log.Warnf("[player-pickup] pickUpItem: player role not found, session=%s", sessionID)
+		return false
+	}
+
+	roleCtx := iPlayerRole.WithContext(context.Background())
+	bagSys := bag.GetBagSys(roleCtx)
+	if bagSys == nil {
+		return false
+	}
+	if err := bagSys.AddItems(roleCtx, []*protocol.ItemAmount{req.Item}); err != nil {
+		log.Infof("[player-pickup] pickUpItem: add item failed: drop=%d err=%v", req.DropHdl, err)
+		_ = iPlayerRole.SendProtoMessage(uint16(protocol.S2CProtocol_S2CError), &protocol.ErrorData{
+			Code: customerr.GetErrCode(err),
+			Msg:  customerr.GetErrMsgByErr(err),
+		})
+		return false
+	}
+
+	resp := &protocol.S2CPickUpReq{
+		DropHdl: req.DropHdl,
+		ItemId:  req.Item.ItemId,
+		Count:   uint32(req.Item.Count),
+	}
+	if err := iPlayerRole.SendProtoMessage(uint16(protocol.S2CProtocol_S2CPickUp), resp); err != nil {
+		log.Warnf("[player-pickup] pickUpItem: send pick up failed: %v", err)
+	}
+	return true
+}
+
+func init() {
+	gevent.Subscribe(gevent.OnSrvStart, func(ctx context.Context, _ *event.Event) {
+		gshare.RegisterHandler(uint16(protocol.PlayerActorMsgId_PAMPickUp), HandlePickUpItem)
+	})
+}
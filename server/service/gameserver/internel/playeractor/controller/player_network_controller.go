@@ -7,6 +7,8 @@ import (
 	"postapocgame/server/service/gameserver/internel/gevent"
 	"postapocgame/server/service/gameserver/internel/playeractor/deps"
 	"postapocgame/server/service/gameserver/internel/playeractor/entity"
+	"postapocgame/server/service/gameserver/internel/playeractor/equip"
+	"postapocgame/server/service/gameserver/internel/playeractor/level"
 	"postapocgame/server/service/gameserver/internel/playeractor/router"
 	"postapocgame/server/service/gameserver/internel/playeractor/skill"
 
@@ -15,8 +17,10 @@ import (
 	"postapocgame/server/internal/protocol"
 	"postapocgame/server/pkg/customerr"
 	"postapocgame/server/pkg/log"
+	"postapocgame/server/pkg/routine"
 	"postapocgame/server/service/gameserver/internel/gshare"
 	"postapocgame/server/service/gameserver/internel/iface"
+	"time"
 
 	"google.golang.org/protobuf/proto"
 )
@@ -76,12 +80,14 @@ func (d enterGameDeps) enterGame(ctx context.Context, sessionId string, req *pro
 		Sex:      role.Sex,
 		RoleName: role.RoleName,
 		Level:    role.Level,
+		GmLevel:  role.GmLevel,
 	}
 
 	playerRole := entity.NewPlayerRole(sessionId, selectedRole)
 	if playerRole == nil {
 		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "create player role failed")
 	}
+	playerRole.SetMuteUntil(role.MuteUntil)
 
 	d.roleMgr.Add(playerRole)
 	session.SetRoleId(playerRole.GetPlayerRoleId())
@@ -90,6 +96,12 @@ func (d enterGameDeps) enterGame(ctx context.Context, sessionId string, req *pro
 		return customerr.Wrap(err)
 	}
 
+	return enterDungeon(playerRole)
+}
+
+// enterDungeon 收集战斗所需的角色数据，通知 DungeonActor 创建玩家实体
+func enterDungeon(playerRole *entity.PlayerRole) error {
+	sessionId := playerRole.GetSessionId()
 	roleCtx := playerRole.WithContext(context.Background())
 	skillSys := skill.GetSkillSys(roleCtx)
 	var skillMap = make(map[uint32]uint32)
@@ -99,11 +111,24 @@ func (d enterGameDeps) enterGame(ctx context.Context, sessionId string, req *pro
 		}
 	}
 
+	var equipAttrs map[uint32]int64
+	var appearance map[uint32]uint32
+	if equipSys := equip.GetEquipSys(roleCtx); equipSys != nil {
+		equipAttrs = equipSys.GetEquipAttrs(roleCtx)
+		appearance = equipSys.GetAppearance(roleCtx)
+	}
+
+	roleLevel := playerRole.GetPlayerSimpleData().Level
 	reqData, err := proto.Marshal(&protocol.DAMEnterGameReq{
 		SessionId:  sessionId,
+		Role:       playerRole.GetPlayerSimpleData(),
 		PlatformId: gshare.GetPlatformId(),
 		SrvId:      gshare.GetSrvId(),
 		SkillMap:   skillMap,
+		Level:      roleLevel,
+		Attrs:      level.BuildLevelAttrs(roleLevel),
+		EquipAttrs: equipAttrs,
+		Appearance: appearance,
 	})
 	if err != nil {
 		return customerr.Wrap(err)
@@ -131,6 +156,178 @@ func HandleRunOneMsg(message actor.IActorMessage) {
 	}
 }
 
+// HandleTimeSync 定时下发服务器时间
+func HandleTimeSync(message actor.IActorMessage) {
+	sessionId, err := sessionIDFromContext(message.GetContext())
+	if err != nil {
+		return
+	}
+	if iPlayerRole := deps.GetPlayerRoleManager().GetBySession(sessionId); iPlayerRole != nil {
+		iPlayerRole.TimeSync()
+	}
+}
+
+// HandleSaveData 定时存盘
+func HandleSaveData(message actor.IActorMessage) {
+	sessionId, err := sessionIDFromContext(message.GetContext())
+	if err != nil {
+		return
+	}
+	if iPlayerRole := deps.GetPlayerRoleManager().GetBySession(sessionId); iPlayerRole != nil {
+		if err := iPlayerRole.SaveToDB(); err != nil {
+			log.Errorf("[player-network] save player data failed: roleId=%d err=%v", iPlayerRole.GetPlayerRoleId(), err)
+		}
+	}
+}
+
+// HandleReEnterDungeon DungeonServer 链路重连后重新进入 DungeonActor
+func HandleReEnterDungeon(message actor.IActorMessage) {
+	sessionId, err := sessionIDFromContext(message.GetContext())
+	if err != nil {
+		return
+	}
+	playerRole, ok := deps.GetPlayerRoleManager().GetBySession(sessionId).(*entity.PlayerRole)
+	if !ok || playerRole == nil {
+		return
+	}
+	if err := enterDungeon(playerRole); err != nil {
+		log.Errorf("[player-network] re-enter dungeon failed: roleId=%d err=%v", playerRole.GetPlayerRoleId(), err)
+	}
+}
+
+// HandleLogout 会话关闭后执行断线与登出清理，然后移除玩家Actor
+// 移除Actor会等待 Actor goroutine 退出，不能在 Actor 内同步调用
+func HandleLogout(message actor.IActorMessage) {
+	sessionId, err := sessionIDFromContext(message.GetContext())
+	if err != nil {
+		return
+	}
+	if playerRole := deps.GetPlayerRoleManager().GetBySession(sessionId); playerRole != nil {
+		playerRole.OnDisconnect()
+		if err := playerRole.Close(); err != nil {
+			log.Errorf("[player-network] player close on logout failed: roleId=%d err=%v", playerRole.GetPlayerRoleId(), err)
+		}
+		deps.GetPlayerRoleManager().Remove(playerRole.GetPlayerRoleId())
+	}
+	routine.GoV2(func() error {
+		return gshare.RemoveActor(sessionId)
+	})
+}
+
+// resumeSessionTimeout 等待 Gateway 完成会话接管与消息补发的最长时间
+const resumeSessionTimeout = 5 * time.Second
+
+// HandleReconnect 断线重连：新连接凭 reconnect_key 接管仍在 Gateway 保留期内的角色
+// 角色由原会话的 Actor 持有，校验通过后转交原会话 Actor 完成交接
+func HandleReconnect(ctx context.Context, msg *network.ClientMessage) error {
+	var req protocol.C2SReconnectReq
+	if err := proto.Unmarshal(msg.Data, &req); err != nil {
+		return customerr.Wrap(err)
+	}
+
+	sessionId, err := sessionIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	roleMgr := deps.GetPlayerRoleManager()
+	if roleMgr.GetBySession(sessionId) != nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Player_ReconnectFailed), "session already in game")
+	}
+	playerRole := roleMgr.GetByReconnectKey(req.GetReconnectKey())
+	if playerRole == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Player_ReconnectFailed), "reconnect key invalid or expired")
+	}
+	oldSessionId := playerRole.GetSessionId()
+	if oldSessionId == sessionId {
+		return nil
+	}
+
+	data, err := proto.Marshal(&protocol.PAMResumeSessionReq{NewSessionId: sessionId})
+	if err != nil {
+		return customerr.Wrap(err)
+	}
+	resumeCtx := context.WithValue(context.Background(), gshare.ContextKeySession, oldSessionId)
+	message := actor.NewBaseMessage(resumeCtx, uint16(protocol.PlayerActorMsgId_PAMResumeSession), data)
+	if err := gshare.SendMessageAsync(oldSessionId, message); err != nil {
+		return customerr.Wrap(err, int32(protocol.ErrorCode_Player_ReconnectFailed))
+	}
+	return nil
+}
+
+// HandleResumeSession 在角色原会话的 Actor 内完成重连交接，交接后移除原会话 Actor
+// 顺序：Gateway 补发断线期间缓存的消息 → DungeonActor 实体换绑 → 角色切换会话并下发登录结果 → 更新会话索引
+// 更新索引之前两个会话都查不到该角色，新会话的 Actor 不会与这里并发访问角色
+func HandleResumeSession(message actor.IActorMessage) {
+	oldSessionId, err := sessionIDFromContext(message.GetContext())
+	if err != nil {
+		return
+	}
+	var req protocol.PAMResumeSessionReq
+	if err := proto.Unmarshal(message.GetData(), &req); err != nil {
+		log.Errorf("[player-network] resume session: unmarshal failed: %v", err)
+		return
+	}
+	newSessionId := req.GetNewSessionId()
+
+	roleMgr := deps.GetPlayerRoleManager()
+	playerRole, ok := roleMgr.GetBySession(oldSessionId).(*entity.PlayerRole)
+	if !ok || playerRole == nil {
+		sendReconnectFailed(newSessionId, customerr.NewErrorByCode(int32(protocol.ErrorCode_Player_ReconnectFailed), "role already logged out"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), resumeSessionTimeout)
+	defer cancel()
+	if err := gatewaylink.ResumeSession(ctx, oldSessionId, newSessionId); err != nil {
+		log.Warnf("[player-network] resume session failed: roleId=%d %s -> %s err=%v", playerRole.GetPlayerRoleId(), oldSessionId, newSessionId, err)
+		sendReconnectFailed(newSessionId, err)
+		return
+	}
+
+	// 实体换绑失败时在切换会话后重新进入 DungeonActor
+	rebound := true
+	rebindData, err := proto.Marshal(&protocol.DAMRebindSessionReq{NewSessionId: newSessionId})
+	if err == nil {
+		err = playerRole.CallDungeonActor(context.Background(), uint16(protocol.DungeonActorMsgId_DAMRebindSession), rebindData)
+	}
+	if err != nil {
+		log.Warnf("[player-network] rebind dungeon session failed: roleId=%d err=%v", playerRole.GetPlayerRoleId(), err)
+		rebound = false
+	}
+
+	gatewaylink.TransferSession(oldSessionId, newSessionId)
+	playerRole.RebindSession(newSessionId)
+	if !rebound {
+		if err := enterDungeon(playerRole); err != nil {
+			log.Errorf("[player-network] re-enter dungeon after resume failed: roleId=%d err=%v", playerRole.GetPlayerRoleId(), err)
+		}
+	}
+	resp := &protocol.S2CLoginRoleReq{
+		ReconnectKey: playerRole.GetReconnectKey(),
+		RoleData:     playerRole.GetPlayerSimpleData(),
+	}
+	if err := playerRole.SendProtoMessage(uint16(protocol.S2CProtocol_S2CLoginRole), resp); err != nil {
+		log.Warnf("[player-network] send resume result failed: roleId=%d err=%v", playerRole.GetPlayerRoleId(), err)
+	}
+	roleMgr.UpdateSession(playerRole.GetPlayerRoleId(), oldSessionId, newSessionId)
+
+	log.Infof("[player-network] role %d resumed: %s -> %s", playerRole.GetPlayerRoleId(), oldSessionId, newSessionId)
+	routine.GoV2(func() error {
+		return gshare.RemoveActor(oldSessionId)
+	})
+}
+
+func sendReconnectFailed(sessionId string, err error) {
+	resp := &protocol.ErrorData{
+		Code: customerr.GetErrCode(err),
+		Msg:  customerr.GetErrMsgByErr(err),
+	}
+	if sErr := gatewaylink.SendToSessionProto(sessionId, uint16(protocol.S2CProtocol_S2CError), resp); sErr != nil {
+		log.Errorf("[player-network] send reconnect error failed: session=%s err=%v", sessionId, sErr)
+	}
+}
+
 // HandleSendToClient 统一的 S2C 透传
 func HandleSendToClient(message actor.IActorMessage) {
 	var req protocol.PAMSendToClientReq
@@ -156,7 +353,13 @@ func init() {
 		gshare.RegisterHandler(uint16(protocol.PlayerActorMsgId_PAMNetworkMsg), protocolRouter.HandleDoNetworkMsg)
 		gshare.RegisterHandler(uint16(protocol.PlayerActorMsgId_PAMRunOneMsg), HandleRunOneMsg)
 		gshare.RegisterHandler(uint16(protocol.PlayerActorMsgId_PAMSendToClient), HandleSendToClient)
+		gshare.RegisterHandler(uint16(protocol.PlayerActorMsgId_PAMReEnterDungeon), HandleReEnterDungeon)
+		gshare.RegisterHandler(uint16(protocol.PlayerActorMsgId_PAMLogout), HandleLogout)
+		gshare.RegisterHandler(uint16(protocol.PlayerActorMsgId_PAMTimeSync), HandleTimeSync)
+		gshare.RegisterHandler(uint16(protocol.PlayerActorMsgId_PAMSaveData), HandleSaveData)
+		gshare.RegisterHandler(uint16(protocol.PlayerActorMsgId_PAMResumeSession), HandleResumeSession)
 		router.RegisterProtocolHandler(uint16(protocol.C2SProtocol_C2SEnterGame), HandleEnterGame)
+		router.RegisterProtocolHandler(uint16(protocol.C2SProtocol_C2SReconnect), HandleReconnect)
 	})
 }
 
@@ -6,6 +6,8 @@ import "golang.org/x/crypto/bcrypt"
 type Account struct {
 	ID           uint64
 	Username     string
+	BanUntil     int64 // 封禁截止时间（秒），0 未封禁，-1 永久封禁
+	BanReason    string
 	passwordHash string
 }
 
@@ -22,3 +24,8 @@ func NewAccount(id uint64, username string, passwordHash string) *Account {
 func (a *Account) CheckPassword(password string) bool {
 	return bcrypt.CompareHashAndPassword([]byte(a.passwordHash), []byte(password)) == nil
 }
+
+// IsBanned 账号在 now（秒）时是否处于封禁期
+func (a *Account) IsBanned(now int64) bool {
+	return a.BanUntil < 0 || a.BanUntil > now
+}
@@ -8,4 +8,6 @@ type Role struct {
 	Job       uint32
 	Sex       uint32
 	Level     uint32
+	GmLevel   uint32
+	MuteUntil int64 // 禁言截止时间（秒），0 未禁言，-1 永久禁言
 }
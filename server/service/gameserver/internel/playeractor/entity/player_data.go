@@ -27,3 +27,25 @@ func (pr *PlayerRole) GetSkillData() *protocol.SiSkillData {
 	}
 	return data.SkillData
 }
+
+func (pr *PlayerRole) GetBagData() *protocol.SiBagData {
+	data := pr.GetBinaryData()
+	if data.BagData == nil {
+		data.BagData = &protocol.SiBagData{}
+	}
+	if data.BagData.Items == nil {
+		data.BagData.Items = make(map[uint32]*protocol.BagItemSt)
+	}
+	return data.BagData
+}
+
+func (pr *PlayerRole) GetEquipData() *protocol.SiEquipData {
+	data := pr.GetBinaryData()
+	if data.EquipData == nil {
+		data.EquipData = &protocol.SiEquipData{}
+	}
+	if data.EquipData.Equips == nil {
+		data.EquipData.Equips = make(map[uint32]*protocol.EquipSt)
+	}
+	return data.EquipData
+}
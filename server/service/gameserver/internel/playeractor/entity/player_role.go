@@ -15,6 +15,7 @@ import (
 	"postapocgame/server/service/gameserver/internel/iface"
 	"postapocgame/server/service/gameserver/internel/playeractor/deps"
 	"postapocgame/server/service/gameserver/internel/playeractor/entitysystem"
+	"sync/atomic"
 	"time"
 
 	"google.golang.org/protobuf/proto"
@@ -23,7 +24,7 @@ import (
 // PlayerRole 玩家角色
 type PlayerRole struct {
 	// 基础信息
-	SessionId  string
+	sessionId  atomic.Pointer[string] // 当前会话ID，RebindSession 在 Actor 内切换，聊天、邮件等其他协程经 GetSessionId 读取
 	SimpleData *protocol.PlayerSimpleData
 	MainData   *protocol.PlayerRoleMainData
 	BinaryData *protocol.PlayerRoleBinaryData
@@ -33,6 +34,9 @@ type PlayerRole struct {
 	IsOnline     bool
 	DisconnectAt time.Time
 
+	// 禁言截止时间（秒），0 未禁言，-1 永久禁言
+	muteUntil int64
+
 	// 事件总线（每个玩家独立的事件总线）
 	eventBus *event.Bus
 
@@ -40,10 +44,8 @@ type PlayerRole struct {
 	runtime *deps.Runtime
 
 	// 系统管理器
-	sysMgr       iface.ISystemMgr
-	_1sChecker   *tool.TimeChecker
-	_5minChecker *tool.TimeChecker
-	timeCursor   timeCursorMark
+	sysMgr     iface.ISystemMgr
+	timeCursor timeCursorMark
 }
 
 type timeCursorMark struct {
@@ -58,7 +60,6 @@ type timeCursorMark struct {
 // NewPlayerRole 创建玩家角色
 func NewPlayerRole(sessionId string, roleInfo *protocol.PlayerSimpleData) *PlayerRole {
 	pr := &PlayerRole{
-		SessionId:    sessionId,
 		SimpleData:   roleInfo,
 		IsOnline:     true,
 		ReconnectKey: generateReconnectKey(sessionId, roleInfo.RoleId),
@@ -72,6 +73,7 @@ func NewPlayerRole(sessionId string, roleInfo *protocol.PlayerSimpleData) *Playe
 			deps.NewDungeonServerGateway(),
 		),
 	}
+	pr.sessionId.Store(&sessionId)
 	// 创建系统管理器
 	pr.sysMgr = entitysystem.NewSysMgr()
 
@@ -112,8 +114,6 @@ func NewPlayerRole(sessionId string, roleInfo *protocol.PlayerSimpleData) *Playe
 		log.Errorf("sys mgr on init failed, err:%v", err)
 	}
 
-	pr._1sChecker = tool.NewTimeChecker(time.Second)
-	pr._5minChecker = tool.NewTimeChecker(5 * time.Minute)
 	pr.timeCursor = newTimeCursorMark(servertime.Now())
 
 	return pr
@@ -121,7 +121,7 @@ func NewPlayerRole(sessionId string, roleInfo *protocol.PlayerSimpleData) *Playe
 
 // OnLogin 登录回调
 func (pr *PlayerRole) OnLogin() error {
-	log.Infof("[PlayerRole] OnLogin: RoleId=%d, SessionId=%s", pr.SimpleData.RoleId, pr.SessionId)
+	log.Infof("[PlayerRole] OnLogin: RoleId=%d, SessionId=%s", pr.SimpleData.RoleId, pr.GetSessionId())
 
 	pr.IsOnline = true
 	pr.DisconnectAt = time.Time{}
@@ -148,6 +148,14 @@ func (pr *PlayerRole) OnLogout() error {
 	pr.IsOnline = false
 	pr.touchLogoutTime(servertime.Now())
 
+	// 发布玩家登出事件
+	pr.Publish(gevent.OnPlayerLogout)
+
+	// 通知 DungeonActor 移除玩家实体
+	if err := pr.CallDungeonActor(context.Background(), uint16(protocol.DungeonActorMsgId_DAMLeaveGame), nil); err != nil {
+		log.Warnf("notify dungeon leave game failed: roleId=%d err=%v", pr.SimpleData.RoleId, err)
+	}
+
 	// 保存BinaryData到数据库
 	if pr.BinaryData != nil {
 		if err := database.SavePlayerBinaryData(uint(pr.SimpleData.RoleId), pr.BinaryData); err != nil {
@@ -166,6 +174,16 @@ func (pr *PlayerRole) OnDisconnect() {
 	pr.DisconnectAt = servertime.Now()
 }
 
+// RebindSession 断线重连后切换到新会话
+// 调用方在切换完成后再更新 PlayerRoleManager 的会话索引，此前 GetBySession 仍按原会话查到该角色，新会话查不到
+func (pr *PlayerRole) RebindSession(sessionId string) {
+	log.Infof("[PlayerRole] RebindSession: RoleId=%d, SessionId=%s -> %s", pr.SimpleData.RoleId, pr.GetSessionId(), sessionId)
+
+	pr.sessionId.Store(&sessionId)
+	pr.IsOnline = true
+	pr.DisconnectAt = time.Time{}
+}
+
 // Close 关闭回调（3分钟超时或主动登出）
 func (pr *PlayerRole) Close() error {
 	log.Infof("[PlayerRole] Close: RoleId=%d", pr.SimpleData.RoleId)
@@ -182,8 +200,9 @@ func (pr *PlayerRole) GetPlayerRoleId() uint64 {
 	return pr.SimpleData.RoleId
 }
 
+// GetSessionId 获取当前会话ID，可在任意协程调用
 func (pr *PlayerRole) GetSessionId() string {
-	return pr.SessionId
+	return *pr.sessionId.Load()
 }
 
 // GetRuntime 获取 Runtime 实例（Phase 2D：供系统从 PlayerRole 获取依赖）
@@ -202,6 +221,19 @@ func (pr *PlayerRole) GetGMLevel() uint32 {
 	return pr.SimpleData.GetGmLevel()
 }
 
+func (pr *PlayerRole) GetMuteUntil() int64 {
+	return pr.muteUntil
+}
+
+func (pr *PlayerRole) SetMuteUntil(until int64) {
+	pr.muteUntil = until
+}
+
+// IsMuted 是否处于禁言期
+func (pr *PlayerRole) IsMuted() bool {
+	return pr.muteUntil < 0 || pr.muteUntil > servertime.Now().Unix()
+}
+
 func (pr *PlayerRole) GetJob() uint32 {
 	if pr.SimpleData == nil {
 		return 0
@@ -218,7 +250,7 @@ func (pr *PlayerRole) GetSystem(sysId uint32) iface.ISystem {
 }
 
 func (pr *PlayerRole) SendMessage(protoId uint16, data []byte) error {
-	return gatewaylink.SendToSession(pr.SessionId, protoId, data)
+	return gatewaylink.SendToSession(pr.GetSessionId(), protoId, data)
 }
 
 func (pr *PlayerRole) SendProtoMessage(protoId uint16, v proto.Message) error {
@@ -237,8 +269,8 @@ func (pr *PlayerRole) WithContext(parentCtx context.Context) context.Context {
 	// 注入 PlayerRole
 	ctx = context.WithValue(ctx, gshare.ContextKeyRole, pr)
 	// 注入 SessionId，便于日志与下游链路从 ctx 还原会话信息
-	if pr.SessionId != "" {
-		ctx = context.WithValue(ctx, gshare.ContextKeySession, pr.SessionId)
+	if sessionId := pr.GetSessionId(); sessionId != "" {
+		ctx = context.WithValue(ctx, gshare.ContextKeySession, sessionId)
 	}
 	// 注入 Runtime，方便下游通过 deps.FromContext(ctx) 获取运行时依赖
 	if pr.runtime != nil {
@@ -283,7 +315,11 @@ func (pr *PlayerRole) CallDungeonActor(ctx context.Context, msgId uint16, data [
 	return pr.runtime.DungeonGateway().AsyncCall(msgCtx, pr.GetSessionId(), msgId, data)
 }
 
-func (pr *PlayerRole) timeSync() {
+// TimeSync 下发服务器时间，由玩家 Actor 定时器每秒驱动
+func (pr *PlayerRole) TimeSync() {
+	if !pr.IsOnline {
+		return
+	}
 	resp := &protocol.S2CTimeSyncReq{
 		ServerTimeMs: servertime.UnixMilli(),
 	}
@@ -293,25 +329,13 @@ func (pr *PlayerRole) timeSync() {
 	}
 }
 
-// RunOne 每帧调用，处理属性增量更新等
+// RunOne 由玩家 Actor 定时器驱动，处理跨小时/天等时间事件
 func (pr *PlayerRole) RunOne() {
 	if !pr.IsOnline {
 		return
 	}
 
 	pr.handleTimeEvents()
-
-	if pr._1sChecker.CheckAndSet(true) {
-		pr.timeSync()
-	}
-
-	if pr._5minChecker.CheckAndSet(true) {
-		err := pr.SaveToDB()
-		if err != nil {
-			log.Errorf("save player binary data failed: %v", err)
-		}
-	}
-
 }
 
 func (pr *PlayerRole) OnNewHour(ctx context.Context) {
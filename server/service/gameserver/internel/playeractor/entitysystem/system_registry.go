@@ -49,5 +49,7 @@ func GetDefaultSystemIds() []uint32 {
 	return []uint32{
 		uint32(protocol.SystemId_SysLevel),
 		uint32(protocol.SystemId_SysSkill),
+		uint32(protocol.SystemId_SysBag),
+		uint32(protocol.SystemId_SysEquip),
 	}
 }
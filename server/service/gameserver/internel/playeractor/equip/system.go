@@ -0,0 +1,267 @@
+package equip
+
+import (
+	"context"
+	"postapocgame/server/internal/attrcalc"
+	"postapocgame/server/internal/jsonconf"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/gshare"
+	"postapocgame/server/service/gameserver/internel/iface"
+	"postapocgame/server/service/gameserver/internel/playeractor/bag"
+	"postapocgame/server/service/gameserver/internel/playeractor/deps"
+	"postapocgame/server/service/gameserver/internel/playeractor/entitysystem"
+	"postapocgame/server/service/gameserver/internel/playeractor/level"
+	"postapocgame/server/service/gameserver/internel/playeractor/sysbase"
+	"sort"
+
+	"google.golang.org/protobuf/proto"
+)
+
+var _ iface.ISystem = (*SystemAdapter)(nil)
+
+type SystemAdapter struct {
+	*sysbase.BaseSystem
+	rt *deps.Runtime
+}
+
+// NewEquipSystemAdapter 创建装备系统适配器
+func NewEquipSystemAdapter(rt *deps.Runtime) *SystemAdapter {
+	return &SystemAdapter{
+		BaseSystem: sysbase.NewBaseSystem(uint32(protocol.SystemId_SysEquip)),
+		rt:         rt,
+	}
+}
+
+// OnRoleLogin 登录下发装备数据
+func (a *SystemAdapter) OnRoleLogin(ctx context.Context) {
+	if err := a.SendEquipData(ctx); err != nil {
+		log.Errorf("equip sys OnRoleLogin send equip data err:%v", err)
+	}
+}
+
+// SendEquipData 下发全部装备
+func (a *SystemAdapter) SendEquipData(ctx context.Context) error {
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	equipData, err := a.rt.PlayerRepo().GetEquipData(ctx)
+	if err != nil {
+		return err
+	}
+	resp := &protocol.S2CEquipDataReq{
+		Equips: make([]*protocol.EquipSt, 0, len(equipData.Equips)),
+	}
+	for _, equip := range equipData.Equips {
+		resp.Equips = append(resp.Equips, equip)
+	}
+	sort.Slice(resp.Equips, func(i, j int) bool { return resp.Equips[i].Slot < resp.Equips[j].Slot })
+	return playerRole.SendProtoMessage(uint16(protocol.S2CProtocol_S2CEquipData), resp)
+}
+
+// Wear 穿戴背包格子中的装备，槽位已有装备时放回该背包格子
+func (a *SystemAdapter) Wear(ctx context.Context, bagSlot uint32) error {
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	bagSys := bag.GetBagSys(ctx)
+	if bagSys == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_System_NotEnabled), "bag system not opened")
+	}
+	item := bagSys.GetSlotItem(ctx, bagSlot)
+	if item == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Item_NotFound), "bag slot %d is empty", bagSlot)
+	}
+	cfg := jsonconf.GetConfigManager().GetItemConfig(item.ItemId)
+	if cfg == nil || cfg.Equip == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Item_CannotUse), "item %d is not equipment", item.ItemId)
+	}
+	if err := checkWear(ctx, playerRole, cfg); err != nil {
+		return err
+	}
+
+	equipData, err := a.rt.PlayerRepo().GetEquipData(ctx)
+	if err != nil {
+		return err
+	}
+	slot := cfg.Equip.Slot
+	var putBack *protocol.BagItemSt
+	if old := equipData.Equips[slot]; old != nil {
+		putBack = &protocol.BagItemSt{ItemId: old.ItemId, Count: 1, Bind: old.Bind}
+	}
+	taken, err := bagSys.ExchangeSlotItem(ctx, bagSlot, putBack)
+	if err != nil {
+		return err
+	}
+	equipData.Equips[slot] = &protocol.EquipSt{Slot: slot, ItemId: taken.ItemId, Bind: taken.Bind}
+
+	a.onEquipChanged(ctx)
+	return nil
+}
+
+// TakeOff 卸下装备放回背包，背包已满时失败
+func (a *SystemAdapter) TakeOff(ctx context.Context, equipSlot uint32) error {
+	equipData, err := a.rt.PlayerRepo().GetEquipData(ctx)
+	if err != nil {
+		return err
+	}
+	equip := equipData.Equips[equipSlot]
+	if equip == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Equip_SlotEmpty), "equip slot %d is empty", equipSlot)
+	}
+	bagSys := bag.GetBagSys(ctx)
+	if bagSys == nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_System_NotEnabled), "bag system not opened")
+	}
+	err = bagSys.AddItems(ctx, []*protocol.ItemAmount{{
+		ItemType: uint32(protocol.ItemType_ItemTypeEquip),
+		ItemId:   equip.ItemId,
+		Count:    1,
+		Bind:     equip.Bind,
+	}})
+	if err != nil {
+		return err
+	}
+	delete(equipData.Equips, equipSlot)
+
+	a.onEquipChanged(ctx)
+	return nil
+}
+
+// GetEquipAttrs 获取当前装备属性汇总
+func (a *SystemAdapter) GetEquipAttrs(ctx context.Context) map[uint32]int64 {
+	equipData, err := a.rt.PlayerRepo().GetEquipData(ctx)
+	if err != nil {
+		return nil
+	}
+	return BuildEquipAttrs(equipData)
+}
+
+// GetAppearance 获取当前外观
+func (a *SystemAdapter) GetAppearance(ctx context.Context) map[uint32]uint32 {
+	equipData, err := a.rt.PlayerRepo().GetEquipData(ctx)
+	if err != nil {
+		return nil
+	}
+	return BuildAppearance(equipData)
+}
+
+// onEquipChanged 装备变化后下发客户端并同步 DungeonActor 实体属性与外观
+func (a *SystemAdapter) onEquipChanged(ctx context.Context) {
+	if err := a.SendEquipData(ctx); err != nil {
+		log.Warnf("send equip data failed: %v", err)
+	}
+
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		log.Errorf("equip sys onEquipChanged get role err:%v", err)
+		return
+	}
+	data, err := proto.Marshal(&protocol.DAMSyncEquipReq{
+		Attrs:      a.GetEquipAttrs(ctx),
+		Appearance: a.GetAppearance(ctx),
+	})
+	if err != nil {
+		log.Errorf("marshal sync equip failed: %v", err)
+		return
+	}
+	if err := playerRole.CallDungeonActor(ctx, uint16(protocol.DungeonActorMsgId_DAMSyncEquip), data); err != nil {
+		log.Warnf("sync equip to dungeon actor failed: roleId=%d err=%v", playerRole.GetPlayerRoleId(), err)
+	}
+}
+
+// checkWear 校验职业槽位、职业限制与穿戴等级
+func checkWear(ctx context.Context, playerRole iface.IPlayerRole, cfg *jsonconf.ItemConfig) error {
+	var job uint32
+	if simpleData := playerRole.GetPlayerSimpleData(); simpleData != nil {
+		job = simpleData.Job
+	}
+	jobCfg := jsonconf.GetConfigManager().GetJobConfig(job)
+	if jobCfg == nil || !jobCfg.HasEquipSlot(cfg.Equip.Slot) || !cfg.Equip.CanWearByJob(job) {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Equip_JobLimit), "job %d cannot wear item %d", job, cfg.ItemId)
+	}
+	if cfg.Equip.Level > 0 {
+		levelSys := level.GetLevelSys(ctx)
+		if levelSys == nil {
+			return customerr.NewErrorByCode(int32(protocol.ErrorCode_System_NotEnabled), "level system not opened")
+		}
+		roleLevel, err := levelSys.GetLevel(ctx)
+		if err != nil {
+			return err
+		}
+		if roleLevel < cfg.Equip.Level {
+			return customerr.NewErrorByCode(int32(protocol.ErrorCode_Equip_LevelLimit), "level %d < %d", roleLevel, cfg.Equip.Level)
+		}
+	}
+	return nil
+}
+
+// BuildEquipAttrs 汇总全部装备属性（归入 SaEquip 属性系统）
+func BuildEquipAttrs(equipData *protocol.SiEquipData) map[uint32]int64 {
+	calc := attrcalc.NewFightAttrCalc()
+	configMgr := jsonconf.GetConfigManager()
+	for _, equip := range equipData.GetEquips() {
+		cfg := configMgr.GetItemConfig(equip.ItemId)
+		if cfg == nil || cfg.Equip == nil {
+			continue
+		}
+		for _, attr := range cfg.Equip.Attrs {
+			if attr != nil {
+				calc.AddValue(attr.Type, attr.Value)
+			}
+		}
+	}
+	attrs := make(map[uint32]int64)
+	calc.DoRange(func(attrType uint32, value int64) {
+		attrs[attrType] = value
+	})
+	return attrs
+}
+
+// BuildAppearance 获取装备外观，未配置外观的槽位不下发
+func BuildAppearance(equipData *protocol.SiEquipData) map[uint32]uint32 {
+	appearance := make(map[uint32]uint32)
+	configMgr := jsonconf.GetConfigManager()
+	for slot, equip := range equipData.GetEquips() {
+		cfg := configMgr.GetItemConfig(equip.ItemId)
+		if cfg == nil || cfg.Equip == nil || cfg.Equip.Appearance == 0 {
+			continue
+		}
+		appearance[slot] = cfg.Equip.Appearance
+	}
+	return appearance
+}
+
+// GetEquipSys 获取装备系统
+func GetEquipSys(ctx context.Context) *SystemAdapter {
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		log.Errorf("get player role error:%v", err)
+		return nil
+	}
+	system := playerRole.GetSystem(uint32(protocol.SystemId_SysEquip))
+	if system == nil {
+		log.Errorf("not found system [%v]", protocol.SystemId_SysEquip)
+		return nil
+	}
+	sys, ok := system.(*SystemAdapter)
+	if !ok {
+		log.Errorf("invalid system type for [%v]", protocol.SystemId_SysEquip)
+		return nil
+	}
+	if sys == nil || !sys.IsOpened() {
+		log.Errorf("get player role system [%v] error", protocol.SystemId_SysEquip)
+		return nil
+	}
+	return sys
+}
+
+// RegisterSystemFactory 注册装备系统工厂（由 register.RegisterAll 调用）
+func RegisterSystemFactory(rt *deps.Runtime) {
+	entitysystem.RegisterSystemFactory(uint32(protocol.SystemId_SysEquip), func() iface.ISystem {
+		return NewEquipSystemAdapter(rt)
+	})
+}
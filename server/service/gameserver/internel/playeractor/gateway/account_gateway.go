@@ -43,5 +43,8 @@ func convertAccount(acct *database.Account) *model.Account {
 	if acct == nil {
 		return nil
 	}
-	return model.NewAccount(uint64(acct.ID), acct.Username, acct.Password)
+	account := model.NewAccount(uint64(acct.ID), acct.Username, acct.Password)
+	account.BanUntil = acct.BanUntil
+	account.BanReason = acct.BanReason
+	return account
 }
@@ -2,13 +2,13 @@ package gateway
 
 import (
 	"context"
-	"postapocgame/server/pkg/customerr"
-	"postapocgame/server/service/gameserver/internel/dungeonactor"
+	"postapocgame/server/internal/actor"
+	"postapocgame/server/service/gameserver/internel/gshare"
 	"postapocgame/server/service/gameserver/internel/iface"
 )
 
 // DungeonServerGatewayImpl DungeonServer Gateway 实现
-// 将调用路由到 GameServer 进程内的 DungeonActor
+// 通过 gshare 的 DungeonActor 门面投递：进程内模式为本地 DungeonActor，远程模式为 DungeonServer 链路
 type DungeonServerGatewayImpl struct{}
 
 // NewDungeonServerGateway 创建 DungeonServer Gateway
@@ -18,9 +18,6 @@ func NewDungeonServerGateway() iface.DungeonServerGateway {
 
 // AsyncCall 调用 DungeonActor
 func (g *DungeonServerGatewayImpl) AsyncCall(ctx context.Context, sessionId string, msgId uint16, data []byte) error {
-	da := dungeonactor.GetDungeonActor()
-	if da == nil {
-		return customerr.NewError("dungeon actor not initialized")
-	}
-	return da.AsyncCall(ctx, sessionId, msgId, data)
+	ctxWithSession := context.WithValue(ctx, gshare.ContextKeySession, sessionId)
+	return gshare.SendDungeonMessageAsync("global", actor.NewBaseMessage(ctxWithSession, msgId, data))
 }
@@ -45,3 +45,29 @@ func (g *PlayerGateway) GetSkillData(ctx context.Context) (*protocol.SiSkillData
 	}
 	return skillData, nil
 }
+
+func (g *PlayerGateway) GetBagData(ctx context.Context) (*protocol.SiBagData, error) {
+	// 优先从 Context 中的 PlayerRole 获取（共享引用）
+	playerRole := gshare.MustGetPlayerRoleFromContext(ctx)
+	if playerRole == nil {
+		return nil, iface.ErrBagDataNotFound
+	}
+	bagData := playerRole.GetBagData()
+	if bagData == nil {
+		return nil, iface.ErrBagDataNotFound
+	}
+	return bagData, nil
+}
+
+func (g *PlayerGateway) GetEquipData(ctx context.Context) (*protocol.SiEquipData, error) {
+	// 优先从 Context 中的 PlayerRole 获取（共享引用）
+	playerRole := gshare.MustGetPlayerRoleFromContext(ctx)
+	if playerRole == nil {
+		return nil, iface.ErrEquipDataNotFound
+	}
+	equipData := playerRole.GetEquipData()
+	if equipData == nil {
+		return nil, iface.ErrEquipDataNotFound
+	}
+	return equipData, nil
+}
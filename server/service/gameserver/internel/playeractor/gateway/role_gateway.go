@@ -68,5 +68,7 @@ func convertPlayer(player *database.Player) *model.Role {
 		Job:       uint32(player.Job),
 		Sex:       uint32(player.Sex),
 		Level:     uint32(player.Level),
+		GmLevel:   player.GmLevel,
+		MuteUntil: player.MuteUntil,
 	}
 }
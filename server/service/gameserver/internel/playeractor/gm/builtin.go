@@ -0,0 +1,249 @@
+package gm
+
+import (
+	"context"
+	"fmt"
+	"postapocgame/server/internal/jsonconf"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/internal/servertime"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/service/gameserver/internel/iface"
+	"postapocgame/server/service/gameserver/internel/playeractor/bag"
+	"postapocgame/server/service/gameserver/internel/playeractor/level"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// maxSpawnCount 单次 spawnmonster 的数量上限
+const maxSpawnCount = 50
+
+// RegisterBuiltinCommands 注册内置 GM 指令（由 register.All 调用）
+func RegisterBuiltinCommands() {
+	Register(&Command{
+		Name:     "help",
+		MinLevel: LevelGM,
+		Desc:     "列出当前 gm_level 可用的指令",
+		Handler:  handleHelp,
+	})
+	Register(&Command{
+		Name:     "additem",
+		MinLevel: LevelSenior,
+		Args:     []ArgSpec{{Name: "itemId", Kind: ArgUint32}, {Name: "count", Kind: ArgUint32, Optional: true, Default: "1"}},
+		Desc:     "发放道具到背包",
+		Handler:  handleAddItem,
+	})
+	Register(&Command{
+		Name:     "setlevel",
+		MinLevel: LevelSenior,
+		Args:     []ArgSpec{{Name: "level", Kind: ArgUint32}},
+		Desc:     "设置等级，经验清零",
+		Handler:  handleSetLevel,
+	})
+	Register(&Command{
+		Name:     "addexp",
+		MinLevel: LevelGM,
+		Args:     []ArgSpec{{Name: "exp", Kind: ArgUint}},
+		Desc:     "增加经验",
+		Handler:  handleAddExp,
+	})
+	Register(&Command{
+		Name:     "teleport",
+		MinLevel: LevelGM,
+		Args:     []ArgSpec{{Name: "sceneId", Kind: ArgUint32}, {Name: "x", Kind: ArgUint32}, {Name: "y", Kind: ArgUint32}},
+		Desc:     "传送到当前副本内的场景坐标，sceneId 为 0 表示当前场景",
+		Handler:  handleTeleport,
+	})
+	Register(&Command{
+		Name:     "spawnmonster",
+		MinLevel: LevelSenior,
+		Args:     []ArgSpec{{Name: "monsterId", Kind: ArgUint32}, {Name: "count", Kind: ArgUint32, Optional: true, Default: "1"}},
+		Desc:     "在当前位置刷出怪物（死亡后不重生）",
+		Handler:  handleSpawnMonster,
+	})
+	Register(&Command{
+		Name:     "killall",
+		MinLevel: LevelSenior,
+		Desc:     "击杀当前场景全部怪物",
+		Handler:  handleKillAll,
+	})
+	Register(&Command{
+		Name:     "movesuspicion",
+		MinLevel: LevelGM,
+		Args:     []ArgSpec{{Name: "roleId", Kind: ArgUint, Optional: true, Default: "0"}, {Name: "op", Kind: ArgString, Optional: true, Default: "list"}},
+		Desc:     "查看移动违规记录（roleId 为 0 列出全部），op 为 reset 时清除该角色记录（高级 GM）",
+		Handler:  handleMoveSuspicion,
+	})
+	Register(&Command{
+		Name:     "settime",
+		MinLevel: LevelSuper,
+		Args:     []ArgSpec{{Name: "offset", Kind: ArgString}},
+		Desc:     "调整服务器时间（全服生效），offset 为时长如 24h、-1h30m，reset 恢复",
+		Handler:  handleSetTime,
+	})
+	Register(&Command{
+		Name:     "opensys",
+		MinLevel: LevelSenior,
+		Args:     []ArgSpec{{Name: "sysId", Kind: ArgUint32}},
+		Desc:     "开启系统",
+		Handler:  handleOpenSys,
+	})
+}
+
+func handleHelp(_ context.Context, role iface.IPlayerRole, _ *Args) (string, error) {
+	cmds := ListCommands(role.GetGMLevel())
+	lines := make([]string, 0, len(cmds))
+	for _, cmd := range cmds {
+		lines = append(lines, cmd.Usage()+"  "+cmd.Desc)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func handleAddItem(ctx context.Context, _ iface.IPlayerRole, args *Args) (string, error) {
+	itemId, count := args.Uint32("itemId"), args.Uint32("count")
+	if jsonconf.GetConfigManager().GetItemConfig(itemId) == nil {
+		return "", customerr.NewErrorByCode(int32(protocol.ErrorCode_Item_NotFound), "item %d not configured", itemId)
+	}
+	if count == 0 {
+		return "", customerr.NewErrorByCode(int32(protocol.ErrorCode_Gm_ArgInvalid), "count must be positive")
+	}
+	bagSys := bag.GetBagSys(ctx)
+	if bagSys == nil {
+		return "", customerr.NewErrorByCode(int32(protocol.ErrorCode_System_NotEnabled), "bag system not opened")
+	}
+	if err := bagSys.AddItems(ctx, []*protocol.ItemAmount{{ItemId: itemId, Count: int64(count)}}); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("added item %d x%d", itemId, count), nil
+}
+
+func handleSetLevel(ctx context.Context, _ iface.IPlayerRole, args *Args) (string, error) {
+	levelSys, err := getLevelSys(ctx)
+	if err != nil {
+		return "", err
+	}
+	lv := args.Uint32("level")
+	if err := levelSys.SetLevel(ctx, lv); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("level set to %d", lv), nil
+}
+
+func handleAddExp(ctx context.Context, _ iface.IPlayerRole, args *Args) (string, error) {
+	levelSys, err := getLevelSys(ctx)
+	if err != nil {
+		return "", err
+	}
+	exp := args.Uint("exp")
+	if err := levelSys.AddExp(ctx, exp); err != nil {
+		return "", err
+	}
+	lv, _ := levelSys.GetLevel(ctx)
+	return fmt.Sprintf("added exp %d, level %d", exp, lv), nil
+}
+
+func handleTeleport(ctx context.Context, role iface.IPlayerRole, args *Args) (string, error) {
+	req := &protocol.DAMGmTeleportReq{
+		SceneId: args.Uint32("sceneId"),
+		X:       args.Uint32("x"),
+		Y:       args.Uint32("y"),
+	}
+	if err := callDungeon(ctx, role, protocol.DungeonActorMsgId_DAMGmTeleport, req); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("teleport to scene %d (%d,%d) requested", req.SceneId, req.X, req.Y), nil
+}
+
+func handleSpawnMonster(ctx context.Context, role iface.IPlayerRole, args *Args) (string, error) {
+	monsterId, count := args.Uint32("monsterId"), args.Uint32("count")
+	if jsonconf.GetConfigManager().GetMonsterConfig(monsterId) == nil {
+		return "", customerr.NewErrorByCode(int32(protocol.ErrorCode_Gm_ArgInvalid), "monster %d not configured", monsterId)
+	}
+	if count == 0 || count > maxSpawnCount {
+		return "", customerr.NewErrorByCode(int32(protocol.ErrorCode_Gm_ArgInvalid), "count must be in [1, %d]", maxSpawnCount)
+	}
+	req := &protocol.DAMGmSpawnMonsterReq{MonsterId: monsterId, Count: count}
+	if err := callDungeon(ctx, role, protocol.DungeonActorMsgId_DAMGmSpawnMonster, req); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("spawn monster %d x%d requested", monsterId, count), nil
+}
+
+func handleKillAll(ctx context.Context, role iface.IPlayerRole, _ *Args) (string, error) {
+	if err := role.CallDungeonActor(ctx, uint16(protocol.DungeonActorMsgId_DAMGmKillAll), nil); err != nil {
+		return "", err
+	}
+	return "kill all monsters requested", nil
+}
+
+func handleMoveSuspicion(ctx context.Context, role iface.IPlayerRole, args *Args) (string, error) {
+	roleId, op := args.Uint("roleId"), args.String("op")
+	req := &protocol.DAMGmMoveSuspicionReq{Name: "movesuspicion", RoleId: roleId}
+	switch strings.ToLower(op) {
+	case "list":
+	case "reset":
+		if roleId == 0 {
+			return "", customerr.NewErrorByCode(int32(protocol.ErrorCode_Gm_ArgInvalid), "reset requires roleId")
+		}
+		if role.GetGMLevel() < LevelSenior {
+			return "", customerr.NewErrorByCode(int32(protocol.ErrorCode_Gm_PermissionDenied), "reset requires gm_level %d", LevelSenior)
+		}
+		req.Clear = true
+	default:
+		return "", customerr.NewErrorByCode(int32(protocol.ErrorCode_Gm_ArgInvalid), "invalid op %q, usage: movesuspicion [roleId] [list|reset]", op)
+	}
+	// 记录保存在 DungeonActor，结果由其以 S2CGmCommand 直接下发
+	if err := callDungeon(ctx, role, protocol.DungeonActorMsgId_DAMGmMoveSuspicion, req); err != nil {
+		return "", err
+	}
+	return "move suspicion query requested", nil
+}
+
+func handleSetTime(_ context.Context, _ iface.IPlayerRole, args *Args) (string, error) {
+	offset := args.String("offset")
+	if strings.EqualFold(offset, "reset") {
+		servertime.ResetOffset()
+	} else {
+		d, err := time.ParseDuration(offset)
+		if err != nil {
+			return "", customerr.NewErrorByCode(int32(protocol.ErrorCode_Gm_ArgInvalid), "invalid offset %q, usage: settime <duration|reset>", offset)
+		}
+		servertime.AddOffset(d)
+	}
+	return "server time now " + servertime.Now().Format(time.RFC3339), nil
+}
+
+func handleOpenSys(ctx context.Context, role iface.IPlayerRole, args *Args) (string, error) {
+	sysId := args.Uint32("sysId")
+	if sysId == 0 || sysId >= uint32(protocol.SystemId_SysIdMax) {
+		return "", customerr.NewErrorByCode(int32(protocol.ErrorCode_Gm_ArgInvalid), "invalid sysId %d", sysId)
+	}
+	system := role.GetSystem(sysId)
+	if system == nil {
+		return "", customerr.NewErrorByCode(int32(protocol.ErrorCode_Gm_ArgInvalid), "system %d not mounted", sysId)
+	}
+	if system.IsOpened() {
+		return fmt.Sprintf("system %d already opened", sysId), nil
+	}
+	role.SetSysStatus(sysId, true)
+	system.SetOpened(true)
+	system.OnOpen(ctx)
+	return fmt.Sprintf("system %d opened", sysId), nil
+}
+
+func getLevelSys(ctx context.Context) (*level.SystemAdapter, error) {
+	levelSys := level.GetLevelSys(ctx)
+	if levelSys == nil {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_System_NotEnabled), "level system not opened")
+	}
+	return levelSys, nil
+}
+
+func callDungeon(ctx context.Context, role iface.IPlayerRole, msgId protocol.DungeonActorMsgId, req proto.Message) error {
+	data, err := proto.Marshal(req)
+	if err != nil {
+		return customerr.Wrap(err)
+	}
+	return role.CallDungeonActor(ctx, uint16(msgId), data)
+}
@@ -0,0 +1,243 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/11
+ * @Desc: GM 指令注册表：指令声明最低 gm_level 与参数类型，执行前统一校验，GM 角色的调用写审计表
+**/
+
+package gm
+
+import (
+	"context"
+	"fmt"
+	"postapocgame/server/internal/database"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/iface"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GM 等级，对应 PlayerSimpleData.gm_level
+const (
+	LevelNone   uint32 = 0 // 普通玩家
+	LevelGM     uint32 = 1 // GM
+	LevelSenior uint32 = 2 // 高级 GM
+	LevelSuper  uint32 = 3 // 超级 GM
+)
+
+// ArgKind 参数类型
+type ArgKind int
+
+const (
+	ArgUint     ArgKind = iota // 非负整数
+	ArgUint32                  // 非负整数，超过 MaxUint32 视为参数错误
+	ArgInt                     // 整数
+	ArgString                  // 字符串
+	ArgDuration                // 时长，如 1h30m、-2h
+)
+
+func (k ArgKind) String() string {
+	switch k {
+	case ArgUint:
+		return "uint"
+	case ArgUint32:
+		return "uint32"
+	case ArgInt:
+		return "int"
+	case ArgDuration:
+		return "duration"
+	default:
+		return "string"
+	}
+}
+
+// ArgSpec 参数声明；Optional 参数必须位于必填参数之后，缺省时取 Default
+type ArgSpec struct {
+	Name     string
+	Kind     ArgKind
+	Optional bool
+	Default  string
+}
+
+// Handler 指令处理函数，返回给 GM 的结果描述
+type Handler func(ctx context.Context, role iface.IPlayerRole, args *Args) (string, error)
+
+// Command GM 指令
+type Command struct {
+	Name     string
+	MinLevel uint32
+	Args     []ArgSpec
+	Desc     string
+	Handler  Handler
+}
+
+// Usage 指令用法，如 additem <itemId:uint> [count:uint=1]
+func (c *Command) Usage() string {
+	var sb strings.Builder
+	sb.WriteString(c.Name)
+	for _, spec := range c.Args {
+		if spec.Optional {
+			fmt.Fprintf(&sb, " [%s:%s=%s]", spec.Name, spec.Kind, spec.Default)
+		} else {
+			fmt.Fprintf(&sb, " <%s:%s>", spec.Name, spec.Kind)
+		}
+	}
+	return sb.String()
+}
+
+// Args 已按声明解析的参数
+type Args struct {
+	values map[string]interface{}
+}
+
+// Uint 获取 ArgUint 参数
+func (a *Args) Uint(name string) uint64 {
+	v, _ := a.values[name].(uint64)
+	return v
+}
+
+// Uint32 获取 ArgUint32 参数，范围已在解析时校验
+func (a *Args) Uint32(name string) uint32 {
+	v, _ := a.values[name].(uint32)
+	return v
+}
+
+// Int 获取 ArgInt 参数
+func (a *Args) Int(name string) int64 {
+	v, _ := a.values[name].(int64)
+	return v
+}
+
+// String 获取 ArgString 参数
+func (a *Args) String(name string) string {
+	v, _ := a.values[name].(string)
+	return v
+}
+
+// Duration 获取 ArgDuration 参数
+func (a *Args) Duration(name string) time.Duration {
+	v, _ := a.values[name].(time.Duration)
+	return v
+}
+
+// parseArgs 按声明解析原始参数，多余参数视为错误
+func (c *Command) parseArgs(raw []string) (*Args, error) {
+	if len(raw) > len(c.Args) {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Gm_ArgInvalid), "too many args, usage: %s", c.Usage())
+	}
+	args := &Args{values: make(map[string]interface{}, len(c.Args))}
+	for i, spec := range c.Args {
+		var text string
+		switch {
+		case i < len(raw):
+			text = raw[i]
+		case spec.Optional:
+			text = spec.Default
+		default:
+			return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Gm_ArgInvalid), "missing arg %s, usage: %s", spec.Name, c.Usage())
+		}
+		v, err := parseValue(spec.Kind, text)
+		if err != nil {
+			return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Gm_ArgInvalid), "invalid arg %s=%q, usage: %s", spec.Name, text, c.Usage())
+		}
+		args.values[spec.Name] = v
+	}
+	return args, nil
+}
+
+func parseValue(kind ArgKind, text string) (interface{}, error) {
+	switch kind {
+	case ArgUint:
+		return strconv.ParseUint(text, 10, 64)
+	case ArgUint32:
+		v, err := strconv.ParseUint(text, 10, 32)
+		return uint32(v), err
+	case ArgInt:
+		return strconv.ParseInt(text, 10, 64)
+	case ArgDuration:
+		return time.ParseDuration(text)
+	default:
+		return text, nil
+	}
+}
+
+var (
+	commands = make(map[string]*Command)
+	// saveAudit 审计写入，测试中替换
+	saveAudit = database.SaveGmAudit
+)
+
+// Register 注册 GM 指令，指令名不区分大小写，重复注册会 panic
+func Register(cmd *Command) {
+	if cmd == nil || cmd.Name == "" || cmd.Handler == nil {
+		panic("gm: invalid command")
+	}
+	key := strings.ToLower(cmd.Name)
+	if _, ok := commands[key]; ok {
+		panic("gm: duplicate command " + cmd.Name)
+	}
+	commands[key] = cmd
+}
+
+// GetCommand 获取 GM 指令
+func GetCommand(name string) *Command {
+	return commands[strings.ToLower(name)]
+}
+
+// ListCommands 列出 gm_level 可用的指令，按名称排序
+func ListCommands(gmLevel uint32) []*Command {
+	list := make([]*Command, 0, len(commands))
+	for _, cmd := range commands {
+		if gmLevel >= cmd.MinLevel {
+			list = append(list, cmd)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Execute 执行 GM 指令：校验 gm_level 与参数后调用处理函数
+// 非 GM 角色直接拒绝且不写审计，避免普通玩家刷 C2SGmCommand 产生同步写库；GM 角色的调用无论成败都写入审计表
+func Execute(ctx context.Context, role iface.IPlayerRole, name string, rawArgs []string) (result string, err error) {
+	gmLevel := role.GetGMLevel()
+	if gmLevel == LevelNone {
+		return "", customerr.NewErrorByCode(int32(protocol.ErrorCode_Gm_PermissionDenied), "role is not gm")
+	}
+	defer func() {
+		audit := &database.GmAudit{
+			RoleId:  role.GetPlayerRoleId(),
+			GmLevel: gmLevel,
+			Command: name,
+			Args:    strings.Join(rawArgs, " "),
+			Success: err == nil,
+			Result:  result,
+		}
+		if err != nil {
+			audit.Result = err.Error()
+		}
+		if auditErr := saveAudit(audit); auditErr != nil {
+			log.Errorf("save gm audit failed: roleId=%d cmd=%s err=%v", audit.RoleId, name, auditErr)
+		}
+	}()
+
+	cmd := GetCommand(name)
+	if cmd == nil {
+		return "", customerr.NewErrorByCode(int32(protocol.ErrorCode_Gm_CommandNotFound), "gm command %s not found", name)
+	}
+	if gmLevel < cmd.MinLevel {
+		return "", customerr.NewErrorByCode(int32(protocol.ErrorCode_Gm_PermissionDenied), "gm command %s requires gm_level %d, have %d", cmd.Name, cmd.MinLevel, gmLevel)
+	}
+	args, err := cmd.parseArgs(rawArgs)
+	if err != nil {
+		return "", err
+	}
+	result, err = cmd.Handler(ctx, role, args)
+	if err != nil {
+		return "", err
+	}
+	log.Infof("role %d executed gm command: %s %s", role.GetPlayerRoleId(), cmd.Name, strings.Join(rawArgs, " "))
+	return result, nil
+}
@@ -0,0 +1,121 @@
+package gm
+
+import (
+	"context"
+	"postapocgame/server/internal/database"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/service/gameserver/internel/iface"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// fakeRole 仅实现 Execute 用到的方法
+type fakeRole struct {
+	iface.IPlayerRole
+	gmLevel uint32
+}
+
+func (r *fakeRole) GetGMLevel() uint32      { return r.gmLevel }
+func (r *fakeRole) GetPlayerRoleId() uint64 { return 42 }
+
+func setupRegistry(t *testing.T) *[]*database.GmAudit {
+	t.Helper()
+	oldCommands, oldSave := commands, saveAudit
+	t.Cleanup(func() { commands, saveAudit = oldCommands, oldSave })
+
+	audits := make([]*database.GmAudit, 0)
+	commands = make(map[string]*Command)
+	saveAudit = func(a *database.GmAudit) error {
+		audits = append(audits, a)
+		return nil
+	}
+	Register(&Command{
+		Name:     "echo",
+		MinLevel: LevelSenior,
+		Args: []ArgSpec{
+			{Name: "n", Kind: ArgUint},
+			{Name: "d", Kind: ArgDuration, Optional: true, Default: "1m"},
+		},
+		Handler: func(_ context.Context, _ iface.IPlayerRole, args *Args) (string, error) {
+			return args.Duration("d").String(), nil
+		},
+	})
+	Register(&Command{
+		Name:     "id",
+		MinLevel: LevelGM,
+		Args:     []ArgSpec{{Name: "id", Kind: ArgUint32}},
+		Handler: func(_ context.Context, _ iface.IPlayerRole, args *Args) (string, error) {
+			return strconv.FormatUint(uint64(args.Uint32("id")), 10), nil
+		},
+	})
+	return &audits
+}
+
+func TestExecuteChecksGmLevelAndAudits(t *testing.T) {
+	audits := setupRegistry(t)
+	ctx := context.Background()
+
+	// 非 GM 角色直接拒绝，不写审计
+	if _, err := Execute(ctx, &fakeRole{gmLevel: LevelNone}, "echo", []string{"1"}); customerr.GetErrCode(err) != int32(protocol.ErrorCode_Gm_PermissionDenied) {
+		t.Fatalf("non gm got %v", err)
+	}
+	if len(*audits) != 0 {
+		t.Fatalf("non gm call wrote %d audits", len(*audits))
+	}
+
+	cases := []struct {
+		level uint32
+		name  string
+		args  []string
+		code  protocol.ErrorCode
+	}{
+		{LevelGM, "echo", []string{"1"}, protocol.ErrorCode_Gm_PermissionDenied},
+		{LevelSuper, "nope", nil, protocol.ErrorCode_Gm_CommandNotFound},
+		{LevelSenior, "echo", nil, protocol.ErrorCode_Gm_ArgInvalid},
+		{LevelSenior, "echo", []string{"-1"}, protocol.ErrorCode_Gm_ArgInvalid},
+		{LevelSenior, "echo", []string{"1", "1h", "x"}, protocol.ErrorCode_Gm_ArgInvalid},
+	}
+	for _, c := range cases {
+		_, err := Execute(ctx, &fakeRole{gmLevel: c.level}, c.name, c.args)
+		if customerr.GetErrCode(err) != int32(c.code) {
+			t.Fatalf("level %d %s %v got %v, want %v", c.level, c.name, c.args, err, c.code)
+		}
+	}
+
+	result, err := Execute(ctx, &fakeRole{gmLevel: LevelSuper}, "ECHO", []string{"1"})
+	if err != nil || result != time.Minute.String() {
+		t.Fatalf("default arg got %q, %v", result, err)
+	}
+	result, err = Execute(ctx, &fakeRole{gmLevel: LevelSenior}, "echo", []string{"1", "90s"})
+	if err != nil || result != (90*time.Second).String() {
+		t.Fatalf("explicit arg got %q, %v", result, err)
+	}
+
+	// GM 角色的每次调用（含失败）都写审计
+	if len(*audits) != len(cases)+2 {
+		t.Fatalf("got %d audits, want %d", len(*audits), len(cases)+2)
+	}
+	first, last := (*audits)[0], (*audits)[len(*audits)-1]
+	if first.Success || first.RoleId != 42 || first.GmLevel != LevelGM {
+		t.Fatalf("denied audit %+v", first)
+	}
+	if !last.Success || last.Args != "1 90s" || last.Result != "1m30s" {
+		t.Fatalf("success audit %+v", last)
+	}
+}
+
+func TestUint32ArgRejectsOverflow(t *testing.T) {
+	setupRegistry(t)
+	ctx := context.Background()
+	role := &fakeRole{gmLevel: LevelGM}
+
+	result, err := Execute(ctx, role, "id", []string{"4294967295"})
+	if err != nil || result != "4294967295" {
+		t.Fatalf("max uint32 got %q, %v", result, err)
+	}
+	if _, err := Execute(ctx, role, "id", []string{"4294967296"}); customerr.GetErrCode(err) != int32(protocol.ErrorCode_Gm_ArgInvalid) {
+		t.Fatalf("uint32 overflow got %v, want Gm_ArgInvalid", err)
+	}
+}
@@ -5,10 +5,17 @@ import (
 	"postapocgame/server/internal/actor"
 	"postapocgame/server/internal/protocol"
 	"postapocgame/server/service/gameserver/internel/gshare"
+	"time"
 )
 
 var _ actor.IActorHandler = (*PlayerHandler)(nil)
 
+const (
+	runOneInterval   = 200 * time.Millisecond
+	timeSyncInterval = time.Second
+	saveDataInterval = 5 * time.Minute
+)
+
 // NewPlayerHandler 创建玩家消息处理器
 func NewPlayerHandler() *PlayerHandler {
 	return &PlayerHandler{
@@ -27,11 +34,21 @@ func (h *PlayerHandler) SetActorContext(ctx actor.IActorContext) {
 	h.actorCtx = ctx
 }
 
-func (h *PlayerHandler) Loop() {
-	if h.actorCtx != nil {
-		sessionId := h.actorCtx.GetData("key") // 在这使用 sessionId做的key
-		ctx := context.Background()
-		ctx = context.WithValue(ctx, gshare.ContextKeySession, sessionId)
-		h.actorCtx.ExecuteAsync(actor.NewBaseMessage(ctx, uint16(protocol.PlayerActorMsgId_PAMRunOneMsg), []byte{}))
+// OnStart 注册玩家 Actor 的定时任务，回调在 Actor goroutine 上按消息分发给已注册的处理函数
+func (h *PlayerHandler) OnStart() {
+	if h.actorCtx == nil {
+		return
+	}
+	h.actorCtx.Every(runOneInterval, h.dispatchTimer(protocol.PlayerActorMsgId_PAMRunOneMsg))
+	h.actorCtx.Every(timeSyncInterval, h.dispatchTimer(protocol.PlayerActorMsgId_PAMTimeSync))
+	h.actorCtx.Every(saveDataInterval, h.dispatchTimer(protocol.PlayerActorMsgId_PAMSaveData))
+}
+
+func (h *PlayerHandler) dispatchTimer(msgId protocol.PlayerActorMsgId) func() {
+	sessionId := h.actorCtx.GetData("key") // 在这使用 sessionId做的key
+	ctx := context.WithValue(context.Background(), gshare.ContextKeySession, sessionId)
+	msg := actor.NewBaseMessage(ctx, uint16(msgId), nil)
+	return func() {
+		h.HandleMessage(msg)
 	}
 }
@@ -0,0 +1,87 @@
+package level
+
+import (
+	"context"
+	"postapocgame/server/internal/jsonconf"
+	"postapocgame/server/service/gameserver/internel/playeractor/deps"
+)
+
+// AddExpResult 加经验结果
+type AddExpResult struct {
+	OldLevel     uint32
+	NewLevel     uint32
+	UnlockSkills map[uint32]uint32 // 升级解锁的新技能（skillId -> level）
+}
+
+// LevelChanged 是否发生了升级
+func (r *AddExpResult) LevelChanged() bool {
+	return r.NewLevel != r.OldLevel
+}
+
+type AddExpUseCase struct {
+	rt *deps.Runtime
+}
+
+func NewAddExpUseCase(rt *deps.Runtime) *AddExpUseCase {
+	return &AddExpUseCase{
+		rt: rt,
+	}
+}
+
+// Execute 增加经验并处理连续升级，满级后溢出经验丢弃
+func (uc *AddExpUseCase) Execute(ctx context.Context, exp int64) (*AddExpResult, error) {
+	levelData, err := uc.rt.PlayerRepo().GetLevelData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := &AddExpResult{
+		OldLevel: levelData.Level,
+		NewLevel: levelData.Level,
+	}
+	if exp <= 0 {
+		return result, nil
+	}
+
+	configMgr := jsonconf.GetConfigManager()
+	levelData.Exp += exp
+	for {
+		cfg := configMgr.GetLevelConfig(levelData.Level)
+		if cfg == nil || cfg.Exp <= 0 {
+			// 满级
+			levelData.Exp = 0
+			break
+		}
+		if levelData.Exp < cfg.Exp {
+			break
+		}
+		next := configMgr.GetLevelConfig(levelData.Level + 1)
+		if next == nil {
+			levelData.Exp = 0
+			break
+		}
+		levelData.Exp -= cfg.Exp
+		levelData.Level = next.Level
+		for _, skillId := range next.UnlockSkillIds {
+			if result.UnlockSkills == nil {
+				result.UnlockSkills = make(map[uint32]uint32)
+			}
+			result.UnlockSkills[skillId] = 1
+		}
+	}
+	result.NewLevel = levelData.Level
+
+	if len(result.UnlockSkills) > 0 {
+		skillData, err := uc.rt.PlayerRepo().GetSkillData(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for skillId, skillLv := range result.UnlockSkills {
+			if _, ok := skillData.SkillMap[skillId]; ok {
+				delete(result.UnlockSkills, skillId)
+				continue
+			}
+			skillData.SkillMap[skillId] = skillLv
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,65 @@
+package level
+
+import (
+	"context"
+	"postapocgame/server/internal/jsonconf"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/service/gameserver/internel/playeractor/deps"
+)
+
+// SetLevelUseCase 直接设置等级（GM 指令使用），经验清零
+type SetLevelUseCase struct {
+	rt *deps.Runtime
+}
+
+func NewSetLevelUseCase(rt *deps.Runtime) *SetLevelUseCase {
+	return &SetLevelUseCase{
+		rt: rt,
+	}
+}
+
+// Execute 设置等级，升级时补发跨越等级解锁的技能；降级不回收技能
+func (uc *SetLevelUseCase) Execute(ctx context.Context, level uint32) (*AddExpResult, error) {
+	configMgr := jsonconf.GetConfigManager()
+	if level == 0 || configMgr.GetLevelConfig(level) == nil {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "level %d not configured", level)
+	}
+	levelData, err := uc.rt.PlayerRepo().GetLevelData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := &AddExpResult{
+		OldLevel: levelData.Level,
+		NewLevel: level,
+	}
+	for lv := levelData.Level + 1; lv <= level; lv++ {
+		cfg := configMgr.GetLevelConfig(lv)
+		if cfg == nil {
+			continue
+		}
+		for _, skillId := range cfg.UnlockSkillIds {
+			if result.UnlockSkills == nil {
+				result.UnlockSkills = make(map[uint32]uint32)
+			}
+			result.UnlockSkills[skillId] = 1
+		}
+	}
+	levelData.Level = level
+	levelData.Exp = 0
+
+	if len(result.UnlockSkills) > 0 {
+		skillData, err := uc.rt.PlayerRepo().GetSkillData(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for skillId, skillLv := range result.UnlockSkills {
+			if _, ok := skillData.SkillMap[skillId]; ok {
+				delete(result.UnlockSkills, skillId)
+				continue
+			}
+			skillData.SkillMap[skillId] = skillLv
+		}
+	}
+	return result, nil
+}
@@ -2,13 +2,18 @@ package level
 
 import (
 	"context"
+	"postapocgame/server/internal/database"
+	"postapocgame/server/internal/jsonconf"
 	"postapocgame/server/internal/protocol"
 	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/gevent"
 	"postapocgame/server/service/gameserver/internel/gshare"
 	"postapocgame/server/service/gameserver/internel/iface"
 	"postapocgame/server/service/gameserver/internel/playeractor/deps"
 	"postapocgame/server/service/gameserver/internel/playeractor/entitysystem"
 	"postapocgame/server/service/gameserver/internel/playeractor/sysbase"
+
+	"google.golang.org/protobuf/proto"
 )
 
 var _ iface.ISystem = (*SystemAdapter)(nil)
@@ -26,24 +31,155 @@ func NewLevelSystemAdapter(rt *deps.Runtime) *SystemAdapter {
 	}
 }
 
-// OnInit 系统初始化
+// OnInit 系统初始化，修正等级数据并同步到角色简要信息
 func (a *SystemAdapter) OnInit(ctx context.Context) {
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		log.Errorf("level sys OnInit get role err:%v", err)
+		return
+	}
+	levelData, err := a.rt.PlayerRepo().GetLevelData(ctx)
+	if err != nil {
+		log.Errorf("level sys OnInit get level data err:%v", err)
+		return
+	}
+	simpleData := playerRole.GetPlayerSimpleData()
+	if levelData.Level == 0 {
+		levelData.Level = 1
+		if simpleData != nil && simpleData.Level > 0 {
+			levelData.Level = simpleData.Level
+		}
+	}
+	if maxLevel := jsonconf.GetConfigManager().GetMaxLevel(); maxLevel > 0 && levelData.Level > maxLevel {
+		levelData.Level = maxLevel
+		levelData.Exp = 0
+	}
+	if simpleData != nil {
+		simpleData.Level = levelData.Level
+	}
+}
 
+// OnRoleLogin 登录下发等级数据
+func (a *SystemAdapter) OnRoleLogin(ctx context.Context) {
+	levelData, err := a.rt.PlayerRepo().GetLevelData(ctx)
+	if err != nil {
+		log.Errorf("level sys OnRoleLogin get level data err:%v", err)
+		return
+	}
+	a.sendLevelData(ctx, levelData)
 }
 
 // AddExp 添加经验值（对外接口，供其他系统调用）
 func (a *SystemAdapter) AddExp(ctx context.Context, exp uint64) error {
+	if exp == 0 {
+		return nil
+	}
+	result, err := NewAddExpUseCase(a.rt).Execute(ctx, int64(exp))
+	if err != nil {
+		return err
+	}
+	levelData, err := a.rt.PlayerRepo().GetLevelData(ctx)
+	if err != nil {
+		return err
+	}
+	if result.LevelChanged() {
+		a.onLevelUp(ctx, result)
+	}
+	a.sendLevelData(ctx, levelData)
+	return nil
+}
+
+// SetLevel 直接设置等级（GM 指令使用），经验清零
+func (a *SystemAdapter) SetLevel(ctx context.Context, level uint32) error {
+	result, err := NewSetLevelUseCase(a.rt).Execute(ctx, level)
+	if err != nil {
+		return err
+	}
+	levelData, err := a.rt.PlayerRepo().GetLevelData(ctx)
+	if err != nil {
+		return err
+	}
+	if result.LevelChanged() {
+		a.onLevelUp(ctx, result)
+	}
+	a.sendLevelData(ctx, levelData)
 	return nil
 }
 
 // GetLevel 获取当前等级
 func (a *SystemAdapter) GetLevel(ctx context.Context) (uint32, error) {
-	return 0, nil
+	levelData, err := a.rt.PlayerRepo().GetLevelData(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return levelData.Level, nil
 }
 
 // GetExp 获取当前经验
 func (a *SystemAdapter) GetExp(ctx context.Context) (int64, error) {
-	return 0, nil
+	levelData, err := a.rt.PlayerRepo().GetLevelData(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return levelData.Exp, nil
+}
+
+// onLevelUp 等级变化后同步角色信息、DungeonActor 实体属性并抛出升级事件
+func (a *SystemAdapter) onLevelUp(ctx context.Context, result *AddExpResult) {
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		log.Errorf("level sys onLevelUp get role err:%v", err)
+		return
+	}
+	roleId := playerRole.GetPlayerRoleId()
+	if simpleData := playerRole.GetPlayerSimpleData(); simpleData != nil {
+		simpleData.Level = result.NewLevel
+	}
+	if err := database.UpdatePlayerLevel(uint(roleId), result.NewLevel); err != nil {
+		log.Warnf("update player level failed: roleId=%d err=%v", roleId, err)
+	}
+
+	data, err := proto.Marshal(&protocol.DAMSyncLevelReq{
+		Level:        result.NewLevel,
+		Attrs:        BuildLevelAttrs(result.NewLevel),
+		UnlockSkills: result.UnlockSkills,
+	})
+	if err != nil {
+		log.Errorf("marshal sync level failed: %v", err)
+	} else if err := playerRole.CallDungeonActor(ctx, uint16(protocol.DungeonActorMsgId_DAMSyncLevel), data); err != nil {
+		log.Warnf("sync level to dungeon actor failed: roleId=%d err=%v", roleId, err)
+	}
+
+	log.Infof("role %d level up %d -> %d", roleId, result.OldLevel, result.NewLevel)
+	playerRole.Publish(gevent.OnPlayerLevelUp, result.OldLevel, result.NewLevel)
+}
+
+func (a *SystemAdapter) sendLevelData(ctx context.Context, levelData *protocol.SiLevelData) {
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		return
+	}
+	resp := &protocol.S2CLevelDataReq{
+		LevelData: &protocol.SiLevelData{Level: levelData.Level, Exp: levelData.Exp},
+	}
+	if err := playerRole.SendProtoMessage(uint16(protocol.S2CProtocol_S2CLevelData), resp); err != nil {
+		log.Warnf("send level data failed: %v", err)
+	}
+}
+
+// BuildLevelAttrs 获取等级对应的基础属性
+func BuildLevelAttrs(level uint32) map[uint32]int64 {
+	cfg := jsonconf.GetConfigManager().GetLevelConfig(level)
+	if cfg == nil {
+		return nil
+	}
+	attrs := make(map[uint32]int64, len(cfg.Attrs))
+	for _, attr := range cfg.Attrs {
+		if attr != nil {
+			attrs[attr.Type] = attr.Value
+		}
+	}
+	return attrs
 }
 
 // GetLevelSys 获取等级系统
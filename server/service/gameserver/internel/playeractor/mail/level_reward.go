@@ -0,0 +1,75 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"postapocgame/server/internal/event"
+	"postapocgame/server/internal/jsonconf"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/gevent"
+	"postapocgame/server/service/gameserver/internel/gshare"
+)
+
+// levelRewardItems 汇总 (oldLevel, newLevel] 各等级配置的升级奖励，同种道具合并
+func levelRewardItems(oldLevel, newLevel uint32) []*protocol.ItemAmount {
+	configMgr := jsonconf.GetConfigManager()
+	var items []*protocol.ItemAmount
+	index := make(map[uint32]*protocol.ItemAmount)
+	for level := oldLevel + 1; level <= newLevel; level++ {
+		cfg := configMgr.GetLevelConfig(level)
+		if cfg == nil {
+			continue
+		}
+		for _, reward := range cfg.Rewards {
+			if reward == nil || reward.Count == 0 {
+				continue
+			}
+			if item, ok := index[reward.ItemId]; ok {
+				item.Count += int64(reward.Count)
+				continue
+			}
+			item := &protocol.ItemAmount{ItemId: reward.ItemId, Count: int64(reward.Count)}
+			if itemCfg := configMgr.GetItemConfig(reward.ItemId); itemCfg != nil {
+				item.ItemType = itemCfg.Type
+			}
+			index[reward.ItemId] = item
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// handleMailOnPlayerLevelUp 升级时通过系统邮件发放本次跨过的各等级奖励，背包满时不会丢失
+// GM 降级不发放
+func handleMailOnPlayerLevelUp(ctx context.Context, ev *event.Event) {
+	if len(ev.Data) < 2 {
+		return
+	}
+	oldLevel, _ := ev.Data[0].(uint32)
+	newLevel, _ := ev.Data[1].(uint32)
+	if newLevel <= oldLevel {
+		return
+	}
+	items := levelRewardItems(oldLevel, newLevel)
+	if len(items) == 0 {
+		return
+	}
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		log.Errorf("[mail] get player role on level up failed: %v", err)
+		return
+	}
+	roleId := playerRole.GetPlayerRoleId()
+	if err := SendToRole(roleId, &SystemMail{
+		Title:   "升级奖励",
+		Content: fmt.Sprintf("恭喜你升到 %d 级，请领取升级奖励。", newLevel),
+		Items:   items,
+	}); err != nil {
+		log.Errorf("[mail] send level %d->%d rewards to role %d failed: %v", oldLevel, newLevel, roleId, err)
+	}
+}
+
+func init() {
+	gevent.SubscribePlayerEvent(gevent.OnPlayerLevelUp, handleMailOnPlayerLevelUp)
+}
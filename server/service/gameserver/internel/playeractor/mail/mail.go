@@ -0,0 +1,334 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/12
+ * @Desc: 邮件：玩家邮件与系统邮件（单发、群发、全服）；附件领取以数据库条件更新保证幂等，背包变更与领取标记同事务落库
+**/
+
+package mail
+
+import (
+	"context"
+	"errors"
+	"math"
+	"postapocgame/server/internal/database"
+	"postapocgame/server/internal/event"
+	"postapocgame/server/internal/jsonconf"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/internal/servertime"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/gevent"
+	"postapocgame/server/service/gameserver/internel/gshare"
+	"postapocgame/server/service/gameserver/internel/iface"
+	"postapocgame/server/service/gameserver/internel/playeractor/bag"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// systemMailExpireSeconds 系统邮件有效期 30 天
+	systemMailExpireSeconds = 30 * 24 * 3600
+	// playerMailExpireSeconds 玩家邮件有效期 15 天
+	playerMailExpireSeconds = 15 * 24 * 3600
+	// neverExpireAt 永不过期邮件的过期时间，下发客户端时为 0
+	neverExpireAt = math.MaxInt64
+
+	// maxListSize 邮件列表最多返回的数量
+	maxListSize = 100
+	// maxAttachments 玩家邮件最多附件数
+	maxAttachments = 5
+	// maxTitleLen 标题最大字符数
+	maxTitleLen = 32
+	// maxContentLen 正文最大字符数
+	maxContentLen = 500
+)
+
+// List 获取邮件列表
+func List(playerRole iface.IPlayerRole) ([]*protocol.MailSt, error) {
+	mails, err := database.GetMailsByRole(playerRole.GetPlayerRoleId(), servertime.Now().Unix(), maxListSize)
+	if err != nil {
+		return nil, customerr.Wrap(err)
+	}
+	result := make([]*protocol.MailSt, 0, len(mails))
+	for _, mail := range mails {
+		st, err := toMailSt(mail)
+		if err != nil {
+			log.Warnf("[mail] build mail %d failed: %v", mail.ID, err)
+			continue
+		}
+		result = append(result, st)
+	}
+	return result, nil
+}
+
+// Read 阅读邮件并标记已读
+func Read(playerRole iface.IPlayerRole, mailId uint64) (*protocol.MailSt, error) {
+	roleId := playerRole.GetPlayerRoleId()
+	mail, err := getMail(roleId, mailId)
+	if err != nil {
+		return nil, err
+	}
+	if !mail.IsRead {
+		if err := database.MarkMailRead(mail.ID, roleId); err != nil {
+			return nil, customerr.Wrap(err)
+		}
+		mail.IsRead = true
+	}
+	st, err := toMailSt(mail)
+	if err != nil {
+		return nil, customerr.Wrap(err)
+	}
+	return st, nil
+}
+
+// Claim 领取单封邮件附件，返回领取到的道具
+func Claim(ctx context.Context, playerRole iface.IPlayerRole, mailId uint64) ([]*protocol.ItemAmount, error) {
+	mail, err := getMail(playerRole.GetPlayerRoleId(), mailId)
+	if err != nil {
+		return nil, err
+	}
+	return claim(ctx, playerRole, mail)
+}
+
+// ClaimAll 按发送顺序逐封领取附件，背包放不下时停止，返回领取成功的邮件与道具
+func ClaimAll(ctx context.Context, playerRole iface.IPlayerRole) ([]uint64, []*protocol.ItemAmount, error) {
+	mails, err := database.GetClaimableMails(playerRole.GetPlayerRoleId(), servertime.Now().Unix(), maxListSize)
+	if err != nil {
+		return nil, nil, customerr.Wrap(err)
+	}
+	var mailIds []uint64
+	var items []*protocol.ItemAmount
+	for _, mail := range mails {
+		claimed, err := claim(ctx, playerRole, mail)
+		if err != nil {
+			if customerr.GetErrCode(err) == int32(protocol.ErrorCode_Mail_AlreadyClaimed) {
+				continue
+			}
+			if len(mailIds) > 0 && customerr.GetErrCode(err) == int32(protocol.ErrorCode_Bag_Full) {
+				break
+			}
+			return mailIds, items, err
+		}
+		mailIds = append(mailIds, uint64(mail.ID))
+		items = append(items, claimed...)
+	}
+	return mailIds, items, nil
+}
+
+// claim 附件放入背包的副本，以“未领取”为条件标记领取并保存背包，成功后才提交到内存
+func claim(ctx context.Context, playerRole iface.IPlayerRole, mail *database.Mail) ([]*protocol.ItemAmount, error) {
+	now := servertime.Now().Unix()
+	if mail.ExpireAt <= now {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Mail_Expired), "mail %d expired", mail.ID)
+	}
+	if !mail.HasAttachment || mail.Claimed {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Mail_AlreadyClaimed), "mail %d has nothing to claim", mail.ID)
+	}
+	items, err := mail.GetAttachments()
+	if err != nil {
+		return nil, customerr.Wrap(err)
+	}
+	bagSys := bag.GetBagSys(ctx)
+	if bagSys == nil {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_System_NotEnabled), "bag system not opened")
+	}
+	roleId := playerRole.GetPlayerRoleId()
+	err = bagSys.ApplyWithPersist(ctx, &bag.ItemChange{Add: items}, func(binaryData *protocol.PlayerRoleBinaryData) error {
+		ok, err := database.ClaimMailWithBinaryData(mail.ID, roleId, now, binaryData)
+		if err != nil {
+			return customerr.Wrap(err)
+		}
+		if !ok {
+			return customerr.NewErrorByCode(int32(protocol.ErrorCode_Mail_AlreadyClaimed), "mail %d already claimed", mail.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	mail.Claimed = true
+	mail.IsRead = true
+	log.Infof("[mail] claim: mail=%d roleId=%d items=%v", mail.ID, roleId, items)
+	return items, nil
+}
+
+// Delete 删除邮件，带未领取附件的邮件跳过，返回删除成功的邮件
+func Delete(playerRole iface.IPlayerRole, mailIds []uint64) ([]uint64, error) {
+	if len(mailIds) == 0 || len(mailIds) > maxListSize {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "mail count must be in [1, %d]", maxListSize)
+	}
+	ids := make([]uint, 0, len(mailIds))
+	for _, id := range mailIds {
+		ids = append(ids, uint(id))
+	}
+	deleted, err := database.DeleteMails(playerRole.GetPlayerRoleId(), ids)
+	if err != nil {
+		return nil, customerr.Wrap(err)
+	}
+	result := make([]uint64, 0, len(deleted))
+	for _, id := range deleted {
+		result = append(result, uint64(id))
+	}
+	return result, nil
+}
+
+// Send 按角色名发送玩家邮件，附件从背包格子中扣除并与邮件同事务落库
+func Send(ctx context.Context, playerRole iface.IPlayerRole, req *protocol.C2SMailSendReq) (uint64, error) {
+	if err := checkText(req.Title, req.Content); err != nil {
+		return 0, err
+	}
+	if len(req.Items) > maxAttachments {
+		return 0, customerr.NewErrorByCode(int32(protocol.ErrorCode_Mail_AttachmentLimit), "at most %d attachments", maxAttachments)
+	}
+	receiver, err := database.GetPlayerByRoleName(req.ToRoleName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, customerr.NewErrorByCode(int32(protocol.ErrorCode_Mail_ReceiverNotFound), "role %s not found", req.ToRoleName)
+		}
+		return 0, customerr.Wrap(err)
+	}
+	senderId := playerRole.GetPlayerRoleId()
+	if uint64(receiver.ID) == senderId {
+		return 0, customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "cannot send mail to self")
+	}
+
+	bagSys := bag.GetBagSys(ctx)
+	if bagSys == nil {
+		return 0, customerr.NewErrorByCode(int32(protocol.ErrorCode_System_NotEnabled), "bag system not opened")
+	}
+	change := &bag.ItemChange{}
+	items := make([]*protocol.ItemAmount, 0, len(req.Items))
+	for _, sendItem := range req.Items {
+		item := bagSys.GetSlotItem(ctx, sendItem.Slot)
+		if item == nil {
+			return 0, customerr.NewErrorByCode(int32(protocol.ErrorCode_Item_NotFound), "slot %d is empty", sendItem.Slot)
+		}
+		count := sendItem.Count
+		if count == 0 {
+			count = item.Count
+		}
+		itemCfg := jsonconf.GetConfigManager().GetItemConfig(item.ItemId)
+		if itemCfg == nil {
+			return 0, customerr.NewErrorByCode(int32(protocol.ErrorCode_Item_NotFound), "item config not found: %d", item.ItemId)
+		}
+		if !canAttach(item, itemCfg) {
+			return 0, customerr.NewErrorByCode(int32(protocol.ErrorCode_Mail_ItemBound), "item %d cannot be mailed", item.ItemId)
+		}
+		change.TakeSlots = append(change.TakeSlots, &bag.SlotAmount{Slot: sendItem.Slot, Count: count})
+		items = append(items, &protocol.ItemAmount{ItemId: item.ItemId, Count: int64(count)})
+	}
+
+	now := servertime.Now().Unix()
+	mail := &database.Mail{
+		RoleId:     uint64(receiver.ID),
+		SenderId:   senderId,
+		SenderName: playerRole.GetPlayerSimpleData().GetRoleName(),
+		Title:      req.Title,
+		Content:    req.Content,
+		ExpireAt:   now + playerMailExpireSeconds,
+		CreatedAt:  now,
+	}
+	if err := mail.SetAttachments(items); err != nil {
+		return 0, customerr.Wrap(err)
+	}
+	if len(items) == 0 {
+		err = customerr.Wrap(database.CreateMails([]*database.Mail{mail}))
+	} else {
+		err = bagSys.ApplyWithPersist(ctx, change, func(binaryData *protocol.PlayerRoleBinaryData) error {
+			return customerr.Wrap(database.CreateMailWithBinaryData(mail, binaryData))
+		})
+	}
+	if err != nil {
+		return 0, err
+	}
+	log.Infof("[mail] send: mail=%d from=%d to=%d items=%v", mail.ID, senderId, mail.RoleId, items)
+	notifyNewMail(mail)
+	return uint64(mail.ID), nil
+}
+
+// checkText 校验标题与正文长度
+func checkText(title, content string) error {
+	if n := utf8.RuneCountInString(title); n == 0 || n > maxTitleLen {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "title length must be in [1, %d]", maxTitleLen)
+	}
+	if utf8.RuneCountInString(content) > maxContentLen {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "content length must be at most %d", maxContentLen)
+	}
+	return nil
+}
+
+// canAttach 绑定道具与任务道具不能邮寄
+func canAttach(item *protocol.BagItemSt, itemCfg *jsonconf.ItemConfig) bool {
+	if item.Bind == 1 || itemCfg.Bind == 1 {
+		return false
+	}
+	return protocol.ItemType(itemCfg.Type) != protocol.ItemType_ItemTypeQuest
+}
+
+func getMail(roleId uint64, mailId uint64) (*database.Mail, error) {
+	mail, err := database.GetMail(uint(mailId), roleId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Mail_NotFound), "mail %d not found", mailId)
+		}
+		return nil, customerr.Wrap(err)
+	}
+	if mail.ExpireAt <= servertime.Now().Unix() {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Mail_Expired), "mail %d expired", mailId)
+	}
+	return mail, nil
+}
+
+func toMailSt(mail *database.Mail) (*protocol.MailSt, error) {
+	items, err := mail.GetAttachments()
+	if err != nil {
+		return nil, err
+	}
+	return &protocol.MailSt{
+		MailId:     uint64(mail.ID),
+		SenderId:   mail.SenderId,
+		SenderName: mail.SenderName,
+		Title:      mail.Title,
+		Content:    mail.Content,
+		Items:      items,
+		SendTime:   mail.CreatedAt,
+		ExpireAt:   clientExpireAt(mail.ExpireAt),
+		IsRead:     mail.IsRead,
+		Claimed:    mail.Claimed,
+	}, nil
+}
+
+// clientExpireAt 永不过期的邮件下发 0
+func clientExpireAt(expireAt int64) int64 {
+	if expireAt == neverExpireAt {
+		return 0
+	}
+	return expireAt
+}
+
+// handleMailOnPlayerLogin 登录时展开未收到的全服邮件并下发未读数
+func handleMailOnPlayerLogin(ctx context.Context, _ *event.Event) {
+	playerRole, err := gshare.GetPlayerRoleFromContext(ctx)
+	if err != nil {
+		log.Errorf("[mail] get player role on login failed: %v", err)
+		return
+	}
+	roleId := playerRole.GetPlayerRoleId()
+	now := servertime.Now().Unix()
+	if _, err := database.ExpandGlobalMails(roleId, 0, now); err != nil {
+		log.Errorf("[mail] expand global mails for role %d failed: %v", roleId, err)
+	}
+	unread, err := database.CountUnreadMails(roleId, now)
+	if err != nil {
+		log.Errorf("[mail] count unread mails for role %d failed: %v", roleId, err)
+		return
+	}
+	if err := playerRole.SendProtoMessage(uint16(protocol.S2CProtocol_S2CMailUnread), &protocol.S2CMailUnreadReq{Unread: uint32(unread)}); err != nil {
+		log.Warnf("[mail] send unread to role %d failed: %v", roleId, err)
+	}
+}
+
+func init() {
+	gevent.SubscribePlayerEvent(gevent.OnPlayerLogin, handleMailOnPlayerLogin)
+}
@@ -0,0 +1,116 @@
+package mail
+
+import (
+	"postapocgame/server/internal/database"
+	"postapocgame/server/internal/jsonconf"
+	"postapocgame/server/internal/protocol"
+	"strings"
+	"testing"
+)
+
+func TestCheckText(t *testing.T) {
+	cases := []struct {
+		name    string
+		title   string
+		content string
+		ok      bool
+	}{
+		{"normal", "你好", "正文", true},
+		{"empty title", "", "正文", false},
+		{"title by runes", strings.Repeat("邮", maxTitleLen), "", true},
+		{"title too long", strings.Repeat("邮", maxTitleLen+1), "", false},
+		{"content too long", "你好", strings.Repeat("a", maxContentLen+1), false},
+	}
+	for _, c := range cases {
+		if got := checkText(c.title, c.content) == nil; got != c.ok {
+			t.Fatalf("%s: got ok=%v", c.name, got)
+		}
+	}
+}
+
+func TestCanAttach(t *testing.T) {
+	normal := &jsonconf.ItemConfig{Type: uint32(protocol.ItemType_ItemTypeMaterial)}
+	if !canAttach(&protocol.BagItemSt{ItemId: 1}, normal) {
+		t.Fatal("unbound item should be mailable")
+	}
+	if canAttach(&protocol.BagItemSt{ItemId: 1, Bind: 1}, normal) {
+		t.Fatal("bound item should not be mailable")
+	}
+	quest := &jsonconf.ItemConfig{Type: uint32(protocol.ItemType_ItemTypeQuest)}
+	if canAttach(&protocol.BagItemSt{ItemId: 1}, quest) {
+		t.Fatal("quest item should not be mailable")
+	}
+}
+
+func TestNoExpireSystemMail(t *testing.T) {
+	const now = 1_700_000_000
+	mail, err := newSystemMail(7, &SystemMail{Title: "拍卖所得", NoExpire: true}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mail.ExpireAt <= now+100*365*24*3600 {
+		t.Fatalf("auction delivery expires at %d", mail.ExpireAt)
+	}
+	st, err := toMailSt(mail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.ExpireAt != 0 {
+		t.Fatalf("never-expiring mail sent to client with expire_at %d", st.ExpireAt)
+	}
+	normal, err := newSystemMail(7, &SystemMail{Title: "补偿"}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if normal.ExpireAt != now+systemMailExpireSeconds {
+		t.Fatalf("system mail expires at %d", normal.ExpireAt)
+	}
+}
+
+func TestToMailSt(t *testing.T) {
+	mail := &database.Mail{ID: 3, RoleId: 7, Title: "补偿", ExpireAt: 100, CreatedAt: 10}
+	if err := mail.SetAttachments([]*protocol.ItemAmount{{ItemId: 1, Count: 500}, {ItemId: 2001, Count: 2}}); err != nil {
+		t.Fatal(err)
+	}
+	if !mail.HasAttachment {
+		t.Fatal("mail with items should have attachment")
+	}
+	st, err := toMailSt(mail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.MailId != 3 || st.SendTime != 10 || len(st.Items) != 2 || st.Items[0].Count != 500 || st.Items[1].ItemId != 2001 {
+		t.Fatalf("unexpected mail %+v", st)
+	}
+
+	empty := &database.Mail{ID: 4}
+	if err := empty.SetAttachments(nil); err != nil || empty.HasAttachment {
+		t.Fatalf("mail without items: err=%v hasAttachment=%v", err, empty.HasAttachment)
+	}
+}
+
+func TestLevelRewardItemsMergesCrossedLevels(t *testing.T) {
+	if err := jsonconf.GetConfigManager().Init("../../../../../output/config"); err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if items := levelRewardItems(2, 4); len(items) != 0 {
+		t.Fatalf("levels without rewards got %v", items)
+	}
+	// 1 -> 10 跨过 2、5、10 级的奖励，金币合并为一条
+	counts := make(map[uint32]int64)
+	for _, item := range levelRewardItems(1, 10) {
+		if _, dup := counts[item.ItemId]; dup {
+			t.Fatalf("item %d not merged", item.ItemId)
+		}
+		counts[item.ItemId] = item.Count
+	}
+	want := map[uint32]int64{1: 2500, 1001: 5, 1003: 3, 1101: 1}
+	if len(counts) != len(want) {
+		t.Fatalf("rewards %v, want %v", counts, want)
+	}
+	for itemId, count := range want {
+		if counts[itemId] != count {
+			t.Fatalf("item %d count %d, want %d", itemId, counts[itemId], count)
+		}
+	}
+}
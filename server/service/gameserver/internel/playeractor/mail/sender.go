@@ -0,0 +1,174 @@
+package mail
+
+import (
+	"context"
+	"postapocgame/server/internal/database"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/internal/servertime"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+	"postapocgame/server/service/gameserver/internel/gatewaylink"
+	"postapocgame/server/service/gameserver/internel/manager"
+	"postapocgame/server/service/gameserver/internel/opsapi"
+	"postapocgame/server/service/gameserver/internel/playeractor/auction"
+
+	"gorm.io/gorm"
+)
+
+// SystemSenderName 系统邮件的发件人名称
+const SystemSenderName = "系统"
+
+// SystemMail 系统邮件内容，供运维、拍卖行、活动奖励等系统发送
+type SystemMail struct {
+	Title   string
+	Content string
+	Items   []*protocol.ItemAmount
+	// NoExpire 永不过期，用于拍卖行所得与退回等托管资产，避免长期离线的收件人失去资产
+	NoExpire bool
+}
+
+// newSystemMail 构造一封发给 roleId 的系统邮件
+func newSystemMail(roleId uint64, m *SystemMail, now int64) (*database.Mail, error) {
+	expireAt := int64(neverExpireAt)
+	if !m.NoExpire {
+		expireAt = now + systemMailExpireSeconds
+	}
+	mail := &database.Mail{
+		RoleId:     roleId,
+		SenderName: SystemSenderName,
+		Title:      m.Title,
+		Content:    m.Content,
+		ExpireAt:   expireAt,
+		CreatedAt:  now,
+	}
+	if err := mail.SetAttachments(m.Items); err != nil {
+		return nil, customerr.Wrap(err)
+	}
+	return mail, nil
+}
+
+// SendToRole 向单个角色发送系统邮件，角色离线时登录后可见
+func SendToRole(roleId uint64, m *SystemMail) error {
+	return SendToRoles([]uint64{roleId}, m)
+}
+
+// SendToRoles 向多个角色发送系统邮件，同一事务写入
+func SendToRoles(roleIds []uint64, m *SystemMail) error {
+	if len(roleIds) == 0 {
+		return nil
+	}
+	now := servertime.Now().Unix()
+	mails := make([]*database.Mail, 0, len(roleIds))
+	for _, roleId := range roleIds {
+		mail, err := newSystemMail(roleId, m, now)
+		if err != nil {
+			return err
+		}
+		mails = append(mails, mail)
+	}
+	if err := database.CreateMails(mails); err != nil {
+		return customerr.Wrap(err)
+	}
+	for _, mail := range mails {
+		notifyNewMail(mail)
+	}
+	return nil
+}
+
+// SendTx 在调用方的事务内写入系统邮件，事务提交后由调用方调用 NotifyNewMail 通知在线收件人
+func SendTx(tx *gorm.DB, roleId uint64, m *SystemMail) (*database.Mail, error) {
+	mail, err := newSystemMail(roleId, m, servertime.Now().Unix())
+	if err != nil {
+		return nil, err
+	}
+	if err := database.CreateMailsTx(tx, []*database.Mail{mail}); err != nil {
+		return nil, customerr.Wrap(err)
+	}
+	return mail, nil
+}
+
+// SendToAll 发送全服邮件：在线角色立即展开，离线角色及之后创建的角色在登录时展开
+func SendToAll(m *SystemMail) error {
+	now := servertime.Now().Unix()
+	global := &database.GlobalMail{
+		SenderName: SystemSenderName,
+		Title:      m.Title,
+		Content:    m.Content,
+		ExpireAt:   now + systemMailExpireSeconds,
+		CreatedAt:  now,
+	}
+	if err := global.SetAttachments(m.Items); err != nil {
+		return customerr.Wrap(err)
+	}
+	if err := database.CreateGlobalMail(global); err != nil {
+		return customerr.Wrap(err)
+	}
+	log.Infof("[mail] global mail %d created: %s", global.ID, global.Title)
+	for _, role := range manager.GetPlayerRoleManager().GetAll() {
+		mails, err := database.ExpandGlobalMails(role.GetPlayerRoleId(), global.ID, now)
+		if err != nil {
+			// 展开失败的角色在下次登录时补齐
+			log.Errorf("[mail] expand global mail %d for role %d failed: %v", global.ID, role.GetPlayerRoleId(), err)
+			continue
+		}
+		for _, mail := range mails {
+			notifyNewMail(mail)
+		}
+	}
+	return nil
+}
+
+// NotifyNewMail 通知在线收件人有新邮件，可在任意协程调用
+func NotifyNewMail(mail *database.Mail) {
+	notifyNewMail(mail)
+}
+
+func notifyNewMail(mail *database.Mail) {
+	role := manager.GetPlayerRole(mail.RoleId)
+	if role == nil {
+		return
+	}
+	st, err := toMailSt(mail)
+	if err != nil {
+		log.Warnf("[mail] build mail %d failed: %v", mail.ID, err)
+		return
+	}
+	if err := gatewaylink.SendToSessionProto(role.GetSessionId(), uint16(protocol.S2CProtocol_S2CMailNew), &protocol.S2CMailNewReq{Mail: st}); err != nil {
+		log.Warnf("[mail] notify role %d new mail failed: %v", mail.RoleId, err)
+	}
+}
+
+// OpsMailSender 运维接口发送邮件的实现，RoleIds 为空时发送全服邮件
+func OpsMailSender(_ context.Context, m *opsapi.SystemMail) error {
+	mail := &SystemMail{Title: m.Title, Content: m.Content, Items: m.Items}
+	if len(m.RoleIds) == 0 {
+		return SendToAll(mail)
+	}
+	return SendToRoles(m.RoleIds, mail)
+}
+
+// AuctionDeliverer 拍卖行卖家所得与退回道具的邮件发放实现
+type AuctionDeliverer struct{}
+
+// DeliverTx 实现 auction.Deliverer，邮件永不过期
+func (AuctionDeliverer) DeliverTx(tx *gorm.DB, delivery *auction.Delivery) error {
+	_, err := SendTx(tx, delivery.RoleId, &SystemMail{Title: delivery.Title, Content: delivery.Content, Items: delivery.Items, NoExpire: true})
+	return err
+}
+
+// OnDelivered 实现 auction.Deliverer，事务提交后提醒在线卖家
+func (AuctionDeliverer) OnDelivered(delivery *auction.Delivery) {
+	role := manager.GetPlayerRole(delivery.RoleId)
+	if role == nil {
+		return
+	}
+	now := servertime.Now().Unix()
+	unread, err := database.CountUnreadMails(delivery.RoleId, now)
+	if err != nil {
+		log.Warnf("[mail] count unread mails for role %d failed: %v", delivery.RoleId, err)
+		return
+	}
+	if err := gatewaylink.SendToSessionProto(role.GetSessionId(), uint16(protocol.S2CProtocol_S2CMailUnread), &protocol.S2CMailUnreadReq{Unread: uint32(unread)}); err != nil {
+		log.Warnf("[mail] notify role %d unread failed: %v", delivery.RoleId, err)
+	}
+}
@@ -2,8 +2,11 @@ package register
 
 import (
 	"postapocgame/server/internal/protocol"
+	"postapocgame/server/service/gameserver/internel/playeractor/bag"
 	"postapocgame/server/service/gameserver/internel/playeractor/controller"
 	"postapocgame/server/service/gameserver/internel/playeractor/deps"
+	"postapocgame/server/service/gameserver/internel/playeractor/equip"
+	"postapocgame/server/service/gameserver/internel/playeractor/gm"
 	"postapocgame/server/service/gameserver/internel/playeractor/level"
 	"postapocgame/server/service/gameserver/internel/playeractor/router"
 	"postapocgame/server/service/gameserver/internel/playeractor/skill"
@@ -11,10 +14,20 @@ import (
 
 func All(rt *deps.Runtime) {
 	registerSkillHandlers()
+	registerBagHandlers()
+	registerEquipHandlers()
+	registerPickUpHandlers()
+	registerFuBenHandlers()
+	registerAuctionHandlers()
+	registerMailHandlers()
+	registerChatHandlers()
+	registerGmHandlers()
 
 	// 注册所有系统工厂
 	level.RegisterSystemFactory(rt)
 	skill.RegisterSystemFactory(rt)
+	bag.RegisterSystemFactory(rt)
+	equip.RegisterSystemFactory(rt)
 }
 
 // registerSkillHandlers 注册技能相关协议处理器
@@ -22,3 +35,68 @@ func registerSkillHandlers() {
 	skillController := controller.NewSkillController()
 	router.RegisterProtocolHandler(uint16(protocol.C2SProtocol_C2SUseSkill), skillController.HandleUseSkill)
 }
+
+// registerBagHandlers 注册背包相关协议处理器
+func registerBagHandlers() {
+	bagController := controller.NewBagController()
+	router.RegisterProtocolHandler(uint16(protocol.C2SProtocol_C2SBagList), bagController.HandleBagList)
+	router.RegisterProtocolHandler(uint16(protocol.C2SProtocol_C2SBagUseItem), bagController.HandleUseItem)
+	router.RegisterProtocolHandler(uint16(protocol.C2SProtocol_C2SBagSplitItem), bagController.HandleSplitItem)
+	router.RegisterProtocolHandler(uint16(protocol.C2SProtocol_C2SBagSort), bagController.HandleSort)
+	router.RegisterProtocolHandler(uint16(protocol.C2SProtocol_C2SBagDiscardItem), bagController.HandleDiscardItem)
+}
+
+// registerEquipHandlers 注册装备相关协议处理器
+func registerEquipHandlers() {
+	equipController := controller.NewEquipController()
+	router.RegisterProtocolHandler(uint16(protocol.C2SProtocol_C2SEquipWear), equipController.HandleWear)
+	router.RegisterProtocolHandler(uint16(protocol.C2SProtocol_C2SEquipTakeOff), equipController.HandleTakeOff)
+}
+
+// registerPickUpHandlers 注册拾取相关协议处理器
+func registerPickUpHandlers() {
+	pickUpController := controller.NewPickUpController()
+	router.RegisterProtocolHandler(uint16(protocol.C2SProtocol_C2SPickUp), pickUpController.HandlePickUp)
+}
+
+// registerFuBenHandlers 注册副本相关协议处理器
+func registerFuBenHandlers() {
+	fubenController := controller.NewFuBenController()
+	router.RegisterProtocolHandler(uint16(protocol.C2SProtocol_C2SEnterFuBen), fubenController.HandleEnterFuBen)
+	router.RegisterProtocolHandler(uint16(protocol.C2SProtocol_C2SLeaveFuBen), fubenController.HandleLeaveFuBen)
+	router.RegisterProtocolHandler(uint16(protocol.C2SProtocol_C2STransferScene), fubenController.HandleTransferScene)
+}
+
+// registerAuctionHandlers 注册拍卖行相关协议处理器
+func registerAuctionHandlers() {
+	auctionController := controller.NewAuctionController()
+	router.RegisterProtocolHandler(uint16(protocol.C2SProtocol_C2SAuctionSell), auctionController.HandleSell)
+	router.RegisterProtocolHandler(uint16(protocol.C2SProtocol_C2SAuctionSearch), auctionController.HandleSearch)
+	router.RegisterProtocolHandler(uint16(protocol.C2SProtocol_C2SAuctionBuy), auctionController.HandleBuy)
+	router.RegisterProtocolHandler(uint16(protocol.C2SProtocol_C2SAuctionCancel), auctionController.HandleCancel)
+	router.RegisterProtocolHandler(uint16(protocol.C2SProtocol_C2SAuctionMine), auctionController.HandleMine)
+}
+
+// registerMailHandlers 注册邮件相关协议处理器
+func registerMailHandlers() {
+	mailController := controller.NewMailController()
+	router.RegisterProtocolHandler(uint16(protocol.C2SProtocol_C2SMailList), mailController.HandleList)
+	router.RegisterProtocolHandler(uint16(protocol.C2SProtocol_C2SMailRead), mailController.HandleRead)
+	router.RegisterProtocolHandler(uint16(protocol.C2SProtocol_C2SMailClaim), mailController.HandleClaim)
+	router.RegisterProtocolHandler(uint16(protocol.C2SProtocol_C2SMailClaimAll), mailController.HandleClaimAll)
+	router.RegisterProtocolHandler(uint16(protocol.C2SProtocol_C2SMailDelete), mailController.HandleDelete)
+	router.RegisterProtocolHandler(uint16(protocol.C2SProtocol_C2SMailSend), mailController.HandleSend)
+}
+
+// registerChatHandlers 注册聊天相关协议处理器
+func registerChatHandlers() {
+	chatController := controller.NewChatController()
+	router.RegisterProtocolHandler(uint16(protocol.C2SProtocol_C2SChatSend), chatController.HandleSend)
+}
+
+// registerGmHandlers 注册 GM 指令协议处理器及内置指令
+func registerGmHandlers() {
+	gm.RegisterBuiltinCommands()
+	gmController := controller.NewGmController()
+	router.RegisterProtocolHandler(uint16(protocol.C2SProtocol_C2SGmCommand), gmController.HandleGmCommand)
+}
@@ -2,8 +2,12 @@ package playerauth
 
 import (
 	"context"
+	"fmt"
+	"postapocgame/server/internal/servertime"
 	"postapocgame/server/service/gameserver/internel/iface"
+	"postapocgame/server/service/gameserver/internel/playeractor/domain/model"
 	"strings"
+	"time"
 )
 
 // LoginInput 登录入参
@@ -69,6 +73,13 @@ func (uc *LoginUseCase) Execute(ctx context.Context, input LoginInput) (*LoginRe
 		}, nil
 	}
 
+	if account.IsBanned(servertime.Now().Unix()) {
+		return &LoginResult{
+			Success: false,
+			Message: bannedMessage(account),
+		}, nil
+	}
+
 	token := uc.tokenProvider.Generate(account.ID)
 	return &LoginResult{
 		Success:   true,
@@ -77,3 +88,15 @@ func (uc *LoginUseCase) Execute(ctx context.Context, input LoginInput) (*LoginRe
 		AccountID: account.ID,
 	}, nil
 }
+
+// bannedMessage 封禁提示，附带原因与解封时间
+func bannedMessage(account *model.Account) string {
+	msg := "账号已被封禁"
+	if account.BanReason != "" {
+		msg += "：" + account.BanReason
+	}
+	if account.BanUntil > 0 {
+		msg += fmt.Sprintf("（解封时间 %s）", time.Unix(account.BanUntil, 0).Format(time.DateTime))
+	}
+	return msg
+}
@@ -12,11 +12,15 @@ import (
 	"postapocgame/server/pkg/log"
 	"postapocgame/server/pkg/tool"
 	"postapocgame/server/service/gameserver/internel/dungeonactor"
+	"postapocgame/server/service/gameserver/internel/dungeonlink"
 	engine2 "postapocgame/server/service/gameserver/internel/engine"
 	"postapocgame/server/service/gameserver/internel/gevent"
 	"postapocgame/server/service/gameserver/internel/gshare"
+	"postapocgame/server/service/gameserver/internel/opsapi"
 	"postapocgame/server/service/gameserver/internel/playeractor"
+	"postapocgame/server/service/gameserver/internel/playeractor/auction"
 	"postapocgame/server/service/gameserver/internel/playeractor/deps"
+	"postapocgame/server/service/gameserver/internel/playeractor/mail"
 	"postapocgame/server/service/gameserver/internel/playeractor/register"
 	"syscall"
 	"time"
@@ -86,8 +90,18 @@ func main() {
 		log.Fatalf("err:%v", err)
 	}
 
-	// 副本 / 战斗 DungeonActor（单 Actor，常驻运行）
-	dActor := dungeonactor.NewDungeonActor(actor.ModeSingle)
+	// 副本 / 战斗 DungeonActor：配置了 DungeonServer 时走远程链路，否则在进程内常驻运行
+	var (
+		dActor        *dungeonactor.DungeonActor
+		dungeonClient *dungeonlink.DungeonClient
+	)
+	if addr, ok := serverConfig.GetDungeonServerAddr(); ok {
+		dungeonClient = dungeonlink.NewDungeonClient(addr)
+		gshare.SetDungeonActorFacade(dungeonClient)
+		log.Infof("DungeonActor runs in remote dungeon server: %s", addr)
+	} else {
+		dActor = dungeonactor.NewDungeonActor(actor.ModePerKey)
+	}
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
@@ -101,12 +115,36 @@ func main() {
 		log.Fatalf("Start GameServer failed: %v", err)
 	}
 
-	if err := dActor.Start(ctx); err != nil {
-		log.Fatalf("Start DungeonActor failed: %v", err)
+	if dActor != nil {
+		if err := dActor.Start(ctx); err != nil {
+			log.Fatalf("Start DungeonActor failed: %v", err)
+		}
+	}
+	if dungeonClient != nil {
+		if err := dungeonClient.Connect(ctx); err != nil {
+			log.Fatalf("Connect DungeonServer failed: %v", err)
+		}
 	}
 
+	// 邮件系统承接运维邮件与拍卖行发放
+	opsapi.SetMailSender(mail.OpsMailSender)
+	auction.SetDeliverer(mail.AuctionDeliverer{})
+
 	gevent.Publish(context.Background(), event.NewEvent(gevent.OnSrvStart))
 
+	// 运维接口（admin-server 游戏运维模块）
+	var opsServer *opsapi.Server
+	if serverConfig.OpsAddr != "" {
+		opsServer = opsapi.NewServer(&opsapi.Config{
+			Addr:     serverConfig.OpsAddr,
+			Secret:   serverConfig.OpsSecret,
+			AllowIPs: serverConfig.OpsAllowIPs,
+		})
+		if err := opsServer.Start(ctx); err != nil {
+			log.Fatalf("Start ops api server failed: %v", err)
+		}
+	}
+
 	// 等待退出信号
 	<-ctx.Done()
 
@@ -114,6 +152,12 @@ func main() {
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer shutdownCancel()
 
+	if opsServer != nil {
+		if err := opsServer.Stop(shutdownCtx); err != nil {
+			log.Errorf("Stop ops api server failed: %v", err)
+		}
+	}
+
 	// 发布停服事件，便于各子系统清理资源
 	gevent.Publish(context.Background(), event.NewEvent(gevent.OnSrvStop))
 
@@ -123,8 +167,15 @@ func main() {
 	if err := playerRoleActor.Stop(shutdownCtx); err != nil {
 		log.Errorf("Stop playerRoleActor failed: %v", err)
 	}
-	if err := dActor.Stop(shutdownCtx); err != nil {
-		log.Errorf("Stop DungeonActor failed: %v", err)
+	if dActor != nil {
+		if err := dActor.Stop(shutdownCtx); err != nil {
+			log.Errorf("Stop DungeonActor failed: %v", err)
+		}
+	}
+	if dungeonClient != nil {
+		if err := dungeonClient.Close(); err != nil {
+			log.Errorf("Close DungeonServer link failed: %v", err)
+		}
 	}
 	// 获取 PlayerRoleManager，并指定批次大小（每批 100 个角色）
 	if err := deps.GetPlayerRoleManager().FlushAndSave(shutdownCtx, 100); err != nil {
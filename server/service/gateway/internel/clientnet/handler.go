@@ -10,23 +10,35 @@ import (
 	"context"
 	"fmt"
 	"postapocgame/server/internal/network"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/internal/servertime"
+	"postapocgame/server/pkg/customerr"
 	"postapocgame/server/pkg/log"
 	"postapocgame/server/pkg/routine"
+	"postapocgame/server/service/gateway/internel/flood"
 	"strings"
 	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
 )
 
+// kickCloseDelay 下发踢下线消息后等待发送协程写出再断开连接
+const kickCloseDelay = 200 * time.Millisecond
+
 type ClientHandler struct {
 	SessionMgr  *SessionManager
 	GsConnector IGameServerConnector
+	Guard       *flood.Guard // 限流与 IP 封禁，为 nil 时不限流
 	Sessions    map[network.IConnection]*Session
 	mu          sync.RWMutex
 }
 
-func NewClientHandler(gsConnector IGameServerConnector, sessionMgr *SessionManager) *ClientHandler {
+func NewClientHandler(gsConnector IGameServerConnector, sessionMgr *SessionManager, guard *flood.Guard) *ClientHandler {
 	return &ClientHandler{
 		SessionMgr:  sessionMgr,
 		GsConnector: gsConnector,
+		Guard:       guard,
 		Sessions:    make(map[network.IConnection]*Session),
 	}
 }
@@ -39,19 +51,58 @@ func (h *ClientHandler) HandleMessage(_ context.Context, conn network.IConnectio
 		return nil
 	}
 
+	// 封禁期内的 IP 不建立会话
+	now := servertime.Now()
+	ip := flood.HostOf(conn.RemoteAddr())
+	if h.Guard != nil && h.Guard.IsBanned(ip, now) {
+		h.kickConn(conn, protocol.KickReason_KickIpBanned)
+		return nil
+	}
+
 	// 获取或创建会话
 	session := h.getOrCreateSession(conn)
 	if session == nil {
 		return fmt.Errorf("failed to create session")
 	}
+	if session.kicked.Load() {
+		return nil
+	}
+	var msgId uint16
+	clientMsg, err := network.DefaultCodec().DecodeClientMessage(msg.Payload)
+	if err == nil {
+		msgId = clientMsg.MsgId
+	}
+	if session.limiter != nil {
+		verdict := session.limiter.Check(msgId, len(msg.Payload), now)
+		switch verdict.Action {
+		case flood.ActionDrop:
+			log.Debugf("drop client msg: session=%s msgId=%d reason=%s class=%s", session.Id, msgId, verdict.Reason, verdict.Class)
+			return nil
+		case flood.ActionKick:
+			log.Warnf("kick flooding session: session=%s ip=%s msgId=%d reason=%s class=%s", session.Id, ip, msgId, verdict.Reason, verdict.Class)
+			h.Guard.Ban(ip, now)
+			h.kickSession(conn, session, verdict.Reason)
+			return nil
+		}
+	}
 
 	// 更新活跃时间
 	h.SessionMgr.UpdateActivity(session.Id)
 
-	return h.GsConnector.ForwardClientMsg(context.Background(), &network.ForwardMessage{
+	// 区服选择由 Gateway 处理，不转发
+	if clientMsg != nil && h.handleRealmMsg(session, clientMsg) {
+		return nil
+	}
+
+	err = h.GsConnector.ForwardClientMsg(context.Background(), &network.ForwardMessage{
 		SessionId: session.Id,
 		Payload:   msg.Payload,
 	})
+	if customerr.GetErrCode(err) == int32(protocol.ErrorCode_Realm_NotSelected) {
+		SendError(session, err)
+		return nil
+	}
+	return err
 }
 
 // getOrCreateSession 获取或创建会话
@@ -79,6 +130,9 @@ func (h *ClientHandler) getOrCreateSession(conn network.IConnection) *Session {
 		return nil
 	}
 
+	if h.Guard != nil {
+		session.limiter = h.Guard.NewSessionLimiter()
+	}
 	h.Sessions[conn] = session
 
 	// 启动发送协程
@@ -93,14 +147,17 @@ func (h *ClientHandler) getOrCreateSession(conn network.IConnection) *Session {
 // handleSend 处理发送（优雅关闭版本）
 func (h *ClientHandler) handleSend(conn network.IConnection, session *Session) {
 	routine.Run(func() {
+		// 连接不可用时会话转入断开保留状态，未送达的消息留待重连后补发
+		var detach bool
+		var unsent []byte
 		defer func() {
 			// 清理工作
-			h.mu.Lock()
-			delete(h.Sessions, conn)
-			h.mu.Unlock()
-			// 关闭会话
-			if err := h.SessionMgr.CloseSession(session.Id); err != nil {
-				log.Errorf("CloseSession failed for session %s, err:%v", session.Id, err)
+			h.removeConn(conn, session)
+			if detach {
+				if err := conn.Close(); err != nil {
+					log.Warnf("close conn failed for session %s, err:%v", session.Id, err)
+				}
+				h.SessionMgr.DetachSession(session.Id, unsent)
 			}
 			log.Infof("handleSend goroutine exited for session: %s", session.Id)
 		}()
@@ -111,13 +168,7 @@ func (h *ClientHandler) handleSend(conn network.IConnection, session *Session) {
 
 		for {
 			select {
-			case data, ok := <-session.SendChan:
-				// channel 被关闭，退出
-				if !ok {
-					log.Infof("SendChan closed for session: %s", session.Id)
-					return
-				}
-
+			case data := <-session.SendChan:
 				message := network.GetMessage()
 				message.Type = network.MsgTypeClient
 				message.Payload = data
@@ -132,6 +183,7 @@ func (h *ClientHandler) handleSend(conn network.IConnection, session *Session) {
 					if isConnectionError(err) {
 						log.Errorf("Connection error detected for session %s: %v", session.Id, err)
 						network.PutMessage(message)
+						detach, unsent = true, data
 						return
 					}
 
@@ -139,6 +191,7 @@ func (h *ClientHandler) handleSend(conn network.IConnection, session *Session) {
 					if consecutiveFailures >= maxConsecutiveFailures {
 						log.Errorf("Max consecutive failures reached for session %s, closing connection", session.Id)
 						network.PutMessage(message)
+						detach, unsent = true, data
 						return
 					}
 
@@ -154,11 +207,132 @@ func (h *ClientHandler) handleSend(conn network.IConnection, session *Session) {
 			case <-session.stopChan: // 🔧 新增：会话级别的停止信号
 				log.Infof("Session stop signal received for session: %s", session.Id)
 				return
+
+			case <-session.connStop:
+				log.Infof("Conn stop signal received for session: %s", session.Id)
+				return
 			}
 		}
 	})
 }
 
+// OnDisconnect 客户端连接断开（读循环退出），会话转入断开保留状态等待重连
+func (h *ClientHandler) OnDisconnect(conn network.IConnection) {
+	h.mu.RLock()
+	session, ok := h.Sessions[conn]
+	h.mu.RUnlock()
+	if !ok {
+		return
+	}
+	h.removeConn(conn, session)
+	// 被踢的会话不保留
+	if session.kicked.Load() {
+		if err := h.SessionMgr.CloseSession(session.Id); err != nil {
+			log.Errorf("CloseSession failed for session %s, err:%v", session.Id, err)
+		}
+		return
+	}
+	h.SessionMgr.DetachSession(session.Id, nil)
+}
+
+// kickSession 下发踢下线消息，稍后关闭会话与连接；被踢的会话不进入断线保留，之后该连接上的消息全部忽略
+func (h *ClientHandler) kickSession(conn network.IConnection, session *Session, reason protocol.KickReason) {
+	if !session.kicked.CompareAndSwap(false, true) {
+		return
+	}
+	if err := SendProto(session, uint16(protocol.S2CProtocol_S2CKick), &protocol.S2CKickReq{Reason: reason, Msg: reason.String()}); err != nil {
+		log.Errorf("send kick to session %s failed: %v", session.Id, err)
+	}
+	time.AfterFunc(kickCloseDelay, func() {
+		h.removeConn(conn, session)
+		if err := h.SessionMgr.CloseSession(session.Id); err != nil {
+			log.Errorf("CloseSession failed for session %s, err:%v", session.Id, err)
+		}
+		if err := conn.Close(); err != nil {
+			log.Warnf("close kicked conn failed for session %s, err:%v", session.Id, err)
+		}
+	})
+}
+
+// NotifyKick 下发踢下线消息后稍后关闭连接，会话在连接断开时由 OnDisconnect 回收（GameServer 踢人 RPC 使用）
+// 会话没有连接（断线保留中）或已被踢时返回 false，由调用方直接关闭会话
+func (sm *SessionManager) NotifyKick(session *Session, reason protocol.KickReason, msg string) bool {
+	conn := sm.SessionConn(session)
+	if conn == nil || !session.kicked.CompareAndSwap(false, true) {
+		return false
+	}
+	if msg == "" {
+		msg = reason.String()
+	}
+	if err := SendProto(session, uint16(protocol.S2CProtocol_S2CKick), &protocol.S2CKickReq{Reason: reason, Msg: msg}); err != nil {
+		log.Errorf("send kick to session %s failed: %v", session.Id, err)
+	}
+	time.AfterFunc(kickCloseDelay, func() {
+		if err := conn.Close(); err != nil {
+			log.Warnf("close kicked conn failed for session %s, err:%v", session.Id, err)
+		}
+	})
+	return true
+}
+
+// kickConn 尚未建立会话的连接直接下发踢下线消息并断开
+func (h *ClientHandler) kickConn(conn network.IConnection, reason protocol.KickReason) {
+	if payload, err := encodeS2C(uint16(protocol.S2CProtocol_S2CKick), &protocol.S2CKickReq{Reason: reason, Msg: reason.String()}); err == nil {
+		message := network.GetMessage()
+		message.Type = network.MsgTypeClient
+		message.Payload = payload
+		if err := conn.SendMessage(message); err != nil {
+			log.Warnf("send kick to %s failed: %v", conn.RemoteAddr(), err)
+		}
+		network.PutMessage(message)
+	}
+	if err := conn.Close(); err != nil {
+		log.Warnf("close banned conn %s failed: %v", conn.RemoteAddr(), err)
+	}
+}
+
+func encodeS2C(msgId uint16, msg proto.Message) ([]byte, error) {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	return network.DefaultCodec().EncodeClientMessage(&network.ClientMessage{
+		MsgId: msgId,
+		Data:  data,
+	})
+}
+
+// SendProto Gateway 直接向会话下发 S2C 消息，经 SendChan 与 GameServer 下行消息保持顺序；缓冲区满时丢弃
+func SendProto(session *Session, msgId uint16, msg proto.Message) error {
+	payload, err := encodeS2C(msgId, msg)
+	if err != nil {
+		return customerr.Wrap(err)
+	}
+	if err := session.enqueue(context.Background(), payload, 0); err != nil {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Internal_Error), "session %s send failed: %v", session.Id, err)
+	}
+	return nil
+}
+
+// SendError 向会话下发 S2CError
+func SendError(session *Session, err error) {
+	if sErr := SendProto(session, uint16(protocol.S2CProtocol_S2CError), &protocol.ErrorData{
+		Code: customerr.GetErrCode(err),
+		Msg:  customerr.GetErrMsgByErr(err),
+	}); sErr != nil {
+		log.Warnf("send error to session %s failed: %v", session.Id, sErr)
+	}
+}
+
+// removeConn 解除连接与会话的映射
+func (h *ClientHandler) removeConn(conn network.IConnection, session *Session) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.Sessions[conn] == session {
+		delete(h.Sessions, conn)
+	}
+}
+
 // isConnectionError 判断是否是连接错误（不可恢复的错误）
 func isConnectionError(err error) bool {
 	if err == nil {
@@ -10,6 +10,9 @@ import (
 	"context"
 	"net"
 	"postapocgame/server/internal/network"
+	"postapocgame/server/internal/protocol"
+
+	"google.golang.org/protobuf/proto"
 )
 
 // ConnType 连接类型
@@ -27,6 +30,7 @@ const (
 	SessionStateConnected SessionState = iota // 已连接
 	SessionStateAuthed                        // 已认证
 	SessionStateClosed                        // 已关闭
+	SessionStateDetached                      // 连接已断开，等待凭 reconnect_key 恢复
 )
 
 // IConnection 网络连接接口
@@ -36,11 +40,20 @@ type IConnection interface {
 	Type() ConnType
 }
 
-// IGameServerConnector GameServer连接器接口
+// IGameServerConnector GameServer连接器接口，多区服时按会话所选区服路由
 type IGameServerConnector interface {
-	Connect(ctx context.Context, addr string) error
+	// Connect 连接全部区服的 GameServer
+	Connect(ctx context.Context) error
 	NotifySessionEvent(ctx context.Context, event *network.SessionEvent) error
 	ForwardClientMsg(ctx context.Context, fwdMsg *network.ForwardMessage) error
 	ReceiveGsMessage(ctx context.Context) (*network.ForwardMessage, error)
+	// CallProto 调用 GameServer RPC
+	CallProto(ctx context.Context, sessionId string, msgId uint16, req proto.Message, resp proto.Message) error
+	// GetRPCServer 获取 Gateway 对 GameServer 提供的RPC服务，用于注册处理函数
+	GetRPCServer() *network.RPCServer
+	// ListRealms 区服列表及连接状态
+	ListRealms() []*protocol.RealmInfo
+	// SelectRealm 会话选择区服，之后的消息转发到该区服
+	SelectRealm(ctx context.Context, sessionId string, platformId, srvId uint32) (*protocol.RealmInfo, error)
 	Close() error
 }
@@ -0,0 +1,53 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/10
+ * @Desc: 区服查询与选择（Gateway 本地处理的客户端协议）
+**/
+
+package clientnet
+
+import (
+	"context"
+	"postapocgame/server/internal/network"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// selectRealmTimeout 选择区服时通知 GameServer 的超时
+const selectRealmTimeout = 5 * time.Second
+
+// handleRealmMsg 处理区服协议，返回 false 表示不是区服协议、需转发到 GameServer
+func (h *ClientHandler) handleRealmMsg(session *Session, msg *network.ClientMessage) bool {
+	switch protocol.C2SProtocol(msg.MsgId) {
+	case protocol.C2SProtocol_C2SQueryRealms:
+		if err := SendProto(session, uint16(protocol.S2CProtocol_S2CRealmList), &protocol.S2CRealmListReq{
+			Realms: h.GsConnector.ListRealms(),
+		}); err != nil {
+			log.Warnf("send realm list to session %s failed: %v", session.Id, err)
+		}
+		return true
+	case protocol.C2SProtocol_C2SSelectRealm:
+		var req protocol.C2SSelectRealmReq
+		if err := proto.Unmarshal(msg.Data, &req); err != nil {
+			SendError(session, customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "invalid select realm request"))
+			return true
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), selectRealmTimeout)
+		defer cancel()
+		realm, err := h.GsConnector.SelectRealm(ctx, session.Id, req.PlatformId, req.SrvId)
+		if err != nil {
+			log.Warnf("session %s select realm %d-%d failed: %v", session.Id, req.PlatformId, req.SrvId, err)
+			SendError(session, err)
+			return true
+		}
+		if err := SendProto(session, uint16(protocol.S2CProtocol_S2CSelectRealm), &protocol.S2CSelectRealmReq{Realm: realm}); err != nil {
+			log.Warnf("send select realm result to session %s failed: %v", session.Id, err)
+		}
+		return true
+	}
+	return false
+}
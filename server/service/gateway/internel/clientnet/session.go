@@ -1,41 +1,101 @@
 package clientnet
 
 import (
+	"context"
+	"errors"
 	"net"
+	"postapocgame/server/service/gateway/internel/flood"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+var (
+	// ErrSessionNotFound 会话不存在或已关闭
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrSendBufferFull 会话发送缓冲区已满
+	ErrSendBufferFull = errors.New("session send buffer full")
+	// errSessionClosed 写入途中会话被关闭或被新会话接管
+	errSessionClosed = errors.New("session closed")
+)
+
 // Session 统一的会话抽象
 type Session struct {
 	Id         string        // 会话ID
 	Addr       net.Addr      // 客户端地址
+	Conn       IConnection   // 客户端连接（用于主动断开）
 	ConnType   ConnType      // 连接类型
 	State      SessionState  // 会话状态
 	UserId     string        // 用户ID(认证后设置)
-	SendChan   chan []byte   // 发送消息通道
+	SendChan   chan []byte   // 发送消息通道，只经 enqueue 写入，不关闭
+	sendMu     sync.Mutex    // 串行化 SendChan 写入，恢复会话补发期间持有，保证补发消息排在之后投递的消息之前
 	stopChan   chan struct{} // 🔧 新增：停止信号
 	CreatedAt  time.Time     // 创建时间
 	LastActive time.Time     // 最后活跃时间
 	closeOnce  sync.Once
+
+	// 断线恢复：连接断开后会话保留到 resumeTimer 到期，期间下行消息继续缓存在 SendChan
+	connStop    chan struct{} // 当前连接发送协程的停止信号，连接断开时关闭
+	DetachedAt  time.Time     // 连接断开时间
+	unsent      [][]byte      // 连接断开时发送失败的消息，恢复时排在 SendChan 之前补发
+	resumeTimer *time.Timer
+	connOnce    sync.Once
+
+	limiter *flood.SessionLimiter // 只在连接读协程上使用
+	kicked  atomic.Bool           // 已下发踢下线，等待断开
 }
 
-// SafeClose ensures SendChan is closed exactly once
+// SafeClose 关闭 stopChan 通知所有监听者，只执行一次
+// SendChan 不关闭：写入方可能与关闭并发，关闭后写入会 panic；发送协程通过 stopChan 退出
 func (s *Session) SafeClose() {
 	s.closeOnce.Do(func() {
-		// 先关闭 stopChan，通知所有监听者
 		if s.stopChan != nil {
 			close(s.stopChan)
 		}
+	})
+}
 
-		// 再关闭 SendChan
-		if s.SendChan != nil {
-			close(s.SendChan)
+// enqueue 在发送锁内写入 SendChan，缓冲区满时最多等待 wait，wait 为 0 时立即返回 ErrSendBufferFull
+func (s *Session) enqueue(ctx context.Context, data []byte, wait time.Duration) error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	select {
+	case <-s.stopChan:
+		return errSessionClosed
+	default:
+	}
+	if wait <= 0 {
+		select {
+		case s.SendChan <- data:
+			return nil
+		default:
+			return ErrSendBufferFull
 		}
-	})
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case s.SendChan <- data:
+		return nil
+	case <-s.stopChan:
+		return errSessionClosed
+	case <-timer.C:
+		return ErrSendBufferFull
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // Stop 主动停止会话（用于外部调用）
 func (s *Session) Stop() {
 	s.SafeClose()
 }
+
+// stopConn 通知当前连接的发送协程退出，会话与 SendChan 保留
+func (s *Session) stopConn() {
+	s.connOnce.Do(func() {
+		if s.connStop != nil {
+			close(s.connStop)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package clientnet
 
 import (
 	"context"
+	"errors"
 	"postapocgame/server/internal/network"
 	"postapocgame/server/internal/protocol"
 	"postapocgame/server/internal/servertime"
@@ -17,6 +18,7 @@ import (
 // SessionManager 会话管理器
 type SessionManager struct {
 	sessions map[string]*Session
+	resumed  map[string]string // 已被接管的原会话 -> 当前会话，交接途中仍发往原会话的消息转给新会话
 	mu       sync.RWMutex
 
 	gsConn   IGameServerConnector
@@ -26,18 +28,24 @@ type SessionManager struct {
 	sessionBufferSize int           // 每个会话的发送缓冲区大小
 	maxSessions       uint32        // 最大会话数
 	sessionTimeout    time.Duration // 会话超时时间
+	resumeWindow      time.Duration // 连接断开后会话保留时间，0 表示断开即关闭
 }
 
+// resumeReplayTimeout 恢复会话时补发缓存消息的最长等待时间
+const resumeReplayTimeout = 3 * time.Second
+
 // NewSessionManager 创建会话管理器
-func NewSessionManager(maxSessions uint32, sessionBufferSize int, sessionTimeout time.Duration, gsConn IGameServerConnector) *SessionManager {
+func NewSessionManager(maxSessions uint32, sessionBufferSize int, sessionTimeout, resumeWindow time.Duration, gsConn IGameServerConnector) *SessionManager {
 	return &SessionManager{
 		sessions: make(map[string]*Session),
+		resumed:  make(map[string]string),
 		gsConn:   gsConn,
 		stopChan: make(chan struct{}),
 
 		sessionBufferSize: sessionBufferSize,
 		maxSessions:       maxSessions,
 		sessionTimeout:    sessionTimeout,
+		resumeWindow:      resumeWindow,
 	}
 }
 
@@ -56,10 +64,12 @@ func (sm *SessionManager) CreateSession(conn IConnection) (*Session, error) {
 	session := &Session{
 		Id:         sessionID,
 		Addr:       conn.RemoteAddr(),
+		Conn:       conn,
 		ConnType:   conn.Type(),
 		State:      SessionStateConnected,
 		SendChan:   make(chan []byte, sm.sessionBufferSize),
 		stopChan:   make(chan struct{}), // 🔧 初始化停止信号
+		connStop:   make(chan struct{}),
 		CreatedAt:  now,
 		LastActive: now,
 	}
@@ -80,14 +90,26 @@ func (sm *SessionManager) CreateSession(conn IConnection) (*Session, error) {
 	return session, nil
 }
 
-// GetSession 获取会话
+// GetSession 获取会话，已被重连接管的原会话返回接管它的新会话
 func (sm *SessionManager) GetSession(sessionID string) (*Session, bool) {
 	sm.mu.RLock()
 	defer sm.mu.RUnlock()
 	session, ok := sm.sessions[sessionID]
+	if !ok {
+		if current, resumed := sm.resumed[sessionID]; resumed {
+			session, ok = sm.sessions[current]
+		}
+	}
 	return session, ok
 }
 
+// SessionConn 在会话锁内读取会话当前连接，断线保留期间为 nil
+func (sm *SessionManager) SessionConn(session *Session) IConnection {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return session.Conn
+}
+
 // CloseSession closes session safely and is fully idempotent
 func (sm *SessionManager) CloseSession(sessionID string) error {
 	sm.mu.Lock()
@@ -105,10 +127,18 @@ func (sm *SessionManager) CloseSession(sessionID string) error {
 	}
 
 	session.State = SessionStateClosed
+	if session.resumeTimer != nil {
+		session.resumeTimer.Stop()
+	}
 	// 安全关闭 channel（SafeClose 内部用 sync.Once）
 	session.SafeClose()
 	// 从 map 中删除
 	delete(sm.sessions, sessionID)
+	for oldId, current := range sm.resumed {
+		if current == sessionID {
+			delete(sm.resumed, oldId)
+		}
+	}
 	sm.mu.Unlock()
 
 	// 通知 GameServer
@@ -124,6 +154,185 @@ func (sm *SessionManager) CloseSession(sessionID string) error {
 	return nil
 }
 
+// DetachSession 连接断开后保留会话，resumeWindow 内可由新连接凭 reconnect_key 恢复，到期后关闭并通知 GameServer
+// 保留期间 GameServer 下发的消息继续缓存在 SendChan；unsent 为发送协程因连接断开未送达的消息
+func (sm *SessionManager) DetachSession(sessionId string, unsent []byte) {
+	if sm.resumeWindow <= 0 {
+		if err := sm.CloseSession(sessionId); err != nil {
+			log.Errorf("CloseSession %s err:%v", sessionId, err)
+		}
+		return
+	}
+
+	sm.mu.Lock()
+	session, ok := sm.sessions[sessionId]
+	if !ok || session.State == SessionStateClosed {
+		sm.mu.Unlock()
+		return
+	}
+	if unsent != nil {
+		session.unsent = append(session.unsent, unsent)
+	}
+	if session.State == SessionStateDetached {
+		sm.mu.Unlock()
+		return
+	}
+	session.State = SessionStateDetached
+	session.Conn = nil
+	session.DetachedAt = servertime.Now()
+	session.stopConn()
+	session.resumeTimer = time.AfterFunc(sm.resumeWindow, func() {
+		sm.expireDetached(sessionId)
+	})
+	sm.mu.Unlock()
+
+	log.Infof("session %s detached, resumable for %v", sessionId, sm.resumeWindow)
+}
+
+// expireDetached 保留期内未恢复的会话按断线关闭
+func (sm *SessionManager) expireDetached(sessionId string) {
+	sm.mu.RLock()
+	session, ok := sm.sessions[sessionId]
+	detached := ok && session.State == SessionStateDetached
+	sm.mu.RUnlock()
+	if !detached {
+		return
+	}
+	log.Infof("detached session %s not resumed in %v, closing", sessionId, sm.resumeWindow)
+	if err := sm.CloseSession(sessionId); err != nil {
+		log.Errorf("CloseSession %s err:%v", sessionId, err)
+	}
+}
+
+// IsDetached 会话是否处于连接断开、等待恢复的状态
+func (sm *SessionManager) IsDetached(sessionId string) bool {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	session, ok := sm.sessions[sessionId]
+	return ok && session.State == SessionStateDetached
+}
+
+// ResumeSession 新会话接管原会话：原会话缓存的下行消息按原顺序转交新会话，原会话回收且不向 GameServer 发送关闭事件
+// 原会话的连接尚未被发现断开时（客户端先于服务端察觉）直接断开旧连接
+// 返回补发的消息数
+func (sm *SessionManager) ResumeSession(oldSessionId, newSessionId string) (int, error) {
+	if oldSessionId == newSessionId {
+		return 0, customerr.NewErrorByCode(int32(protocol.ErrorCode_Param_Invalid), "session %s cannot resume itself", oldSessionId)
+	}
+
+	sm.mu.RLock()
+	oldSession, oldOk := sm.sessions[oldSessionId]
+	newSession, newOk := sm.sessions[newSessionId]
+	sm.mu.RUnlock()
+	if !oldOk {
+		return 0, customerr.NewErrorByCode(int32(protocol.ErrorCode_Player_ReconnectFailed), "session %s expired", oldSessionId)
+	}
+	if !newOk {
+		return 0, customerr.NewErrorByCode(int32(protocol.ErrorCode_Player_ReconnectFailed), "session %s not connected", newSessionId)
+	}
+
+	// 先持有双方发送锁再切换映射，直到补发完成：期间投递给原会话或新会话的消息都在锁上等待，不会排到补发消息之前
+	// 加锁顺序固定为发送锁在前、sm.mu 在后，双方发送锁按会话ID排序
+	first, second := oldSession, newSession
+	if first.Id > second.Id {
+		first, second = second, first
+	}
+	first.sendMu.Lock()
+	defer first.sendMu.Unlock()
+	second.sendMu.Lock()
+	defer second.sendMu.Unlock()
+
+	sm.mu.Lock()
+	if sm.sessions[oldSessionId] != oldSession || oldSession.State == SessionStateClosed {
+		sm.mu.Unlock()
+		return 0, customerr.NewErrorByCode(int32(protocol.ErrorCode_Player_ReconnectFailed), "session %s expired", oldSessionId)
+	}
+	if sm.sessions[newSessionId] != newSession || newSession.State == SessionStateClosed || newSession.State == SessionStateDetached {
+		sm.mu.Unlock()
+		return 0, customerr.NewErrorByCode(int32(protocol.ErrorCode_Player_ReconnectFailed), "session %s not connected", newSessionId)
+	}
+
+	if oldSession.resumeTimer != nil {
+		oldSession.resumeTimer.Stop()
+	}
+	oldSession.stopConn()
+	oldSession.State = SessionStateClosed
+	delete(sm.sessions, oldSessionId)
+	for id, current := range sm.resumed {
+		if current == oldSessionId {
+			sm.resumed[id] = newSessionId
+		}
+	}
+	sm.resumed[oldSessionId] = newSessionId
+
+	pending := oldSession.unsent
+	oldSession.unsent = nil
+	for drained := false; !drained; {
+		select {
+		case data := <-oldSession.SendChan:
+			pending = append(pending, data)
+		default:
+			drained = true
+		}
+	}
+	newSession.UserId = oldSession.UserId
+	oldConn := oldSession.Conn
+	sm.mu.Unlock()
+
+	// 在发送锁上等待的原会话写入方看到关闭后按新映射重新投递
+	oldSession.SafeClose()
+	if oldConn != nil {
+		if err := oldConn.Close(); err != nil {
+			log.Warnf("close replaced session %s conn failed: %v", oldSessionId, err)
+		}
+	}
+
+	timeout := time.NewTimer(resumeReplayTimeout)
+	defer timeout.Stop()
+	for i, data := range pending {
+		select {
+		case newSession.SendChan <- data:
+		case <-newSession.stopChan:
+			return i, customerr.NewErrorByCode(int32(protocol.ErrorCode_Player_ReconnectFailed), "session %s closed during replay", newSessionId)
+		case <-timeout.C:
+			return i, customerr.NewErrorByCode(int32(protocol.ErrorCode_Network_Timeout), "replay to session %s timeout, %d/%d sent", newSessionId, i, len(pending))
+		}
+	}
+
+	log.Infof("session %s resumed as %s, replayed %d messages", oldSessionId, newSessionId, len(pending))
+	return len(pending), nil
+}
+
+// deliverRetry 投递途中会话被接管时按最新映射重新投递的次数
+const deliverRetry = 3
+
+// Deliver 投递下行消息，已被接管的原会话投递给接管它的新会话
+// 连接断开的会话只缓存不等待，缓存写满则无法完整补发，直接按断线关闭；在线会话缓冲区满时最多等待 wait
+func (sm *SessionManager) Deliver(ctx context.Context, sessionId string, payload []byte, wait time.Duration) error {
+	for i := 0; i < deliverRetry; i++ {
+		session, ok := sm.GetSession(sessionId)
+		if !ok {
+			return ErrSessionNotFound
+		}
+		detached := sm.IsDetached(session.Id)
+		if detached {
+			wait = 0
+		}
+		err := session.enqueue(ctx, payload, wait)
+		if errors.Is(err, errSessionClosed) {
+			continue
+		}
+		if detached && errors.Is(err, ErrSendBufferFull) {
+			log.Warnf("Detached session buffer full, closing: %s", session.Id)
+			if err := sm.CloseSession(session.Id); err != nil {
+				log.Errorf("CloseSession %s err:%v", session.Id, err)
+			}
+		}
+		return err
+	}
+	return ErrSessionNotFound
+}
+
 // UpdateActivity 更新会话活跃时间
 func (sm *SessionManager) UpdateActivity(sessionId string) {
 	sm.mu.Lock()
@@ -161,6 +370,10 @@ func (sm *SessionManager) cleanupTimeoutSessions() {
 
 	sm.mu.RLock()
 	for id, session := range sm.sessions {
+		// 断开的会话由 resumeTimer 负责关闭
+		if session.State == SessionStateDetached {
+			continue
+		}
 		if sm.sessionTimeout > 0 && now.Sub(session.LastActive) > sm.sessionTimeout {
 			toClose = append(toClose, id)
 		}
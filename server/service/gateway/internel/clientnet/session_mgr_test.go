@@ -0,0 +1,361 @@
+package clientnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"postapocgame/server/internal/network"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/service/gateway/internel/flood"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+type fakeGsConn struct {
+	mu     sync.Mutex
+	events []network.SessionEvent
+}
+
+func (f *fakeGsConn) Connect(context.Context) error { return nil }
+
+func (f *fakeGsConn) NotifySessionEvent(_ context.Context, ev *network.SessionEvent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, *ev)
+	return nil
+}
+
+func (f *fakeGsConn) ForwardClientMsg(context.Context, *network.ForwardMessage) error { return nil }
+
+func (f *fakeGsConn) ReceiveGsMessage(ctx context.Context) (*network.ForwardMessage, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (f *fakeGsConn) CallProto(context.Context, string, uint16, proto.Message, proto.Message) error {
+	return nil
+}
+
+func (f *fakeGsConn) GetRPCServer() *network.RPCServer { return nil }
+
+func (f *fakeGsConn) ListRealms() []*protocol.RealmInfo { return nil }
+
+func (f *fakeGsConn) SelectRealm(context.Context, string, uint32, uint32) (*protocol.RealmInfo, error) {
+	return nil, nil
+}
+
+func (f *fakeGsConn) Close() error { return nil }
+
+func (f *fakeGsConn) closed(sessionId string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, ev := range f.events {
+		if ev.EventType == network.SessionEventClose && ev.SessionId == sessionId {
+			return true
+		}
+	}
+	return false
+}
+
+type fakeClientConn struct {
+	mu     sync.Mutex
+	sent   []string
+	closed bool
+}
+
+func (c *fakeClientConn) SendMessage(msg *network.Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return net.ErrClosed
+	}
+	c.sent = append(c.sent, string(msg.Payload))
+	return nil
+}
+
+func (c *fakeClientConn) ReceiveMessage(ctx context.Context) (*network.Message, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (c *fakeClientConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	return nil
+}
+
+func (c *fakeClientConn) RemoteAddr() net.Addr { return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)} }
+
+func (c *fakeClientConn) GetMeta() interface{} { return nil }
+
+func (c *fakeClientConn) SetMeta(interface{}) {}
+
+func (c *fakeClientConn) received() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.sent...)
+}
+
+func newTestHandler(resumeWindow time.Duration) (*ClientHandler, *fakeGsConn) {
+	gs := &fakeGsConn{}
+	return NewClientHandler(gs, NewSessionManager(0, 16, time.Minute, resumeWindow, gs), nil), gs
+}
+
+// connect 模拟客户端在新连接上发送第一条消息，返回为该连接创建的会话
+func connect(t *testing.T, h *ClientHandler, conn *fakeClientConn) *Session {
+	t.Helper()
+	if err := h.HandleMessage(context.Background(), conn, &network.Message{Type: network.MsgTypeClient}); err != nil {
+		t.Fatal(err)
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	session, ok := h.Sessions[conn]
+	if !ok {
+		t.Fatal("session not created for conn")
+	}
+	return session
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestResumeReplaysBufferedMessagesInOrder(t *testing.T) {
+	h, gs := newTestHandler(time.Minute)
+	conn1 := &fakeClientConn{}
+	s1 := connect(t, h, conn1)
+	s1.SendChan <- []byte("m1")
+	waitFor(t, "m1 delivered", func() bool { return len(conn1.received()) == 1 })
+
+	h.OnDisconnect(conn1)
+	if !h.SessionMgr.IsDetached(s1.Id) {
+		t.Fatal("session not detached after disconnect")
+	}
+	for _, m := range []string{"m2", "m3", "m4"} {
+		s1.SendChan <- []byte(m)
+	}
+
+	conn2 := &fakeClientConn{}
+	s2 := connect(t, h, conn2)
+	replayed, err := h.SessionMgr.ResumeSession(s1.Id, s2.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed != 3 {
+		t.Fatalf("replayed %d messages, want 3", replayed)
+	}
+	// 仍发往原会话的消息转给新会话
+	if session, ok := h.SessionMgr.GetSession(s1.Id); !ok || session != s2 {
+		t.Fatal("old session id does not resolve to the resuming session")
+	}
+	s2.SendChan <- []byte("m5")
+
+	waitFor(t, "replay delivered", func() bool { return len(conn2.received()) == 4 })
+	want := []string{"m2", "m3", "m4", "m5"}
+	for i, got := range conn2.received() {
+		if got != want[i] {
+			t.Fatalf("conn2 received %v, want %v", conn2.received(), want)
+		}
+	}
+	if got := conn1.received(); len(got) != 1 {
+		t.Fatalf("dropped conn received %v after disconnect", got)
+	}
+	if gs.closed(s1.Id) {
+		t.Fatal("resumed session reported as closed to GameServer")
+	}
+}
+
+// TestDeliverConcurrentWithResume 下行投递与恢复会话并发：不 panic，补发消息与之后投递的消息整体保持顺序
+func TestDeliverConcurrentWithResume(t *testing.T) {
+	gs := &fakeGsConn{}
+	h := NewClientHandler(gs, NewSessionManager(0, 256, time.Minute, time.Minute, gs), nil)
+	conn1 := &fakeClientConn{}
+	s1 := connect(t, h, conn1)
+	h.OnDisconnect(conn1)
+
+	const total = 200
+	var want []string
+	for i := 0; i < total; i++ {
+		want = append(want, fmt.Sprintf("m%d", i))
+	}
+	conn2 := &fakeClientConn{}
+	s2 := connect(t, h, conn2)
+
+	started := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		for i, m := range want {
+			if i == total/4 {
+				close(started)
+			}
+			if err := h.SessionMgr.Deliver(context.Background(), s1.Id, []byte(m), time.Second); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+	<-started
+	if _, err := h.SessionMgr.ResumeSession(s1.Id, s2.Id); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("deliver failed: %v", err)
+	}
+
+	// 原连接的发送协程退出前可能已取走少量消息，两条连接收到的消息首尾相接应完整有序
+	received := func() []string { return append(conn1.received(), conn2.received()...) }
+	waitFor(t, "all messages delivered", func() bool { return len(received()) == total })
+	for i, got := range received() {
+		if got != want[i] {
+			t.Fatalf("message %d is %s, want %s", i, got, want[i])
+		}
+	}
+}
+
+// TestDeliverConcurrentWithClose 会话关闭与投递并发时投递方不 panic
+func TestDeliverConcurrentWithClose(t *testing.T) {
+	h, _ := newTestHandler(time.Minute)
+	s := connect(t, h, &fakeClientConn{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			_ = h.SessionMgr.Deliver(context.Background(), s.Id, []byte("m"), time.Millisecond)
+		}
+	}()
+	if err := h.SessionMgr.CloseSession(s.Id); err != nil {
+		t.Fatal(err)
+	}
+	<-done
+	if err := h.SessionMgr.Deliver(context.Background(), s.Id, []byte("m"), 0); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("deliver to closed session: %v", err)
+	}
+}
+
+func TestDetachedSessionClosesAfterResumeWindow(t *testing.T) {
+	h, gs := newTestHandler(50 * time.Millisecond)
+	conn := &fakeClientConn{}
+	s := connect(t, h, conn)
+
+	h.OnDisconnect(conn)
+	if gs.closed(s.Id) {
+		t.Fatal("session closed immediately on disconnect")
+	}
+	waitFor(t, "session close after resume window", func() bool { return gs.closed(s.Id) })
+	if _, ok := h.SessionMgr.GetSession(s.Id); ok {
+		t.Fatal("expired session still registered")
+	}
+
+	conn2 := &fakeClientConn{}
+	s2 := connect(t, h, conn2)
+	if _, err := h.SessionMgr.ResumeSession(s.Id, s2.Id); err == nil {
+		t.Fatal("resumed an expired session")
+	}
+}
+
+func TestSendFailureDetachesAndKeepsUnsentMessage(t *testing.T) {
+	h, _ := newTestHandler(time.Minute)
+	conn1 := &fakeClientConn{}
+	s1 := connect(t, h, conn1)
+	_ = conn1.Close()
+	s1.SendChan <- []byte("lost-on-wire")
+	waitFor(t, "detach on send failure", func() bool { return h.SessionMgr.IsDetached(s1.Id) })
+	s1.SendChan <- []byte("after")
+
+	conn2 := &fakeClientConn{}
+	s2 := connect(t, h, conn2)
+	if _, err := h.SessionMgr.ResumeSession(s1.Id, s2.Id); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "replay delivered", func() bool { return len(conn2.received()) == 2 })
+	if got := conn2.received(); got[0] != "lost-on-wire" || got[1] != "after" {
+		t.Fatalf("conn2 received %v, want [lost-on-wire after]", got)
+	}
+}
+
+func TestFloodingSessionIsKickedAndIpBanned(t *testing.T) {
+	gs := &fakeGsConn{}
+	guard := flood.NewGuard(&flood.Config{
+		Session:       flood.BucketConfig{Rate: 1, Burst: 1},
+		MaxViolations: 1,
+		BanSeconds:    60,
+	})
+	h := NewClientHandler(gs, NewSessionManager(0, 16, time.Minute, time.Minute, gs), guard)
+	conn := &fakeClientConn{}
+	s := connect(t, h, conn)
+	if err := h.HandleMessage(context.Background(), conn, &network.Message{Type: network.MsgTypeClient}); err != nil {
+		t.Fatal(err)
+	}
+
+	isKick := func(payload string) bool {
+		msg, err := network.DefaultCodec().DecodeClientMessage([]byte(payload))
+		if err != nil || msg.MsgId != uint16(protocol.S2CProtocol_S2CKick) {
+			return false
+		}
+		var req protocol.S2CKickReq
+		return proto.Unmarshal(msg.Data, &req) == nil && req.Reason == protocol.KickReason_KickRateLimit
+	}
+	waitFor(t, "kick delivered", func() bool {
+		got := conn.received()
+		return len(got) == 1 && isKick(got[0])
+	})
+	waitFor(t, "kicked session closed", func() bool { return gs.closed(s.Id) })
+
+	banned := &fakeClientConn{}
+	if err := h.HandleMessage(context.Background(), banned, &network.Message{Type: network.MsgTypeClient}); err != nil {
+		t.Fatal(err)
+	}
+	if len(banned.received()) != 1 || !banned.closed {
+		t.Fatal("banned ip not kicked on new connection")
+	}
+	if len(h.Sessions) != 0 {
+		t.Fatalf("sessions left after kick: %d", len(h.Sessions))
+	}
+}
+
+func TestNotifyKickSendsReasonAndSkipsResume(t *testing.T) {
+	h, gs := newTestHandler(time.Minute)
+	conn := &fakeClientConn{}
+	s := connect(t, h, conn)
+
+	if !h.SessionMgr.NotifyKick(s, protocol.KickReason_KickAccountBanned, "banned") {
+		t.Fatal("NotifyKick returned false for connected session")
+	}
+	if h.SessionMgr.NotifyKick(s, protocol.KickReason_KickByGm, "") {
+		t.Fatal("session kicked twice")
+	}
+	waitFor(t, "kick delivered", func() bool {
+		got := conn.received()
+		if len(got) != 1 {
+			return false
+		}
+		msg, err := network.DefaultCodec().DecodeClientMessage([]byte(got[0]))
+		if err != nil || msg.MsgId != uint16(protocol.S2CProtocol_S2CKick) {
+			return false
+		}
+		var req protocol.S2CKickReq
+		return proto.Unmarshal(msg.Data, &req) == nil && req.Reason == protocol.KickReason_KickAccountBanned && req.Msg == "banned"
+	})
+	waitFor(t, "conn closed", func() bool {
+		conn.mu.Lock()
+		defer conn.mu.Unlock()
+		return conn.closed
+	})
+
+	// 连接断开后被踢的会话直接关闭，不进入断线保留
+	h.OnDisconnect(conn)
+	waitFor(t, "kicked session closed", func() bool { return gs.closed(s.Id) })
+}
@@ -13,13 +13,24 @@ import (
 	"path"
 	"postapocgame/server/internal"
 	"postapocgame/server/pkg/tool"
+	"postapocgame/server/service/gateway/internel/flood"
 	"time"
 )
 
+// GameServerConfig 区服对应的 GameServer，(platform_id, srv_id) 与 gamesrv.json 一致
+type GameServerConfig struct {
+	PlatformId uint32 `json:"platform_id"`
+	SrvId      uint32 `json:"srv_id"`
+	Name       string `json:"name"` // 区服名称，下发给客户端展示
+	Addr       string `json:"addr"`
+}
+
 // Config Gateway配置
 type Config struct {
-	// 游戏服务器地址
+	// 游戏服务器地址（单区服部署的简写，等价于只有一项且区服号为 0 的 game_servers）
 	GameServerAddr string `json:"gameServerAddr"`
+	// 区服列表，客户端登录前选择区服，会话按所选区服路由到对应 GameServer
+	GameServers []GameServerConfig `json:"game_servers"`
 
 	// TCP配置
 	TCPAddr string `json:"tcp_addr"` // TCP监听地址,如 ":8080"
@@ -28,12 +39,25 @@ type Config struct {
 	WSAddr string `json:"ws_addr"` // WebSocket监听地址,如 ":8081"
 	WSPath string `json:"ws_path"` // WebSocket路径,如 "/ws"
 
+	// 加密配置，开启后客户端必须先握手，关闭时为明文模式（Go 调试客户端默认使用明文）
+	Encrypt bool `json:"encrypt"`
+
 	// 会话配置
 	SessionBufferSize int           // 每个会话的发送缓冲区大小
 	MaxSessions       uint32        // 最大会话数
 	SessionTimeout    time.Duration // 会话超时时间
 
+	// 连接断开后会话保留秒数，期间客户端可凭 reconnect_key 恢复；0 表示断开即关闭，不配置时为 30 秒
+	ResumeWindowSec *int          `json:"resume_window_sec"`
+	ResumeWindow    time.Duration `json:"-"` // 由 ResumeWindowSec 换算
+
 	MaxFrameSize int // 帧协议配置 最大帧大小
+
+	// 限流与防刷配置，修改 gateway.json 后每 FloodReloadSec 秒检查一次并热加载（仅 flood 段生效）
+	Flood          flood.Config `json:"flood"`
+	FloodReloadSec int          `json:"flood_reload_sec"`
+
+	confPath string // 配置文件路径，热加载时重新读取
 }
 
 const (
@@ -41,10 +65,15 @@ const (
 	defaultSessionBufferSize = 256
 	defaultMaxSessions       = 10000
 	defaultSessionTimeout    = 5 * time.Minute
+	defaultResumeWindow      = 30 * time.Second
 	defaultMaxFrameSize      = 10 * 1024 * 1024 // 10MB
+	defaultFloodReloadSec    = 5
 )
 
 func (c *Config) applyDefaults() {
+	if len(c.GameServers) == 0 && c.GameServerAddr != "" {
+		c.GameServers = []GameServerConfig{{Addr: c.GameServerAddr}}
+	}
 	if c.WSPath == "" {
 		c.WSPath = defaultWSPath
 	}
@@ -57,17 +86,34 @@ func (c *Config) applyDefaults() {
 	if c.SessionTimeout <= 0 {
 		c.SessionTimeout = defaultSessionTimeout
 	}
+	if c.ResumeWindowSec == nil {
+		c.ResumeWindow = defaultResumeWindow
+	} else {
+		c.ResumeWindow = time.Duration(*c.ResumeWindowSec) * time.Second
+	}
 	if c.MaxFrameSize <= 0 {
 		c.MaxFrameSize = defaultMaxFrameSize
 	}
+	if c.FloodReloadSec <= 0 {
+		c.FloodReloadSec = defaultFloodReloadSec
+	}
+	c.Flood.ApplyDefaults()
 }
 
 func (c *Config) Validate() error {
-	if c.GameServerAddr == "" {
-		return fmt.Errorf("gameServerAddr is required")
+	if len(c.GameServers) == 0 {
+		return fmt.Errorf("game_servers or gameServerAddr is required")
 	}
-	if err := validateAddr(c.GameServerAddr); err != nil {
-		return fmt.Errorf("invalid gameServerAddr: %w", err)
+	realms := make(map[[2]uint32]struct{}, len(c.GameServers))
+	for i, gs := range c.GameServers {
+		if err := validateAddr(gs.Addr); err != nil {
+			return fmt.Errorf("invalid game_servers[%d].addr: %w", i, err)
+		}
+		key := [2]uint32{gs.PlatformId, gs.SrvId}
+		if _, ok := realms[key]; ok {
+			return fmt.Errorf("duplicate game server realm %d-%d", gs.PlatformId, gs.SrvId)
+		}
+		realms[key] = struct{}{}
 	}
 	if c.TCPAddr == "" && c.WSAddr == "" {
 		return fmt.Errorf("at least one of tcp_addr or ws_addr must be configured")
@@ -91,9 +137,15 @@ func (c *Config) Validate() error {
 	if c.SessionTimeout <= 0 {
 		return fmt.Errorf("sessionTimeout must be greater than 0")
 	}
+	if c.ResumeWindowSec != nil && *c.ResumeWindowSec < 0 {
+		return fmt.Errorf("resume_window_sec must not be negative")
+	}
 	if c.MaxFrameSize <= 0 {
 		return fmt.Errorf("maxFrameSize must be greater than 0")
 	}
+	if err := c.Flood.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -118,6 +170,7 @@ func LoadGatewayConf(confPath string) (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	conf.confPath = confPath
 	conf.applyDefaults()
 	if err := conf.Validate(); err != nil {
 		return nil, err
@@ -0,0 +1,38 @@
+package engine
+
+import (
+	"postapocgame/server/internal"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResumeWindowConfig(t *testing.T) {
+	cases := []struct {
+		json string
+		want time.Duration
+	}{
+		{`{}`, defaultResumeWindow},
+		{`{"resume_window_sec": 0}`, 0},
+		{`{"resume_window_sec": 90}`, 90 * time.Second},
+	}
+	for _, c := range cases {
+		var conf Config
+		if err := internal.Unmarshal([]byte(c.json), &conf); err != nil {
+			t.Fatalf("unmarshal %s: %v", c.json, err)
+		}
+		conf.applyDefaults()
+		if conf.ResumeWindow != c.want {
+			t.Fatalf("%s resume window = %v, want %v", c.json, conf.ResumeWindow, c.want)
+		}
+	}
+
+	var conf Config
+	if err := internal.Unmarshal([]byte(`{"game_servers":[{"addr":"127.0.0.1:3011"}],"tcp_addr":":1011","resume_window_sec":-1}`), &conf); err != nil {
+		t.Fatal(err)
+	}
+	conf.applyDefaults()
+	if err := conf.Validate(); err == nil || !strings.Contains(err.Error(), "resume_window_sec") {
+		t.Fatalf("negative resume_window_sec should be rejected, got %v", err)
+	}
+}
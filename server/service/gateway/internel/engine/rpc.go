@@ -0,0 +1,62 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/2
+ * @Desc: Gateway 对 GameServer 提供的RPC
+**/
+
+package engine
+
+import (
+	"context"
+	"postapocgame/server/internal/network"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+)
+
+func (g *GatewayServer) registerRPCHandlers() {
+	rpcServer := g.gsConnector.GetRPCServer()
+	network.RegisterRPCProtoHandler(rpcServer, uint16(protocol.RpcMsgId_RpcKickSession), g.handleKickSession)
+	network.RegisterRPCProtoHandler(rpcServer, uint16(protocol.RpcMsgId_RpcResumeSession), g.handleResumeSession)
+}
+
+// handleKickSession 断开客户端会话（GM 踢人、顶号等），带原因时先下发 S2CKick
+// 只能踢发起请求的区服上的会话
+func (g *GatewayServer) handleKickSession(ctx context.Context, _ string, req *protocol.RpcKickSessionReq) (*protocol.RpcKickSessionResp, error) {
+	session, ok := g.sessionMgr.GetSession(req.SessionId)
+	if !ok {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Player_NotFound), "session %s not found", req.SessionId)
+	}
+	if err := g.gsConnector.CheckCaller(ctx, session.Id); err != nil {
+		return nil, err
+	}
+	if req.Reason != protocol.KickReason_KickNil && g.sessionMgr.NotifyKick(session, req.Reason, req.Msg) {
+		return &protocol.RpcKickSessionResp{}, nil
+	}
+	conn := g.sessionMgr.SessionConn(session)
+	if err := g.sessionMgr.CloseSession(req.SessionId); err != nil {
+		return nil, customerr.Wrap(err)
+	}
+	if conn != nil {
+		if err := conn.Close(); err != nil {
+			log.Warnf("close kicked session %s conn failed: %v", req.SessionId, err)
+		}
+	}
+	return &protocol.RpcKickSessionResp{}, nil
+}
+
+// handleResumeSession 断线重连：GameServer 校验 reconnect_key 后，由新会话接管原会话并补发断线期间缓存的消息
+// 原会话与新会话都须绑定在发起请求的区服上
+func (g *GatewayServer) handleResumeSession(ctx context.Context, _ string, req *protocol.RpcResumeSessionReq) (*protocol.RpcResumeSessionResp, error) {
+	for _, sessionId := range []string{req.OldSessionId, req.NewSessionId} {
+		if err := g.gsConnector.CheckCaller(ctx, sessionId); err != nil {
+			return nil, err
+		}
+	}
+	replayed, err := g.sessionMgr.ResumeSession(req.OldSessionId, req.NewSessionId)
+	if err != nil {
+		return nil, customerr.Wrap(err)
+	}
+	g.gsConnector.Unbind(req.OldSessionId)
+	return &protocol.RpcResumeSessionResp{Replayed: uint32(replayed)}, nil
+}
@@ -2,12 +2,16 @@ package engine
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"os"
 	"postapocgame/server/internal/network"
+	"postapocgame/server/internal/protocol"
 	"postapocgame/server/pkg/log"
 	"postapocgame/server/pkg/routine"
 	"postapocgame/server/service/gateway/internel/clientnet"
+	"postapocgame/server/service/gateway/internel/flood"
 	"postapocgame/server/service/gateway/internel/gameserverlink"
 	"sync"
 	"time"
@@ -16,7 +20,8 @@ import (
 type GatewayServer struct {
 	config      *Config
 	sessionMgr  *clientnet.SessionManager
-	gsConnector clientnet.IGameServerConnector
+	gsConnector *gameserverlink.Pool
+	guard       *flood.Guard
 	tcpServer   network.ITCPServer
 	wsServer    *network.WSServer
 	stopChan    chan struct{}
@@ -26,15 +31,24 @@ type GatewayServer struct {
 }
 
 func NewGatewayServer(config *Config) (*GatewayServer, error) {
-	gsConnector := gameserverlink.NewGameClient(config.GameServerAddr)
-	sessionMgr := clientnet.NewSessionManager(config.MaxSessions, config.SessionBufferSize, config.SessionTimeout, gsConnector)
-
-	return &GatewayServer{
-		config:      config,
-		sessionMgr:  sessionMgr,
-		gsConnector: gsConnector,
-		stopChan:    make(chan struct{}),
-	}, nil
+	g := &GatewayServer{
+		config:   config,
+		guard:    flood.NewGuard(&config.Flood),
+		stopChan: make(chan struct{}),
+	}
+	backends := make([]gameserverlink.Backend, 0, len(config.GameServers))
+	for _, gs := range config.GameServers {
+		backends = append(backends, gameserverlink.Backend{
+			PlatformId: gs.PlatformId,
+			SrvId:      gs.SrvId,
+			Name:       gs.Name,
+			Addr:       gs.Addr,
+		})
+	}
+	g.gsConnector = gameserverlink.NewPool(backends, g.onRealmDown)
+	g.sessionMgr = clientnet.NewSessionManager(config.MaxSessions, config.SessionBufferSize, config.SessionTimeout, config.ResumeWindow, g.gsConnector)
+	g.registerRPCHandlers()
+	return g, nil
 }
 
 // Start 启动网关
@@ -42,7 +56,7 @@ func (g *GatewayServer) Start(ctx context.Context) error {
 	g.ctx, g.cancel = context.WithCancel(ctx)
 
 	// 连接到GameServer
-	if err := g.gsConnector.Connect(g.ctx, g.config.GameServerAddr); err != nil {
+	if err := g.gsConnector.Connect(g.ctx); err != nil {
 		return fmt.Errorf("connect to game server failed: %w", err)
 	}
 
@@ -56,6 +70,15 @@ func (g *GatewayServer) Start(ctx context.Context) error {
 		return nil
 	})
 
+	// 启动限流配置热加载
+	if g.config.confPath != "" {
+		g.wg.Add(1)
+		routine.GoV2(func() error {
+			g.watchFloodConfig(g.ctx)
+			return nil
+		})
+	}
+
 	// 启动TCP服务器
 	if g.config.TCPAddr != "" {
 		if err := g.startTCPServer(g.ctx); err != nil {
@@ -139,7 +162,7 @@ func (g *GatewayServer) Stop(ctx context.Context) error {
 
 // startTCPServer 启动TCP服务器（使用统一接口）
 func (g *GatewayServer) startTCPServer(ctx context.Context) error {
-	handler := clientnet.NewClientHandler(g.gsConnector, g.sessionMgr)
+	handler := clientnet.NewClientHandler(g.gsConnector, g.sessionMgr, g.guard)
 
 	g.tcpServer = network.NewTCPServer(
 		network.WithTCPServerOptionNetworkMessageHandler(handler),
@@ -148,9 +171,11 @@ func (g *GatewayServer) startTCPServer(ctx context.Context) error {
 		}),
 		network.WithTCPServerOptionOnDisConn(func(conn network.IConnection) {
 			log.Infof("dis conn......")
+			handler.OnDisconnect(conn)
 		}),
 		network.WithTCPServerOptionMaxConnections(g.config.MaxSessions),
 		network.WithTCPServerOptionAddr(g.config.TCPAddr),
+		network.WithTCPServerOptionHandshake(g.config.Encrypt),
 	)
 	if err := g.tcpServer.Start(ctx); err != nil {
 		return err
@@ -162,19 +187,21 @@ func (g *GatewayServer) startTCPServer(ctx context.Context) error {
 
 // startWSServer 启动WebSocket服务器（使用统一接口）
 func (g *GatewayServer) startWSServer(ctx context.Context) error {
+	handler := &clientnet.ClientHandler{
+		SessionMgr:  g.sessionMgr,
+		GsConnector: g.gsConnector,
+		Guard:       g.guard,
+		Sessions:    make(map[network.IConnection]*clientnet.Session),
+	}
+
 	serverConfig := &network.WSServerConfig{
 		Addr:            g.config.WSAddr,
 		Path:            g.config.WSPath,
 		AllowedIPs:      nil,
 		MaxConnections:  g.config.MaxSessions,
-		HandshakeEnable: false,
+		HandshakeEnable: g.config.Encrypt,
 		CheckOrigin:     func(r *http.Request) bool { return true },
-	}
-
-	handler := &clientnet.ClientHandler{
-		SessionMgr:  g.sessionMgr,
-		GsConnector: g.gsConnector,
-		Sessions:    make(map[network.IConnection]*clientnet.Session),
+		OnDisconnected:  handler.OnDisconnect,
 	}
 
 	g.wsServer = network.NewWSServer(serverConfig, handler)
@@ -228,27 +255,73 @@ func (g *GatewayServer) dispatchGameServerMessages(ctx context.Context) {
 			continue
 		}
 
-		// 获取会话
-		session, ok := g.sessionMgr.GetSession(msg.SessionId)
-		if !ok {
-			log.Debugf("Session not found: %s", msg.SessionId)
-			releaseMsg()
-			continue
+		// 投递到会话（在线会话缓冲区满时最多等待 100ms）
+		sessionId := msg.SessionId
+		err = g.sessionMgr.Deliver(ctx, sessionId, msg.Payload, 100*time.Millisecond)
+		releaseMsg()
+		switch {
+		case err == nil:
+		case errors.Is(err, clientnet.ErrSessionNotFound):
+			log.Debugf("Session not found: %s", sessionId)
+		case errors.Is(err, clientnet.ErrSendBufferFull):
+			log.Warnf("Session send channel full or timeout: %s", sessionId)
+		default:
+			log.Infof("Dispatch stopped: %v", err)
+			return
 		}
+	}
+}
+
+// watchFloodConfig 定期检查配置文件修改时间，变化后重新加载并替换限流配置
+func (g *GatewayServer) watchFloodConfig(ctx context.Context) {
+	defer g.wg.Done()
+
+	var lastMod time.Time
+	if info, err := os.Stat(g.config.confPath); err == nil {
+		lastMod = info.ModTime()
+	}
+	ticker := time.NewTicker(time.Duration(g.config.FloodReloadSec) * time.Second)
+	defer ticker.Stop()
 
-		// 发送到客户端（非阻塞）
+	for {
 		select {
-		case session.SendChan <- msg.Payload:
-			releaseMsg()
-		case <-time.After(100 * time.Millisecond):
-			log.Warnf("Session send channel full or timeout: %s", msg.SessionId)
-			releaseMsg()
-		case <-g.stopChan:
-			releaseMsg()
-			return
 		case <-ctx.Done():
-			releaseMsg()
 			return
+		case <-g.stopChan:
+			return
+		case <-ticker.C:
+		}
+		info, err := os.Stat(g.config.confPath)
+		if err != nil || !info.ModTime().After(lastMod) {
+			continue
+		}
+		lastMod = info.ModTime()
+		conf, err := LoadGatewayConf(g.config.confPath)
+		if err != nil {
+			log.Errorf("reload flood config failed, keep current: %v", err)
+			continue
+		}
+		g.guard.SetConfig(&conf.Flood)
+		log.Infof("flood config reloaded from %s", g.config.confPath)
+	}
+}
+
+// onRealmDown 区服 GameServer 连接断开：通知其会话重新选择区服，会话连接保留
+func (g *GatewayServer) onRealmDown(realm *protocol.RealmInfo, sessionIds []string) {
+	var available []*protocol.RealmInfo
+	for _, info := range g.gsConnector.ListRealms() {
+		if info.Online {
+			available = append(available, info)
+		}
+	}
+	msg := &protocol.S2CRealmDownReq{Realm: realm, Available: available}
+	for _, sessionId := range sessionIds {
+		session, ok := g.sessionMgr.GetSession(sessionId)
+		if !ok {
+			continue
+		}
+		if err := clientnet.SendProto(session, uint16(protocol.S2CProtocol_S2CRealmDown), msg); err != nil {
+			log.Warnf("notify realm down to session %s failed: %v", sessionId, err)
 		}
 	}
 }
@@ -0,0 +1,122 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/9
+ * @Desc: 网关限流与防刷配置（gateway.json 的 flood 段，支持热加载）
+**/
+
+package flood
+
+import (
+	"fmt"
+	"postapocgame/server/internal/protocol"
+	"time"
+)
+
+// BucketConfig 令牌桶：每秒补充 Rate 个令牌，最多积累 Burst 个；Rate<=0 表示不限
+type BucketConfig struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+// ClassConfig 协议类别限流，MsgIds 或 [MinMsgId, MaxMsgId] 命中即归入该类别，按配置顺序取第一个命中的类别
+type ClassConfig struct {
+	Name     string   `json:"name"`
+	MsgIds   []uint16 `json:"msg_ids"`
+	MinMsgId uint16   `json:"min_msg_id"`
+	MaxMsgId uint16   `json:"max_msg_id"`
+	Rate     float64  `json:"rate"`
+	Burst    int      `json:"burst"`
+}
+
+func (c *ClassConfig) match(msgId uint16) bool {
+	for _, id := range c.MsgIds {
+		if id == msgId {
+			return true
+		}
+	}
+	return c.MaxMsgId > 0 && msgId >= c.MinMsgId && msgId <= c.MaxMsgId
+}
+
+// Config 限流与防刷配置
+// 超限的消息直接丢弃并计 1 分违规，超长消息计 OversizePenalty 分；
+// ViolationWindowSec 内违规分达到 MaxViolations 时踢下线并封禁 IP BanSeconds 秒
+type Config struct {
+	Disable            bool          `json:"disable"`
+	Session            BucketConfig  `json:"session"`              // 每个会话的全部消息
+	Classes            []ClassConfig `json:"classes"`              // 按协议类别
+	MaxPayloadSize     int           `json:"max_payload_size"`     // 单条客户端消息上限（字节）
+	OversizePenalty    int           `json:"oversize_penalty"`     // 超长消息的违规分
+	MaxViolations      int           `json:"max_violations"`       // 踢下线的违规分阈值
+	ViolationWindowSec int           `json:"violation_window_sec"` // 违规分统计窗口
+	BanSeconds         int           `json:"ban_seconds"`          // 踢下线后封禁 IP 的时长，小于 0 表示不封禁
+}
+
+const (
+	defaultMaxPayloadSize     = 64 * 1024
+	defaultOversizePenalty    = 10
+	defaultMaxViolations      = 30
+	defaultViolationWindowSec = 10
+	defaultBanSeconds         = 300
+)
+
+// DefaultClasses 默认协议类别：账号、移动、技能、聊天
+func DefaultClasses() []ClassConfig {
+	return []ClassConfig{
+		{Name: "account", MinMsgId: uint16(protocol.C2SProtocol_C2SRegister), MaxMsgId: 19, Rate: 2, Burst: 5},
+		{Name: "movement", MinMsgId: uint16(protocol.C2SProtocol_C2SStartMove), MaxMsgId: 39, Rate: 30, Burst: 60},
+		{Name: "skill", MinMsgId: uint16(protocol.C2SProtocol_C2SUseSkill), MaxMsgId: 59, Rate: 10, Burst: 20},
+		{Name: "chat", MinMsgId: 200, MaxMsgId: 219, Rate: 1, Burst: 5},
+	}
+}
+
+// ApplyDefaults 填充未配置的项
+func (c *Config) ApplyDefaults() {
+	if c.Session.Rate == 0 && c.Session.Burst == 0 {
+		c.Session = BucketConfig{Rate: 50, Burst: 100}
+	}
+	if c.Classes == nil {
+		c.Classes = DefaultClasses()
+	}
+	if c.MaxPayloadSize <= 0 {
+		c.MaxPayloadSize = defaultMaxPayloadSize
+	}
+	if c.OversizePenalty <= 0 {
+		c.OversizePenalty = defaultOversizePenalty
+	}
+	if c.MaxViolations <= 0 {
+		c.MaxViolations = defaultMaxViolations
+	}
+	if c.ViolationWindowSec <= 0 {
+		c.ViolationWindowSec = defaultViolationWindowSec
+	}
+	if c.BanSeconds == 0 {
+		c.BanSeconds = defaultBanSeconds
+	}
+}
+
+func (c *Config) Validate() error {
+	if c.Session.Rate > 0 && c.Session.Burst <= 0 {
+		return fmt.Errorf("flood.session.burst must be greater than 0")
+	}
+	for i := range c.Classes {
+		class := &c.Classes[i]
+		if class.Name == "" {
+			return fmt.Errorf("flood.classes[%d].name is required", i)
+		}
+		if class.MaxMsgId < class.MinMsgId {
+			return fmt.Errorf("flood class %s: max_msg_id less than min_msg_id", class.Name)
+		}
+		if class.Rate > 0 && class.Burst <= 0 {
+			return fmt.Errorf("flood class %s: burst must be greater than 0", class.Name)
+		}
+	}
+	return nil
+}
+
+func (c *Config) violationWindow() time.Duration {
+	return time.Duration(c.ViolationWindowSec) * time.Second
+}
+
+func (c *Config) banDuration() time.Duration {
+	return time.Duration(c.BanSeconds) * time.Second
+}
@@ -0,0 +1,95 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/9
+ * @Desc: 限流配置热加载与临时 IP 封禁
+**/
+
+package flood
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Guard 网关防刷入口：持有当前限流配置与 IP 封禁表，所有会话共享
+type Guard struct {
+	cfg atomic.Pointer[Config]
+
+	mu   sync.Mutex
+	bans map[string]time.Time // ip -> 解封时间
+}
+
+// NewGuard 创建防刷入口，cfg 为 nil 时使用默认配置
+func NewGuard(cfg *Config) *Guard {
+	g := &Guard{bans: make(map[string]time.Time)}
+	g.SetConfig(cfg)
+	return g
+}
+
+// SetConfig 替换限流配置（热加载），各会话在下一条消息到达时按新配置重建令牌桶
+func (g *Guard) SetConfig(cfg *Config) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	c := *cfg
+	c.ApplyDefaults()
+	g.cfg.Store(&c)
+}
+
+// Config 获取当前限流配置
+func (g *Guard) Config() *Config {
+	return g.cfg.Load()
+}
+
+// NewSessionLimiter 为新会话创建限流状态
+func (g *Guard) NewSessionLimiter() *SessionLimiter {
+	return &SessionLimiter{guard: g}
+}
+
+// Ban 封禁 IP 到 now+BanSeconds，BanSeconds 小于 0 时不封禁
+func (g *Guard) Ban(ip string, now time.Time) {
+	d := g.Config().banDuration()
+	if ip == "" || d <= 0 {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for bannedIp, until := range g.bans {
+		if !now.Before(until) {
+			delete(g.bans, bannedIp)
+		}
+	}
+	g.bans[ip] = now.Add(d)
+}
+
+// IsBanned IP 是否处于封禁期
+func (g *Guard) IsBanned(ip string, now time.Time) bool {
+	if ip == "" {
+		return false
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	until, ok := g.bans[ip]
+	if !ok {
+		return false
+	}
+	if !now.Before(until) {
+		delete(g.bans, ip)
+		return false
+	}
+	return true
+}
+
+// HostOf 从连接地址取出 IP
+func HostOf(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
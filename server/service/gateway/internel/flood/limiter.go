@@ -0,0 +1,128 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/9
+ * @Desc: 每会话令牌桶限流与违规计分
+**/
+
+package flood
+
+import (
+	"postapocgame/server/internal/protocol"
+	"time"
+)
+
+// Action 限流判定结果
+type Action int
+
+const (
+	ActionAllow Action = iota // 放行
+	ActionDrop                // 丢弃本条消息
+	ActionKick                // 违规分超过阈值，踢下线并封禁 IP
+)
+
+// Verdict 单条消息的限流判定
+type Verdict struct {
+	Action Action
+	Reason protocol.KickReason // 丢弃或踢下线的原因
+	Class  string              // 超限的协议类别，会话总量超限时为空
+}
+
+// tokenBucket 令牌桶，rate<=0 时不限
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(cfg BucketConfig, now time.Time) *tokenBucket {
+	return &tokenBucket{
+		rate:   cfg.Rate,
+		burst:  float64(cfg.Burst),
+		tokens: float64(cfg.Burst),
+		last:   now,
+	}
+}
+
+func (b *tokenBucket) allow(now time.Time) bool {
+	if b == nil || b.rate <= 0 {
+		return true
+	}
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// SessionLimiter 单个会话的限流状态，只在该会话连接的读协程上使用，不加锁
+// Guard 的配置热加载后，下一条消息到达时按新配置重建令牌桶
+type SessionLimiter struct {
+	guard *Guard
+	cfg   *Config
+
+	session *tokenBucket
+	classes []*tokenBucket // 与 cfg.Classes 一一对应
+
+	violations  int
+	windowStart time.Time
+}
+
+func (l *SessionLimiter) rebuild(cfg *Config, now time.Time) {
+	l.cfg = cfg
+	l.session = newTokenBucket(cfg.Session, now)
+	l.classes = make([]*tokenBucket, len(cfg.Classes))
+	for i := range cfg.Classes {
+		class := &cfg.Classes[i]
+		l.classes[i] = newTokenBucket(BucketConfig{Rate: class.Rate, Burst: class.Burst}, now)
+	}
+}
+
+// Check 判定一条客户端消息，size 为消息体（含 msgId）字节数
+func (l *SessionLimiter) Check(msgId uint16, size int, now time.Time) Verdict {
+	cfg := l.guard.Config()
+	if cfg.Disable {
+		return Verdict{Action: ActionAllow}
+	}
+	if cfg != l.cfg {
+		l.rebuild(cfg, now)
+	}
+
+	if size > cfg.MaxPayloadSize {
+		return l.violate(cfg.OversizePenalty, protocol.KickReason_KickOversize, "", now)
+	}
+	if !l.session.allow(now) {
+		return l.violate(1, protocol.KickReason_KickRateLimit, "", now)
+	}
+	for i := range cfg.Classes {
+		class := &cfg.Classes[i]
+		if !class.match(msgId) {
+			continue
+		}
+		if !l.classes[i].allow(now) {
+			return l.violate(1, protocol.KickReason_KickRateLimit, class.Name, now)
+		}
+		break
+	}
+	return Verdict{Action: ActionAllow}
+}
+
+// violate 累计违规分，统计窗口过期后重新计分
+func (l *SessionLimiter) violate(points int, reason protocol.KickReason, class string, now time.Time) Verdict {
+	if l.windowStart.IsZero() || now.Sub(l.windowStart) > l.cfg.violationWindow() {
+		l.windowStart = now
+		l.violations = 0
+	}
+	l.violations += points
+	if l.violations >= l.cfg.MaxViolations {
+		return Verdict{Action: ActionKick, Reason: reason, Class: class}
+	}
+	return Verdict{Action: ActionDrop, Reason: reason, Class: class}
+}
@@ -0,0 +1,119 @@
+package flood
+
+import (
+	"postapocgame/server/internal/protocol"
+	"testing"
+	"time"
+)
+
+func testConfig() *Config {
+	return &Config{
+		Session: BucketConfig{Rate: 100, Burst: 100},
+		Classes: []ClassConfig{
+			{Name: "chat", MinMsgId: 200, MaxMsgId: 219, Rate: 1, Burst: 2},
+			{Name: "account", MsgIds: []uint16{1, 2}, Rate: 0},
+		},
+		MaxPayloadSize:     128,
+		OversizePenalty:    5,
+		MaxViolations:      6,
+		ViolationWindowSec: 10,
+		BanSeconds:         60,
+	}
+}
+
+func TestClassBucketDropsAndRefills(t *testing.T) {
+	g := NewGuard(testConfig())
+	l := g.NewSessionLimiter()
+	now := time.Unix(1000, 0)
+
+	for i := 0; i < 2; i++ {
+		if v := l.Check(201, 10, now); v.Action != ActionAllow {
+			t.Fatalf("msg %d within burst got %v", i, v.Action)
+		}
+	}
+	v := l.Check(201, 10, now)
+	if v.Action != ActionDrop || v.Class != "chat" || v.Reason != protocol.KickReason_KickRateLimit {
+		t.Fatalf("over burst got %+v, want drop in chat", v)
+	}
+	// 其他类别与未分类的消息不受影响
+	if v := l.Check(1, 10, now); v.Action != ActionAllow {
+		t.Fatalf("unlimited class got %v", v.Action)
+	}
+	if v := l.Check(50, 10, now); v.Action != ActionAllow {
+		t.Fatalf("unclassified msg got %v", v.Action)
+	}
+	if v := l.Check(201, 10, now.Add(time.Second)); v.Action != ActionAllow {
+		t.Fatalf("after refill got %v", v.Action)
+	}
+}
+
+func TestViolationsKickAndWindowReset(t *testing.T) {
+	g := NewGuard(testConfig())
+	l := g.NewSessionLimiter()
+	now := time.Unix(1000, 0)
+
+	// 超长消息计 5 分，再超限 1 次达到阈值 6
+	if v := l.Check(50, 129, now); v.Action != ActionDrop || v.Reason != protocol.KickReason_KickOversize {
+		t.Fatalf("oversize got %+v, want drop", v)
+	}
+	// 窗口过期后重新计分
+	later := now.Add(11 * time.Second)
+	if v := l.Check(50, 129, later); v.Action != ActionDrop {
+		t.Fatalf("oversize in new window got %v, want drop", v.Action)
+	}
+	l.Check(201, 10, later)
+	l.Check(201, 10, later)
+	v := l.Check(201, 10, later)
+	if v.Action != ActionKick || v.Reason != protocol.KickReason_KickRateLimit {
+		t.Fatalf("violation threshold got %+v, want kick", v)
+	}
+}
+
+func TestSetConfigRebuildsBuckets(t *testing.T) {
+	g := NewGuard(testConfig())
+	l := g.NewSessionLimiter()
+	now := time.Unix(1000, 0)
+	l.Check(201, 10, now)
+	l.Check(201, 10, now)
+	if v := l.Check(201, 10, now); v.Action != ActionDrop {
+		t.Fatalf("got %v, want drop", v.Action)
+	}
+
+	cfg := testConfig()
+	cfg.Classes[0].Burst = 10
+	g.SetConfig(cfg)
+	for i := 0; i < 10; i++ {
+		if v := l.Check(201, 10, now); v.Action != ActionAllow {
+			t.Fatalf("msg %d after reload got %v", i, v.Action)
+		}
+	}
+
+	cfg.Disable = true
+	g.SetConfig(cfg)
+	if v := l.Check(201, 1024, now); v.Action != ActionAllow {
+		t.Fatalf("disabled limiter got %v", v.Action)
+	}
+}
+
+func TestBanExpires(t *testing.T) {
+	g := NewGuard(testConfig())
+	now := time.Unix(1000, 0)
+	g.Ban("10.0.0.1", now)
+	if !g.IsBanned("10.0.0.1", now.Add(59*time.Second)) {
+		t.Fatal("ip not banned within ban duration")
+	}
+	if g.IsBanned("10.0.0.2", now) {
+		t.Fatal("unrelated ip banned")
+	}
+	if g.IsBanned("10.0.0.1", now.Add(60*time.Second)) {
+		t.Fatal("ban did not expire")
+	}
+
+	cfg := testConfig()
+	cfg.BanSeconds = -1
+	g.SetConfig(cfg)
+	g.Ban("10.0.0.1", now)
+	if g.IsBanned("10.0.0.1", now) {
+		t.Fatal("banned with ban_seconds < 0")
+	}
+}
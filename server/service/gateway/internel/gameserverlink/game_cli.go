@@ -5,6 +5,8 @@ import (
 	"postapocgame/server/pkg/customerr"
 	"sync"
 
+	"google.golang.org/protobuf/proto"
+
 	"postapocgame/server/internal/network"
 	"postapocgame/server/pkg/log"
 )
@@ -13,41 +15,64 @@ var (
 	ErrNotConnected = customerr.NewErrorByCode(-1, "not connected to game service")
 )
 
-// GameClient GameServer客户端
+// GameClient 单个 GameServer 的连接
 type GameClient struct {
-	client  network.ITCPClient
-	codec   *network.Codec
-	handler *GameMessageHandler
-	mu      sync.Mutex
-	stopped bool
+	addr      string
+	client    network.ITCPClient
+	codec     *network.Codec
+	handler   *GameMessageHandler
+	rpcClient *network.RPCClient
+	rpcServer *network.RPCServer
+	mu        sync.Mutex
+	stopped   bool
 }
 
-func NewGameClient(addr string) *GameClient {
-	handler := NewGameMessageHandler()
+// NewGameClient 创建区服 realm 的 GameServer 连接，下行消息写入 recvChan，rpcServer 由各连接共用
+// onStateChange 在连接建立或断开后回调（异步执行，回调内应以 IsConnected 为准）
+func NewGameClient(realm RealmKey, addr string, rpcServer *network.RPCServer, recvChan chan<- *network.ForwardMessage, onStateChange func()) *GameClient {
+	rpcClient := network.NewRPCClient(nil)
+	handler := NewGameMessageHandler(realm, rpcClient, rpcServer, recvChan)
 
 	client := network.NewTCPClient(
 		network.WithTCPClientOptionOnConn(func(conn network.IConnection) {
 			log.Infof("connected to game server: %s", addr)
+			if onStateChange != nil {
+				onStateChange()
+			}
 		}),
 		network.WithTCPClientOptionOnDisConn(func(conn network.IConnection) {
 			log.Warnf("disconnected from game server: %s", addr)
+			rpcClient.CancelAll()
+			if onStateChange != nil {
+				onStateChange()
+			}
 		}),
 		network.WithTCPClientOptionNetworkMessageHandler(handler),
 	)
 
+	rpcClient.SetSender(client)
+
 	gsc := &GameClient{
-		client:  client,
-		codec:   network.DefaultCodec(),
-		handler: handler,
+		addr:      addr,
+		client:    client,
+		codec:     network.DefaultCodec(),
+		handler:   handler,
+		rpcClient: rpcClient,
+		rpcServer: rpcServer,
 	}
 
 	return gsc
 }
 
 // Connect 连接到GameServer
-func (gsc *GameClient) Connect(ctx context.Context, addr string) error {
-	// 连接(会自动重连和启动接收协程)
-	return gsc.client.Connect(ctx, addr)
+func (gsc *GameClient) Connect(ctx context.Context) error {
+	// 连接(会自动重连和启动接收协程)，首次连接失败也在后台重试
+	return gsc.client.Connect(ctx, gsc.addr)
+}
+
+// IsConnected 连接是否正常（TCPClient 心跳检测失败即视为断开）
+func (gsc *GameClient) IsConnected() bool {
+	return gsc.client.IsConnected()
 }
 
 // NotifySessionEvent 通知会话事件
@@ -84,9 +109,12 @@ func (gsc *GameClient) ForwardClientMsg(_ context.Context, forwardMsg *network.F
 	return gsc.client.SendMessage(msg)
 }
 
-// ReceiveGsMessage 接收GameServer消息
-func (gsc *GameClient) ReceiveGsMessage(ctx context.Context) (*network.ForwardMessage, error) {
-	return gsc.handler.ReceiveMessage(ctx)
+// CallProto 调用 GameServer RPC
+func (gsc *GameClient) CallProto(ctx context.Context, sessionId string, msgId uint16, req proto.Message, resp proto.Message) error {
+	if !gsc.client.IsConnected() {
+		return ErrNotConnected
+	}
+	return gsc.rpcClient.CallProto(ctx, sessionId, msgId, req, resp)
 }
 
 // Close 关闭连接
@@ -99,16 +127,13 @@ func (gsc *GameClient) Close() error {
 	gsc.stopped = true
 	gsc.mu.Unlock()
 
-	log.Infof("closing game server connector...")
+	log.Infof("closing game server connector: %s", gsc.addr)
 
 	// 关闭TCP客户端(会停止重连和接收协程)
 	if err := gsc.client.Close(); err != nil {
 		log.Errorf("close tcp client failed: %v", err)
 	}
 
-	// 关闭消息处理器
-	gsc.handler.Close()
-
-	log.Infof("game server connector closed")
+	log.Infof("game server connector closed: %s", gsc.addr)
 	return nil
 }
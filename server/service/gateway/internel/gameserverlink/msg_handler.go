@@ -8,28 +8,36 @@ package gameserverlink
 
 import (
 	"context"
-	"errors"
 	"postapocgame/server/internal/network"
 	"postapocgame/server/pkg/log"
-	"sync"
 )
 
-// GameMessageHandler 消息处理器
+// GameMessageHandler 消息处理器，各 GameServer 链路的下行消息汇入同一个接收通道
 type GameMessageHandler struct {
-	codec    *network.Codec
-	recvChan chan *network.ForwardMessage
-	mu       sync.Mutex
-	closed   bool
+	codec     *network.Codec
+	rpcClient *network.RPCClient
+	rpcServer *network.RPCServer
+	recvChan  chan<- *network.ForwardMessage
+	realm     RealmKey // 该链路对应的区服，随 RPC 请求传给处理函数
 }
 
-func NewGameMessageHandler() *GameMessageHandler {
+func NewGameMessageHandler(realm RealmKey, rpcClient *network.RPCClient, rpcServer *network.RPCServer, recvChan chan<- *network.ForwardMessage) *GameMessageHandler {
 	return &GameMessageHandler{
-		codec:    network.DefaultCodec(),
-		recvChan: make(chan *network.ForwardMessage, 1024),
+		realm:     realm,
+		codec:     network.DefaultCodec(),
+		rpcClient: rpcClient,
+		rpcServer: rpcServer,
+		recvChan:  recvChan,
 	}
 }
 
-func (h *GameMessageHandler) HandleMessage(_ context.Context, _ network.IConnection, msg *network.Message) error {
+func (h *GameMessageHandler) HandleMessage(ctx context.Context, conn network.IConnection, msg *network.Message) error {
+	switch msg.Type {
+	case network.MsgTypeRPCRequest:
+		return h.rpcServer.HandleRequest(withCallerRealm(ctx, h.realm), conn, msg)
+	case network.MsgTypeRPCResponse:
+		return h.rpcClient.HandleResponse(msg)
+	}
 	if msg.Type != network.MsgTypeClient {
 		log.Debugf("ignore message type: %d", msg.Type)
 		return nil
@@ -51,25 +59,3 @@ func (h *GameMessageHandler) HandleMessage(_ context.Context, _ network.IConnect
 		return nil
 	}
 }
-
-func (h *GameMessageHandler) ReceiveMessage(ctx context.Context) (*network.ForwardMessage, error) {
-	select {
-	case <-ctx.Done():
-		return nil, ctx.Err()
-	case msg, ok := <-h.recvChan:
-		if !ok {
-			return nil, errors.New("receive channel closed")
-		}
-		return msg, nil
-	}
-}
-
-func (h *GameMessageHandler) Close() {
-	h.mu.Lock()
-	defer h.mu.Unlock()
-
-	if !h.closed {
-		close(h.recvChan)
-		h.closed = true
-	}
-}
@@ -0,0 +1,315 @@
+/**
+ * @Author: zjj
+ * @Date: 2026/1/10
+ * @Desc: 多区服 GameServer 连接池，按 (platform_id, srv_id) 路由会话
+**/
+
+package gameserverlink
+
+import (
+	"context"
+	"errors"
+	"postapocgame/server/internal/network"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"postapocgame/server/pkg/log"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// RealmKey 区服标识
+type RealmKey struct {
+	PlatformId uint32
+	SrvId      uint32
+}
+
+// Backend 区服对应的 GameServer
+type Backend struct {
+	PlatformId uint32
+	SrvId      uint32
+	Name       string
+	Addr       string
+}
+
+type realm struct {
+	key    RealmKey
+	name   string
+	client *GameClient
+	online bool     // 最近一次观察到的连接状态，用于识别断开/恢复
+	orphan []string // 断开时绑定在该区服的会话，恢复连接后通知 GameServer 关闭
+}
+
+func (r *realm) info() *protocol.RealmInfo {
+	return &protocol.RealmInfo{
+		PlatformId: r.key.PlatformId,
+		SrvId:      r.key.SrvId,
+		Name:       r.name,
+		Online:     r.client.IsConnected(),
+	}
+}
+
+// RealmDownFunc 区服连接断开时回调，sessionIds 为已解除绑定的会话
+type RealmDownFunc func(realm *protocol.RealmInfo, sessionIds []string)
+
+// Pool GameServer 连接池，实现 clientnet.IGameServerConnector
+// 会话选择区服后绑定到对应连接，上行消息、会话事件与 RPC 均按绑定路由；
+// 只配置一个区服时新会话自动绑定，与单 GameServer 部署行为一致
+type Pool struct {
+	realms    []*realm // 配置顺序
+	byKey     map[RealmKey]*realm
+	rpcServer *network.RPCServer // 各连接共用，GameServer 的 RPC 请求统一处理
+	recvChan  chan *network.ForwardMessage
+	done      chan struct{}
+	closeOnce sync.Once
+
+	mu       sync.RWMutex
+	bindings map[string]*realm // sessionId -> 区服
+
+	onRealmDown RealmDownFunc
+}
+
+// notifyOrphanTimeout 区服恢复后通知关闭遗留会话的超时
+const notifyOrphanTimeout = 5 * time.Second
+
+// NewPool 创建连接池，onRealmDown 可为 nil
+func NewPool(backends []Backend, onRealmDown RealmDownFunc) *Pool {
+	p := &Pool{
+		byKey:       make(map[RealmKey]*realm, len(backends)),
+		rpcServer:   network.NewRPCServer(),
+		recvChan:    make(chan *network.ForwardMessage, 1024),
+		done:        make(chan struct{}),
+		bindings:    make(map[string]*realm),
+		onRealmDown: onRealmDown,
+	}
+	for _, b := range backends {
+		r := &realm{
+			key:  RealmKey{PlatformId: b.PlatformId, SrvId: b.SrvId},
+			name: b.Name,
+		}
+		r.client = NewGameClient(r.key, b.Addr, p.rpcServer, p.recvChan, func() {
+			p.onStateChange(r)
+		})
+		p.realms = append(p.realms, r)
+		p.byKey[r.key] = r
+	}
+	return p
+}
+
+// Connect 连接全部 GameServer，未连上的区服在后台重试，期间不可选择
+func (p *Pool) Connect(ctx context.Context) error {
+	for _, r := range p.realms {
+		if err := r.client.Connect(ctx); err != nil {
+			return customerr.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// onStateChange 区服连接断开时解除其会话绑定并回调 onRealmDown；恢复连接时通知 GameServer 关闭断开期间遗留的会话
+func (p *Pool) onStateChange(r *realm) {
+	connected := r.client.IsConnected()
+
+	p.mu.Lock()
+	if connected == r.online {
+		p.mu.Unlock()
+		return
+	}
+	r.online = connected
+	var lost, orphan []string
+	if connected {
+		orphan, r.orphan = r.orphan, nil
+	} else {
+		for sessionId, bound := range p.bindings {
+			if bound == r {
+				lost = append(lost, sessionId)
+				delete(p.bindings, sessionId)
+			}
+		}
+		r.orphan = append(r.orphan, lost...)
+	}
+	p.mu.Unlock()
+
+	if !connected {
+		log.Warnf("realm %d-%d down, %d sessions unbound", r.key.PlatformId, r.key.SrvId, len(lost))
+		if p.onRealmDown != nil && len(lost) > 0 {
+			p.onRealmDown(r.info(), lost)
+		}
+		return
+	}
+
+	log.Infof("realm %d-%d online, closing %d orphaned sessions", r.key.PlatformId, r.key.SrvId, len(orphan))
+	for _, sessionId := range orphan {
+		ctx, cancel := context.WithTimeout(context.Background(), notifyOrphanTimeout)
+		err := r.client.NotifySessionEvent(ctx, &network.SessionEvent{
+			EventType: network.SessionEventClose,
+			SessionId: sessionId,
+		})
+		cancel()
+		if err != nil {
+			log.Errorf("notify orphaned session %s close to realm %d-%d failed: %v", sessionId, r.key.PlatformId, r.key.SrvId, err)
+		}
+	}
+}
+
+// route 获取会话所在区服
+func (p *Pool) route(sessionId string) (*realm, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	r, ok := p.bindings[sessionId]
+	return r, ok
+}
+
+// callerRealmKey RPC 请求上下文中发起请求的区服
+type callerRealmKey struct{}
+
+func withCallerRealm(ctx context.Context, key RealmKey) context.Context {
+	return context.WithValue(ctx, callerRealmKey{}, key)
+}
+
+// CheckCaller 校验会话绑定在发起 RPC 的区服上，GameServer 只能操作本区服的会话
+func (p *Pool) CheckCaller(ctx context.Context, sessionId string) error {
+	caller, ok := ctx.Value(callerRealmKey{}).(RealmKey)
+	if !ok {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Realm_SessionMismatch), "rpc caller realm unknown")
+	}
+	r, ok := p.route(sessionId)
+	if !ok || r.key != caller {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Realm_SessionMismatch), "session %s not bound to realm %d-%d", sessionId, caller.PlatformId, caller.SrvId)
+	}
+	return nil
+}
+
+// Unbind 解除会话与区服的绑定，不通知 GameServer（断线重连接管原会话时使用）
+func (p *Pool) Unbind(sessionId string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.bindings, sessionId)
+}
+
+// ListRealms 区服列表及连接状态
+func (p *Pool) ListRealms() []*protocol.RealmInfo {
+	infos := make([]*protocol.RealmInfo, 0, len(p.realms))
+	for _, r := range p.realms {
+		infos = append(infos, r.info())
+	}
+	return infos
+}
+
+// SelectRealm 会话选择区服：绑定后向该区服 GameServer 发送会话创建事件；已在其他区服时先通知原区服关闭会话
+func (p *Pool) SelectRealm(ctx context.Context, sessionId string, platformId, srvId uint32) (*protocol.RealmInfo, error) {
+	r, ok := p.byKey[RealmKey{PlatformId: platformId, SrvId: srvId}]
+	if !ok {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Realm_NotFound), "realm %d-%d not found", platformId, srvId)
+	}
+	if !r.client.IsConnected() {
+		return nil, customerr.NewErrorByCode(int32(protocol.ErrorCode_Realm_Unavailable), "realm %d-%d unavailable", platformId, srvId)
+	}
+
+	p.mu.Lock()
+	prev := p.bindings[sessionId]
+	p.bindings[sessionId] = r
+	p.mu.Unlock()
+	if prev == r {
+		return r.info(), nil
+	}
+
+	if prev != nil {
+		if err := prev.client.NotifySessionEvent(ctx, &network.SessionEvent{
+			EventType: network.SessionEventClose,
+			SessionId: sessionId,
+		}); err != nil {
+			log.Warnf("notify session %s close to realm %d-%d failed: %v", sessionId, prev.key.PlatformId, prev.key.SrvId, err)
+		}
+	}
+	if err := r.client.NotifySessionEvent(ctx, &network.SessionEvent{
+		EventType: network.SessionEventNew,
+		SessionId: sessionId,
+	}); err != nil {
+		p.Unbind(sessionId)
+		return nil, customerr.Wrap(err, int32(protocol.ErrorCode_Realm_Unavailable))
+	}
+	log.Infof("session %s selected realm %d-%d", sessionId, platformId, srvId)
+	return r.info(), nil
+}
+
+// NotifySessionEvent 按会话所在区服发送会话事件
+// 多区服时新会话在选择区服后才通知 GameServer；会话关闭后解除绑定
+func (p *Pool) NotifySessionEvent(ctx context.Context, event *network.SessionEvent) error {
+	r, ok := p.route(event.SessionId)
+	switch event.EventType {
+	case network.SessionEventNew:
+		if !ok {
+			if len(p.realms) != 1 {
+				return nil
+			}
+			r = p.realms[0]
+			p.mu.Lock()
+			p.bindings[event.SessionId] = r
+			p.mu.Unlock()
+		}
+		if err := r.client.NotifySessionEvent(ctx, event); err != nil {
+			p.Unbind(event.SessionId)
+			return err
+		}
+		return nil
+	case network.SessionEventClose:
+		p.Unbind(event.SessionId)
+	}
+	if !ok {
+		return nil
+	}
+	return r.client.NotifySessionEvent(ctx, event)
+}
+
+// ForwardClientMsg 转发客户端消息到会话所在区服
+func (p *Pool) ForwardClientMsg(ctx context.Context, forwardMsg *network.ForwardMessage) error {
+	r, ok := p.route(forwardMsg.SessionId)
+	if !ok {
+		return customerr.NewErrorByCode(int32(protocol.ErrorCode_Realm_NotSelected), "session %s has not selected a realm", forwardMsg.SessionId)
+	}
+	return r.client.ForwardClientMsg(ctx, forwardMsg)
+}
+
+// CallProto 调用会话所在区服的 GameServer RPC；sessionId 为空时仅单区服可用
+func (p *Pool) CallProto(ctx context.Context, sessionId string, msgId uint16, req proto.Message, resp proto.Message) error {
+	r, ok := p.route(sessionId)
+	if !ok {
+		if sessionId != "" || len(p.realms) != 1 {
+			return customerr.NewErrorByCode(int32(protocol.ErrorCode_Realm_NotSelected), "no realm for session %q", sessionId)
+		}
+		r = p.realms[0]
+	}
+	return r.client.CallProto(ctx, sessionId, msgId, req, resp)
+}
+
+// ReceiveGsMessage 接收任一 GameServer 下发的消息
+func (p *Pool) ReceiveGsMessage(ctx context.Context) (*network.ForwardMessage, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-p.done:
+		return nil, errors.New("game server pool closed")
+	case msg := <-p.recvChan:
+		return msg, nil
+	}
+}
+
+// GetRPCServer 获取 Gateway 对 GameServer 提供的RPC服务（各区服共用）
+func (p *Pool) GetRPCServer() *network.RPCServer {
+	return p.rpcServer
+}
+
+// Close 关闭全部连接
+func (p *Pool) Close() error {
+	p.closeOnce.Do(func() {
+		close(p.done)
+		for _, r := range p.realms {
+			if err := r.client.Close(); err != nil {
+				log.Errorf("close realm %d-%d link failed: %v", r.key.PlatformId, r.key.SrvId, err)
+			}
+		}
+	})
+	return nil
+}
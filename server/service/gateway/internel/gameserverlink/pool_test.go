@@ -0,0 +1,226 @@
+package gameserverlink
+
+import (
+	"context"
+	"net"
+	"postapocgame/server/internal/network"
+	"postapocgame/server/internal/protocol"
+	"postapocgame/server/pkg/customerr"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeGameServer 记录 Gateway 发来的会话事件与转发消息
+type fakeGameServer struct {
+	addr   string
+	server network.ITCPServer
+
+	mu     sync.Mutex
+	events []string
+}
+
+func (f *fakeGameServer) HandleMessage(_ context.Context, _ network.IConnection, msg *network.Message) error {
+	codec := network.DefaultCodec()
+	var record string
+	switch msg.Type {
+	case network.MsgTypeSessionEvent:
+		ev, err := codec.DecodeSessionEvent(msg.Payload)
+		if err != nil {
+			return err
+		}
+		if ev.EventType == network.SessionEventNew {
+			record = "new:" + ev.SessionId
+		} else {
+			record = "close:" + ev.SessionId
+		}
+	case network.MsgTypeClient:
+		fwd, err := codec.DecodeForwardMessage(msg.Payload)
+		if err != nil {
+			return err
+		}
+		record = "msg:" + fwd.SessionId
+	default:
+		return nil
+	}
+	f.mu.Lock()
+	f.events = append(f.events, record)
+	f.mu.Unlock()
+	return nil
+}
+
+func (f *fakeGameServer) has(record string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, ev := range f.events {
+		if ev == record {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeGameServer) start(t *testing.T) {
+	t.Helper()
+	f.server = network.NewTCPServer(
+		network.WithTCPServerOptionNetworkMessageHandler(f),
+		network.WithTCPServerOptionAddr(f.addr),
+	)
+	if err := f.server.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newFakeGameServer(t *testing.T) *fakeGameServer {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	_ = l.Close()
+	f := &fakeGameServer{addr: addr}
+	f.start(t)
+	return f
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func realmOnline(p *Pool, srvId uint32) bool {
+	for _, info := range p.ListRealms() {
+		if info.SrvId == srvId {
+			return info.Online
+		}
+	}
+	return false
+}
+
+func TestPoolRoutesBySelectedRealmAndFailsOver(t *testing.T) {
+	gs1, gs2 := newFakeGameServer(t), newFakeGameServer(t)
+	defer gs2.server.Stop(context.Background())
+
+	var downMu sync.Mutex
+	var downSessions []string
+	pool := NewPool([]Backend{
+		{PlatformId: 1, SrvId: 1, Name: "s1", Addr: gs1.addr},
+		{PlatformId: 1, SrvId: 2, Name: "s2", Addr: gs2.addr},
+	}, func(realm *protocol.RealmInfo, sessionIds []string) {
+		downMu.Lock()
+		defer downMu.Unlock()
+		downSessions = append(downSessions, sessionIds...)
+	})
+	defer pool.Close()
+	ctx := context.Background()
+	if err := pool.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "realms online", func() bool { return realmOnline(pool, 1) && realmOnline(pool, 2) })
+
+	// 多区服时新会话在选择区服前不通知任何 GameServer
+	if err := pool.NotifySessionEvent(ctx, &network.SessionEvent{EventType: network.SessionEventNew, SessionId: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	err := pool.ForwardClientMsg(ctx, &network.ForwardMessage{SessionId: "a"})
+	if customerr.GetErrCode(err) != int32(protocol.ErrorCode_Realm_NotSelected) {
+		t.Fatalf("forward before select got %v", err)
+	}
+	if _, err := pool.SelectRealm(ctx, "a", 1, 9); customerr.GetErrCode(err) != int32(protocol.ErrorCode_Realm_NotFound) {
+		t.Fatalf("select unknown realm got %v", err)
+	}
+
+	if _, err := pool.SelectRealm(ctx, "a", 1, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.ForwardClientMsg(ctx, &network.ForwardMessage{SessionId: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "session routed to realm 2", func() bool { return gs2.has("new:a") && gs2.has("msg:a") })
+
+	// 切换区服：原区服收到关闭事件
+	if _, err := pool.SelectRealm(ctx, "a", 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "session moved to realm 1", func() bool { return gs2.has("close:a") && gs1.has("new:a") })
+
+	// 区服断开：会话解绑并回调，选择该区服返回不可用
+	_ = gs1.server.Stop(ctx)
+	waitFor(t, "realm down callback", func() bool {
+		downMu.Lock()
+		defer downMu.Unlock()
+		return len(downSessions) == 1 && downSessions[0] == "a"
+	})
+	if _, err := pool.SelectRealm(ctx, "b", 1, 1); customerr.GetErrCode(err) != int32(protocol.ErrorCode_Realm_Unavailable) {
+		t.Fatalf("select offline realm got %v", err)
+	}
+	err = pool.ForwardClientMsg(ctx, &network.ForwardMessage{SessionId: "a"})
+	if customerr.GetErrCode(err) != int32(protocol.ErrorCode_Realm_NotSelected) {
+		t.Fatalf("forward after realm down got %v", err)
+	}
+
+	// 区服恢复：通知 GameServer 关闭断开期间遗留的会话
+	restarted := &fakeGameServer{addr: gs1.addr}
+	restarted.start(t)
+	defer restarted.server.Stop(ctx)
+	waitFor(t, "orphaned session closed on recovery", func() bool { return restarted.has("close:a") })
+}
+
+func TestSingleRealmBindsNewSessionAutomatically(t *testing.T) {
+	gs := newFakeGameServer(t)
+	defer gs.server.Stop(context.Background())
+	pool := NewPool([]Backend{{Addr: gs.addr}}, nil)
+	defer pool.Close()
+	ctx := context.Background()
+	if err := pool.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "realm online", func() bool { return realmOnline(pool, 0) })
+
+	if err := pool.NotifySessionEvent(ctx, &network.SessionEvent{EventType: network.SessionEventNew, SessionId: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.ForwardClientMsg(ctx, &network.ForwardMessage{SessionId: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.NotifySessionEvent(ctx, &network.SessionEvent{EventType: network.SessionEventClose, SessionId: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "session lifecycle forwarded", func() bool {
+		return gs.has("new:a") && gs.has("msg:a") && gs.has("close:a")
+	})
+	if _, ok := pool.route("a"); ok {
+		t.Fatal("closed session still bound")
+	}
+}
+
+func TestCheckCallerRequiresSessionBoundToCallerRealm(t *testing.T) {
+	pool := NewPool([]Backend{
+		{PlatformId: 1, SrvId: 1, Addr: "127.0.0.1:0"},
+		{PlatformId: 1, SrvId: 2, Addr: "127.0.0.1:0"},
+	}, nil)
+	pool.bindings["a"] = pool.byKey[RealmKey{PlatformId: 1, SrvId: 1}]
+
+	mismatch := func(err error) bool {
+		return customerr.GetErrCode(err) == int32(protocol.ErrorCode_Realm_SessionMismatch)
+	}
+	if err := pool.CheckCaller(withCallerRealm(context.Background(), RealmKey{PlatformId: 1, SrvId: 1}), "a"); err != nil {
+		t.Fatalf("own realm session rejected: %v", err)
+	}
+	if err := pool.CheckCaller(withCallerRealm(context.Background(), RealmKey{PlatformId: 1, SrvId: 2}), "a"); !mismatch(err) {
+		t.Fatalf("other realm session got %v", err)
+	}
+	if err := pool.CheckCaller(withCallerRealm(context.Background(), RealmKey{PlatformId: 1, SrvId: 1}), "b"); !mismatch(err) {
+		t.Fatalf("unbound session got %v", err)
+	}
+	if err := pool.CheckCaller(context.Background(), "a"); !mismatch(err) {
+		t.Fatalf("unknown caller got %v", err)
+	}
+}